@@ -1,39 +1,233 @@
 package main
 
 import (
+	"bibliography_log/internal/domain"
 	"bibliography_log/internal/infrastructure"
+	"bibliography_log/internal/isbn"
+	"bibliography_log/internal/romaji"
 	"bibliography_log/internal/service"
 	"fmt"
+	"io"
 	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
 )
 
+// Supported storage backends.
+const (
+	BackendCSV    = "csv"
+	BackendSQLite = "sqlite"
+)
+
+// Config holds the options used to build the application.
+type Config struct {
+	DataDir string
+	Backend string // BackendCSV or BackendSQLite
+	// ReadingDictPath is an optional SKK or IPADIC (.csv) kanji reading dictionary
+	// that extends the bundled one for romanizing Japanese titles and authors.
+	ReadingDictPath string
+	// ISBNRangesPath is an optional RangeMessage.xml from the International ISBN Agency
+	// that replaces the bundled range table used to hyphenate ISBNs.
+	ISBNRangesPath string
+	// MetadataProviders lists the catalogues asked by 'add-bib -lookup', in order,
+	// as comma-separated names, each optionally followed by "=<base URL>".
+	MetadataProviders string
+	// User is recorded as who changed a review; the login name is used if empty.
+	User string
+}
+
+// DefaultMetadataProviders asks openBD first, as it has the best coverage of Japanese books.
+const DefaultMetadataProviders = "openbd,googlebooks,openlibrary"
+
 // App holds the application dependencies.
 type App struct {
 	BibService    *service.BibliographyService
 	ReviewService *service.ReviewService
+	SearchService *service.SearchService
+	// ReadingService tracks reading status; see 'biblog start', 'finish' and 'abandon'.
+	ReadingService *service.ReadingService
+	// HighlightService records quotes; see 'biblog add-quote' and 'list-quotes'.
+	HighlightService *service.HighlightService
+	// MetadataService looks up books by ISBN; see 'biblog add-bib -lookup'.
+	MetadataService *service.MetadataService
+	// ISBNNormalizer rewrites stored ISBNs; see 'biblog migrate-isbn'.
+	ISBNNormalizer domain.ISBNNormalizer
+
+	closer io.Closer
 }
 
 // NewApp initializes the application and its dependencies.
-func NewApp() (*App, error) {
+func NewApp(cfg Config) (*App, error) {
 	// Ensure data directory exists
-	dataDir := "data"
+	dataDir := cfg.DataDir
+	if dataDir == "" {
+		dataDir = "data"
+	}
 	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
 		if err := os.Mkdir(dataDir, 0o755); err != nil {
 			return nil, fmt.Errorf("error creating data directory: %w", err)
 		}
 	}
 
+	if cfg.ISBNRangesPath != "" {
+		ranges, err := isbn.LoadRangesFile(cfg.ISBNRangesPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading ISBN ranges: %w", err)
+		}
+		isbn.SetDefault(ranges)
+	}
+
 	// Initialize Repositories
-	bibRepo := infrastructure.NewCSVBibliographyRepository(dataDir + "/bibliographies.csv")
-	classRepo := infrastructure.NewCSVClassificationRepository(dataDir + "/classifications.csv")
-	reviewRepo := infrastructure.NewCSVReviewRepository(dataDir + "/reviews.csv")
+	var (
+		bibRepo        domain.BibliographyRepository
+		classRepo      domain.ClassificationRepository
+		reviewRepo     domain.ReviewRepository
+		statusRepo     domain.ReadingStatusRepository
+		sessionRepo    domain.ReadingSessionRepository
+		highlightRepo  domain.HighlightRepository
+		revisionRepo   domain.ReviewRevisionRepository
+		isbnNormalizer domain.ISBNNormalizer
+		closer         io.Closer
+	)
+	switch cfg.Backend {
+	case "", BackendCSV:
+		csvBibRepo := infrastructure.NewCSVBibliographyRepository(filepath.Join(dataDir, "bibliographies.csv"))
+		csvBibRepo.ReviewFilePath = filepath.Join(dataDir, "reviews.csv")
+		csvBibRepo.StatusFilePath = filepath.Join(dataDir, "reading_status.csv")
+		bibRepo = csvBibRepo
+		isbnNormalizer = csvBibRepo
+		classRepo = infrastructure.NewCSVClassificationRepository(filepath.Join(dataDir, "classifications.csv"))
+		reviewRepo = infrastructure.NewCSVReviewRepository(filepath.Join(dataDir, "reviews.csv"))
+		statusRepo = infrastructure.NewCSVReadingStatusRepository(filepath.Join(dataDir, "reading_status.csv"))
+		sessionRepo = infrastructure.NewCSVReadingSessionRepository(filepath.Join(dataDir, "reading_sessions.csv"))
+		highlightRepo = infrastructure.NewCSVHighlightRepository(filepath.Join(dataDir, "highlights.csv"))
+		revisionRepo = infrastructure.NewCSVReviewRevisionRepository(filepath.Join(dataDir, "review_revisions.csv"))
+	case BackendSQLite:
+		db, err := infrastructure.OpenSQLiteDB(filepath.Join(dataDir, "biblog.db"))
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite database: %w", err)
+		}
+		sqliteBibRepo := infrastructure.NewSQLiteBibliographyRepository(db)
+		bibRepo = sqliteBibRepo
+		isbnNormalizer = sqliteBibRepo
+		classRepo = infrastructure.NewSQLiteClassificationRepository(db)
+		reviewRepo = infrastructure.NewSQLiteReviewRepository(db)
+		statusRepo = infrastructure.NewSQLiteReadingStatusRepository(db)
+		sessionRepo = infrastructure.NewSQLiteReadingSessionRepository(db)
+		highlightRepo = infrastructure.NewSQLiteHighlightRepository(db)
+		revisionRepo = infrastructure.NewSQLiteReviewRevisionRepository(db)
+		closer = db
+	default:
+		return nil, fmt.Errorf("unknown backend %q (expected %q or %q)", cfg.Backend, BackendCSV, BackendSQLite)
+	}
 
 	// Initialize Service
-	bibSvc := service.NewBibliographyService(bibRepo, classRepo)
+	bibSvc := service.NewBibliographyService(bibRepo, classRepo, reviewRepo)
+	if cfg.ReadingDictPath != "" {
+		userDict, err := romaji.LoadDictionaryFile(cfg.ReadingDictPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading reading dictionary: %w", err)
+		}
+		bibSvc.SetRomanizer(romaji.NewRomanizer(romaji.Bundled().WithOverrides(userDict)))
+	}
+	bibSvc.SetReadingStatusRepository(statusRepo)
+	bibSvc.SetReadingSessionRepository(sessionRepo)
+	bibSvc.SetHighlightRepository(highlightRepo)
+	bibSvc.SetReviewRevisionRepository(revisionRepo)
 	reviewSvc := service.NewReviewService(reviewRepo, bibRepo)
+	reviewSvc.SetRevisionRepository(revisionRepo, userName(cfg.User))
+
+	// The search index is shared by both backends and kept up to date by the services
+	searchIndex := infrastructure.NewFileSearchIndex(filepath.Join(dataDir, "search.idx"))
+	bibSvc.SetSearchIndex(searchIndex)
+	reviewSvc.SetSearchIndex(searchIndex)
+	searchSvc := service.NewSearchService(searchIndex, bibRepo, reviewRepo)
+
+	// Catalogue responses are cached so that books can be looked up again offline
+	providers, err := newMetadataProviders(cfg.MetadataProviders, infrastructure.NewMetadataCache(filepath.Join(dataDir, "cache", "metadata")))
+	if err != nil {
+		return nil, err
+	}
 
 	return &App{
-		BibService:    bibSvc,
-		ReviewService: reviewSvc,
+		BibService:       bibSvc,
+		ReviewService:    reviewSvc,
+		SearchService:    searchSvc,
+		ReadingService:   service.NewReadingService(statusRepo, sessionRepo, bibRepo),
+		HighlightService: service.NewHighlightService(highlightRepo, bibRepo),
+		MetadataService:  service.NewMetadataService(providers...),
+		ISBNNormalizer:   isbnNormalizer,
+		closer:           closer,
 	}, nil
 }
+
+// newMetadataProviders parses a provider list such as "openbd,openlibrary=http://localhost:8080".
+func newMetadataProviders(spec string, cache *infrastructure.MetadataCache) ([]domain.MetadataProvider, error) {
+	if spec == "" {
+		spec = DefaultMetadataProviders
+	}
+	var providers []domain.MetadataProvider
+	for _, entry := range strings.Split(spec, ",") {
+		name, baseURL, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			continue
+		}
+		p, err := infrastructure.NewMetadataProvider(strings.ToLower(name), baseURL, cache)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring metadata providers: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// userName returns name, or the login name of the current user if name is empty.
+func userName(name string) string {
+	if name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// Close releases resources held by the storage backend.
+func (a *App) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// FindBibliography resolves a reference given on the command line, which is either
+// a bibliography UUID or a BibIndex. It returns nil if nothing matches.
+func (a *App) FindBibliography(ref string) (*domain.Bibliography, error) {
+	if id, err := domain.ParseBibliographyID(ref); err == nil {
+		return a.BibService.FindByID(id)
+	}
+	return a.BibService.FindByBibIndex(ref)
+}
+
+// BibliographyDetail gathers everything show reports about bib.
+func (a *App) BibliographyDetail(bib *domain.Bibliography) (bibliographyDetail, error) {
+	detail := bibliographyDetail{Bibliography: bib}
+	var err error
+	if detail.Classification, err = a.BibService.FindClassification(bib); err != nil {
+		return detail, fmt.Errorf("failed to find classification for %s: %w", bib.Code, err)
+	}
+	if detail.Reviews, err = a.ReviewService.ListReviewsByBookID(bib.ID); err != nil {
+		return detail, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	if detail.Reading, err = a.ReadingService.History(bib.ID); err != nil {
+		return detail, fmt.Errorf("failed to find reading status: %w", err)
+	}
+	if detail.Sessions, err = a.ReadingService.Sessions(bib.ID); err != nil {
+		return detail, fmt.Errorf("failed to list reading sessions: %w", err)
+	}
+	if detail.Highlights, err = a.HighlightService.ListHighlightsByBookID(bib.ID); err != nil {
+		return detail, fmt.Errorf("failed to list highlights: %w", err)
+	}
+	return detail, nil
+}
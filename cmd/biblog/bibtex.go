@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bibliography_log/internal/bibtex"
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bibliographiesToBibTeX converts bibliographies to BibTeX entries keyed by BibIndex.
+// Books and articles map to @book and @article; every other type becomes @misc
+// with the original type kept in the "type" field.
+func bibliographiesToBibTeX(bibs []*domain.Bibliography) []bibtex.Entry {
+	entries := make([]bibtex.Entry, 0, len(bibs))
+	for _, bib := range bibs {
+		entry := bibtex.Entry{Key: bib.BibIndex}
+		publisherField := "publisher"
+		switch strings.ToLower(bib.Type) {
+		case "book":
+			entry.Type = "book"
+		case "article":
+			entry.Type = "article"
+			publisherField = "journal"
+		default:
+			entry.Type = "misc"
+		}
+
+		add := func(name, value string) {
+			if value != "" {
+				entry.Fields = append(entry.Fields, bibtex.Field{Name: name, Value: value})
+			}
+		}
+		if len(bib.Contributors) == 0 {
+			add("author", bibtex.Encode(bib.Author))
+		}
+		for _, field := range bibtexNameFields {
+			add(field.name, bibtexNames(bib.Contributors, field.role))
+		}
+		// Extra braces keep bibliography styles from changing the capitalization
+		add("title", "{"+bibtex.Encode(bib.Title)+"}")
+		add(publisherField, bibtex.Encode(bib.Publisher))
+		if !bib.PublishedDate.IsZero() {
+			add("year", strconv.Itoa(bib.PublishedDate.Year()))
+		}
+		add("isbn", bib.ISBN.String())
+		if entry.Type == "misc" {
+			add("type", bibtex.Encode(bib.Type))
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// bibtexNameFields are the name list fields that contributors are exported to and imported
+// from. Other roles have no standard field and only appear in the author line.
+var bibtexNameFields = []struct {
+	name string
+	role domain.ContributorRole
+}{
+	{"author", domain.RoleAuthor},
+	{"editor", domain.RoleEditor},
+	{"translator", domain.RoleTranslator}, // BibLaTeX
+}
+
+// bibtexNames joins the names of the contributors with role as a BibTeX name list.
+func bibtexNames(contributors []domain.Contributor, role domain.ContributorRole) string {
+	var names []string
+	for _, c := range contributors {
+		if c.Role == role {
+			names = append(names, bibtex.Encode(c.Name))
+		}
+	}
+	return strings.Join(names, " and ")
+}
+
+// bibtexTypes maps BibTeX and BibLaTeX entry types to bibliography types.
+// Unlisted entry types become "Misc".
+var bibtexTypes = map[string]string{
+	"book":          "Book",
+	"mvbook":        "Book",
+	"inbook":        "Book",
+	"incollection":  "Book",
+	"collection":    "Book",
+	"booklet":       "Book",
+	"article":       "Article",
+	"inproceedings": "Paper",
+	"conference":    "Paper",
+	"proceedings":   "Paper",
+	"phdthesis":     "Thesis",
+	"mastersthesis": "Thesis",
+	"thesis":        "Thesis",
+	"techreport":    "Report",
+	"report":        "Report",
+	"online":        "Web",
+}
+
+// bibtexToImports converts BibTeX entries to import candidates, assigning
+// classifications from mapping and falling back to defaultClass ("" for none).
+func bibtexToImports(entries []bibtex.Entry, mapping classMapping, defaultClass string) []service.BibliographyImport {
+	items := make([]service.BibliographyImport, 0, len(entries))
+	for _, e := range entries {
+		typ, ok := bibtexTypes[e.Type]
+		if !ok {
+			typ = "Misc"
+		}
+		// @misc entries written by export keep their original type
+		if t := bibtex.Decode(e.Get("type")); e.Type == "misc" && t != "" {
+			typ = t
+		}
+
+		publisher := e.Get("publisher")
+		if publisher == "" {
+			publisher = e.Get("journal")
+		}
+
+		classCode := mapping.classFor(e)
+		if classCode == "" {
+			classCode = defaultClass
+		}
+
+		var contributors []domain.Contributor
+		for _, field := range bibtexNameFields {
+			for _, name := range bibtex.ParseNames(e.Get(field.name)) {
+				contributors = append(contributors, domain.Contributor{Name: name, Role: field.role})
+			}
+		}
+		// Authors alone keep the BibTeX style "A and B"; editors and translators are credited
+		author := strings.Join(bibtex.ParseNames(e.Get("author")), " and ")
+		if len(contributors) > 0 && contributors[len(contributors)-1].Role != domain.RoleAuthor {
+			author = domain.CreditLine(contributors)
+		}
+
+		items = append(items, service.BibliographyImport{
+			Key:           e.Key,
+			Title:         bibtex.Decode(e.Get("title")),
+			Author:        author,
+			Contributors:  contributors,
+			Publisher:     bibtex.Decode(publisher),
+			ISBN:          bibtex.Decode(e.Get("isbn")),
+			Type:          typ,
+			ClassCode:     classCode,
+			PublishedDate: bibtexPublishedDate(e),
+		})
+	}
+	return items
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// bibtexPublishedDate reads the year and month fields, or a BibLaTeX date ("2003-08-30").
+// It returns the zero time if no year is given.
+func bibtexPublishedDate(e bibtex.Entry) time.Time {
+	if date := bibtex.Decode(e.Get("date")); e.Get("year") == "" && date != "" {
+		if t, err := time.Parse(time.DateOnly, date); err == nil {
+			return t
+		}
+		if t, err := time.Parse("2006-01", date); err == nil {
+			return t
+		}
+		if t, err := time.Parse("2006", date); err == nil {
+			return t
+		}
+		return time.Time{}
+	}
+
+	year, err := strconv.Atoi(yearPattern.FindString(bibtex.Decode(e.Get("year"))))
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Date(year, bibtexMonth(bibtex.Decode(e.Get("month"))), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// bibtexMonth parses a month given as a number, name or abbreviation, defaulting to January.
+func bibtexMonth(s string) time.Month {
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= 12 {
+		return time.Month(n)
+	}
+	for m := time.January; m <= time.December; m++ {
+		if len(s) >= 3 && strings.HasPrefix(strings.ToLower(m.String()), strings.ToLower(s)) {
+			return m
+		}
+	}
+	return time.January
+}
+
+// classMapping assigns classification codes to BibTeX entries,
+// keyed by lower-cased citation key or keyword.
+type classMapping map[string]string
+
+// loadClassMapping reads a CSV file of "citation key or keyword,class code" lines.
+// Lines starting with '#' are comments.
+func loadClassMapping(path string) (classMapping, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("Failed to close file: %v", err)
+		}
+	}()
+	return parseClassMapping(file)
+}
+
+func parseClassMapping(r io.Reader) (classMapping, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid class mapping: %w", err)
+	}
+
+	mapping := make(classMapping, len(records))
+	for _, record := range records {
+		code, err := domain.ParseClassCode(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid class code %q for %q in class mapping", record[1], record[0])
+		}
+		mapping[strings.ToLower(strings.TrimSpace(record[0]))] = code.String()
+	}
+	return mapping, nil
+}
+
+// classFor returns the class code for e by citation key, then by the first mapped
+// keyword in the "keywords" field. It returns "" if nothing matches.
+func (m classMapping) classFor(e bibtex.Entry) string {
+	if code, ok := m[strings.ToLower(e.Key)]; ok {
+		return code
+	}
+	keywords := strings.FieldsFunc(bibtex.Decode(e.Get("keywords")), func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+	for _, keyword := range keywords {
+		if code, ok := m[strings.ToLower(strings.TrimSpace(keyword))]; ok {
+			return code
+		}
+	}
+	return ""
+}
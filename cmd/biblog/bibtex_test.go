@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bibliography_log/internal/bibtex"
+	"bibliography_log/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBibTeXRoundTrip(t *testing.T) {
+	bibs := []*domain.Bibliography{
+		{BibIndex: "B56EE03DDD", Type: "Book", Title: "Domain Driven Design", Author: "Eric Evans", Publisher: "Addison-Wesley", ISBN: domain.MustParseISBN("0-321-12521-5"), PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "A56MF05FI", Type: "Article", Title: "Fluent Interface", Author: "Martin Fowler", Publisher: "IEEE Software", PublishedDate: time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "E16MS24MM", Type: "Essay", Title: "R&D 100%", Author: "マシュー スチュワート(稲岡大志訳)", PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "B56EG94DP", Type: "Book", Title: "Design Patterns", Author: "Erich Gamma; Richard Helm; 本位田真一 (translator)", PublishedDate: time.Date(1994, 1, 1, 0, 0, 0, 0, time.UTC),
+			Contributors: []domain.Contributor{
+				{Name: "Erich Gamma", Role: domain.RoleAuthor},
+				{Name: "Richard Helm", Role: domain.RoleAuthor},
+				{Name: "本位田真一", Role: domain.RoleTranslator},
+			}},
+	}
+
+	var b strings.Builder
+	if err := bibtex.Write(&b, bibliographiesToBibTeX(bibs)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{"@book{B56EE03DDD,", "@article{A56MF05FI,", "journal = {IEEE Software}", "@misc{E16MS24MM,", `title  = {{R\&D 100\%}}`, "type   = {Essay}",
+		"= {Erich Gamma and Richard Helm},", "translator = {本位田真一}"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	entries, err := bibtex.Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	items := bibtexToImports(entries, classMapping{}, "56")
+	if len(items) != len(bibs) {
+		t.Fatalf("Expected %d items, got %d", len(bibs), len(items))
+	}
+	for i, item := range items {
+		bib := bibs[i]
+		if item.Key != bib.BibIndex || item.Type != bib.Type || item.Title != bib.Title || item.Author != bib.Author ||
+			item.Publisher != bib.Publisher || domain.MustParseISBN(item.ISBN) != bib.ISBN || !item.PublishedDate.Equal(bib.PublishedDate) {
+			t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", item, bib)
+		}
+		if !reflect.DeepEqual(item.Contributors, bib.Contributors) && len(bib.Contributors) > 0 {
+			t.Errorf("Contributors = %+v, want %+v", item.Contributors, bib.Contributors)
+		}
+		if item.ClassCode != "56" {
+			t.Errorf("Expected default class 56 for %s, got %q", item.Key, item.ClassCode)
+		}
+	}
+}
+
+func TestBibTeXToImports(t *testing.T) {
+	input := `
+@InProceedings{Knuth84,
+  author   = {Knuth, Donald E. and Lamport, Leslie},
+  title    = {Literate {P}rogramming},
+  year     = {1984},
+  month    = may,
+  keywords = {programming; documentation},
+}
+@online{blog, author = {Fowler, Martin}, title = {Bliki}, date = {2019-08-30}}
+@misc{nodate, title = {Undated}}
+@collection{edited, editor = {Helm, Richard}, title = {Edited}}
+`
+	entries, err := bibtex.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	mapping, err := parseClassMapping(strings.NewReader("# key or keyword, class\nDocumentation,16\nblog, 56\n"))
+	if err != nil {
+		t.Fatalf("parseClassMapping failed: %v", err)
+	}
+
+	items := bibtexToImports(entries, mapping, "")
+	if got := items[0]; got.Type != "Paper" || got.Author != "Donald E. Knuth and Leslie Lamport" ||
+		got.Title != "Literate Programming" || got.ClassCode != "16" ||
+		!got.PublishedDate.Equal(time.Date(1984, time.May, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected inproceedings import: %+v", got)
+	}
+	if got := items[1]; got.Type != "Web" || got.ClassCode != "56" ||
+		!got.PublishedDate.Equal(time.Date(2019, time.August, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected online import: %+v", got)
+	}
+	if got := items[2]; got.Type != "Misc" || got.ClassCode != "" || !got.PublishedDate.IsZero() {
+		t.Errorf("Unexpected misc import: %+v", got)
+	}
+	if got := items[3]; got.Author != "Richard Helm (editor)" || len(got.Contributors) != 1 || got.Contributors[0].Role != domain.RoleEditor {
+		t.Errorf("Unexpected collection import: %+v", got)
+	}
+}
+
+func TestParseClassMapping_Invalid(t *testing.T) {
+	if _, err := parseClassMapping(strings.NewReader("key,notanumber\n")); err == nil {
+		t.Error("Expected error for non-numeric class code, got nil")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,10 +47,10 @@ func promptString(label string, required bool) string {
 				}
 				// If EOF with no input and required, exit with error
 				fmt.Printf("\nError: required input for '%s' not provided before EOF\n", label)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Printf("\nError reading input: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		input = strings.TrimSpace(input)
 		if input != "" {
@@ -87,10 +88,10 @@ func promptInt(label string, required bool) int {
 				}
 				// If required and EOF with no valid input, exit with error
 				fmt.Printf("\nError: required integer input for '%s' but reached end of input.\n", label)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Printf("\nError reading input: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		input = strings.TrimSpace(input)
 		if input == "" && !required {
@@ -103,3 +104,39 @@ func promptInt(label string, required bool) int {
 		fmt.Println("Invalid number, please try again.")
 	}
 }
+
+// promptConfirm asks a yes/no question and returns true only for an explicit yes.
+// EOF or an empty answer counts as no.
+func promptConfirm(label string) bool {
+	fmt.Printf("%s [y/N]: ", label)
+	input, err := getReader().ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Printf("\nError reading input: %v\n", err)
+		exit(1)
+	}
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// runEditor opens file in the user's editor, $VISUAL or $EDITOR, falling back to vi, and
+// waits for it to exit. The variables may hold arguments, as in "code --wait".
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	cmd := exec.Command(args[0], append(args[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+	return nil
+}
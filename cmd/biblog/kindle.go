@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/kindle"
+	"bibliography_log/internal/service"
+	"fmt"
+)
+
+// kindleNoteTag marks highlights imported from notes that were not written on a highlight.
+const kindleNoteTag = "note"
+
+// kindleBook is the clippings of one book, in file order.
+type kindleBook struct {
+	Title     string
+	Author    string
+	Clippings []kindle.Clipping
+}
+
+// kindleImport is the outcome of importing one book's clippings.
+type kindleImport struct {
+	Book         kindleBook
+	Bibliography *domain.Bibliography // nil if the book was skipped
+	SkipReason   string
+	Results      []service.HighlightImportResult
+}
+
+// groupClippings groups clippings by book in the order the books first appear.
+func groupClippings(clippings []kindle.Clipping) []kindleBook {
+	var books []kindleBook
+	index := make(map[[2]string]int)
+	for _, c := range clippings {
+		key := [2]string{c.Title, c.Author}
+		i, ok := index[key]
+		if !ok {
+			i = len(books)
+			index[key] = i
+			books = append(books, kindleBook{Title: c.Title, Author: c.Author})
+		}
+		books[i].Clippings = append(books[i].Clippings, c)
+	}
+	return books
+}
+
+// clippingsToImports converts highlights and notes to highlight imports. A note attached to
+// a highlight becomes its comment; a note on its own is imported as a highlight tagged "note".
+func clippingsToImports(clippings []kindle.Clipping) []service.HighlightImport {
+	items := make([]service.HighlightImport, 0, len(clippings))
+	for _, c := range clippings {
+		item := service.HighlightImport{
+			Text:      c.Text,
+			Location:  c.Label(),
+			Comment:   c.Note,
+			CreatedAt: c.AddedAt,
+		}
+		if c.Kind == kindle.KindNote {
+			item.Tags = []string{kindleNoteTag}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// resolveKindleBook finds the bibliography a book's clippings belong to. A single match by
+// title and author is used directly. Otherwise the user is asked for a BibIndex or UUID, or
+// to create a new bibliography; without prompts (structured output) the book is skipped.
+// The returned string is the reason for skipping when the bibliography is nil.
+func resolveKindleBook(app *App, out *Output, book kindleBook, dryRun bool) (*domain.Bibliography, string, error) {
+	candidates, err := app.BibService.MatchBibliographies(book.Title, book.Author)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(candidates) == 1 {
+		return candidates[0], "", nil
+	}
+	reason := "no matching bibliography"
+	if len(candidates) > 1 {
+		reason = fmt.Sprintf("%d bibliographies match", len(candidates))
+	}
+	if out.Structured() {
+		return nil, reason, nil
+	}
+
+	fmt.Fprintf(out.W, "\n%s (%s): %d clipping(s), %s\n", book.Title, book.Author, len(book.Clippings), reason)
+	for _, bib := range candidates {
+		fmt.Fprintf(out.W, "  %s  %s by %s\n", bib.BibIndex, bib.Title, bib.Author)
+	}
+	label := "BibIndex or UUID to import into, 'new' to create a bibliography, or empty to skip"
+	if dryRun {
+		label = "BibIndex or UUID to import into, or empty to skip"
+	}
+	for {
+		ref := promptString(label, false)
+		switch {
+		case ref == "":
+			return nil, "skipped by user", nil
+		case ref == "new" && !dryRun:
+			bib, err := addKindleBibliography(app, book)
+			if err != nil {
+				fmt.Fprintf(out.W, "Error adding bibliography: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(out.W, "Bibliography added: %v\n", bib)
+			return bib, "", nil
+		}
+		bib, err := app.FindBibliography(ref)
+		if err != nil {
+			return nil, "", err
+		}
+		if bib != nil {
+			return bib, "", nil
+		}
+		fmt.Fprintf(out.W, "Bibliography %s not found\n", ref)
+	}
+}
+
+// addKindleBibliography creates a bibliography for a book, prompting for the fields the
+// clippings file does not give.
+func addKindleBibliography(app *App, book kindleBook) (*domain.Bibliography, error) {
+	req := &AddBibliographyRequest{Title: book.Title, Author: book.Author, Type: "Book"}
+	req.PromptMissing()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return app.BibService.AddBibliography(req.Title, req.Author, req.ToContributors(), req.Publisher, req.ISBN, req.Type,
+		req.ClassCode, req.ToPublishedDate(), req.TitleEn, req.AuthorEn, req.BibIndex)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/kindle"
+	"bibliography_log/internal/service"
+	"strings"
+	"testing"
+)
+
+func TestClippingsToImports(t *testing.T) {
+	clippings := []kindle.Clipping{
+		{Title: "A", Author: "X", Kind: kindle.KindHighlight, Page: "3", Location: "40-42", Text: "first", Note: "my note"},
+		{Title: "B", Kind: kindle.KindHighlight, Location: "7", Text: "other"},
+		{Title: "A", Author: "X", Kind: kindle.KindNote, Location: "90", Text: "standalone"},
+	}
+
+	books := groupClippings(clippings)
+	if len(books) != 2 || books[0].Title != "A" || len(books[0].Clippings) != 2 || books[1].Title != "B" {
+		t.Fatalf("Unexpected grouping: %+v", books)
+	}
+
+	items := clippingsToImports(books[0].Clippings)
+	if got := items[0]; got.Location != "p. 3, loc. 40-42" || got.Comment != "my note" || len(got.Tags) != 0 {
+		t.Errorf("Unexpected highlight import: %+v", got)
+	}
+	if got := items[1]; got.Text != "standalone" || len(got.Tags) != 1 || got.Tags[0] != kindleNoteTag {
+		t.Errorf("Unexpected note import: %+v", got)
+	}
+}
+
+func TestRenderKindleImport(t *testing.T) {
+	bib := &domain.Bibliography{BibIndex: "B56EE03DDD", Title: "Domain Driven Design"}
+	imports := []kindleImport{
+		{
+			Book:         kindleBook{Title: "Domain-Driven Design", Clippings: make([]kindle.Clipping, 3)},
+			Bibliography: bib,
+			Results: []service.HighlightImportResult{
+				{Highlight: &domain.Highlight{}},
+				{SkipReason: "already imported"},
+				{SkipReason: "already imported"},
+			},
+		},
+		{Book: kindleBook{Title: "Unknown", Author: "Nobody", Clippings: make([]kindle.Clipping, 2)}, SkipReason: "no matching bibliography"},
+	}
+
+	var b strings.Builder
+	renderKindleImport(&b, imports, true)
+	want := "Would import 1 highlight(s) into B56EE03DDD (Domain Driven Design); skipped 2 already imported\n" +
+		"Skipped Unknown (Nobody): 2 clipping(s), no matching bibliography\n" +
+		"Would import: 1, skipped: 4\n"
+	if b.String() != want {
+		t.Errorf("renderKindleImport() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
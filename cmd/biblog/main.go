@@ -1,42 +1,167 @@
 package main
 
 import (
+	"bibliography_log/internal/bibtex"
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/kindle"
+	"bibliography_log/internal/ndc"
+	"bibliography_log/internal/service"
+	"bibliography_log/internal/textdiff"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
+	"net"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 )
 
+const usageMessage = "expected 'add-class', 'list-class', 'seed-class', 'rename-class', 'renumber-class', 'delete-class', 'add-bib', 'update-bib', 'delete-bib', 'add-review', 'update-review', 'review-history', 'diff-review', 'revert-review', 'edit-review', 'list', 'show', 'queue', 'start', 'finish', 'abandon', 'log-session', 'add-quote', 'list-quotes', 'search', 'reindex', 'check-indexes', 'migrate-isbn', 'export', 'export-notes', 'sync-notes', 'import', 'import-kindle', 'serve' or 'site' subcommands"
+
+// contributorUsage documents the repeatable -contributor flag.
+const contributorUsage = "Contributor as [role:]Name[=English name], repeatable (roles: author, editor, translator, illustrator, speaker, host)"
+
 func main() {
-	app, err := NewApp()
+	os.Exit(run())
+}
+
+// run executes the command given on the command line and returns the exit status.
+func run() (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			c, ok := r.(exitCode)
+			if !ok {
+				panic(r)
+			}
+			code = int(c)
+		}
+	}()
+
+	// Global Flags (must precede the subcommand)
+	cfg := Config{}
+	flag.StringVar(&cfg.DataDir, "data-dir", "data", "Directory holding the data files")
+	flag.StringVar(&cfg.Backend, "backend", envOrDefault("BIBLOG_BACKEND", BackendCSV), "Storage backend (csv or sqlite); defaults to $BIBLOG_BACKEND")
+	flag.StringVar(&cfg.ReadingDictPath, "reading-dict", os.Getenv("BIBLOG_READING_DICT"), "Kanji reading dictionary (SKK, or IPADIC if .csv) used to romanize Japanese; defaults to $BIBLOG_READING_DICT")
+	flag.StringVar(&cfg.ISBNRangesPath, "isbn-ranges", os.Getenv("BIBLOG_ISBN_RANGES"), "ISBN range table (RangeMessage.xml from isbn-international.org) used to hyphenate ISBNs; defaults to $BIBLOG_ISBN_RANGES")
+	flag.StringVar(&cfg.MetadataProviders, "metadata-providers", envOrDefault("BIBLOG_METADATA_PROVIDERS", DefaultMetadataProviders), "Catalogues used by 'add-bib -lookup', in order (openbd, googlebooks, openlibrary; name=URL overrides the base URL); defaults to $BIBLOG_METADATA_PROVIDERS")
+	flag.StringVar(&cfg.User, "user", os.Getenv("BIBLOG_USER"), "Name recorded in review revisions; defaults to $BIBLOG_USER, then the login name")
+	outputFlag := flag.String("output", envOrDefault("BIBLOG_OUTPUT", string(OutputText)), "Output format (text, json, jsonl, csv or tsv); defaults to $BIBLOG_OUTPUT")
+	flag.Parse()
+	args := flag.Args()
+
+	format, err := ParseOutputFormat(*outputFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	out := NewOutput(format)
+
+	app, err := NewApp(cfg)
 	if err != nil {
-		fmt.Printf("Error initializing application: %v\n", err)
-		os.Exit(1)
+		out.Fail(errFailed, "Error initializing application: %v", err)
 	}
+	defer func() {
+		if err := app.Close(); err != nil {
+			log.Printf("Failed to close application: %v", err)
+		}
+	}()
 
 	// Subcommands
-	addClassCmd := flag.NewFlagSet("add-class", flag.ExitOnError)
-	addBibCmd := flag.NewFlagSet("add-bib", flag.ExitOnError)
-	addReviewCmd := flag.NewFlagSet("add-review", flag.ExitOnError)
-	updateReviewCmd := flag.NewFlagSet("update-review", flag.ExitOnError)
-	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+	addClassCmd := newFlagSet("add-class")
+	listClassCmd := newFlagSet("list-class")
+	seedClassCmd := newFlagSet("seed-class")
+	renameClassCmd := newFlagSet("rename-class")
+	renumberClassCmd := newFlagSet("renumber-class")
+	deleteClassCmd := newFlagSet("delete-class")
+	addBibCmd := newFlagSet("add-bib")
+	addReviewCmd := newFlagSet("add-review")
+	updateReviewCmd := newFlagSet("update-review")
+	reviewHistoryCmd := newFlagSet("review-history")
+	diffReviewCmd := newFlagSet("diff-review")
+	revertReviewCmd := newFlagSet("revert-review")
+	editReviewCmd := newFlagSet("edit-review")
+	listCmd := newFlagSet("list")
+	showCmd := newFlagSet("show")
+	updateBibCmd := newFlagSet("update-bib")
+	deleteBibCmd := newFlagSet("delete-bib")
+	checkIndexesCmd := newFlagSet("check-indexes")
+	migrateISBNCmd := newFlagSet("migrate-isbn")
+	exportCmd := newFlagSet("export")
+	exportNotesCmd := newFlagSet("export-notes")
+	syncNotesCmd := newFlagSet("sync-notes")
+	importCmd := newFlagSet("import")
+	importKindleCmd := newFlagSet("import-kindle")
+	logSessionCmd := newFlagSet("log-session")
+	addQuoteCmd := newFlagSet("add-quote")
+	listQuotesCmd := newFlagSet("list-quotes")
+	searchCmd := newFlagSet("search")
+	reindexCmd := newFlagSet("reindex")
+	serveCmd := newFlagSet("serve")
+	siteBuildCmd := newFlagSet("site build")
+	siteTemplatesCmd := newFlagSet("site templates")
+	statusCmds := map[string]*flag.FlagSet{
+		"queue":   newFlagSet("queue"),
+		"start":   newFlagSet("start"),
+		"finish":  newFlagSet("finish"),
+		"abandon": newFlagSet("abandon"),
+	}
 
 	// Add Class Flags
 	addClassReq := &AddClassificationRequest{}
-	addClassCmd.IntVar(&addClassReq.Code, "code", 0, "Classification Code Number (e.g. 56)")
+	addClassCmd.StringVar(&addClassReq.Code, "code", "", "Classification code (e.g. 5, 56 or 547.48); subclasses extend their parent's code")
 	addClassCmd.StringVar(&addClassReq.Name, "name", "", "Classification Name (e.g. Technology)")
 
+	// List Class Flags
+	listClassReq := &ListClassificationsRequest{}
+	listClassCmd.BoolVar(&listClassReq.Tree, "tree", false, "Show subclasses indented under their parents")
+
+	// Seed Class Flags
+	seedClassReq := &SeedClassificationsRequest{}
+	seedClassCmd.StringVar(&seedClassReq.Scheme, "scheme", "ndc", "Classification scheme to add (ndc: Nippon Decimal Classification)")
+	seedClassCmd.IntVar(&seedClassReq.Depth, "depth", 2, "1 for the main classes only, 2 to include the divisions")
+	seedClassCmd.BoolVar(&seedClassReq.DryRun, "dry-run", false, "Report what would be added without saving anything")
+
+	// Rename Class Flags
+	renameClassReq := &RenameClassificationRequest{}
+	renameClassCmd.StringVar(&renameClassReq.Code, "code", "", "Code of the classification to rename")
+	renameClassCmd.StringVar(&renameClassReq.Name, "name", "", "New name")
+
+	// Renumber Class Flags
+	renumberClassReq := &RenumberClassificationRequest{}
+	renumberClassCmd.StringVar(&renumberClassReq.Code, "code", "", "Current code of the classification")
+	renumberClassCmd.StringVar(&renumberClassReq.To, "to", "", "New code, which must not be in use")
+	renumberClassCmd.BoolVar(&renumberClassReq.RewriteBibIndex, "bib-index", false, "Also replace the old Code at the start of BibIndexes (B56EE03DDD -> B547EE03DDD)")
+	renumberClassCmd.BoolVar(&renumberClassReq.DryRun, "dry-run", false, "Show the bibliographies that would change without saving anything")
+	renumberClassCmd.BoolVar(&renumberClassReq.Yes, "yes", false, "Do not ask for confirmation")
+
+	// Delete Class Flags
+	deleteClassReq := &DeleteClassificationRequest{}
+	deleteClassCmd.StringVar(&deleteClassReq.Code, "code", "", "Code of the classification to delete")
+	deleteClassCmd.StringVar(&deleteClassReq.ReassignTo, "reassign-to", "", "Code of the classification to move its bibliographies to (required if it is used)")
+	deleteClassCmd.BoolVar(&deleteClassReq.RewriteBibIndex, "bib-index", false, "With -reassign-to, also replace the old Code at the start of BibIndexes")
+	deleteClassCmd.BoolVar(&deleteClassReq.DryRun, "dry-run", false, "Show the bibliographies that would be moved without saving anything")
+	deleteClassCmd.BoolVar(&deleteClassReq.Yes, "yes", false, "Do not ask for confirmation")
+
 	// Add Bib Flags
 	addBibReq := &AddBibliographyRequest{}
 	addBibCmd.StringVar(&addBibReq.Title, "title", "", "Title of the bibliography")
-	addBibCmd.StringVar(&addBibReq.Author, "author", "", "Author of the bibliography")
+	addBibCmd.StringVar(&addBibReq.Author, "author", "", "Author of the bibliography as credited (e.g. \"Gamma; Helm (ed.)\"); contributors are read from it unless -contributor is given")
+	addBibCmd.Func("contributor", contributorUsage, appendTo(&addBibReq.Contributors))
 	addBibCmd.StringVar(&addBibReq.Publisher, "publisher", "", "Publisher of the bibliography")
 	addBibCmd.StringVar(&addBibReq.Type, "type", "", "Type (Book, Essay, Video, etc.)")
-	addBibCmd.IntVar(&addBibReq.ClassCode, "class", 0, "Classification Code Number")
+	addBibCmd.StringVar(&addBibReq.ClassCode, "class", "", "Classification code (e.g. 56 or 547.48)")
 	addBibCmd.IntVar(&addBibReq.Year, "year", 0, "Published Year (e.g. 2024)")
 	addBibCmd.StringVar(&addBibReq.ISBN, "isbn", "", "ISBN")
-	addBibCmd.StringVar(&addBibReq.TitleEn, "title-en", "", "English translation of title (required if title contains Japanese)")
-	addBibCmd.StringVar(&addBibReq.AuthorEn, "author-en", "", "English translation of author (required if author contains Japanese)")
-	addBibCmd.StringVar(&addBibReq.BibIndex, "bib-index", "", "Manual BibIndex (overrides auto-generation and bypasses English translation requirements)")
+	addBibCmd.StringVar(&addBibReq.TitleEn, "title-en", "", "English translation of title (overrides automatic romanization of Japanese)")
+	addBibCmd.StringVar(&addBibReq.AuthorEn, "author-en", "", "English translation of author (overrides automatic romanization of Japanese)")
+	addBibCmd.StringVar(&addBibReq.BibIndex, "bib-index", "", "Manual BibIndex (overrides auto-generation)")
+	addBibCmd.BoolVar(&addBibReq.Lookup, "lookup", false, "Look up the ISBN in online catalogues and prefill the fields not given")
 
 	// Add Review Flags
 	addReviewReq := &AddReviewRequest{}
@@ -50,45 +175,317 @@ func main() {
 	updateReviewCmd.StringVar(&updateReviewReq.Goals, "goals", "", "New goals for reading (optional)")
 	updateReviewCmd.StringVar(&updateReviewReq.Summary, "summary", "", "New summary of the review (optional)")
 
+	// Review History takes the review UUID as a positional argument
+	reviewHistoryReq := &ReviewHistoryRequest{}
+
+	// Diff Review Flags (review UUID is positional)
+	diffReviewReq := &DiffReviewRequest{}
+	diffReviewCmd.IntVar(&diffReviewReq.From, "from", 0, "Older revision (default: the one before -to)")
+	diffReviewCmd.IntVar(&diffReviewReq.To, "to", 0, "Newer revision (default: the latest)")
+
+	// Revert Review Flags (review UUID is positional)
+	revertReviewReq := &RevertReviewRequest{}
+	revertReviewCmd.IntVar(&revertReviewReq.To, "to", 0, "Revision to restore (required; see review-history)")
+	revertReviewCmd.BoolVar(&revertReviewReq.Yes, "yes", false, "Do not ask for confirmation")
+
+	// Edit Review Flags
+	editReviewReq := &EditReviewRequest{}
+	editReviewCmd.BoolVar(&editReviewReq.Yes, "yes", false, "Save the edits without asking for confirmation")
+
 	// List Flags
 	listReq := &ListBibliographiesRequest{}
 	listCmd.IntVar(&listReq.Limit, "limit", 100, "Maximum number of items to display (default: 100, 0 for all)")
 	listCmd.IntVar(&listReq.Offset, "offset", 0, "Number of items to skip (default: 0)")
+	listCmd.StringVar(&listReq.Type, "type", "", "Only this type (e.g. Book)")
+	listCmd.StringVar(&listReq.ClassCode, "class", "", "Only this classification code")
+	listCmd.BoolVar(&listReq.Recursive, "recursive", false, "With -class, also include its subclasses (e.g. 547 for -class 5)")
+	listCmd.IntVar(&listReq.YearFrom, "year-from", 0, "Only published in or after this year")
+	listCmd.IntVar(&listReq.YearTo, "year-to", 0, "Only published in or before this year")
+	listCmd.StringVar(&listReq.Author, "author", "", "Only authors containing this text (case-insensitive)")
+	listCmd.StringVar(&listReq.Publisher, "publisher", "", "Only publishers containing this text (case-insensitive)")
+	listCmd.StringVar(&listReq.HasReview, "has-review", "", "yes: only reviewed, no: only unreviewed")
+	listCmd.StringVar(&listReq.HasISBN, "has-isbn", "", "yes: only with an ISBN, no: only without")
+	listCmd.StringVar(&listReq.Status, "status", "", "Only this reading status (none, wishlist, to-read, reading, finished or abandoned)")
+	listCmd.StringVar(&listReq.Sort, "sort", "insertion", "Sort by insertion, title, author, year or bibindex")
+	listCmd.BoolVar(&listReq.Desc, "desc", false, "Sort in descending order")
+
+	// Show takes the BibIndex or UUID as a positional argument
+	showReq := &ShowBibliographyRequest{}
+
+	// Update Bib Flags (BibIndex or UUID is positional)
+	updateBibReq := &UpdateBibliographyRequest{}
+	updateBibCmd.StringVar(&updateBibReq.Title, "title", "", "New title")
+	updateBibCmd.StringVar(&updateBibReq.Author, "author", "", "New author")
+	updateBibCmd.Func("contributor", contributorUsage+"; replaces all contributors", appendTo(&updateBibReq.Contributors))
+	updateBibCmd.StringVar(&updateBibReq.Publisher, "publisher", "", "New publisher")
+	updateBibCmd.StringVar(&updateBibReq.Type, "type", "", "New type (Book, Essay, Video, etc.)")
+	updateBibCmd.StringVar(&updateBibReq.ClassCode, "class", "", "New classification code")
+	updateBibCmd.IntVar(&updateBibReq.Year, "year", 0, "New published year")
+	updateBibCmd.StringVar(&updateBibReq.ISBN, "isbn", "", "New ISBN")
+	updateBibCmd.StringVar(&updateBibReq.TitleEn, "title-en", "", "English translation of title (used with -regen-index)")
+	updateBibCmd.StringVar(&updateBibReq.AuthorEn, "author-en", "", "English translation of author (used with -regen-index)")
+	updateBibCmd.StringVar(&updateBibReq.BibIndex, "bib-index", "", "Set a new BibIndex manually")
+	updateBibCmd.BoolVar(&updateBibReq.RegenerateID, "regen-index", false, "Regenerate the BibIndex when author, title, year, type or classification change")
 
-	if len(os.Args) < 2 {
-		fmt.Println("expected 'add-class', 'add-bib', 'add-review', 'update-review' or 'list' subcommands")
-		os.Exit(1)
+	// Delete Bib Flags (BibIndex or UUID is positional)
+	deleteBibReq := &DeleteBibliographyRequest{}
+	deleteBibCmd.StringVar(&deleteBibReq.Reviews, "reviews", "refuse", "What to do with existing reviews: refuse, cascade (delete them) or orphan (keep them; CSV backend only)")
+	deleteBibCmd.BoolVar(&deleteBibReq.Yes, "yes", false, "Do not ask for confirmation")
+
+	// Reading Status Flags (BibIndex or UUID is positional)
+	changeStatusReq := &ChangeStatusRequest{}
+	for _, fs := range statusCmds {
+		fs.StringVar(&changeStatusReq.At, "at", "", "When the status changed, as YYYY-MM-DD or RFC3339 (default: now)")
 	}
+	statusCmds["queue"].BoolVar(&changeStatusReq.Wishlist, "wishlist", false, "Put it on the wishlist instead of the to-read list")
+
+	// Log Session Flags (BibIndex or UUID is positional)
+	logSessionReq := &LogSessionRequest{}
+	logSessionCmd.StringVar(&logSessionReq.Date, "date", "", "Day of the session as YYYY-MM-DD (default: today)")
+	logSessionCmd.StringVar(&logSessionReq.Duration, "duration", "", "Time spent reading (e.g. 45m or 1h30m)")
+	logSessionCmd.IntVar(&logSessionReq.From, "from", service.ContinueFromLast, "Page (or percent) where the session started (default: where the last session ended)")
+	logSessionCmd.IntVar(&logSessionReq.To, "to", service.TimeOnly, "Page (or percent) reached")
+	logSessionCmd.BoolVar(&logSessionReq.Percent, "percent", false, "-from and -to are percentages (e.g. for e-books)")
+	logSessionCmd.IntVar(&logSessionReq.Total, "total", 0, "Page count of the book (remembered from earlier sessions)")
+	logSessionCmd.StringVar(&logSessionReq.Note, "note", "", "Note about the session")
+
+	// Add Quote Flags (BibIndex or UUID is positional)
+	addQuoteReq := &AddQuoteRequest{}
+	addQuoteCmd.StringVar(&addQuoteReq.Text, "text", "", "Quoted text (required; line breaks are kept)")
+	addQuoteCmd.StringVar(&addQuoteReq.Location, "location", "", "Page or e-book location (e.g. \"p. 42\")")
+	addQuoteCmd.StringVar(&addQuoteReq.Comment, "comment", "", "Your comment on the quote")
+	addQuoteCmd.StringVar(&addQuoteReq.Tags, "tags", "", "Comma-separated tags (e.g. ddd,modeling)")
+
+	// List Quotes Flags (optional BibIndex or UUID is positional)
+	listQuotesReq := &ListQuotesRequest{}
+	listQuotesCmd.StringVar(&listQuotesReq.Tag, "tag", "", "Only highlights with this tag")
+	listQuotesCmd.IntVar(&listQuotesReq.Limit, "limit", 100, "Maximum number of highlights to display (0 for all)")
+	listQuotesCmd.IntVar(&listQuotesReq.Offset, "offset", 0, "Number of highlights to skip")
+
+	// Migrate ISBN Flags
+	migrateISBNReq := &MigrateISBNRequest{}
+	migrateISBNCmd.BoolVar(&migrateISBNReq.DryRun, "dry-run", false, "Report what would change without saving anything")
+	migrateISBNCmd.BoolVar(&migrateISBNReq.ClearInvalid, "clear-invalid", false, "Remove ISBNs that are not valid instead of leaving them unchanged")
+
+	// Export Flags
+	exportReq := &ExportRequest{}
+	exportCmd.StringVar(&exportReq.Format, "format", "bibtex", "Export format (bibtex)")
+	exportCmd.StringVar(&exportReq.Out, "out", "", "File to write to (default: standard output)")
+
+	// Import Flags (file is positional)
+	importReq := &ImportRequest{}
+	importCmd.StringVar(&importReq.Format, "format", "bibtex", "Import format (bibtex)")
+	importCmd.StringVar(&importReq.ClassCode, "class", "", "Classification code for entries not matched by -class-map")
+	importCmd.StringVar(&importReq.ClassMap, "class-map", "", "CSV file mapping citation keys or keywords to classification code numbers")
+	importCmd.BoolVar(&importReq.DryRun, "dry-run", false, "Report what would be imported without saving anything")
 
-	switch os.Args[1] {
+	// Export Notes Flags
+	exportNotesReq := &ExportNotesRequest{}
+	exportNotesCmd.StringVar(&exportNotesReq.Vault, "vault", "", "Directory to write the notes to, e.g. a folder of an Obsidian vault (required)")
+	exportNotesCmd.BoolVar(&exportNotesReq.Force, "force", false, "Overwrite reviews edited in the notes that were not synced back")
+	exportNotesCmd.BoolVar(&exportNotesReq.DryRun, "dry-run", false, "Report which notes would be written without writing them")
+
+	// Sync Notes Flags
+	syncNotesReq := &SyncNotesRequest{}
+	syncNotesCmd.StringVar(&syncNotesReq.Vault, "vault", "", "Directory the notes were exported to (required)")
+	syncNotesCmd.BoolVar(&syncNotesReq.Force, "force", false, "Push reviews edited in both places, keeping the note's text")
+	syncNotesCmd.BoolVar(&syncNotesReq.DryRun, "dry-run", false, "Report which reviews would be pushed without updating them")
+
+	// Import Kindle Flags (file is positional)
+	importKindleReq := &ImportKindleRequest{}
+	importKindleCmd.BoolVar(&importKindleReq.DryRun, "dry-run", false, "Report what would be imported without saving anything")
+
+	// Search Flags (query is positional)
+	searchReq := &SearchRequest{}
+	searchCmd.IntVar(&searchReq.Limit, "limit", 20, "Maximum number of results (0 for all)")
+
+	// Serve Flags
+	serveReq := &ServeRequest{}
+	serveCmd.StringVar(&serveReq.Addr, "addr", "localhost:8080", "Address to listen on (e.g. :8080 to accept connections from other devices)")
+
+	// Site Flags
+	siteBuildReq := &SiteBuildRequest{}
+	siteBuildCmd.StringVar(&siteBuildReq.Out, "out", "public", "Directory to write the site to")
+	siteBuildCmd.StringVar(&siteBuildReq.Templates, "templates", "", "Directory with templates replacing the built-in ones of the same name (see 'site templates')")
+	siteBuildCmd.StringVar(&siteBuildReq.BaseURL, "base-url", "", "URL the site is published at (e.g. https://example.github.io/reading/), used for links in the feed")
+	siteBuildCmd.StringVar(&siteBuildReq.Title, "title", "Reading Log", "Title of the site and the feed")
+	siteTemplatesReq := &SiteTemplatesRequest{}
+	siteTemplatesCmd.StringVar(&siteTemplatesReq.Out, "out", "templates", "Directory to copy the built-in templates to")
+
+	if len(args) < 1 {
+		out.Fail(errUsage, "%s", usageMessage)
+	}
+	out.Command = args[0]
+
+	switch args[0] {
 	case "add-class":
-		_ = addClassCmd.Parse(os.Args[2:])
-		addClassReq.PromptMissing()
+		parseFlags(out, addClassCmd, args[1:])
+		if !out.Structured() {
+			addClassReq.PromptMissing()
+		}
 		if err := addClassReq.Validate(); err != nil {
-			fmt.Printf("Validation error: %v\n", err)
-			addClassCmd.PrintDefaults()
-			os.Exit(1)
+			out.Invalid(addClassCmd, err)
 		}
 
 		class, err := app.BibService.AddClassification(addClassReq.Code, addClassReq.Name)
 		if err != nil {
-			fmt.Printf("Error adding classification: %v\n", err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error adding classification: %v", err)
 		}
-		fmt.Printf("Classification added: %v\n", class)
+		render(out, emit(out, newClassificationView(class), func(w io.Writer) {
+			fmt.Fprintf(w, "Classification added: %v\n", class)
+		}))
+
+	case "list-class":
+		parseFlags(out, listClassCmd, args[1:])
+
+		roots, err := app.BibService.ClassificationTree()
+		if err != nil {
+			out.Fail(errFailed, "Error listing classifications: %v", err)
+		}
+		render(out, emitList(out, newClassNodeViews(roots), func(w io.Writer) {
+			if listClassReq.Tree {
+				renderClassTree(w, roots)
+			} else {
+				renderClassList(w, roots)
+			}
+		}))
+
+	case "seed-class":
+		parseFlags(out, seedClassCmd, args[1:])
+		if err := seedClassReq.Validate(); err != nil {
+			out.Invalid(seedClassCmd, err)
+		}
+
+		var items []service.ClassificationImport
+		for _, c := range ndc.Classes(seedClassReq.Depth) {
+			items = append(items, service.ClassificationImport{Code: c.Code, Name: c.Name})
+		}
+		results, err := app.BibService.ImportClassifications(items, seedClassReq.DryRun)
+		render(out, emitList(out, newClassificationImportResultViews(results, seedClassReq.DryRun), func(w io.Writer) {
+			renderClassificationImport(w, results, seedClassReq.DryRun)
+		}))
+		if err != nil {
+			out.Fail(errFailed, "Error adding classifications: %v", err)
+		}
+
+	case "rename-class":
+		parseFlags(out, renameClassCmd, args[1:])
+		if !out.Structured() {
+			renameClassReq.PromptMissing()
+		}
+		if err := renameClassReq.Validate(); err != nil {
+			out.Invalid(renameClassCmd, err)
+		}
+
+		class, err := app.BibService.RenameClassification(renameClassReq.Code, renameClassReq.Name)
+		if err != nil {
+			out.Fail(errFailed, "Error renaming classification: %v", err)
+		}
+		render(out, emit(out, newClassificationView(class), func(w io.Writer) {
+			fmt.Fprintf(w, "Classification renamed: %s %s\n", class.Code, class.Name)
+		}))
+
+	case "renumber-class":
+		parseFlags(out, renumberClassCmd, args[1:])
+		if !out.Structured() {
+			renumberClassReq.PromptMissing()
+		}
+		if err := renumberClassReq.Validate(); err != nil {
+			out.Invalid(renumberClassCmd, err)
+		}
+		req := renumberClassReq
+
+		renumbered, moves, err := app.BibService.RenumberClassification(req.Code, req.To, req.RewriteBibIndex, true)
+		if err != nil {
+			out.Fail(errFailed, "Error renumbering classification: %v", err)
+		}
+		if !req.DryRun && !req.Yes {
+			if out.Structured() {
+				out.Fail(errUsage, "Refusing to renumber %s without confirmation; pass -yes or -dry-run", req.Code)
+			}
+			fmt.Printf("%d bibliographies will change:\n", len(moves))
+			renderReclassifications(os.Stdout, moves)
+			if !promptConfirm(fmt.Sprintf("Renumber %s %s to %s?", req.Code, renumbered.Name, renumbered.Code)) {
+				fmt.Println("Aborted")
+				exit(1)
+			}
+		}
+		if !req.DryRun {
+			renumbered, moves, err = app.BibService.RenumberClassification(req.Code, req.To, req.RewriteBibIndex, false)
+			if err != nil {
+				out.Fail(errFailed, "Error renumbering classification: %v", err)
+			}
+		}
+		render(out, emit(out, newClassificationChangeView(renumbered, moves, req.DryRun), func(w io.Writer) {
+			if req.DryRun {
+				fmt.Fprintf(w, "Would renumber %s %s to %s and change %d bibliographies:\n", req.Code, renumbered.Name, renumbered.Code, len(moves))
+				renderReclassifications(w, moves)
+				return
+			}
+			fmt.Fprintf(w, "Classification renumbered: %s %s (was %s); changed %d bibliographies\n", renumbered.Code, renumbered.Name, req.Code, len(moves))
+		}))
+
+	case "delete-class":
+		parseFlags(out, deleteClassCmd, args[1:])
+		if !out.Structured() {
+			deleteClassReq.PromptMissing()
+		}
+		if err := deleteClassReq.Validate(); err != nil {
+			out.Invalid(deleteClassCmd, err)
+		}
+		req := deleteClassReq
+
+		class, moves, err := app.BibService.DeleteClassification(req.Code, req.ReassignTo, req.RewriteBibIndex, true)
+		if err != nil {
+			out.Fail(errFailed, "Error deleting classification: %v", err)
+		}
+		if !req.DryRun && !req.Yes {
+			if out.Structured() {
+				out.Fail(errUsage, "Refusing to delete %s without confirmation; pass -yes or -dry-run", req.Code)
+			}
+			if len(moves) > 0 {
+				fmt.Printf("%d bibliographies will move to %s:\n", len(moves), req.ReassignTo)
+				renderReclassifications(os.Stdout, moves)
+			}
+			if !promptConfirm(fmt.Sprintf("Delete classification %s %s?", class.Code, class.Name)) {
+				fmt.Println("Aborted")
+				exit(1)
+			}
+		}
+		if !req.DryRun {
+			class, moves, err = app.BibService.DeleteClassification(req.Code, req.ReassignTo, req.RewriteBibIndex, false)
+			if err != nil {
+				out.Fail(errFailed, "Error deleting classification: %v", err)
+			}
+		}
+		render(out, emit(out, newClassificationChangeView(class, moves, req.DryRun), func(w io.Writer) {
+			if req.DryRun {
+				fmt.Fprintf(w, "Would delete %s %s and move %d bibliographies:\n", class.Code, class.Name, len(moves))
+				renderReclassifications(w, moves)
+				return
+			}
+			fmt.Fprintf(w, "Classification deleted: %s %s\n", class.Code, class.Name)
+			if len(moves) > 0 {
+				fmt.Fprintf(w, "Moved %d bibliographies to %s\n", len(moves), req.ReassignTo)
+			}
+		}))
 
 	case "add-bib":
-		_ = addBibCmd.Parse(os.Args[2:])
-		addBibReq.PromptMissing()
+		parseFlags(out, addBibCmd, args[1:])
+		if addBibReq.Lookup {
+			lookupBibliography(app, out, addBibCmd, addBibReq)
+		}
+		if !out.Structured() {
+			addBibReq.PromptMissing()
+		}
 		if err := addBibReq.Validate(); err != nil {
-			fmt.Printf("Validation error: %v\n", err)
-			addBibCmd.PrintDefaults()
-			os.Exit(1)
+			out.Invalid(addBibCmd, err)
 		}
 
 		bib, err := app.BibService.AddBibliography(
 			addBibReq.Title,
 			addBibReq.Author,
+			addBibReq.ToContributors(),
 			addBibReq.Publisher,
 			addBibReq.ISBN,
 			addBibReq.Type,
@@ -99,52 +496,51 @@ func main() {
 			addBibReq.BibIndex,
 		)
 		if err != nil {
-			fmt.Printf("Error adding bibliography: %v\n", err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error adding bibliography: %v", err)
 		}
-		fmt.Printf("Bibliography added: %v\n", bib)
+		render(out, emit(out, newBibliographyView(bib), func(w io.Writer) {
+			fmt.Fprintf(w, "Bibliography added: %v\n", bib)
+		}))
 
 	case "add-review":
-		_ = addReviewCmd.Parse(os.Args[2:])
-		addReviewReq.PromptMissing()
+		parseFlags(out, addReviewCmd, args[1:])
+		if !out.Structured() {
+			addReviewReq.PromptMissing()
+		}
 		if err := addReviewReq.Validate(); err != nil {
-			fmt.Printf("Validation error: %v\n", err)
-			addReviewCmd.PrintDefaults()
-			os.Exit(1)
+			out.Invalid(addReviewCmd, err)
 		}
 
 		// Resolve BibIndex to ID efficiently
 		bib, err := app.BibService.FindByBibIndex(addReviewReq.BibIndex)
 		if err != nil {
-			fmt.Printf("Error finding bibliography with BibIndex %s: %v\n", addReviewReq.BibIndex, err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error finding bibliography with BibIndex %s: %v", addReviewReq.BibIndex, err)
 		}
 		if bib == nil {
-			fmt.Printf("Bibliography with BibIndex %s not found\n", addReviewReq.BibIndex)
-			os.Exit(1)
+			out.Fail(errNotFound, "Bibliography with BibIndex %s not found", addReviewReq.BibIndex)
 		}
 
 		review, err := app.ReviewService.AddReview(bib.ID, addReviewReq.Goals, addReviewReq.Summary)
 		if err != nil {
-			fmt.Printf("Error adding review: %v\n", err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error adding review: %v", err)
 		}
-		fmt.Printf("Review added: %v\n", review)
+		render(out, emit(out, newReviewView(review), func(w io.Writer) {
+			fmt.Fprintf(w, "Review added: %v\n", review)
+		}))
 
 	case "update-review":
-		_ = updateReviewCmd.Parse(os.Args[2:])
-		updateReviewReq.PromptMissing()
+		parseFlags(out, updateReviewCmd, args[1:])
+		if !out.Structured() {
+			updateReviewReq.PromptMissing()
+		}
 		if err := updateReviewReq.Validate(); err != nil {
-			fmt.Printf("Validation error: %v\n", err)
-			updateReviewCmd.PrintDefaults()
-			os.Exit(1)
+			out.Invalid(updateReviewCmd, err)
 		}
 
 		// Parse UUID
 		reviewID, err := updateReviewReq.ParseID()
 		if err != nil {
-			fmt.Printf("Invalid review ID format: %v\n", err)
-			os.Exit(1)
+			out.Fail(errValidation, "Invalid review ID format: %v", err)
 		}
 
 		// Prepare optional fields
@@ -159,34 +555,797 @@ func main() {
 
 		review, err := app.ReviewService.UpdateReview(reviewID, goals, summary)
 		if err != nil {
-			fmt.Printf("Error updating review: %v\n", err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error updating review: %v", err)
+		}
+		render(out, emit(out, newReviewView(review), func(w io.Writer) {
+			fmt.Fprintf(w, "Review updated: %v\n", review)
+		}))
+
+	case "review-history":
+		reviewHistoryReq.ReviewIDStr = parseWithRef(out, reviewHistoryCmd, args[1:])
+		if !out.Structured() {
+			reviewHistoryReq.PromptMissing()
+		}
+		if err := reviewHistoryReq.Validate(); err != nil {
+			out.Invalid(nil, err)
+		}
+		reviewID, err := reviewHistoryReq.ParseID()
+		if err != nil {
+			out.Fail(errValidation, "Invalid review ID format: %v", err)
+		}
+
+		_, history, err := app.ReviewService.ReviewHistory(reviewID)
+		if err != nil {
+			out.Fail(errFailed, "Error listing review revisions: %v", err)
+		}
+		render(out, emitList(out, newReviewRevisionViews(history), func(w io.Writer) {
+			renderReviewHistory(w, reviewID, history)
+		}))
+
+	case "diff-review":
+		diffReviewReq.ReviewIDStr = parseWithRef(out, diffReviewCmd, args[1:])
+		if !out.Structured() {
+			diffReviewReq.PromptMissing()
+		}
+		if err := diffReviewReq.Validate(); err != nil {
+			out.Invalid(diffReviewCmd, err)
+		}
+		reviewID, err := diffReviewReq.ParseID()
+		if err != nil {
+			out.Fail(errValidation, "Invalid review ID format: %v", err)
+		}
+
+		diff, err := app.ReviewService.DiffReview(reviewID, diffReviewReq.From, diffReviewReq.To)
+		if err != nil {
+			out.Fail(errFailed, "Error comparing review revisions: %v", err)
+		}
+		render(out, emitList(out, newDiffLineViews(diff), func(w io.Writer) {
+			renderReviewDiff(w, diff)
+		}))
+
+	case "revert-review":
+		revertReviewReq.ReviewIDStr = parseWithRef(out, revertReviewCmd, args[1:])
+		if !out.Structured() {
+			revertReviewReq.PromptMissing()
+		}
+		if err := revertReviewReq.Validate(); err != nil {
+			out.Invalid(revertReviewCmd, err)
+		}
+		req := revertReviewReq
+		reviewID, err := req.ParseID()
+		if err != nil {
+			out.Fail(errValidation, "Invalid review ID format: %v", err)
 		}
-		fmt.Printf("Review updated: %v\n", review)
+
+		_, history, err := app.ReviewService.ReviewHistory(reviewID)
+		if err != nil {
+			out.Fail(errFailed, "Error reverting review: %v", err)
+		}
+		current := history.Latest()
+		if !req.Yes {
+			if out.Structured() {
+				out.Fail(errUsage, "Refusing to revert review %s without confirmation; pass -yes", reviewID)
+			}
+			// Show how the review will change
+			diff, err := app.ReviewService.DiffReview(reviewID, current.Number, req.To)
+			if err != nil {
+				out.Fail(errFailed, "Error reverting review: %v", err)
+			}
+			renderReviewDiff(os.Stdout, diff)
+			if !promptConfirm(fmt.Sprintf("Restore revision %d of review %s?", req.To, reviewID)) {
+				fmt.Println("Aborted")
+				exit(1)
+			}
+		}
+
+		review, revision, err := app.ReviewService.RevertReview(reviewID, req.To)
+		if err != nil {
+			out.Fail(errFailed, "Error reverting review: %v", err)
+		}
+		if revision == nil {
+			// The revert was saved but could not be recorded as a revision, which has been logged
+			render(out, emit(out, newReviewView(review), func(w io.Writer) {
+				fmt.Fprintf(w, "Review reverted to revision %d: %v\n", req.To, review)
+			}))
+		} else {
+			render(out, emit(out, newReviewRevisionView(revision, current), func(w io.Writer) {
+				fmt.Fprintf(w, "Review reverted to revision %d as revision %d: %v\n", req.To, revision.Number, review)
+			}))
+		}
+
+	case "edit-review":
+		editReviewReq.ReviewIDStr = parseWithRef(out, editReviewCmd, args[1:])
+		if !out.Structured() {
+			editReviewReq.PromptMissing()
+		}
+		if err := editReviewReq.Validate(); err != nil {
+			out.Invalid(editReviewCmd, err)
+		}
+		req := editReviewReq
+		reviewID, err := req.ParseID()
+		if err != nil {
+			out.Fail(errValidation, "Invalid review ID format: %v", err)
+		}
+		if out.Structured() && !req.Yes {
+			out.Fail(errUsage, "Refusing to edit review %s without confirmation; pass -yes", reviewID)
+		}
+
+		review, err := app.ReviewService.FindByID(reviewID)
+		if err != nil {
+			out.Fail(errFailed, "Error finding review: %v", err)
+		}
+		if review == nil {
+			out.Fail(errNotFound, "Review not found: %s", reviewID)
+		}
+		bib, err := app.BibService.FindByID(review.BookID)
+		if err != nil || bib == nil {
+			out.Fail(errFailed, "Error finding the bibliography of review %s: %v", reviewID, err)
+		}
+
+		goals, summary, err := editReviewDocument(reviewDocument(bib, review), reviewID)
+		if err != nil {
+			out.Fail(errFailed, "Error editing review: %v", err)
+		}
+		goalsDiff := textdiff.Lines(strings.TrimSpace(review.Goals), goals)
+		summaryDiff := textdiff.Lines(strings.TrimSpace(review.Summary), summary)
+		if !textdiff.Changed(goalsDiff) && !textdiff.Changed(summaryDiff) {
+			render(out, emit(out, newReviewView(review), func(w io.Writer) {
+				fmt.Fprintln(w, "No changes")
+			}))
+			break
+		}
+		if !req.Yes {
+			fmt.Printf("Review %s:\n", reviewID)
+			renderFieldDiffs(os.Stdout, goalsDiff, summaryDiff)
+			if !promptConfirm(fmt.Sprintf("Save the changes to review %s?", reviewID)) {
+				fmt.Println("Aborted")
+				exit(1)
+			}
+		}
+
+		// The review may have been changed while it was being edited
+		latest, err := app.ReviewService.FindByID(reviewID)
+		if err != nil || latest == nil {
+			out.Fail(errFailed, "Error updating review: %v", err)
+		}
+		if latest.Goals != review.Goals || latest.Summary != review.Summary {
+			out.Fail(errFailed, "Review %s was changed while it was being edited; edit it again", reviewID)
+		}
+		var newGoals, newSummary *string
+		if textdiff.Changed(goalsDiff) {
+			newGoals = &goals
+		}
+		if textdiff.Changed(summaryDiff) {
+			newSummary = &summary
+		}
+		review, err = app.ReviewService.UpdateReview(reviewID, newGoals, newSummary)
+		if err != nil {
+			out.Fail(errFailed, "Error updating review: %v", err)
+		}
+		render(out, emit(out, newReviewView(review), func(w io.Writer) {
+			fmt.Fprintf(w, "Review updated: %v\n", review)
+		}))
 
 	case "list":
-		_ = listCmd.Parse(os.Args[2:])
+		parseFlags(out, listCmd, args[1:])
 		if err := listReq.Validate(); err != nil {
-			fmt.Printf("Validation error: %v\n", err)
-			listCmd.PrintDefaults()
-			os.Exit(1)
+			out.Invalid(listCmd, err)
+		}
+
+		query, _ := listReq.Query() // already checked by Validate
+		bibs, err := app.BibService.FindBibliographies(query)
+		if err != nil {
+			out.Fail(errFailed, "Error listing bibliographies: %v", err)
+		}
+		render(out, emitList(out, newBibliographyViews(bibs), func(w io.Writer) {
+			fmt.Fprintln(w, "Bibliographies:")
+			for _, b := range bibs {
+				fmt.Fprintf(w, "[%s] %s by %s (BibIndex: %s)\n", b.Type, b.Title, b.Author, b.BibIndex)
+			}
+			if len(bibs) == listReq.Limit && listReq.Limit > 0 {
+				fmt.Fprintf(w, "\nShowing %d items (use --limit and --offset to see more)\n", len(bibs))
+			}
+		}))
+
+	case "show":
+		showReq.Ref = parseWithRef(out, showCmd, args[1:])
+		if !out.Structured() {
+			showReq.PromptMissing()
+		}
+		if err := showReq.Validate(); err != nil {
+			out.Invalid(nil, err)
+		}
+
+		bib, err := app.FindBibliography(showReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", showReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", showReq.Ref)
 		}
 
-		bibs, err := app.BibService.ListBibliographies(listReq.Limit, listReq.Offset)
+		detail, err := app.BibliographyDetail(bib)
 		if err != nil {
-			fmt.Printf("Error listing bibliographies: %v\n", err)
-			os.Exit(1)
+			out.Fail(errFailed, "Error showing %s: %v", bib.BibIndex, err)
+		}
+		render(out, emit(out, newBibliographyDetailView(detail), func(w io.Writer) {
+			renderBibliographyDetail(w, detail)
+		}))
+
+	case "queue", "start", "finish", "abandon":
+		statusCmd := statusCmds[args[0]]
+		changeStatusReq.Ref = parseWithRef(out, statusCmd, args[1:])
+		if !out.Structured() {
+			changeStatusReq.PromptMissing()
 		}
-		fmt.Println("Bibliographies:")
-		for _, b := range bibs {
-			fmt.Printf("[%s] %s by %s (BibIndex: %s)\n", b.Type, b.Title, b.Author, b.BibIndex)
+		if err := changeStatusReq.Validate(); err != nil {
+			out.Invalid(statusCmd, err)
 		}
-		if len(bibs) == listReq.Limit && listReq.Limit > 0 {
-			fmt.Printf("\nShowing %d items (use --limit and --offset to see more)\n", len(bibs))
+		at, _ := changeStatusReq.Time() // already checked by Validate
+
+		bib, err := app.FindBibliography(changeStatusReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", changeStatusReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", changeStatusReq.Ref)
 		}
 
+		var change *domain.ReadingStatusChange
+		switch args[0] {
+		case "queue":
+			change, err = app.ReadingService.Queue(bib.ID, changeStatusReq.Wishlist, at)
+		case "start":
+			change, err = app.ReadingService.Start(bib.ID, at)
+		case "finish":
+			change, err = app.ReadingService.Finish(bib.ID, at)
+		case "abandon":
+			change, err = app.ReadingService.Abandon(bib.ID, at)
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			out.Fail(errValidation, "Error changing reading status: %v", err)
+		}
+		if err != nil {
+			out.Fail(errFailed, "Error changing reading status: %v", err)
+		}
+		render(out, emit(out, newStatusChangeView(bib, change), func(w io.Writer) {
+			renderStatusChange(w, bib, change)
+		}))
+
+	case "log-session":
+		logSessionReq.Ref = parseWithRef(out, logSessionCmd, args[1:])
+		if !out.Structured() {
+			logSessionReq.PromptMissing()
+		}
+		if err := logSessionReq.Validate(); err != nil {
+			out.Invalid(logSessionCmd, err)
+		}
+		// Both already checked by Validate
+		date, _ := logSessionReq.SessionDate()
+		duration, _ := logSessionReq.SessionDuration()
+
+		bib, err := app.FindBibliography(logSessionReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", logSessionReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", logSessionReq.Ref)
+		}
+
+		session, err := app.ReadingService.LogSession(bib.ID, date, duration, logSessionReq.Unit(), logSessionReq.From, logSessionReq.To, logSessionReq.Total, logSessionReq.Note)
+		if errors.Is(err, domain.ErrInvalidSession) {
+			out.Fail(errValidation, "Error logging reading session: %v", err)
+		}
+		if err != nil {
+			out.Fail(errFailed, "Error logging reading session: %v", err)
+		}
+		progress, err := app.ReadingService.Progress(bib.ID)
+		if err != nil {
+			out.Fail(errFailed, "Error computing progress: %v", err)
+		}
+		render(out, emit(out, newSessionLoggedView(bib, session, progress), func(w io.Writer) {
+			renderSessionLogged(w, bib, session, progress)
+		}))
+
+	case "add-quote":
+		addQuoteReq.Ref = parseWithRef(out, addQuoteCmd, args[1:])
+		if !out.Structured() {
+			addQuoteReq.PromptMissing()
+		}
+		if err := addQuoteReq.Validate(); err != nil {
+			out.Invalid(addQuoteCmd, err)
+		}
+
+		bib, err := app.FindBibliography(addQuoteReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", addQuoteReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", addQuoteReq.Ref)
+		}
+
+		highlight, err := app.HighlightService.AddHighlight(bib.ID, addQuoteReq.Text, addQuoteReq.Location, addQuoteReq.Comment, addQuoteReq.TagList(), time.Time{})
+		if err != nil {
+			out.Fail(errFailed, "Error adding highlight: %v", err)
+		}
+		render(out, emit(out, quoteView{highlightView: newHighlightView(highlight), BibIndex: bib.BibIndex}, func(w io.Writer) {
+			fmt.Fprintf(w, "Highlight added to %s: %s\n", bib.BibIndex, highlight.ID)
+		}))
+
+	case "list-quotes":
+		listQuotesReq.Ref = parseWithRef(out, listQuotesCmd, args[1:])
+		if err := listQuotesReq.Validate(); err != nil {
+			out.Invalid(listQuotesCmd, err)
+		}
+
+		var bookID *domain.BibliographyID
+		if listQuotesReq.Ref != "" {
+			bib, err := app.FindBibliography(listQuotesReq.Ref)
+			if err != nil {
+				out.Fail(errFailed, "Error finding bibliography %s: %v", listQuotesReq.Ref, err)
+			}
+			if bib == nil {
+				out.Fail(errNotFound, "Bibliography %s not found", listQuotesReq.Ref)
+			}
+			bookID = &bib.ID
+		}
+
+		highlights, err := app.HighlightService.ListHighlights(bookID, listQuotesReq.Tag, listQuotesReq.Limit, listQuotesReq.Offset)
+		if err != nil {
+			out.Fail(errFailed, "Error listing highlights: %v", err)
+		}
+		bibIndexes := make(map[domain.BibliographyID]string)
+		views := make([]quoteView, 0, len(highlights))
+		for _, h := range highlights {
+			if _, ok := bibIndexes[h.BookID]; !ok {
+				bib, err := app.FindBibliography(h.BookID.String())
+				if err != nil {
+					out.Fail(errFailed, "Error finding bibliography %s: %v", h.BookID, err)
+				}
+				if bib != nil {
+					bibIndexes[h.BookID] = bib.BibIndex
+				}
+			}
+			views = append(views, quoteView{highlightView: newHighlightView(h), BibIndex: bibIndexes[h.BookID]})
+		}
+		render(out, emitList(out, views, func(w io.Writer) {
+			renderHighlights(w, highlights, bibIndexes)
+			if len(highlights) == listQuotesReq.Limit && listQuotesReq.Limit > 0 {
+				fmt.Fprintf(w, "\nShowing %d highlights (use --limit and --offset to see more)\n", len(highlights))
+			}
+		}))
+
+	case "update-bib":
+		updateBibReq.Ref = parseWithRef(out, updateBibCmd, args[1:])
+		if !out.Structured() {
+			updateBibReq.PromptMissing()
+		}
+		if err := updateBibReq.Validate(); err != nil {
+			out.Invalid(updateBibCmd, err)
+		}
+
+		bib, err := app.FindBibliography(updateBibReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", updateBibReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", updateBibReq.Ref)
+		}
+
+		updated, err := app.BibService.UpdateBibliography(bib.ID, updateBibReq.ToUpdate())
+		if err != nil {
+			out.Fail(errFailed, "Error updating bibliography: %v", err)
+		}
+		render(out, emit(out, newBibliographyView(updated), func(w io.Writer) {
+			fmt.Fprintf(w, "Bibliography updated: %v\n", updated)
+		}))
+
+	case "delete-bib":
+		deleteBibReq.Ref = parseWithRef(out, deleteBibCmd, args[1:])
+		if !out.Structured() {
+			deleteBibReq.PromptMissing()
+		}
+		if err := deleteBibReq.Validate(); err != nil {
+			out.Invalid(deleteBibCmd, err)
+		}
+		policy, _ := deleteBibReq.Policy()
+		if policy == service.KeepOrphanReviews && !app.BibService.OrphanReviewsAllowed() {
+			out.Fail(errValidation, "-reviews orphan is not supported by the %s backend, which requires reviews to belong to a bibliography; use refuse or cascade", cfg.Backend)
+		}
+
+		bib, err := app.FindBibliography(deleteBibReq.Ref)
+		if err != nil {
+			out.Fail(errFailed, "Error finding bibliography %s: %v", deleteBibReq.Ref, err)
+		}
+		if bib == nil {
+			out.Fail(errNotFound, "Bibliography %s not found", deleteBibReq.Ref)
+		}
+		if !deleteBibReq.Yes {
+			if out.Structured() {
+				out.Fail(errUsage, "Refusing to delete %s without confirmation; pass -yes", bib.BibIndex)
+			}
+			if !promptConfirm(fmt.Sprintf("Delete %s (%s)?", bib.BibIndex, bib.Title)) {
+				fmt.Println("Aborted")
+				exit(1)
+			}
+		}
+
+		deleted, reviews, err := app.BibService.DeleteBibliography(bib.ID, policy)
+		if errors.Is(err, domain.ErrInvalid) {
+			out.Fail(errValidation, "Error deleting bibliography: %v", err)
+		}
+		if err != nil {
+			out.Fail(errFailed, "Error deleting bibliography: %v", err)
+		}
+		result := deletedBibliographyView{bibliographyView: newBibliographyView(deleted)}
+		switch policy {
+		case service.CascadeReviews:
+			result.DeletedReviews = len(reviews)
+		case service.KeepOrphanReviews:
+			result.OrphanedReviews = len(reviews)
+		}
+		render(out, emit(out, result, func(w io.Writer) {
+			fmt.Fprintf(w, "Bibliography deleted: %s (%s)\n", deleted.BibIndex, deleted.ID)
+			if result.DeletedReviews > 0 {
+				fmt.Fprintf(w, "Deleted %d review(s)\n", result.DeletedReviews)
+			}
+			if result.OrphanedReviews > 0 {
+				fmt.Fprintf(w, "Kept %d orphaned review(s)\n", result.OrphanedReviews)
+			}
+		}))
+
+	case "check-indexes":
+		parseFlags(out, checkIndexesCmd, args[1:])
+		duplicates, err := app.BibService.FindDuplicateBibIndexes()
+		if err != nil {
+			out.Fail(errFailed, "Error checking BibIndexes: %v", err)
+		}
+		render(out, emitList(out, newDuplicateBibIndexViews(duplicates), func(w io.Writer) {
+			if len(duplicates) == 0 {
+				fmt.Fprintln(w, "No duplicate BibIndexes found")
+				return
+			}
+			for _, dup := range duplicates {
+				fmt.Fprintf(w, "%s is used by %d bibliographies:\n", dup.BibIndex, len(dup.Bibliographies))
+				for _, bib := range dup.Bibliographies {
+					fmt.Fprintf(w, "  %s  %s\n", bib.ID, bib.Title)
+				}
+			}
+			fmt.Fprintln(w, "Fix them with: biblog update-bib <id> -bib-index <new> (or -regen-index)")
+		}))
+		if len(duplicates) > 0 {
+			return 1
+		}
+
+	case "migrate-isbn":
+		parseFlags(out, migrateISBNCmd, args[1:])
+		migrations, err := app.ISBNNormalizer.NormalizeISBNs(migrateISBNReq.DryRun, migrateISBNReq.ClearInvalid)
+		if err != nil {
+			out.Fail(errFailed, "Error migrating ISBNs: %v", err)
+		}
+		render(out, emitList(out, newISBNMigrationViews(migrations), func(w io.Writer) {
+			renderISBNMigrations(w, migrations, migrateISBNReq.DryRun)
+		}))
+		for _, m := range migrations {
+			if m.Err != nil && !m.Cleared {
+				return 1
+			}
+		}
+
+	case "export":
+		parseFlags(out, exportCmd, args[1:])
+		if err := exportReq.Validate(); err != nil {
+			out.Invalid(exportCmd, err)
+		}
+
+		bibs, err := app.BibService.ListBibliographies(0, 0)
+		if err != nil {
+			out.Fail(errFailed, "Error listing bibliographies: %v", err)
+		}
+		// BibTeX is the export's own format, so -output does not apply to it
+		file := os.Stdout
+		if exportReq.Out != "" {
+			file, err = os.Create(exportReq.Out)
+			if err != nil {
+				out.Fail(errFailed, "Error creating output file: %v", err)
+			}
+			defer func() {
+				if err := file.Close(); err != nil {
+					log.Printf("Failed to close output file: %v", err)
+				}
+			}()
+		}
+		if err := bibtex.Write(file, bibliographiesToBibTeX(bibs)); err != nil {
+			out.Fail(errFailed, "Error exporting bibliographies: %v", err)
+		}
+		if exportReq.Out != "" && !out.Structured() {
+			fmt.Printf("Exported %d bibliographies to %s\n", len(bibs), exportReq.Out)
+		}
+
+	case "export-notes":
+		parseFlags(out, exportNotesCmd, args[1:])
+		if err := exportNotesReq.Validate(); err != nil {
+			out.Invalid(exportNotesCmd, err)
+		}
+
+		exports, err := exportNotes(app, exportNotesReq.Vault, exportNotesReq.Force, exportNotesReq.DryRun)
+		if err != nil {
+			out.Fail(errFailed, "Error exporting notes: %v", err)
+		}
+		render(out, emitList(out, newNoteExportViews(exports), func(w io.Writer) {
+			renderNoteExports(w, exports, exportNotesReq.DryRun)
+		}))
+
+	case "sync-notes":
+		parseFlags(out, syncNotesCmd, args[1:])
+		if err := syncNotesReq.Validate(); err != nil {
+			out.Invalid(syncNotesCmd, err)
+		}
+
+		results, err := syncNotes(app, syncNotesReq.Vault, syncNotesReq.Force, syncNotesReq.DryRun)
+		if err != nil {
+			out.Fail(errFailed, "Error syncing notes: %v", err)
+		}
+		render(out, emitList(out, newNoteSyncViews(results), func(w io.Writer) {
+			renderNoteSyncs(w, results, syncNotesReq.DryRun)
+		}))
+
+	case "import":
+		importReq.File = parseWithRef(out, importCmd, args[1:])
+		if err := importReq.Validate(); err != nil {
+			out.Invalid(importCmd, err)
+		}
+
+		mapping := classMapping{}
+		if importReq.ClassMap != "" {
+			mapping, err = loadClassMapping(importReq.ClassMap)
+			if err != nil {
+				out.Fail(errFailed, "Error loading class mapping: %v", err)
+			}
+		}
+		file, err := os.Open(importReq.File)
+		if err != nil {
+			out.Fail(errFailed, "Error opening import file: %v", err)
+		}
+		entries, err := bibtex.Parse(file)
+		_ = file.Close()
+		if err != nil {
+			out.Fail(errFailed, "Error parsing %s: %v", importReq.File, err)
+		}
+
+		results, err := app.BibService.ImportBibliographies(bibtexToImports(entries, mapping, importReq.ClassCode), importReq.DryRun)
+		if err != nil {
+			out.Fail(errFailed, "Error importing bibliographies: %v", err)
+		}
+		render(out, emitList(out, newImportResultViews(results, importReq.DryRun), func(w io.Writer) {
+			renderImportReport(w, results, importReq.DryRun)
+		}))
+
+	case "import-kindle":
+		importKindleReq.File = parseWithRef(out, importKindleCmd, args[1:])
+		if err := importKindleReq.Validate(); err != nil {
+			out.Invalid(importKindleCmd, err)
+		}
+
+		file, err := os.Open(importKindleReq.File)
+		if err != nil {
+			out.Fail(errFailed, "Error opening clippings file: %v", err)
+		}
+		// Kindle writes times in the device's local time without a zone
+		clippings, err := kindle.Parse(file, time.Local)
+		_ = file.Close()
+		if err != nil && clippings == nil {
+			out.Fail(errFailed, "Error parsing %s: %v", importKindleReq.File, err)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipped unreadable clippings:\n%v\n", err)
+		}
+
+		var imports []kindleImport
+		for _, book := range groupClippings(kindle.AttachNotes(clippings)) {
+			imp := kindleImport{Book: book}
+			imp.Bibliography, imp.SkipReason, err = resolveKindleBook(app, out, book, importKindleReq.DryRun)
+			if err != nil {
+				out.Fail(errFailed, "Error matching %s: %v", book.Title, err)
+			}
+			if imp.Bibliography != nil {
+				imp.Results, err = app.HighlightService.ImportHighlights(imp.Bibliography.ID, clippingsToImports(book.Clippings), importKindleReq.DryRun)
+			}
+			imports = append(imports, imp)
+			if err != nil {
+				break
+			}
+		}
+		render(out, emitList(out, newKindleImportResultViews(imports, importKindleReq.DryRun), func(w io.Writer) {
+			renderKindleImport(w, imports, importKindleReq.DryRun)
+		}))
+		if err != nil {
+			out.Fail(errFailed, "Error importing highlights: %v", err)
+		}
+
+	case "search":
+		parseFlags(out, searchCmd, args[1:])
+		searchReq.Query = strings.Join(searchCmd.Args(), " ")
+		if err := searchReq.Validate(); err != nil {
+			out.Invalid(searchCmd, err)
+		}
+
+		results, err := app.SearchService.Search(searchReq.Query, searchReq.Limit)
+		if err != nil {
+			out.Fail(errFailed, "Error searching: %v", err)
+		}
+		render(out, emitList(out, newSearchResultViews(results), func(w io.Writer) {
+			renderSearchResults(w, results)
+		}))
+
+	case "reindex":
+		parseFlags(out, reindexCmd, args[1:])
+		bibCount, reviewCount, err := app.SearchService.RebuildIndex()
+		if err != nil {
+			out.Fail(errFailed, "Error rebuilding search index: %v", err)
+		}
+		render(out, emit(out, reindexView{Bibliographies: bibCount, Reviews: reviewCount}, func(w io.Writer) {
+			fmt.Fprintf(w, "Indexed %d bibliographies and %d reviews\n", bibCount, reviewCount)
+		}))
+
+	case "serve":
+		parseFlags(out, serveCmd, args[1:])
+
+		handler, err := newServerHandler(app)
+		if err != nil {
+			out.Fail(errFailed, "Error loading the web UI: %v", err)
+		}
+		ln, err := net.Listen("tcp", serveReq.Addr)
+		if err != nil {
+			out.Fail(errFailed, "Error listening on %s: %v", serveReq.Addr, err)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		log.Printf("Serving the library at http://%s/ and the API at http://%s/api/ (press Ctrl+C to stop)", ln.Addr(), ln.Addr())
+		if err := serve(ctx, ln, handler); err != nil {
+			out.Fail(errFailed, "Error serving: %v", err)
+		}
+		log.Printf("Server stopped")
+
+	case "site":
+		if len(args) < 2 {
+			out.Fail(errUsage, "expected 'site build' or 'site templates'")
+		}
+		out.Command = "site " + args[1]
+		switch args[1] {
+		case "build":
+			parseFlags(out, siteBuildCmd, args[2:])
+			if err := siteBuildReq.Validate(); err != nil {
+				out.Invalid(siteBuildCmd, err)
+			}
+
+			result, err := buildSite(app, siteBuildReq.Options(), time.Now())
+			if err != nil {
+				out.Fail(errFailed, "Error building site: %v", err)
+			}
+			render(out, emit(out, newSiteView(siteBuildReq.Out, result), func(w io.Writer) {
+				fmt.Fprintf(w, "Built %d pages in %s: %d bibliographies, %d classifications, %d years, %d reviews in the feed\n",
+					result.Pages, siteBuildReq.Out, result.Bibliographies, result.Classifications, result.Years, result.FeedEntries)
+				if result.Removed > 0 {
+					fmt.Fprintf(w, "Removed %d files of the previous build\n", result.Removed)
+				}
+			}))
+
+		case "templates":
+			parseFlags(out, siteTemplatesCmd, args[2:])
+			if err := siteTemplatesReq.Validate(); err != nil {
+				out.Invalid(siteTemplatesCmd, err)
+			}
+
+			written, skipped, err := writeSiteTemplates(siteTemplatesReq.Out)
+			if err != nil {
+				out.Fail(errFailed, "Error copying templates: %v", err)
+			}
+			render(out, emitList(out, newSiteTemplateViews(written, skipped), func(w io.Writer) {
+				for _, name := range written {
+					fmt.Fprintf(w, "Wrote %s\n", filepath.Join(siteTemplatesReq.Out, name))
+				}
+				for _, name := range skipped {
+					fmt.Fprintf(w, "Kept %s (already exists)\n", filepath.Join(siteTemplatesReq.Out, name))
+				}
+			}))
+
+		default:
+			out.Fail(errUsage, "expected 'site build' or 'site templates'")
+		}
+
+	default:
+		out.Command = ""
+		out.Fail(errUsage, "%s", usageMessage)
+	}
+	return 0
+}
+
+// render reports a failure to write a command's result.
+func render(out *Output, err error) {
+	if err != nil {
+		out.Fail(errFailed, "Error writing output: %v", err)
+	}
+}
+
+// lookupBibliography prefills an add-bib request from the catalogue entry of its ISBN.
+// A catalogue that cannot be reached is not fatal: the remaining fields are prompted for
+// (or reported missing) as if -lookup had not been given.
+func lookupBibliography(app *App, out *Output, fs *flag.FlagSet, req *AddBibliographyRequest) {
+	if req.ISBN == "" && !out.Structured() {
+		req.ISBN = promptString("ISBN", true)
+	}
+	if req.ISBN == "" {
+		out.Invalid(fs, fmt.Errorf("-lookup requires -isbn"))
+	}
+	if _, err := domain.ParseISBN(req.ISBN); err != nil {
+		out.Invalid(fs, err)
+	}
+	meta, err := app.MetadataService.LookupISBN(req.ISBN)
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	case meta == nil:
+		if !out.Structured() {
+			fmt.Fprintf(out.W, "No catalogue entry found for ISBN %s\n", req.ISBN)
+		}
 	default:
-		fmt.Println("expected 'add-class', 'add-bib', 'add-review', 'update-review' or 'list' subcommands")
-		os.Exit(1)
+		if !out.Structured() {
+			fmt.Fprintf(out.W, "Found in %s: %s / %s (%s, %d)\n", meta.Source, meta.Title, meta.Author, meta.Publisher, meta.Year)
+		}
+		req.ApplyMetadata(meta)
+	}
+}
+
+// envOrDefault returns the value of the environment variable key, or def if it is unset or empty.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// newFlagSet creates the flag set of a subcommand. Parse errors are reported by parseFlags
+// rather than printed by the flag package, so they follow the -output format.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses the flags of a subcommand. -h prints its flags and stops with status 0;
+// any other parse error is reported as a validation error.
+func parseFlags(out *Output, fs *flag.FlagSet, args []string) {
+	err := fs.Parse(args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fs.SetOutput(out.ErrW)
+		fmt.Fprintf(out.ErrW, "Usage of %s:\n", fs.Name())
+		fs.PrintDefaults()
+		exit(0)
+	case err != nil:
+		out.Invalid(fs, err)
+	}
+}
+
+// parseWithRef parses a subcommand whose first positional argument is a BibIndex or UUID.
+// The reference may come before or after the flags.
+func parseWithRef(out *Output, fs *flag.FlagSet, args []string) string {
+	var ref string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ref, args = args[0], args[1:]
+	}
+	parseFlags(out, fs, args)
+	if ref == "" {
+		ref = fs.Arg(0)
+	}
+	return ref
+}
+
+// appendTo returns a flag.Func callback that collects every use of a repeatable flag.
+func appendTo(values *[]string) func(string) error {
+	return func(s string) error {
+		*values = append(*values, s)
+		return nil
 	}
 }
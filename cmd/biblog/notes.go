@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/notes"
+	"bibliography_log/internal/textdiff"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Outcomes of exporting one note.
+const (
+	noteCreated   = "created"
+	noteUpdated   = "updated"
+	noteUnchanged = "unchanged"
+	noteSkipped   = "skipped"
+)
+
+// noteExport is the outcome of exporting one bibliography to its note.
+type noteExport struct {
+	Bibliography *domain.Bibliography
+	File         string
+	Status       string
+	SkipReason   string
+}
+
+// exportNotes writes a literature note for every bibliography into vault, named by
+// BibIndex. Notes already in the vault get a new generated part and keep the user's
+// notes below the end marker. Notes without the marker are skipped, and so are notes
+// whose reviews were edited since the last export or sync unless force is set. Notes of
+// deleted bibliographies are left alone. With dryRun nothing is written.
+func exportNotes(app *App, vault string, force, dryRun bool) (exports []noteExport, err error) {
+	bibs, err := app.BibService.ListBibliographies(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+	state, err := notes.LoadState(vault)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		if err := os.MkdirAll(vault, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create vault directory: %w", err)
+		}
+		// Notes written before a failure are recorded too
+		defer func() {
+			if saveErr := state.Save(vault); err == nil {
+				err = saveErr
+			}
+		}()
+	}
+
+	exports = make([]noteExport, 0, len(bibs))
+	for _, bib := range bibs {
+		class, err := app.BibService.FindClassification(bib)
+		if err != nil {
+			return exports, fmt.Errorf("failed to find classification for %s: %w", bib.Code, err)
+		}
+		reviews, err := app.ReviewService.ListReviewsByBookID(bib.ID)
+		if err != nil {
+			return exports, fmt.Errorf("failed to list reviews of %s: %w", bib.BibIndex, err)
+		}
+
+		name := bibRef(bib) + ".md"
+		export := noteExport{Bibliography: bib, File: filepath.Join(vault, name)}
+		generated := notes.Render(literatureNote(bib, class, reviews))
+		existing, err := os.ReadFile(export.File)
+		var content []byte
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			export.Status, content = noteCreated, notes.New(generated)
+		case err != nil:
+			return exports, fmt.Errorf("failed to read %s: %w", export.File, err)
+		case !force && reviewsEditedInNote(state.Notes[name], existing):
+			export.Status = noteSkipped
+			export.SkipReason = "reviews were edited in the note; run sync-notes first, or pass -force to overwrite them"
+		default:
+			content, err = notes.Merge(existing, generated)
+			switch {
+			case errors.Is(err, notes.ErrNoMarker):
+				export.Status = noteSkipped
+				export.SkipReason = "no end marker; add one above your notes to let export-notes update the note"
+			case err != nil:
+				return exports, fmt.Errorf("failed to update %s: %w", export.File, err)
+			case bytes.Equal(content, existing):
+				export.Status = noteUnchanged
+			default:
+				export.Status = noteUpdated
+			}
+		}
+
+		if !dryRun && export.Status != noteSkipped {
+			if export.Status != noteUnchanged {
+				if err := writeFileReplacing(export.File, content); err != nil {
+					return exports, err
+				}
+			}
+			if err := recordNote(state, vault, name, bib, reviews, content); err != nil {
+				return exports, err
+			}
+		}
+		exports = append(exports, export)
+	}
+	return exports, nil
+}
+
+// reviewsEditedInNote reports whether a note has review text that differs from what was
+// recorded when it was last exported or synced.
+func reviewsEditedInNote(recorded *notes.NoteState, content []byte) bool {
+	if recorded == nil || notes.Hash(string(content)) == recorded.Hash {
+		return false
+	}
+	reviews, err := notes.Parse(content)
+	if err != nil {
+		return true
+	}
+	for _, r := range reviews {
+		if known, ok := recorded.Reviews[r.ID]; ok && notes.NewReviewState(r.Goals, r.Summary) != known {
+			return true
+		}
+	}
+	return false
+}
+
+// recordNote records a note that agrees with reviews.
+func recordNote(state *notes.State, vault, name string, bib *domain.Bibliography, reviews []*domain.Review, content []byte) error {
+	info, err := os.Stat(filepath.Join(vault, name))
+	if err != nil {
+		return fmt.Errorf("failed to record %s: %w", name, err)
+	}
+	recorded := &notes.NoteState{BibliographyID: bib.ID.String(), Reviews: make(map[string]notes.ReviewState)}
+	recorded.SetFile(content, info)
+	for _, review := range reviews {
+		recorded.Reviews[review.ID.String()] = notes.NewReviewState(review.Goals, review.Summary)
+	}
+	state.Notes[name] = recorded
+	return nil
+}
+
+// literatureNote lays out a bibliography and its reviews as a note.
+func literatureNote(bib *domain.Bibliography, class *domain.Classification, reviews []*domain.Review) notes.Note {
+	var code, className string
+	if class != nil {
+		code, className = class.Code.String(), class.Name
+	}
+	year := 0
+	if !bib.PublishedDate.IsZero() {
+		year = bib.PublishedDate.Year()
+	}
+	note := notes.Note{
+		Title: bib.Title,
+		Fields: []notes.Field{
+			{Key: "title", Value: bib.Title},
+			{Key: "author", Value: bib.Author},
+			{Key: "type", Value: bib.Type},
+			{Key: "classification", Value: code},
+			{Key: "classification_name", Value: className},
+			{Key: "year", Value: year},
+			{Key: "isbn", Value: bib.ISBN.String()},
+			{Key: "bib_index", Value: bib.BibIndex},
+			{Key: "uuid", Value: bib.ID.String()},
+		},
+	}
+	for _, review := range reviews {
+		note.Reviews = append(note.Reviews, notes.Review{ID: review.ID.String(), Goals: review.Goals, Summary: review.Summary})
+	}
+	return note
+}
+
+// Outcomes of syncing one review, or a note as a whole.
+const (
+	syncPushed    = "pushed"
+	syncConflict  = "conflict"
+	syncInvalid   = "invalid"
+	syncUnchanged = "unchanged"
+	syncSkipped   = "skipped"
+)
+
+// noteSync is the outcome of syncing a note (ReviewID empty) or one review in it.
+type noteSync struct {
+	File     string
+	ReviewID string
+	Status   string
+	Reason   string
+	Goals    []textdiff.Line // from the review to the note, for pushed reviews
+	Summary  []textdiff.Line
+}
+
+// syncNotes pushes reviews edited in the notes of vault back with UpdateReview. Notes
+// whose modification time and size are as recorded are not read, and notes whose content
+// hashes the same are unchanged. A review edited in the note is pushed only if it was not
+// changed in biblog as well since the last export or sync; otherwise it is a conflict,
+// which force resolves in favor of the note. Notes with a conflict are synced again next
+// time. With dryRun nothing is saved.
+func syncNotes(app *App, vault string, force, dryRun bool) (results []noteSync, err error) {
+	state, err := notes.LoadState(vault)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Notes) == 0 {
+		return nil, fmt.Errorf("no notes exported to %s; run export-notes first", vault)
+	}
+	if !dryRun {
+		defer func() {
+			if saveErr := state.Save(vault); err == nil {
+				err = saveErr
+			}
+		}()
+	}
+
+	names := make([]string, 0, len(state.Notes))
+	for name := range state.Notes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		recorded := state.Notes[name]
+		file := filepath.Join(vault, name)
+		info, err := os.Stat(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			results = append(results, noteSync{File: file, Status: syncSkipped, Reason: "the note was removed"})
+			continue
+		}
+		if err != nil {
+			return results, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if recorded.Matches(info) {
+			results = append(results, noteSync{File: file, Status: syncUnchanged})
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return results, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if notes.Hash(string(content)) == recorded.Hash {
+			if !dryRun {
+				recorded.SetFile(content, info)
+			}
+			results = append(results, noteSync{File: file, Status: syncUnchanged})
+			continue
+		}
+
+		reviews, err := notes.Parse(content)
+		if err != nil {
+			results = append(results, noteSync{File: file, Status: syncSkipped, Reason: err.Error()})
+			continue
+		}
+		settled := true
+		noteResults, err := syncReviews(app, file, recorded, reviews, force, dryRun)
+		for _, r := range noteResults {
+			if r.Status == syncConflict || r.Status == syncInvalid {
+				settled = false
+			}
+		}
+		results = append(results, noteResults...)
+		if err != nil {
+			return results, err
+		}
+		if len(noteResults) == 0 {
+			// Only text around the reviews changed
+			results = append(results, noteSync{File: file, Status: syncUnchanged})
+		}
+		if settled && !dryRun {
+			recorded.SetFile(content, info)
+		}
+	}
+	return results, nil
+}
+
+// syncReviews pushes the reviews of one note that were edited in it, updating recorded.
+func syncReviews(app *App, file string, recorded *notes.NoteState, reviews []notes.Review, force, dryRun bool) ([]noteSync, error) {
+	var results []noteSync
+	for _, r := range reviews {
+		known, ok := recorded.Reviews[r.ID]
+		edited := notes.NewReviewState(r.Goals, r.Summary)
+		if !ok || edited == known {
+			continue
+		}
+		result := noteSync{File: file, ReviewID: r.ID}
+		id, err := domain.ParseReviewID(r.ID)
+		if err != nil {
+			result.Status, result.Reason = syncInvalid, fmt.Sprintf("invalid review ID: %v", err)
+			results = append(results, result)
+			continue
+		}
+		review, err := app.ReviewService.FindByID(id)
+		if err != nil {
+			return results, fmt.Errorf("failed to find review %s: %w", id, err)
+		}
+		if review == nil {
+			result.Status, result.Reason = syncConflict, "the review was deleted"
+			results = append(results, result)
+			continue
+		}
+
+		current := notes.NewReviewState(review.Goals, review.Summary)
+		switch {
+		case current == edited:
+			// Both sides made the same change
+			recorded.Reviews[r.ID] = current
+			continue
+		case current != known && !force:
+			result.Status, result.Reason = syncConflict, "the review was also changed in biblog; use edit-review to merge, or pass -force to keep the note"
+			results = append(results, result)
+			continue
+		}
+
+		result.Goals = textdiff.Lines(strings.TrimSpace(review.Goals), r.Goals)
+		result.Summary = textdiff.Lines(strings.TrimSpace(review.Summary), r.Summary)
+		result.Status = syncPushed
+		if !dryRun {
+			var goals, summary *string
+			if edited.Goals != current.Goals {
+				goals = &r.Goals
+			}
+			if edited.Summary != current.Summary {
+				summary = &r.Summary
+			}
+			if _, err := app.ReviewService.UpdateReview(id, goals, summary); err != nil {
+				if !errors.Is(err, domain.ErrInvalid) {
+					return results, fmt.Errorf("failed to update review %s: %w", id, err)
+				}
+				result.Status, result.Reason, result.Goals, result.Summary = syncInvalid, err.Error(), nil, nil
+				results = append(results, result)
+				continue
+			}
+			recorded.Reviews[r.ID] = edited
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// reviewDocument is the document edit-review opens in the editor.
+func reviewDocument(bib *domain.Bibliography, review *domain.Review) []byte {
+	return notes.RenderDocument(notes.Note{
+		Title: bib.Title,
+		Fields: []notes.Field{
+			{Key: "review", Value: review.ID.String()},
+			{Key: "bib_index", Value: bib.BibIndex},
+			{Key: "title", Value: bib.Title},
+		},
+		Reviews: []notes.Review{{ID: review.ID.String(), Goals: review.Goals, Summary: review.Summary}},
+	})
+}
+
+// parseReviewDocument reads the Goals and Summary of review back from an edited document.
+func parseReviewDocument(data []byte, id domain.ReviewID) (goals, summary string, err error) {
+	reviews, err := notes.Parse(data)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range reviews {
+		if r.ID == id.String() {
+			return r.Goals, r.Summary, nil
+		}
+	}
+	return "", "", fmt.Errorf("the marker of review %s is missing", id)
+}
+
+// editReviewDocument opens doc in the editor and reads the Goals and Summary of review id
+// back. If they cannot be read the edited file is kept, so that the edits are not lost.
+func editReviewDocument(doc []byte, id domain.ReviewID) (goals, summary string, err error) {
+	tmp, err := os.CreateTemp("", "biblog-review-*.md")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create a file to edit: %w", err)
+	}
+	name := tmp.Name()
+	_, err = tmp.Write(doc)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name)
+		return "", "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := runEditor(name); err != nil {
+		os.Remove(name)
+		return "", "", err
+	}
+	edited, err := os.ReadFile(name)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	goals, summary, err = parseReviewDocument(edited, id)
+	if err != nil {
+		return "", "", fmt.Errorf("%w; the edited review is kept in %s", err, name)
+	}
+	os.Remove(name)
+	return goals, summary, nil
+}
+
+// writeFileReplacing writes data to a temporary file next to name and renames it into
+// place, so that a failed write never leaves a note truncated.
+func writeFileReplacing(name string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
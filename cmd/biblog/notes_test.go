@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/notes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportNotes(t *testing.T) {
+	app, err := NewApp(Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	defer app.Close()
+	if _, err := app.BibService.AddClassification("54", "電気工学"); err != nil {
+		t.Fatal(err)
+	}
+	bib, err := app.BibService.AddBibliography("ドメイン駆動設計入門", "成瀬允宣", nil, "翔泳社", "9784798150727", "Book", "54",
+		time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), "", "", "B54NM20DD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	review, err := app.ReviewService.AddReview(bib.ID, "設計を学ぶ", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault := filepath.Join(t.TempDir(), "Literature")
+	statuses := func(exports []noteExport) string {
+		var s []string
+		for _, e := range exports {
+			s = append(s, e.Status)
+		}
+		return strings.Join(s, ",")
+	}
+
+	// A dry run writes nothing
+	exports, err := exportNotes(app, vault, false, true)
+	if err != nil || statuses(exports) != noteCreated {
+		t.Fatalf("Dry run: %s, %v", statuses(exports), err)
+	}
+	if _, err := os.Stat(vault); !os.IsNotExist(err) {
+		t.Errorf("Expected the dry run not to create the vault, got %v", err)
+	}
+
+	exports, err = exportNotes(app, vault, false, false)
+	if err != nil || statuses(exports) != noteCreated {
+		t.Fatalf("First export: %s, %v", statuses(exports), err)
+	}
+	file := filepath.Join(vault, "B54NM20DD.md")
+	note, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`title: "ドメイン駆動設計入門"`, `classification: "54"`, "year: 2020", `isbn: "978-4-7981-5072-7"`,
+		`uuid: "` + bib.ID.String() + `"`, "## Goals\n\n設計を学ぶ\n", notes.EndMarker,
+	} {
+		if !strings.Contains(string(note), want) {
+			t.Errorf("Expected %q in the note:\n%s", want, note)
+		}
+	}
+
+	// Hand-written notes survive an update of the review
+	handWritten := string(note) + "## Permanent notes\n\n- [[値オブジェクト]]\n"
+	if err := os.WriteFile(file, []byte(handWritten), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if exports, err := exportNotes(app, vault, false, false); err != nil || statuses(exports) != noteUnchanged {
+		t.Errorf("Expected the note to be unchanged, got %s, %v", statuses(exports), err)
+	}
+	summary := "まとめ"
+	if _, err := app.ReviewService.UpdateReview(review.ID, nil, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if exports, err := exportNotes(app, vault, false, false); err != nil || statuses(exports) != noteUpdated {
+		t.Errorf("Expected the note to be updated, got %s, %v", statuses(exports), err)
+	}
+	note, _ = os.ReadFile(file)
+	if !strings.Contains(string(note), "## Summary\n\nまとめ\n") || !strings.HasSuffix(string(note), "- [[値オブジェクト]]\n") {
+		t.Errorf("Unexpected updated note:\n%s", note)
+	}
+
+	// Without the marker the note is not touched
+	if err := os.WriteFile(file, []byte("# My own note\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if exports, err := exportNotes(app, vault, false, false); err != nil || statuses(exports) != noteSkipped {
+		t.Errorf("Expected the note to be skipped, got %s, %v", statuses(exports), err)
+	}
+	if note, _ := os.ReadFile(file); string(note) != "# My own note\n" {
+		t.Errorf("Expected the note to be kept, got %q", note)
+	}
+}
+
+func TestSyncNotes(t *testing.T) {
+	app, err := NewApp(Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	defer app.Close()
+	if _, err := app.BibService.AddClassification("54", "電気工学"); err != nil {
+		t.Fatal(err)
+	}
+	bib, err := app.BibService.AddBibliography("ドメイン駆動設計入門", "成瀬允宣", nil, "翔泳社", "", "Book", "54",
+		time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), "", "", "B54NM20DD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	review, err := app.ReviewService.AddReview(bib.ID, "設計を学ぶ", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault := t.TempDir()
+	if _, err := syncNotes(app, vault, false, false); err == nil {
+		t.Error("Expected an error for a vault without exported notes")
+	}
+	if _, err := exportNotes(app, vault, false, false); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(vault, "B54NM20DD.md")
+	edit := func(old, new string) {
+		t.Helper()
+		note, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(strings.Replace(string(note), old, new, 1)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sync := func(force, dryRun bool) string {
+		t.Helper()
+		results, err := syncNotes(app, vault, force, dryRun)
+		if err != nil {
+			t.Fatalf("syncNotes failed: %v", err)
+		}
+		var s []string
+		for _, r := range results {
+			s = append(s, r.Status)
+		}
+		return strings.Join(s, ",")
+	}
+	current := func() *domain.Review {
+		t.Helper()
+		r, err := app.ReviewService.FindByID(review.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	if got := sync(false, false); got != syncUnchanged {
+		t.Errorf("Expected the note to be unchanged, got %s", got)
+	}
+
+	// A summary written in the note is pushed back
+	edit("## Summary\n\n", "## Summary\n\nまとめ\n\n")
+	if exports, err := exportNotes(app, vault, false, false); err != nil || exports[0].Status != noteSkipped {
+		t.Errorf("Expected export-notes to keep the edited note, got %+v, %v", exports, err)
+	}
+	if got := sync(false, true); got != syncPushed || current().Summary != "" {
+		t.Errorf("Expected a dry run to push nothing, got %s", got)
+	}
+	if got := sync(false, false); got != syncPushed || current().Summary != "まとめ" {
+		t.Errorf("Expected the summary to be pushed, got %s and %q", got, current().Summary)
+	}
+	if got := sync(false, false); got != syncUnchanged {
+		t.Errorf("Expected nothing more to sync, got %s", got)
+	}
+	if exports, err := exportNotes(app, vault, false, false); err != nil || exports[0].Status != noteUnchanged {
+		t.Errorf("Expected the synced note to be unchanged, got %+v, %v", exports, err)
+	}
+
+	// Editing both sides is a conflict until forced
+	edit("設計を学ぶ", "設計を学ぶ (note)")
+	goals := "設計を学ぶ (biblog)"
+	if _, err := app.ReviewService.UpdateReview(review.ID, &goals, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := sync(false, false); got != syncConflict || current().Goals != goals {
+		t.Errorf("Expected a conflict, got %s and %q", got, current().Goals)
+	}
+	if got := sync(false, false); got != syncConflict {
+		t.Errorf("Expected the conflict to remain, got %s", got)
+	}
+	if got := sync(true, false); got != syncPushed || current().Goals != "設計を学ぶ (note)" {
+		t.Errorf("Expected the note to win, got %s and %q", got, current().Goals)
+	}
+
+	// Empty goals are rejected by the review
+	edit("設計を学ぶ (note)", "")
+	if got := sync(false, false); got != syncInvalid || current().Goals != "設計を学ぶ (note)" {
+		t.Errorf("Expected the empty goals to be rejected, got %s and %q", got, current().Goals)
+	}
+}
+
+func TestEditReviewDocument(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is a shell script")
+	}
+	bib := &domain.Bibliography{Title: "ドメイン駆動設計入門", BibIndex: "B54NM20DD"}
+	review := &domain.Review{ID: domain.NewReviewID(), Goals: "設計を学ぶ"}
+	doc := reviewDocument(bib, review)
+
+	dir := t.TempDir()
+	edited := filepath.Join(dir, "edited.md")
+	editor := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\ncp "+edited+` "$1"`+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+	t.Setenv("TMPDIR", dir)
+
+	withSummary := strings.Replace(string(doc), "## Summary\n\n", "## Summary\n\n値オブジェクト\n\n", 1)
+	if err := os.WriteFile(edited, []byte(withSummary), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goals, summary, err := editReviewDocument(doc, review.ID)
+	if err != nil || goals != "設計を学ぶ" || summary != "値オブジェクト" {
+		t.Errorf("editReviewDocument() = %q, %q, %v", goals, summary, err)
+	}
+
+	// Without the review marker the edits are kept for another try
+	if err := os.WriteFile(edited, []byte("## Goals\n\nlost marker\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := editReviewDocument(doc, review.ID); err == nil || !strings.Contains(err.Error(), "kept in") {
+		t.Errorf("Expected the edited file to be kept, got %v", err)
+	}
+}
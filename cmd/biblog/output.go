@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// OutputFormat selects how command results are printed (the global -output flag).
+type OutputFormat string
+
+const (
+	OutputText  OutputFormat = "text"
+	OutputJSON  OutputFormat = "json"
+	OutputJSONL OutputFormat = "jsonl"
+	OutputCSV   OutputFormat = "csv"
+	OutputTSV   OutputFormat = "tsv"
+)
+
+// ParseOutputFormat parses the -output flag value.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(s)); f {
+	case OutputText, OutputJSON, OutputJSONL, OutputCSV, OutputTSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (expected text, json, jsonl, csv or tsv)", s)
+	}
+}
+
+// tabular is implemented by result views so they can be printed as CSV or TSV rows.
+// columns must return the same names for every value of a type.
+type tabular interface {
+	columns() []string
+	values() []string
+}
+
+// errorKind classifies errors reported in structured output modes.
+type errorKind string
+
+const (
+	errUsage      errorKind = "usage"
+	errValidation errorKind = "validation"
+	errNotFound   errorKind = "not_found"
+	errFailed     errorKind = "error"
+)
+
+// errorView is written to ErrW for failures when the output format is not text.
+type errorView struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Kind    errorKind `json:"kind"`
+	Command string    `json:"command,omitempty"`
+	Message string    `json:"message"`
+}
+
+// Output prints command results and errors in the selected format.
+// Text mode keeps the human-readable messages; every other format prints results to W
+// and errors as a JSON object on ErrW, so W stays parseable.
+type Output struct {
+	Format  OutputFormat
+	Command string // subcommand reported in errors
+	W       io.Writer
+	ErrW    io.Writer
+}
+
+// NewOutput creates an Output writing to standard output and standard error.
+func NewOutput(format OutputFormat) *Output {
+	return &Output{Format: format, W: os.Stdout, ErrW: os.Stderr}
+}
+
+// Structured reports whether results are machine-readable. Interactive prompts and
+// informational messages are suppressed in that case.
+func (o *Output) Structured() bool {
+	return o.Format != OutputText
+}
+
+// exitCode is the status a command stops with. Fail and Invalid stop the command by
+// panicking with it, so deferred cleanup such as closing the database still runs;
+// run recovers it and main exits with it.
+type exitCode int
+
+// exit stops the command with status code.
+func exit(code int) {
+	panic(exitCode(code))
+}
+
+// Fail reports an error and stops the command with status 1.
+func (o *Output) Fail(kind errorKind, format string, args ...any) {
+	o.writeError(kind, fmt.Sprintf(format, args...))
+	exit(1)
+}
+
+// Invalid reports a validation error, with the subcommand's flags in text mode, and stops the command with status 1.
+func (o *Output) Invalid(fs *flag.FlagSet, err error) {
+	o.writeError(errValidation, fmt.Sprintf("Validation error: %v", err))
+	if !o.Structured() && fs != nil {
+		fs.SetOutput(o.ErrW)
+		fs.PrintDefaults()
+	}
+	exit(1)
+}
+
+func (o *Output) writeError(kind errorKind, message string) {
+	if !o.Structured() {
+		fmt.Fprintln(o.W, message)
+		return
+	}
+	enc := json.NewEncoder(o.ErrW)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(errorView{Error: errorDetail{Kind: kind, Command: o.Command, Message: message}})
+}
+
+// emit prints a single result. text renders it in text mode.
+func emit[T tabular](o *Output, v T, text func(w io.Writer)) error {
+	if o.Format == OutputJSON {
+		return o.writeJSON(v, "  ")
+	}
+	return emitList(o, []T{v}, text)
+}
+
+// emitList prints a list of results: a JSON array, one JSON object per line,
+// or a header row followed by one row per result.
+func emitList[T tabular](o *Output, items []T, text func(w io.Writer)) error {
+	switch o.Format {
+	case OutputJSON:
+		if items == nil {
+			items = []T{} // [] rather than null
+		}
+		return o.writeJSON(items, "  ")
+	case OutputJSONL:
+		for _, item := range items {
+			if err := o.writeJSON(item, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputCSV, OutputTSV:
+		var zero T
+		rows := [][]string{zero.columns()}
+		for _, item := range items {
+			rows = append(rows, item.values())
+		}
+		if o.Format == OutputTSV {
+			return writeTSV(o.W, rows)
+		}
+		return csv.NewWriter(o.W).WriteAll(rows)
+	default:
+		text(o.W)
+		return nil
+	}
+}
+
+func (o *Output) writeJSON(v any, indent string) error {
+	enc := json.NewEncoder(o.W)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	return enc.Encode(v)
+}
+
+// tsvEscaper escapes the characters that would break a TSV row. Multi-line review text
+// therefore stays on one line.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func writeTSV(w io.Writer, rows [][]string) error {
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, v := range row {
+			escaped[i] = tsvEscaper.Replace(v)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(escaped, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
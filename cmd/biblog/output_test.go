@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBibliographyViews() []bibliographyView {
+	return newBibliographyViews([]*domain.Bibliography{
+		{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      "B56EE03DDD",
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Domain Driven Design",
+			Author:        "Eric Evans",
+			Publisher:     "Addison-Wesley",
+			ISBN:          domain.MustParseISBN("978-0321125217"),
+			PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      "B56SK24DMD",
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Tabs\tand, commas",
+			Author:        "杉本啓",
+			PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, s := range []string{"text", "json", "jsonl", "csv", "tsv", "JSON"} {
+		if _, err := ParseOutputFormat(s); err != nil {
+			t.Errorf("ParseOutputFormat(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format, got nil")
+	}
+}
+
+func TestEmitList(t *testing.T) {
+	views := testBibliographyViews()
+	text := func(w io.Writer) { _, _ = io.WriteString(w, "human\n") }
+
+	tests := []struct {
+		format OutputFormat
+		check  func(t *testing.T, out string)
+	}{
+		{OutputText, func(t *testing.T, out string) {
+			if out != "human\n" {
+				t.Errorf("got %q, want the text rendering", out)
+			}
+		}},
+		{OutputJSON, func(t *testing.T, out string) {
+			var got []map[string]any
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, out)
+			}
+			if len(got) != 2 || got[0]["bib_index"] != "B56EE03DDD" || got[0]["published_date"] != "2003-01-01" {
+				t.Errorf("unexpected JSON: %v", got)
+			}
+		}},
+		{OutputJSONL, func(t *testing.T, out string) {
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got %d lines, want 2:\n%s", len(lines), out)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+				t.Fatalf("invalid JSON line: %v", err)
+			}
+			if got["author"] != "杉本啓" {
+				t.Errorf("author = %v, want 杉本啓", got["author"])
+			}
+		}},
+		{OutputCSV, func(t *testing.T, out string) {
+			lines := strings.Split(out, "\n")
+			if lines[0] != "id,bib_index,code,type,title,author,publisher,isbn,published_date,contributors" {
+				t.Errorf("unexpected header %q", lines[0])
+			}
+			if !strings.Contains(out, `"Tabs`+"\t"+`and, commas"`) {
+				t.Errorf("expected quoted title in:\n%s", out)
+			}
+		}},
+		{OutputTSV, func(t *testing.T, out string) {
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("got %d lines, want 3:\n%s", len(lines), out)
+			}
+			if !strings.Contains(lines[2], `Tabs\tand, commas`) {
+				t.Errorf("expected escaped tab in %q", lines[2])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			out := &Output{Format: tt.format, W: &buf}
+			if err := emitList(out, views, text); err != nil {
+				t.Fatalf("emitList() error = %v", err)
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestEmitList_EmptyJSONIsArray(t *testing.T) {
+	var buf bytes.Buffer
+	out := &Output{Format: OutputJSON, W: &buf}
+	if err := emitList(out, []bibliographyView(nil), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("got %q, want []", got)
+	}
+}
+
+func TestEmit_JSONIsObject(t *testing.T) {
+	var buf bytes.Buffer
+	out := &Output{Format: OutputJSON, W: &buf}
+	class := &domain.Classification{ID: domain.NewClassificationID(), Code: "56", Name: "Technology"}
+	if err := emit(out, newClassificationView(class), nil); err != nil {
+		t.Fatal(err)
+	}
+	var got classificationView
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Code != "56" || got.Name != "Technology" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestBibliographyDetailView(t *testing.T) {
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "B56X", PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "a\nb"}
+	highlight := &domain.Highlight{ID: domain.NewHighlightID(), BookID: bib.ID, Text: "quote"}
+	v := newBibliographyDetailView(bibliographyDetail{Bibliography: bib, Reviews: []*domain.Review{review}, Highlights: []*domain.Highlight{highlight}})
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["bib_index"] != "B56X" || got["classification"] != nil || got["reading_status"] != "none" {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	if reviews, ok := got["reviews"].([]any); !ok || len(reviews) != 1 {
+		t.Errorf("expected one review in %s", data)
+	}
+	if highlights, ok := got["highlights"].([]any); !ok || len(highlights) != 1 {
+		t.Errorf("expected one highlight in %s", data)
+	} else if tags, ok := highlights[0].(map[string]any)["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("expected empty tags array in %s", data)
+	}
+	if len(v.columns()) != len(v.values()) {
+		t.Errorf("columns and values differ in length: %d vs %d", len(v.columns()), len(v.values()))
+	}
+}
+
+func TestOutput_WriteError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	out := &Output{Format: OutputJSON, Command: "show", W: &stdout, ErrW: &stderr}
+	out.writeError(errNotFound, "Bibliography X not found")
+
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing on stdout, got %q", stdout.String())
+	}
+	var got errorView
+	if err := json.Unmarshal(stderr.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := errorDetail{Kind: errNotFound, Command: "show", Message: "Bibliography X not found"}
+	if got.Error != want {
+		t.Errorf("got %+v, want %+v", got.Error, want)
+	}
+
+	stdout.Reset()
+	out.Format = OutputText
+	out.writeError(errNotFound, "Bibliography X not found")
+	if stdout.String() != "Bibliography X not found\n" {
+		t.Errorf("text error = %q", stdout.String())
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	// stopped runs parse and returns the exit status it stopped the command with, or -1.
+	stopped := func(parse func()) (code int) {
+		defer func() {
+			code = -1
+			if r := recover(); r != nil {
+				code = int(r.(exitCode))
+			}
+		}()
+		parse()
+		return
+	}
+
+	tests := []struct {
+		name     string
+		format   OutputFormat
+		args     []string
+		wantCode int
+		check    func(t *testing.T, stdout, stderr string)
+	}{
+		{"valid", OutputJSON, []string{"-limit", "5"}, -1, func(t *testing.T, stdout, stderr string) {
+			if stdout != "" || stderr != "" {
+				t.Errorf("expected no output, got %q and %q", stdout, stderr)
+			}
+		}},
+		{"unknown flag as JSON", OutputJSON, []string{"-bogus"}, 1, func(t *testing.T, stdout, stderr string) {
+			if stdout != "" {
+				t.Errorf("expected nothing on stdout, got %q", stdout)
+			}
+			var got errorView
+			if err := json.Unmarshal([]byte(stderr), &got); err != nil {
+				t.Fatalf("expected a JSON error, got %q: %v", stderr, err)
+			}
+			if got.Error.Kind != errValidation || !strings.Contains(got.Error.Message, "-bogus") {
+				t.Errorf("unexpected error: %+v", got.Error)
+			}
+		}},
+		{"bad value as text", OutputText, []string{"-limit", "many"}, 1, func(t *testing.T, stdout, stderr string) {
+			if !strings.HasPrefix(stdout, "Validation error:") || !strings.Contains(stderr, "-limit") {
+				t.Errorf("expected the error and the flags, got %q and %q", stdout, stderr)
+			}
+		}},
+		{"help", OutputJSON, []string{"-h"}, 0, func(t *testing.T, stdout, stderr string) {
+			if stdout != "" || !strings.Contains(stderr, "Usage of search:") {
+				t.Errorf("expected usage on stderr, got %q and %q", stdout, stderr)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			out := &Output{Format: tt.format, Command: "search", W: &stdout, ErrW: &stderr}
+			fs := newFlagSet("search")
+			fs.Int("limit", 20, "Maximum number of results")
+
+			if code := stopped(func() { parseFlags(out, fs, tt.args) }); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			tt.check(t, stdout.String(), stderr.String())
+		})
+	}
+}
@@ -0,0 +1,548 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
+	"bibliography_log/internal/textdiff"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bibliographyDetail is everything show reports about a bibliography.
+type bibliographyDetail struct {
+	Bibliography   *domain.Bibliography
+	Classification *domain.Classification // nil if it could not be resolved
+	Reviews        []*domain.Review
+	Reading        domain.ReadingHistory
+	Sessions       []*domain.ReadingSession
+	Highlights     []*domain.Highlight
+}
+
+// contributorsWorthListing reports whether the contributors say more than the author line:
+// a single author without an English name is not listed separately.
+func contributorsWorthListing(contributors []domain.Contributor) bool {
+	return len(contributors) > 1 || (len(contributors) == 1 && (contributors[0].Role != domain.RoleAuthor || contributors[0].NameEn != ""))
+}
+
+// renderBibliographyDetail prints every field of a bibliography, its reading history,
+// reading sessions, highlights and reviews. Highlights, Goals and Summary keep their
+// original line breaks; each line is indented under its heading.
+func renderBibliographyDetail(w io.Writer, d bibliographyDetail) {
+	bib := d.Bibliography
+	className := "(unknown)"
+	if d.Classification != nil {
+		className = fmt.Sprintf("%s %s", d.Classification.Code, d.Classification.Name)
+	}
+	status := d.Reading.Current().String()
+	if len(d.Reading) > 0 {
+		status += " since " + d.Reading[len(d.Reading)-1].ChangedAt.Format(time.DateOnly)
+	}
+
+	fmt.Fprintf(w, "BibIndex:       %s\n", bib.BibIndex)
+	fmt.Fprintf(w, "ID:             %s\n", bib.ID)
+	fmt.Fprintf(w, "Type:           %s\n", bib.Type)
+	fmt.Fprintf(w, "Code:           %s\n", bib.Code)
+	fmt.Fprintf(w, "Classification: %s\n", className)
+	fmt.Fprintf(w, "Title:          %s\n", bib.Title)
+	fmt.Fprintf(w, "Author:         %s\n", bib.Author)
+	if contributorsWorthListing(bib.Contributors) {
+		fmt.Fprintf(w, "Contributors:\n")
+		for _, c := range bib.Contributors {
+			name := c.Name
+			if c.NameEn != "" {
+				name += " (" + c.NameEn + ")"
+			}
+			fmt.Fprintf(w, "    %-12s %s\n", c.Role, name)
+		}
+	}
+	fmt.Fprintf(w, "Publisher:      %s\n", bib.Publisher)
+	if bib.ISBN.Valid() {
+		fmt.Fprintf(w, "ISBN:           %s\n", bib.ISBN)
+	} else {
+		fmt.Fprintf(w, "ISBN:           %s (invalid; see 'biblog migrate-isbn')\n", bib.ISBN)
+	}
+	fmt.Fprintf(w, "Published:      %s\n", bib.PublishedDate.Format(time.DateOnly))
+	fmt.Fprintf(w, "Status:         %s\n", status)
+	if len(d.Sessions) > 0 {
+		fmt.Fprintf(w, "Progress:       %s\n", formatProgress(domain.ComputeProgress(d.Sessions)))
+	}
+
+	if len(d.Reading) > 0 {
+		fmt.Fprintf(w, "\nReading History (%d):\n", len(d.Reading))
+		for _, change := range d.Reading {
+			fmt.Fprintf(w, "    %s  %s -> %s\n", change.ChangedAt.Format(time.RFC3339), change.From, change.To)
+		}
+	}
+
+	if len(d.Sessions) > 0 {
+		fmt.Fprintf(w, "\nReading Sessions (%d):\n", len(d.Sessions))
+		for _, session := range d.Sessions {
+			fmt.Fprintf(w, "    %s\n", formatSession(session))
+		}
+	}
+
+	if len(d.Highlights) > 0 {
+		fmt.Fprintf(w, "\nHighlights (%d):\n", len(d.Highlights))
+		for i, h := range d.Highlights {
+			fmt.Fprintf(w, "\n[%d] %s\n", i+1, highlightHeading(h, ""))
+			writeHighlightBody(w, h)
+		}
+	}
+
+	fmt.Fprintf(w, "\nReviews (%d):\n", len(d.Reviews))
+	for i, rev := range d.Reviews {
+		fmt.Fprintf(w, "\n[%d] %s\n", i+1, rev.ID)
+		fmt.Fprintf(w, "    Created: %s\n", rev.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "    Updated: %s\n", rev.UpdatedAt.Format(time.RFC3339))
+		fmt.Fprintln(w, "    Goals:")
+		writeIndented(w, rev.Goals, "      ")
+		fmt.Fprintln(w, "    Summary:")
+		writeIndented(w, rev.Summary, "      ")
+	}
+}
+
+// renderStatusChange prints the result of queue, start, finish or abandon.
+func renderStatusChange(w io.Writer, bib *domain.Bibliography, change *domain.ReadingStatusChange) {
+	fmt.Fprintf(w, "%s (%s): %s -> %s at %s\n", bib.BibIndex, bib.Title, change.From, change.To, change.ChangedAt.Format(time.RFC3339))
+}
+
+// renderHighlights prints the result of list-quotes. bibIndexes maps the bibliographies
+// of the highlights to their BibIndex.
+func renderHighlights(w io.Writer, highlights []*domain.Highlight, bibIndexes map[domain.BibliographyID]string) {
+	if len(highlights) == 0 {
+		fmt.Fprintln(w, "No highlights found")
+		return
+	}
+	for i, h := range highlights {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, highlightHeading(h, bibIndexes[h.BookID]))
+		writeHighlightBody(w, h)
+	}
+}
+
+// highlightHeading describes where a highlight comes from, e.g. "B56EE03DDD p. 13  #ddd".
+func highlightHeading(h *domain.Highlight, bibIndex string) string {
+	var parts []string
+	for _, part := range []string{bibIndex, h.Location} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	heading := strings.Join(parts, " ")
+	if heading == "" {
+		heading = "(no location)"
+	}
+	for _, tag := range h.Tags {
+		heading += "  #" + tag
+	}
+	return heading
+}
+
+// writeHighlightBody writes the text and comment of a highlight, indented like review fields.
+func writeHighlightBody(w io.Writer, h *domain.Highlight) {
+	fmt.Fprintf(w, "    Added: %s\n", h.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintln(w, "    Text:")
+	writeIndented(w, h.Text, "      ")
+	if h.Comment != "" {
+		fmt.Fprintln(w, "    Comment:")
+		writeIndented(w, h.Comment, "      ")
+	}
+}
+
+// renderSessionLogged prints the result of log-session with the progress it leads to.
+func renderSessionLogged(w io.Writer, bib *domain.Bibliography, session *domain.ReadingSession, progress domain.ReadingProgress) {
+	fmt.Fprintf(w, "Logged %s (%s): %s\n", bib.BibIndex, bib.Title, formatSession(session))
+	fmt.Fprintf(w, "Progress: %s\n", formatProgress(progress))
+}
+
+// formatSession describes a session on one line, e.g. "2025-11-01  p. 12-40  45m  Chapter 2".
+func formatSession(s *domain.ReadingSession) string {
+	parts := []string{s.Date.Format(time.DateOnly)}
+	switch {
+	case s.End == s.Start:
+	case s.Unit == domain.ProgressPercent:
+		parts = append(parts, fmt.Sprintf("%d-%d%%", s.Start, s.End))
+	default:
+		parts = append(parts, fmt.Sprintf("p. %d-%d", s.Start, s.End))
+	}
+	if s.Duration > 0 {
+		parts = append(parts, formatDuration(s.Duration))
+	}
+	if s.Note != "" {
+		parts = append(parts, s.Note)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formatProgress describes reading progress, e.g. "90/300 pages (210 left), 1h40m in 2
+// sessions; 22.5 pages/day, done around 2025-11-14".
+func formatProgress(p domain.ReadingProgress) string {
+	unit := " pages"
+	if p.Unit == domain.ProgressPercent {
+		unit = "%"
+	}
+
+	var b strings.Builder
+	switch {
+	case p.Unit == domain.ProgressPercent:
+		fmt.Fprintf(&b, "%d%%", p.Position)
+	case p.Total > 0:
+		fmt.Fprintf(&b, "%d/%d pages", p.Position, p.Total)
+	default:
+		fmt.Fprintf(&b, "page %d", p.Position)
+	}
+	if remaining := p.Remaining(); remaining > 0 {
+		if p.Unit == domain.ProgressPercent {
+			fmt.Fprintf(&b, " (%d%% left)", remaining)
+		} else {
+			fmt.Fprintf(&b, " (%d left)", remaining)
+		}
+	}
+	fmt.Fprintf(&b, ", %s in %d session(s)", formatDuration(p.TotalTime), p.Sessions)
+	if p.PacePerDay > 0 {
+		fmt.Fprintf(&b, "; %.1f%s/day", p.PacePerDay, unit)
+	}
+	if !p.EstimatedFinish.IsZero() {
+		fmt.Fprintf(&b, ", done around %s", p.EstimatedFinish.Format(time.DateOnly))
+	}
+	return b.String()
+}
+
+// formatDuration prints a duration without zero seconds, e.g. "1h40m" instead of "1h40m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// writeIndented writes text line by line with the given prefix, preserving blank lines.
+func writeIndented(w io.Writer, text, prefix string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
+
+// renderImportReport prints one line per imported item followed by totals.
+func renderImportReport(w io.Writer, results []service.ImportResult, dryRun bool) {
+	createdLabel := "Created"
+	if dryRun {
+		createdLabel = "Would create"
+	}
+
+	created := 0
+	for _, r := range results {
+		if r.Skipped() {
+			fmt.Fprintf(w, "Skipped %s (%s): %s\n", r.Item.Key, r.Item.Title, r.SkipReason)
+			continue
+		}
+		created++
+		fmt.Fprintf(w, "%s %s from %s (%s)\n", createdLabel, r.Bibliography.BibIndex, r.Item.Key, r.Item.Title)
+	}
+	fmt.Fprintf(w, "%s: %d, skipped: %d\n", createdLabel, created, len(results)-created)
+}
+
+// renderClassList prints one class per line in code order with its number of
+// bibliographies, counting those filed under its subclasses separately.
+func renderClassList(w io.Writer, roots []*domain.ClassNode) {
+	if len(roots) == 0 {
+		fmt.Fprintln(w, "No classifications found")
+		return
+	}
+	domain.Walk(roots, func(n *domain.ClassNode) {
+		fmt.Fprintf(w, "%-8s %s (%s)\n", n.Class.Code, n.Class.Name, formatClassCount(n))
+	})
+}
+
+// renderClassTree prints the classes with each subclass indented under its parent, e.g.
+//
+//	5 技術. 工学 (4, 1 directly)
+//	├── 50 技術. 工学 (0)
+//	└── 54 電気工学 (3)
+//	    └── 547 通信工学 (3, 2 directly)
+func renderClassTree(w io.Writer, roots []*domain.ClassNode) {
+	if len(roots) == 0 {
+		fmt.Fprintln(w, "No classifications found")
+		return
+	}
+	var walk func(nodes []*domain.ClassNode, indent string)
+	walk = func(nodes []*domain.ClassNode, indent string) {
+		for i, n := range nodes {
+			branch, next := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, next = "└── ", "    "
+			}
+			if n.Parent == nil {
+				branch, next = "", ""
+			}
+			fmt.Fprintf(w, "%s%s%s %s (%s)\n", indent, branch, n.Class.Code, n.Class.Name, formatClassCount(n))
+			walk(n.Children, indent+next)
+		}
+	}
+	walk(roots, "")
+}
+
+// formatClassCount returns the number of bibliographies under a class, e.g. "4" or
+// "4, 1 directly" if some are filed under its subclasses.
+func formatClassCount(n *domain.ClassNode) string {
+	if n.Count == n.Total {
+		return fmt.Sprintf("%d", n.Total)
+	}
+	return fmt.Sprintf("%d, %d directly", n.Total, n.Count)
+}
+
+// renderReclassifications prints how each bibliography changes, one per line, e.g.
+// "B56EE03DDD -> B548EE03DDD  B56 -> B548  Domain Driven Design".
+func renderReclassifications(w io.Writer, moves []service.Reclassification) {
+	for _, move := range moves {
+		bib := move.Bibliography
+		index := bib.BibIndex
+		if move.BibIndexChanged() {
+			index += " -> " + move.BibIndex
+		}
+		fmt.Fprintf(w, "    %s  %s -> %s  %s\n", index, bib.Code, move.Code, bib.Title)
+	}
+}
+
+// renderClassificationImport prints one line per seeded class followed by totals.
+func renderClassificationImport(w io.Writer, results []service.ClassificationImportResult, dryRun bool) {
+	createdLabel := "Created"
+	if dryRun {
+		createdLabel = "Would create"
+	}
+
+	created := 0
+	for _, r := range results {
+		if r.Skipped() {
+			fmt.Fprintf(w, "Skipped %s %s: %s\n", r.Item.Code, r.Item.Name, r.SkipReason)
+			continue
+		}
+		created++
+		fmt.Fprintf(w, "%s %s %s\n", createdLabel, r.Classification.Code, r.Classification.Name)
+	}
+	fmt.Fprintf(w, "%s: %d, skipped: %d\n", createdLabel, created, len(results)-created)
+}
+
+// renderKindleImport prints one line per book followed by totals.
+func renderKindleImport(w io.Writer, imports []kindleImport, dryRun bool) {
+	importedLabel := "Imported"
+	if dryRun {
+		importedLabel = "Would import"
+	}
+
+	imported, skipped := 0, 0
+	for _, imp := range imports {
+		if imp.Bibliography == nil {
+			skipped += len(imp.Book.Clippings)
+			fmt.Fprintf(w, "Skipped %s (%s): %d clipping(s), %s\n", imp.Book.Title, imp.Book.Author, len(imp.Book.Clippings), imp.SkipReason)
+			continue
+		}
+		n := 0
+		reasons := make(map[string]int)
+		for _, r := range imp.Results {
+			if r.Skipped() {
+				reasons[r.SkipReason]++
+				continue
+			}
+			n++
+		}
+		imported += n
+		skipped += len(imp.Results) - n
+		line := fmt.Sprintf("%s %d highlight(s) into %s (%s)", importedLabel, n, imp.Bibliography.BibIndex, imp.Bibliography.Title)
+		var details []string
+		for reason, count := range reasons {
+			details = append(details, fmt.Sprintf("%d %s", count, reason))
+		}
+		if len(details) > 0 {
+			sort.Strings(details)
+			line += "; skipped " + strings.Join(details, ", ")
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "%s: %d, skipped: %d\n", importedLabel, imported, skipped)
+}
+
+// renderSearchResults prints search results in the same form as list, with the matched fields.
+func renderSearchResults(w io.Writer, results []service.SearchResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No bibliographies found")
+		return
+	}
+	fmt.Fprintf(w, "Found %d result(s):\n", len(results))
+	for _, r := range results {
+		b := r.Bibliography
+		fmt.Fprintf(w, "[%s] %s by %s (BibIndex: %s)\n", b.Type, b.Title, b.Author, b.BibIndex)
+		fmt.Fprintf(w, "    matched %s (score %.2f)\n", strings.Join(r.Fields, ", "), r.Score)
+	}
+}
+
+// renderISBNMigrations prints one line per rewritten or invalid ISBN followed by totals.
+func renderISBNMigrations(w io.Writer, migrations []domain.ISBNMigration, dryRun bool) {
+	normalizedLabel, clearedLabel := "Normalized", "Cleared"
+	if dryRun {
+		normalizedLabel, clearedLabel = "Would normalize", "Would clear"
+	}
+
+	normalized, cleared, invalid := 0, 0, 0
+	for _, m := range migrations {
+		switch {
+		case m.Err == nil:
+			normalized++
+			fmt.Fprintf(w, "%s %s: %q -> %s\n", normalizedLabel, m.BibIndex, m.Old, m.New)
+		case m.Cleared:
+			cleared++
+			fmt.Fprintf(w, "%s %s: %v\n", clearedLabel, m.BibIndex, m.Err)
+		default:
+			invalid++
+			fmt.Fprintf(w, "Invalid %s (%s): %v\n", m.BibIndex, m.BibliographyID, m.Err)
+		}
+	}
+	fmt.Fprintf(w, "%s: %d, %s: %d, invalid: %d\n", normalizedLabel, normalized, strings.ToLower(clearedLabel), cleared, invalid)
+	if invalid > 0 {
+		fmt.Fprintln(w, "Fix invalid ISBNs in the data file, or remove them with -clear-invalid")
+	}
+}
+
+// renderReviewHistory prints one line per revision: its number, when and by whom it was
+// made, what made it and the fields changed from the revision before.
+func renderReviewHistory(w io.Writer, id domain.ReviewID, history domain.ReviewHistory) {
+	fmt.Fprintf(w, "Review %s (%d revisions):\n", id, len(history))
+	for _, r := range history {
+		by := r.ChangedBy
+		if by == "" {
+			by = "-"
+		}
+		action := string(r.Action)
+		if r.Action == domain.RevisionReverted {
+			action = fmt.Sprintf("reverted to %d", r.RevertedTo)
+		}
+		changed := strings.Join(r.ChangedFields(history.Previous(r)), ", ")
+		if changed == "" {
+			changed = "no changes"
+		}
+		fmt.Fprintf(w, "  %3d  %s  %s  %s: %s\n", r.Number, r.CreatedAt.Format(time.RFC3339), by, action, changed)
+	}
+}
+
+// renderReviewDiff prints the changed fields of a diff-review, one line per diff line:
+// "- " for deleted lines, "+ " for inserted ones and "  " for context. Within a replaced
+// line the changed characters are marked as [-deleted-] and {+inserted+}.
+func renderReviewDiff(w io.Writer, d *service.ReviewDiff) {
+	from := "empty review"
+	if d.From != nil {
+		from = fmt.Sprintf("revision %d", d.From.Number)
+	}
+	fmt.Fprintf(w, "Review %s: %s -> revision %d (%s)\n", d.To.ReviewID, from, d.To.Number, d.To.CreatedAt.Format(time.RFC3339))
+	renderFieldDiffs(w, d.Goals, d.Summary)
+}
+
+// renderFieldDiffs prints the diffs of the Goals and Summary of a review as
+// renderReviewDiff does.
+func renderFieldDiffs(w io.Writer, goals, summary []textdiff.Line) {
+	for _, field := range []struct {
+		name  string
+		lines []textdiff.Line
+	}{{"Goals", goals}, {"Summary", summary}} {
+		if !textdiff.Changed(field.lines) {
+			fmt.Fprintf(w, "\n%s: unchanged\n", field.name)
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", field.name)
+		for _, line := range field.lines {
+			fmt.Fprintf(w, "%s%s\n", diffPrefix(line.Op), formatDiffLine(line))
+		}
+	}
+}
+
+func diffPrefix(op textdiff.Op) string {
+	switch op {
+	case textdiff.Delete:
+		return "- "
+	case textdiff.Insert:
+		return "+ "
+	default:
+		return "  "
+	}
+}
+
+// formatDiffLine returns the text of a line with its changed characters marked.
+func formatDiffLine(line textdiff.Line) string {
+	if line.Spans == nil {
+		return line.Text
+	}
+	start, end := "{+", "+}"
+	if line.Op == textdiff.Delete {
+		start, end = "[-", "-]"
+	}
+	var b strings.Builder
+	for _, span := range line.Spans {
+		if span.Changed {
+			b.WriteString(start + span.Text + end)
+		} else {
+			b.WriteString(span.Text)
+		}
+	}
+	return b.String()
+}
+
+// renderNoteExports prints the result of export-notes. Unchanged notes are only counted.
+func renderNoteExports(w io.Writer, exports []noteExport, dryRun bool) {
+	labels := map[string]string{noteCreated: "Created", noteUpdated: "Updated", noteSkipped: "Skipped"}
+	if dryRun {
+		labels[noteCreated], labels[noteUpdated] = "Would create", "Would update"
+	}
+	counts := make(map[string]int)
+	for _, e := range exports {
+		counts[e.Status]++
+		if e.Status == noteUnchanged {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s", labels[e.Status], e.File)
+		if e.SkipReason != "" {
+			fmt.Fprintf(w, ": %s", e.SkipReason)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d created, %d updated, %d unchanged, %d skipped\n",
+		counts[noteCreated], counts[noteUpdated], counts[noteUnchanged], counts[noteSkipped])
+}
+
+// renderNoteSyncs prints the result of sync-notes with the diff of each review pushed.
+// Unchanged notes are only counted.
+func renderNoteSyncs(w io.Writer, results []noteSync, dryRun bool) {
+	labels := map[string]string{syncPushed: "Pushed", syncConflict: "Conflict in", syncInvalid: "Invalid review in", syncSkipped: "Skipped"}
+	if dryRun {
+		labels[syncPushed] = "Would push"
+	}
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+		if r.Status == syncUnchanged {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s", labels[r.Status], r.File)
+		if r.ReviewID != "" {
+			fmt.Fprintf(w, " (review %s)", r.ReviewID)
+		}
+		if r.Reason != "" {
+			fmt.Fprintf(w, ": %s", r.Reason)
+		}
+		fmt.Fprintln(w)
+		if r.Status == syncPushed {
+			renderFieldDiffs(w, r.Goals, r.Summary)
+			fmt.Fprintln(w)
+		}
+	}
+	fmt.Fprintf(w, "%d pushed, %d conflicts, %d invalid, %d unchanged, %d skipped\n",
+		counts[syncPushed], counts[syncConflict], counts[syncInvalid], counts[syncUnchanged], counts[syncSkipped])
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
+	"bibliography_log/internal/textdiff"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderBibliographyDetail(t *testing.T) {
+	bib := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56SK24DMD",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "データモデリングでドメインを駆動する",
+		Author:        "杉本啓",
+		Contributors:  []domain.Contributor{{Name: "杉本啓", Role: domain.RoleAuthor}, {Name: "和田卓人", Role: domain.RoleEditor, NameEn: "Takuto Wada"}},
+		Publisher:     "技術評論社",
+		PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	class := &domain.Classification{Code: "56", Name: "Technology"}
+	reviews := []*domain.Review{{
+		ID:        domain.NewReviewID(),
+		BookID:    bib.ID,
+		Goals:     "一行目\n二行目",
+		Summary:   "段落1\n\n段落2",
+		CreatedAt: time.Date(2025, 11, 23, 7, 49, 3, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 11, 23, 7, 51, 21, 0, time.UTC),
+	}}
+
+	var buf bytes.Buffer
+	reading := domain.ReadingHistory{
+		{BookID: bib.ID, To: domain.ReadingStatusToRead, ChangedAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{BookID: bib.ID, From: domain.ReadingStatusToRead, To: domain.ReadingStatusReading, ChangedAt: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	sessions := []*domain.ReadingSession{
+		{BookID: bib.ID, Date: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC), Duration: 40 * time.Minute, Unit: domain.ProgressPages, Start: 0, End: 30, TotalPages: 300},
+		{BookID: bib.ID, Date: time.Date(2025, 11, 23, 0, 0, 0, 0, time.UTC), Duration: time.Hour, Unit: domain.ProgressPages, Start: 30, End: 90, Note: "第3章"},
+	}
+	highlights := []*domain.Highlight{{
+		BookID:    bib.ID,
+		Text:      "データモデルは\n  事実を記録する",
+		Location:  "p. 42",
+		Tags:      []string{"modeling"},
+		CreatedAt: time.Date(2025, 11, 22, 8, 0, 0, 0, time.UTC),
+	}}
+	renderBibliographyDetail(&buf, bibliographyDetail{Bibliography: bib, Classification: class, Reviews: reviews, Reading: reading, Sessions: sessions, Highlights: highlights})
+	out := buf.String()
+
+	for _, want := range []string{
+		"BibIndex:       B56SK24DMD\n",
+		"Classification: 56 Technology\n",
+		"Contributors:\n    author       杉本啓\n    editor       和田卓人 (Takuto Wada)\n",
+		"Status:         reading since 2025-11-20\n",
+		"Reading History (2):\n    2025-11-01T00:00:00Z  none -> to-read\n",
+		"Progress:       90/300 pages (210 left), 1h40m in 2 session(s); 22.5 pages/day, done around 2025-12-03\n",
+		"Reading Sessions (2):\n    2025-11-20  p. 0-30  40m\n    2025-11-23  p. 30-90  1h  第3章\n",
+		"Highlights (1):\n\n[1] p. 42  #modeling\n    Added: 2025-11-22T08:00:00Z\n    Text:\n      データモデルは\n        事実を記録する\n",
+		"Reviews (1):\n",
+		"    Created: 2025-11-23T07:49:03Z\n",
+		"      一行目\n      二行目\n",
+		"      段落1\n      \n      段落2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderBibliographyDetail_UnknownClassification(t *testing.T) {
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), Code: "B99"}
+
+	var buf bytes.Buffer
+	renderBibliographyDetail(&buf, bibliographyDetail{Bibliography: bib})
+
+	if !strings.Contains(buf.String(), "Classification: (unknown)\n") {
+		t.Errorf("Expected unknown classification, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Status:         none\n") || strings.Contains(buf.String(), "Reading History") {
+		t.Errorf("Expected untracked status without history, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Reviews (0):\n") {
+		t.Errorf("Expected zero reviews, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderClassTree(t *testing.T) {
+	classes := []*domain.Classification{
+		{Code: "5", Name: "技術"}, {Code: "50", Name: "工学"}, {Code: "54", Name: "電気工学"},
+		{Code: "547", Name: "通信工学"}, {Code: "548", Name: "情報工学"}, {Code: "16", Name: "Philosophy"},
+	}
+	roots := domain.BuildClassTree(classes, map[domain.ClassCode]int{"5": 1, "547": 2, "548": 1})
+
+	var buf bytes.Buffer
+	renderClassTree(&buf, roots)
+	want := "16 Philosophy (0)\n" +
+		"5 技術 (4, 1 directly)\n" +
+		"├── 50 工学 (0)\n" +
+		"└── 54 電気工学 (3, 0 directly)\n" +
+		"    ├── 547 通信工学 (2)\n" +
+		"    └── 548 情報工学 (1)\n"
+	if buf.String() != want {
+		t.Errorf("renderClassTree() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	renderClassList(&buf, roots)
+	if !strings.Contains(buf.String(), "547      通信工学 (2)\n") {
+		t.Errorf("Expected flat list with counts, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderReviewDiff(t *testing.T) {
+	id := domain.NewReviewID()
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	from := &domain.ReviewRevision{ReviewID: id, Number: 1, Goals: "目標", Summary: "一行目\n事業の成長を論じる"}
+	to := &domain.ReviewRevision{ReviewID: id, Number: 2, Goals: "目標", Summary: "一行目\n事業の急成長を論じる", CreatedAt: at}
+	diff := &service.ReviewDiff{
+		From:    from,
+		To:      to,
+		Goals:   textdiff.Lines(from.Goals, to.Goals),
+		Summary: textdiff.Lines(from.Summary, to.Summary),
+	}
+
+	var buf bytes.Buffer
+	renderReviewDiff(&buf, diff)
+	want := "Review " + id.String() + ": revision 1 -> revision 2 (2026-10-01T09:00:00Z)\n" +
+		"\nGoals: unchanged\n" +
+		"\nSummary:\n" +
+		"  一行目\n" +
+		"- 事業の成長を論じる\n" +
+		"+ 事業の{+急+}成長を論じる\n"
+	if buf.String() != want {
+		t.Errorf("renderReviewDiff() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRenderReviewHistory(t *testing.T) {
+	id := domain.NewReviewID()
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	history := domain.ReviewHistory{
+		{ReviewID: id, Number: 1, Goals: "a", Action: domain.RevisionCreated, ChangedBy: "alice", CreatedAt: at},
+		{ReviewID: id, Number: 2, Goals: "a", Summary: "b", Action: domain.RevisionUpdated, CreatedAt: at},
+		{ReviewID: id, Number: 3, Goals: "a", Action: domain.RevisionReverted, RevertedTo: 1, ChangedBy: "alice", CreatedAt: at},
+	}
+
+	var buf bytes.Buffer
+	renderReviewHistory(&buf, id, history)
+	for _, want := range []string{
+		"(3 revisions)",
+		"  1  2026-10-01T09:00:00Z  alice  created: goals\n",
+		"  2  2026-10-01T09:00:00Z  -  updated: summary\n",
+		"  3  2026-10-01T09:00:00Z  alice  reverted to 1: summary\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -2,19 +2,23 @@ package main
 
 import (
 	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 )
 
-// AddClassificationRequest holds arguments for adding a classification.
+// AddClassificationRequest holds arguments for adding a classification. The JSON names
+// are those of the 'biblog serve' API.
 type AddClassificationRequest struct {
-	Code int
-	Name string
+	Code string `json:"code"` // decimal code such as 5, 56 or 547.48
+	Name string `json:"name"`
 }
 
 func (r *AddClassificationRequest) PromptMissing() {
-	if r.Code == 0 {
-		r.Code = promptInt("Classification Code Number", true)
+	if r.Code == "" {
+		r.Code = promptString("Classification Code", true)
 	}
 	if r.Name == "" {
 		r.Name = promptString("Classification Name", true)
@@ -22,34 +26,166 @@ func (r *AddClassificationRequest) PromptMissing() {
 }
 
 func (r *AddClassificationRequest) Validate() error {
-	if r.Code == 0 {
-		return fmt.Errorf("classification code is required")
+	if _, err := domain.ParseClassCode(r.Code); err != nil {
+		return err
+	}
+	if r.Name == "" {
+		return fmt.Errorf("classification name is required")
+	}
+	return nil
+}
+
+// ListClassificationsRequest holds arguments for listing classifications.
+type ListClassificationsRequest struct {
+	Tree bool // indent subclasses under their parents
+}
+
+// RenameClassificationRequest holds arguments for renaming a classification.
+type RenameClassificationRequest struct {
+	Code string
+	Name string
+}
+
+func (r *RenameClassificationRequest) PromptMissing() {
+	if r.Code == "" {
+		r.Code = promptString("Classification Code", true)
 	}
 	if r.Name == "" {
+		r.Name = promptString("New Classification Name", true)
+	}
+}
+
+func (r *RenameClassificationRequest) Validate() error {
+	if _, err := domain.ParseClassCode(r.Code); err != nil {
+		return err
+	}
+	if strings.TrimSpace(r.Name) == "" {
 		return fmt.Errorf("classification name is required")
 	}
 	return nil
 }
 
-// AddBibliographyRequest holds arguments for adding a bibliography.
+// RenumberClassificationRequest holds arguments for changing the code of a classification.
+type RenumberClassificationRequest struct {
+	Code            string
+	To              string
+	RewriteBibIndex bool // replace the old Code at the start of BibIndexes
+	DryRun          bool
+	Yes             bool
+}
+
+func (r *RenumberClassificationRequest) PromptMissing() {
+	if r.Code == "" {
+		r.Code = promptString("Classification Code", true)
+	}
+	if r.To == "" {
+		r.To = promptString("New Classification Code", true)
+	}
+}
+
+func (r *RenumberClassificationRequest) Validate() error {
+	from, err := domain.ParseClassCode(r.Code)
+	if err != nil {
+		return err
+	}
+	to, err := domain.ParseClassCode(r.To)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("the new code must differ from %s", from)
+	}
+	return nil
+}
+
+// DeleteClassificationRequest holds arguments for deleting a classification.
+type DeleteClassificationRequest struct {
+	Code            string
+	ReassignTo      string // code of the classification bibliographies are moved to
+	RewriteBibIndex bool
+	DryRun          bool
+	Yes             bool
+}
+
+func (r *DeleteClassificationRequest) PromptMissing() {
+	if r.Code == "" {
+		r.Code = promptString("Classification Code", true)
+	}
+}
+
+func (r *DeleteClassificationRequest) Validate() error {
+	if _, err := domain.ParseClassCode(r.Code); err != nil {
+		return err
+	}
+	if r.ReassignTo != "" {
+		if _, err := domain.ParseClassCode(r.ReassignTo); err != nil {
+			return fmt.Errorf("-reassign-to: %w", err)
+		}
+	} else if r.RewriteBibIndex {
+		return fmt.Errorf("-bib-index requires -reassign-to")
+	}
+	return nil
+}
+
+// SeedClassificationsRequest holds arguments for adding the classes of a bundled scheme.
+type SeedClassificationsRequest struct {
+	Scheme string
+	Depth  int // 1 for main classes only, 2 to include divisions
+	DryRun bool
+}
+
+func (r *SeedClassificationsRequest) Validate() error {
+	if r.Scheme != "ndc" {
+		return fmt.Errorf("unsupported classification scheme %q (expected ndc)", r.Scheme)
+	}
+	if r.Depth < 1 || r.Depth > 2 {
+		return fmt.Errorf("depth must be 1 (main classes) or 2 (divisions)")
+	}
+	return nil
+}
+
+// AddBibliographyRequest holds arguments for adding a bibliography. The JSON names are
+// those of the 'biblog serve' API, which does not look books up.
 type AddBibliographyRequest struct {
-	Title     string
-	Author    string
-	Publisher string
-	Type      string
-	ClassCode int
-	Year      int
-	ISBN      string
-	TitleEn   string
-	AuthorEn  string
-	BibIndex  string
+	Title        string   `json:"title"`
+	Author       string   `json:"author"`
+	Contributors []string `json:"contributors"` // "[role:]Name[=NameEn]", see domain.ParseContributor
+	Publisher    string   `json:"publisher"`
+	Type         string   `json:"type"`
+	ClassCode    string   `json:"class"`
+	Year         int      `json:"year"`
+	ISBN         string   `json:"isbn"`
+	TitleEn      string   `json:"title_en"`
+	AuthorEn     string   `json:"author_en"`
+	BibIndex     string   `json:"bib_index"`
+	Lookup       bool     `json:"-"` // prefill from the ISBN's catalogue entry
+}
+
+// ApplyMetadata fills the fields that were not given on the command line from a
+// catalogue entry. Books found by ISBN default to type "Book".
+func (r *AddBibliographyRequest) ApplyMetadata(meta *domain.BookMetadata) {
+	if r.Title == "" {
+		r.Title = meta.Title
+	}
+	if r.Author == "" {
+		r.Author = meta.Author
+	}
+	if r.Publisher == "" {
+		r.Publisher = meta.Publisher
+	}
+	if r.Year == 0 {
+		r.Year = meta.Year
+	}
+	if r.Type == "" {
+		r.Type = "Book"
+	}
 }
 
 func (r *AddBibliographyRequest) PromptMissing() {
 	if r.Title == "" {
 		r.Title = promptString("Title", true)
 	}
-	if r.Author == "" {
+	if r.Author == "" && len(r.Contributors) == 0 {
 		r.Author = promptString("Author", true)
 	}
 	if r.Publisher == "" {
@@ -58,8 +194,8 @@ func (r *AddBibliographyRequest) PromptMissing() {
 	if r.Type == "" {
 		r.Type = promptString("Type", true)
 	}
-	if r.ClassCode == 0 {
-		r.ClassCode = promptInt("Classification Code Number", true)
+	if r.ClassCode == "" {
+		r.ClassCode = promptString("Classification Code", true)
 	}
 	if r.Year == 0 {
 		r.Year = promptInt("Published Year", true)
@@ -76,18 +212,24 @@ func (r *AddBibliographyRequest) Validate() error {
 	if r.Title == "" {
 		return fmt.Errorf("title is required")
 	}
-	if r.Author == "" {
-		return fmt.Errorf("author is required")
+	if r.Author == "" && len(r.Contributors) == 0 {
+		return fmt.Errorf("author or contributor is required")
+	}
+	if _, err := parseContributors(r.Contributors); err != nil {
+		return err
 	}
 	if r.Type == "" {
 		return fmt.Errorf("type is required")
 	}
-	if r.ClassCode == 0 {
-		return fmt.Errorf("classification code is required")
+	if _, err := domain.ParseClassCode(r.ClassCode); err != nil {
+		return err
 	}
 	if r.Year == 0 {
 		return fmt.Errorf("published year is required")
 	}
+	if _, err := domain.ParseISBN(r.ISBN); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -95,6 +237,25 @@ func (r *AddBibliographyRequest) ToPublishedDate() time.Time {
 	return time.Date(r.Year, 1, 1, 0, 0, 0, 0, time.UTC)
 }
 
+// ToContributors parses the -contributor flags. Validate has checked them.
+func (r *AddBibliographyRequest) ToContributors() []domain.Contributor {
+	contributors, _ := parseContributors(r.Contributors)
+	return contributors
+}
+
+// parseContributors parses contributors given as "[role:]Name[=NameEn]".
+func parseContributors(specs []string) ([]domain.Contributor, error) {
+	var contributors []domain.Contributor
+	for _, spec := range specs {
+		c, err := domain.ParseContributor(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contributor %q: %w", spec, err)
+		}
+		contributors = append(contributors, c)
+	}
+	return contributors, nil
+}
+
 // AddReviewRequest holds arguments for adding a review.
 type AddReviewRequest struct {
 	BibIndex string
@@ -156,13 +317,377 @@ func (r *UpdateReviewRequest) ParseID() (domain.ReviewID, error) {
 	return domain.ParseReviewID(r.ReviewIDStr)
 }
 
+// ReviewHistoryRequest holds arguments for listing the revisions of a review.
+type ReviewHistoryRequest struct {
+	ReviewIDStr string
+}
+
+func (r *ReviewHistoryRequest) PromptMissing() {
+	if r.ReviewIDStr == "" {
+		r.ReviewIDStr = promptString("Review UUID", true)
+	}
+}
+
+func (r *ReviewHistoryRequest) Validate() error {
+	if r.ReviewIDStr == "" {
+		return fmt.Errorf("a review UUID is required")
+	}
+	return nil
+}
+
+func (r *ReviewHistoryRequest) ParseID() (domain.ReviewID, error) {
+	return domain.ParseReviewID(r.ReviewIDStr)
+}
+
+// DiffReviewRequest holds arguments for comparing two revisions of a review.
+// Zero revisions select the defaults: To the latest, From the one before To.
+type DiffReviewRequest struct {
+	ReviewHistoryRequest
+	From int
+	To   int
+}
+
+func (r *DiffReviewRequest) Validate() error {
+	if err := r.ReviewHistoryRequest.Validate(); err != nil {
+		return err
+	}
+	if r.From < 0 || r.To < 0 {
+		return fmt.Errorf("revision numbers must be positive")
+	}
+	return nil
+}
+
+// RevertReviewRequest holds arguments for restoring an older revision of a review.
+type RevertReviewRequest struct {
+	ReviewHistoryRequest
+	To  int
+	Yes bool
+}
+
+func (r *RevertReviewRequest) PromptMissing() {
+	r.ReviewHistoryRequest.PromptMissing()
+	if r.To == 0 {
+		r.To = promptInt("Revision to restore", true)
+	}
+}
+
+func (r *RevertReviewRequest) Validate() error {
+	if err := r.ReviewHistoryRequest.Validate(); err != nil {
+		return err
+	}
+	if r.To < 1 {
+		return fmt.Errorf("-to is required: the number of the revision to restore (see review-history)")
+	}
+	return nil
+}
+
+// EditReviewRequest holds arguments for editing a review in $EDITOR.
+type EditReviewRequest struct {
+	ReviewHistoryRequest
+	Yes bool
+}
+
 // ListBibliographiesRequest holds arguments for listing bibliographies.
+// Zero/empty filter fields select everything.
 type ListBibliographiesRequest struct {
+	Limit     int
+	Offset    int
+	Type      string
+	ClassCode string
+	Recursive bool // include the subclasses of ClassCode
+	YearFrom  int
+	YearTo    int
+	Author    string
+	Publisher string
+	HasReview string // "yes", "no" or empty
+	HasISBN   string // "yes", "no" or empty
+	Status    string // reading status, "none" or empty
+	Sort      string
+	Desc      bool
+}
+
+func (r *ListBibliographiesRequest) Validate() error {
+	if r.Limit < 0 {
+		return fmt.Errorf("limit must be non-negative")
+	}
+	if r.Offset < 0 {
+		return fmt.Errorf("offset must be non-negative")
+	}
+	if r.Recursive && r.ClassCode == "" {
+		return fmt.Errorf("-recursive requires -class")
+	}
+	if r.YearFrom < 0 || r.YearTo < 0 {
+		return fmt.Errorf("years must be positive")
+	}
+	if r.YearFrom != 0 && r.YearTo != 0 && r.YearFrom > r.YearTo {
+		return fmt.Errorf("year-from (%d) must not be after year-to (%d)", r.YearFrom, r.YearTo)
+	}
+	if _, err := r.Query(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Query maps the list flags to a domain.BibliographyQuery.
+func (r *ListBibliographiesRequest) Query() (domain.BibliographyQuery, error) {
+	sortKey, err := domain.ParseBibliographySort(r.Sort)
+	if err != nil {
+		return domain.BibliographyQuery{}, err
+	}
+	hasReview, err := parseYesNo("has-review", r.HasReview)
+	if err != nil {
+		return domain.BibliographyQuery{}, err
+	}
+	hasISBN, err := parseYesNo("has-isbn", r.HasISBN)
+	if err != nil {
+		return domain.BibliographyQuery{}, err
+	}
+	query := domain.BibliographyQuery{
+		Type:       strings.TrimSpace(r.Type),
+		YearFrom:   r.YearFrom,
+		YearTo:     r.YearTo,
+		Author:     strings.TrimSpace(r.Author),
+		Publisher:  strings.TrimSpace(r.Publisher),
+		HasReview:  hasReview,
+		HasISBN:    hasISBN,
+		Sort:       sortKey,
+		Descending: r.Desc,
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+	}
+	if r.ClassCode != "" {
+		classCode, err := domain.ParseClassCode(r.ClassCode)
+		if err != nil {
+			return domain.BibliographyQuery{}, err
+		}
+		query.ClassCode = &classCode
+		query.ClassRecursive = r.Recursive
+	}
+	if r.Status != "" {
+		status, err := domain.ParseReadingStatus(r.Status)
+		if err != nil {
+			return domain.BibliographyQuery{}, err
+		}
+		query.Status = &status
+	}
+	return query, nil
+}
+
+// parseYesNo parses an optional yes/no flag value; empty means "either".
+func parseYesNo(name, value string) (*bool, error) {
+	var b bool
+	switch strings.ToLower(value) {
+	case "":
+		return nil, nil
+	case "yes", "y", "true":
+		b = true
+	case "no", "n", "false":
+		b = false
+	default:
+		return nil, fmt.Errorf("%s must be yes or no (got %q)", name, value)
+	}
+	return &b, nil
+}
+
+// ShowBibliographyRequest holds arguments for showing a single bibliography.
+// Ref is either a BibIndex or a bibliography UUID.
+type ShowBibliographyRequest struct {
+	Ref string
+}
+
+func (r *ShowBibliographyRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+}
+
+func (r *ShowBibliographyRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	return nil
+}
+
+// ChangeStatusRequest holds arguments for queue, start, finish and abandon.
+// Ref is either a BibIndex or a bibliography UUID.
+type ChangeStatusRequest struct {
+	Ref      string
+	At       string // YYYY-MM-DD or RFC3339; empty for now
+	Wishlist bool   // queue only
+}
+
+func (r *ChangeStatusRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+}
+
+func (r *ChangeStatusRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	if _, err := r.Time(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Time parses At. Dates without a time are taken as midnight UTC; the zero time means now.
+func (r *ChangeStatusRequest) Time() (time.Time, error) {
+	if r.At == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, r.At); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, r.At)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("at must be YYYY-MM-DD or RFC3339 (got %q)", r.At)
+	}
+	return t, nil
+}
+
+// LogSessionRequest holds arguments for logging a reading session.
+// Ref is either a BibIndex or a bibliography UUID. From and To are pages, or
+// percentages if Percent is set; a From of -1 continues from the previous session and
+// a To of -1 records only the time spent.
+type LogSessionRequest struct {
+	Ref      string
+	Date     string // YYYY-MM-DD; empty for today
+	Duration string // e.g. 45m or 1h30m
+	From     int
+	To       int
+	Percent  bool
+	Total    int // page count of the book, if known
+	Note     string
+}
+
+func (r *LogSessionRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+	if r.Duration == "" && r.To == service.TimeOnly {
+		r.Duration = promptString("Duration (e.g. 45m)", false)
+		if to := promptInt("Reached page (or percent with -percent)", false); to > 0 {
+			r.To = to
+		}
+	}
+}
+
+func (r *LogSessionRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	if _, err := r.SessionDate(); err != nil {
+		return err
+	}
+	duration, err := r.SessionDuration()
+	if err != nil {
+		return err
+	}
+	if duration == 0 && r.To == service.TimeOnly {
+		return fmt.Errorf("duration or to is required")
+	}
+	if r.From < service.ContinueFromLast || r.To < service.TimeOnly {
+		return fmt.Errorf("from and to must not be negative")
+	}
+	if r.Total < 0 {
+		return fmt.Errorf("total must not be negative")
+	}
+	return nil
+}
+
+// SessionDate parses Date; the zero time means today.
+func (r *LogSessionRequest) SessionDate() (time.Time, error) {
+	if r.Date == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, r.Date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be YYYY-MM-DD (got %q)", r.Date)
+	}
+	return t, nil
+}
+
+// SessionDuration parses Duration; empty means no duration was recorded.
+func (r *LogSessionRequest) SessionDuration() (time.Duration, error) {
+	if r.Duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.Duration)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("duration must be a positive duration such as 45m or 1h30m (got %q)", r.Duration)
+	}
+	return d, nil
+}
+
+// Unit returns the progress unit of From and To.
+func (r *LogSessionRequest) Unit() domain.ProgressUnit {
+	if r.Percent {
+		return domain.ProgressPercent
+	}
+	return domain.ProgressPages
+}
+
+// AddQuoteRequest holds arguments for adding a highlight.
+// Ref is either a BibIndex or a bibliography UUID. Tags are comma-separated.
+type AddQuoteRequest struct {
+	Ref      string
+	Text     string
+	Location string
+	Comment  string
+	Tags     string
+}
+
+func (r *AddQuoteRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+	if r.Text == "" {
+		r.Text = promptString("Text", true)
+		if r.Location == "" {
+			r.Location = promptString("Page or location", false)
+		}
+		if r.Comment == "" {
+			r.Comment = promptString("Comment", false)
+		}
+		if r.Tags == "" {
+			r.Tags = promptString("Tags (comma-separated)", false)
+		}
+	}
+}
+
+func (r *AddQuoteRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	if strings.TrimSpace(r.Text) == "" {
+		return fmt.Errorf("text is required")
+	}
+	if _, err := domain.NormalizeTags(r.TagList()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TagList splits Tags at commas.
+func (r *AddQuoteRequest) TagList() []string {
+	if r.Tags == "" {
+		return nil
+	}
+	return strings.Split(r.Tags, ",")
+}
+
+// ListQuotesRequest holds arguments for listing highlights. Ref, if given, is either
+// a BibIndex or a bibliography UUID.
+type ListQuotesRequest struct {
+	Ref    string
+	Tag    string
 	Limit  int
 	Offset int
 }
 
-func (r *ListBibliographiesRequest) Validate() error {
+func (r *ListQuotesRequest) Validate() error {
 	if r.Limit < 0 {
 		return fmt.Errorf("limit must be non-negative")
 	}
@@ -171,3 +696,294 @@ func (r *ListBibliographiesRequest) Validate() error {
 	}
 	return nil
 }
+
+// UpdateBibliographyRequest holds arguments for updating a bibliography.
+// Ref is either a BibIndex or a bibliography UUID. Empty/zero fields are left unchanged.
+type UpdateBibliographyRequest struct {
+	Ref          string
+	Title        string
+	Author       string
+	Contributors []string // replace all contributors; "[role:]Name[=NameEn]"
+	Publisher    string
+	Type         string
+	ClassCode    string
+	Year         int
+	ISBN         string
+	TitleEn      string
+	AuthorEn     string
+	BibIndex     string
+	RegenerateID bool
+}
+
+func (r *UpdateBibliographyRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+	// If no field to update is provided, prompt for them
+	if !r.hasChanges() {
+		r.Title = promptString("New title", false)
+		r.Author = promptString("New author", false)
+		r.Publisher = promptString("New publisher", false)
+		r.Type = promptString("New type", false)
+		r.ClassCode = promptString("New classification code", false)
+		r.Year = promptInt("New published year", false)
+		r.ISBN = promptString("New ISBN", false)
+	}
+}
+
+func (r *UpdateBibliographyRequest) hasChanges() bool {
+	return r.Title != "" || r.Author != "" || len(r.Contributors) > 0 || r.Publisher != "" || r.Type != "" ||
+		r.ClassCode != "" || r.Year != 0 || r.ISBN != "" || r.BibIndex != ""
+}
+
+func (r *UpdateBibliographyRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	if !r.hasChanges() {
+		return fmt.Errorf("at least one field to update is required")
+	}
+	if r.BibIndex != "" && r.RegenerateID {
+		return fmt.Errorf("-bib-index and -regen-index cannot be used together")
+	}
+	if _, err := parseContributors(r.Contributors); err != nil {
+		return err
+	}
+	if r.ClassCode != "" {
+		if _, err := domain.ParseClassCode(r.ClassCode); err != nil {
+			return err
+		}
+	}
+	if _, err := domain.ParseISBN(r.ISBN); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ToUpdate converts the request into a service.BibliographyUpdate, leaving unset fields nil.
+func (r *UpdateBibliographyRequest) ToUpdate() service.BibliographyUpdate {
+	update := service.BibliographyUpdate{
+		TitleEn:            r.TitleEn,
+		AuthorEn:           r.AuthorEn,
+		RegenerateBibIndex: r.RegenerateID,
+	}
+	if r.Title != "" {
+		update.Title = &r.Title
+	}
+	if r.Author != "" {
+		update.Author = &r.Author
+	}
+	update.Contributors, _ = parseContributors(r.Contributors)
+	if r.Publisher != "" {
+		update.Publisher = &r.Publisher
+	}
+	if r.Type != "" {
+		update.Type = &r.Type
+	}
+	if r.ClassCode != "" {
+		update.ClassCode = &r.ClassCode
+	}
+	if r.Year != 0 {
+		publishedDate := time.Date(r.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+		update.PublishedDate = &publishedDate
+	}
+	if r.ISBN != "" {
+		update.ISBN = &r.ISBN
+	}
+	if r.BibIndex != "" {
+		update.BibIndex = &r.BibIndex
+	}
+	return update
+}
+
+// DeleteBibliographyRequest holds arguments for deleting a bibliography.
+// Ref is either a BibIndex or a bibliography UUID.
+type DeleteBibliographyRequest struct {
+	Ref     string
+	Reviews string // "refuse", "cascade" or "orphan"
+	Yes     bool
+}
+
+func (r *DeleteBibliographyRequest) PromptMissing() {
+	if r.Ref == "" {
+		r.Ref = promptString("BibIndex or UUID", true)
+	}
+}
+
+func (r *DeleteBibliographyRequest) Validate() error {
+	if r.Ref == "" {
+		return fmt.Errorf("a BibIndex or UUID is required")
+	}
+	if _, err := r.Policy(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Policy maps the -reviews flag to a service.ReviewDeletePolicy.
+func (r *DeleteBibliographyRequest) Policy() (service.ReviewDeletePolicy, error) {
+	switch r.Reviews {
+	case "", "refuse":
+		return service.RefuseIfReviewed, nil
+	case "cascade":
+		return service.CascadeReviews, nil
+	case "orphan":
+		return service.KeepOrphanReviews, nil
+	default:
+		return 0, fmt.Errorf("reviews must be one of refuse, cascade or orphan (got %q)", r.Reviews)
+	}
+}
+
+// MigrateISBNRequest holds arguments for normalizing stored ISBNs.
+type MigrateISBNRequest struct {
+	DryRun       bool
+	ClearInvalid bool
+}
+
+// ExportRequest holds arguments for exporting bibliographies.
+type ExportRequest struct {
+	Format string
+	Out    string // file to write to; stdout if empty
+}
+
+func (r *ExportRequest) Validate() error {
+	if r.Format != "bibtex" {
+		return fmt.Errorf("unsupported export format %q (expected bibtex)", r.Format)
+	}
+	return nil
+}
+
+// ImportRequest holds arguments for importing bibliographies from a file.
+type ImportRequest struct {
+	Format    string
+	File      string
+	ClassCode string // classification for entries not matched by ClassMap
+	ClassMap  string // CSV file mapping citation keys or keywords to class codes
+	DryRun    bool
+}
+
+func (r *ImportRequest) Validate() error {
+	if r.Format != "bibtex" {
+		return fmt.Errorf("unsupported import format %q (expected bibtex)", r.Format)
+	}
+	if r.File == "" {
+		return fmt.Errorf("a file to import is required")
+	}
+	if r.ClassCode == "" && r.ClassMap == "" {
+		return fmt.Errorf("-class or -class-map is required to classify imported entries")
+	}
+	if r.ClassCode != "" {
+		if _, err := domain.ParseClassCode(r.ClassCode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportKindleRequest holds arguments for importing highlights from a Kindle clippings file.
+type ImportKindleRequest struct {
+	File   string
+	DryRun bool
+}
+
+func (r *ImportKindleRequest) Validate() error {
+	if r.File == "" {
+		return fmt.Errorf("a clippings file to import is required")
+	}
+	return nil
+}
+
+// ExportNotesRequest holds arguments for exporting literature notes.
+type ExportNotesRequest struct {
+	Vault  string
+	Force  bool
+	DryRun bool
+}
+
+func (r *ExportNotesRequest) Validate() error {
+	if r.Vault == "" {
+		return fmt.Errorf("a vault directory is required")
+	}
+	return nil
+}
+
+// SyncNotesRequest holds arguments for pushing reviews edited in literature notes back.
+type SyncNotesRequest struct {
+	Vault  string
+	Force  bool
+	DryRun bool
+}
+
+func (r *SyncNotesRequest) Validate() error {
+	if r.Vault == "" {
+		return fmt.Errorf("a vault directory is required")
+	}
+	return nil
+}
+
+// SearchRequest holds arguments for a full-text search.
+type SearchRequest struct {
+	Query string
+	Limit int
+}
+
+func (r *SearchRequest) Validate() error {
+	if strings.TrimSpace(r.Query) == "" {
+		return fmt.Errorf("a search query is required")
+	}
+	if r.Limit < 0 {
+		return fmt.Errorf("limit must be non-negative")
+	}
+	return nil
+}
+
+// ServeRequest holds arguments for serving the API.
+type ServeRequest struct {
+	Addr string // host:port; an empty host listens on every interface
+}
+
+// SiteBuildRequest holds arguments for building the static site.
+type SiteBuildRequest struct {
+	Out       string
+	Templates string
+	BaseURL   string
+	Title     string
+}
+
+func (r *SiteBuildRequest) Validate() error {
+	if r.Out == "" {
+		return fmt.Errorf("an output directory is required")
+	}
+	if strings.TrimSpace(r.Title) == "" {
+		return fmt.Errorf("site title must not be empty")
+	}
+	if r.BaseURL != "" {
+		u, err := url.Parse(r.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("base URL must be an absolute http or https URL, got %q", r.BaseURL)
+		}
+	}
+	return nil
+}
+
+// Options returns the build options, with the base URL ending in "/" so that page
+// paths can be appended to it.
+func (r *SiteBuildRequest) Options() siteOptions {
+	opts := siteOptions{Out: r.Out, Templates: r.Templates, BaseURL: r.BaseURL, Title: strings.TrimSpace(r.Title)}
+	if opts.BaseURL != "" && !strings.HasSuffix(opts.BaseURL, "/") {
+		opts.BaseURL += "/"
+	}
+	return opts
+}
+
+// SiteTemplatesRequest holds arguments for copying the built-in site templates.
+type SiteTemplatesRequest struct {
+	Out string
+}
+
+func (r *SiteTemplatesRequest) Validate() error {
+	if r.Out == "" {
+		return fmt.Errorf("an output directory is required")
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bibliography_log/internal/domain"
+	"reflect"
 	"testing"
 )
 
@@ -12,17 +14,27 @@ func TestAddClassificationRequest_Validate(t *testing.T) {
 	}{
 		{
 			name:    "valid request",
-			request: AddClassificationRequest{Code: 1, Name: "Test"},
+			request: AddClassificationRequest{Code: "1", Name: "Test"},
 			wantErr: false,
 		},
 		{
 			name:    "missing code",
-			request: AddClassificationRequest{Code: 0, Name: "Test"},
+			request: AddClassificationRequest{Code: "", Name: "Test"},
 			wantErr: true,
 		},
 		{
 			name:    "missing name",
-			request: AddClassificationRequest{Code: 1, Name: ""},
+			request: AddClassificationRequest{Code: "1", Name: ""},
+			wantErr: true,
+		},
+		{
+			name:    "decimal code",
+			request: AddClassificationRequest{Code: "547.48", Name: "Test"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid code",
+			request: AddClassificationRequest{Code: "5.6", Name: "Test"},
 			wantErr: true,
 		},
 	}
@@ -36,6 +48,82 @@ func TestAddClassificationRequest_Validate(t *testing.T) {
 	}
 }
 
+func TestSeedClassificationsRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request SeedClassificationsRequest
+		wantErr bool
+	}{
+		{"ndc divisions", SeedClassificationsRequest{Scheme: "ndc", Depth: 2}, false},
+		{"ndc main classes", SeedClassificationsRequest{Scheme: "ndc", Depth: 1}, false},
+		{"unknown scheme", SeedClassificationsRequest{Scheme: "ddc", Depth: 2}, true},
+		{"too deep", SeedClassificationsRequest{Scheme: "ndc", Depth: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SeedClassificationsRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenameClassificationRequest_Validate(t *testing.T) {
+	if err := (&RenameClassificationRequest{Code: "547.48", Name: "情報通信"}).Validate(); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+	if err := (&RenameClassificationRequest{Code: "547.48", Name: " "}).Validate(); err == nil {
+		t.Error("Expected error for an empty name")
+	}
+	if err := (&RenameClassificationRequest{Code: "x", Name: "Name"}).Validate(); err == nil {
+		t.Error("Expected error for an invalid code")
+	}
+}
+
+func TestRenumberClassificationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request RenumberClassificationRequest
+		wantErr bool
+	}{
+		{"valid", RenumberClassificationRequest{Code: "56", To: "547", RewriteBibIndex: true}, false},
+		{"missing new code", RenumberClassificationRequest{Code: "56"}, true},
+		{"same code", RenumberClassificationRequest{Code: "56", To: " 56"}, true},
+		{"invalid code", RenumberClassificationRequest{Code: "56", To: "5.47"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RenumberClassificationRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteClassificationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request DeleteClassificationRequest
+		wantErr bool
+	}{
+		{"unused class", DeleteClassificationRequest{Code: "56"}, false},
+		{"reassign", DeleteClassificationRequest{Code: "56", ReassignTo: "5", RewriteBibIndex: true}, false},
+		{"missing code", DeleteClassificationRequest{}, true},
+		{"invalid reassign code", DeleteClassificationRequest{Code: "56", ReassignTo: "five"}, true},
+		{"bib-index without reassign", DeleteClassificationRequest{Code: "56", RewriteBibIndex: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteClassificationRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAddBibliographyRequest_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -48,7 +136,7 @@ func TestAddBibliographyRequest_Validate(t *testing.T) {
 				Title:     "Title",
 				Author:    "Author",
 				Type:      "Book",
-				ClassCode: 1,
+				ClassCode: "1",
 				Year:      2023,
 			},
 			wantErr: false,
@@ -58,7 +146,7 @@ func TestAddBibliographyRequest_Validate(t *testing.T) {
 			request: AddBibliographyRequest{
 				Author:    "Author",
 				Type:      "Book",
-				ClassCode: 1,
+				ClassCode: "1",
 				Year:      2023,
 			},
 			wantErr: true,
@@ -68,17 +156,39 @@ func TestAddBibliographyRequest_Validate(t *testing.T) {
 			request: AddBibliographyRequest{
 				Title:     "Title",
 				Type:      "Book",
-				ClassCode: 1,
+				ClassCode: "1",
 				Year:      2023,
 			},
 			wantErr: true,
 		},
+		{
+			name: "contributors instead of author",
+			request: AddBibliographyRequest{
+				Title:        "Title",
+				Contributors: []string{"Author", "translator:Translator=Translator En"},
+				Type:         "Book",
+				ClassCode:    "1",
+				Year:         2023,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown contributor role",
+			request: AddBibliographyRequest{
+				Title:        "Title",
+				Contributors: []string{"narrator:Someone"},
+				Type:         "Book",
+				ClassCode:    "1",
+				Year:         2023,
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing type",
 			request: AddBibliographyRequest{
 				Title:     "Title",
 				Author:    "Author",
-				ClassCode: 1,
+				ClassCode: "1",
 				Year:      2023,
 			},
 			wantErr: true,
@@ -99,7 +209,7 @@ func TestAddBibliographyRequest_Validate(t *testing.T) {
 				Title:     "Title",
 				Author:    "Author",
 				Type:      "Book",
-				ClassCode: 1,
+				ClassCode: "1",
 			},
 			wantErr: true,
 		},
@@ -114,6 +224,16 @@ func TestAddBibliographyRequest_Validate(t *testing.T) {
 	}
 }
 
+func TestAddBibliographyRequest_ApplyMetadata(t *testing.T) {
+	req := AddBibliographyRequest{Title: "Given Title", ISBN: "978-0321125217"}
+	req.ApplyMetadata(&domain.BookMetadata{Title: "Catalogue Title", Author: "Eric Evans", Publisher: "Addison-Wesley", Year: 2003})
+
+	want := AddBibliographyRequest{Title: "Given Title", Author: "Eric Evans", Publisher: "Addison-Wesley", Type: "Book", Year: 2003, ISBN: "978-0321125217"}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("ApplyMetadata() = %+v, want %+v", req, want)
+	}
+}
+
 func TestAddReviewRequest_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -204,6 +324,51 @@ func TestListBibliographiesRequest_Validate(t *testing.T) {
 			request: ListBibliographiesRequest{Limit: 10, Offset: -1},
 			wantErr: true,
 		},
+		{
+			name:    "all filters",
+			request: ListBibliographiesRequest{Type: "Book", ClassCode: "56", YearFrom: 2000, YearTo: 2024, Author: "smith", HasReview: "yes", HasISBN: "no", Sort: "year", Desc: true},
+			wantErr: false,
+		},
+		{
+			name:    "class with subclasses",
+			request: ListBibliographiesRequest{ClassCode: "5", Recursive: true},
+			wantErr: false,
+		},
+		{
+			name:    "recursive without class",
+			request: ListBibliographiesRequest{Recursive: true},
+			wantErr: true,
+		},
+		{
+			name:    "invalid class",
+			request: ListBibliographiesRequest{ClassCode: "5x"},
+			wantErr: true,
+		},
+		{
+			name:    "reversed year range",
+			request: ListBibliographiesRequest{YearFrom: 2024, YearTo: 2000},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sort key",
+			request: ListBibliographiesRequest{Sort: "rating"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid has-review",
+			request: ListBibliographiesRequest{HasReview: "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "status",
+			request: ListBibliographiesRequest{Status: "to-read"},
+			wantErr: false,
+		},
+		{
+			name:    "unknown status",
+			request: ListBibliographiesRequest{Status: "skimmed"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,3 +379,375 @@ func TestListBibliographiesRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeStatusRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request ChangeStatusRequest
+		wantErr bool
+	}{
+		{"ref only", ChangeStatusRequest{Ref: "B56EE03DDD"}, false},
+		{"date", ChangeStatusRequest{Ref: "B56EE03DDD", At: "2025-11-23"}, false},
+		{"timestamp", ChangeStatusRequest{Ref: "B56EE03DDD", At: "2025-11-23T09:30:00+09:00"}, false},
+		{"missing ref", ChangeStatusRequest{}, true},
+		{"invalid date", ChangeStatusRequest{Ref: "B56EE03DDD", At: "23/11/2025"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ChangeStatusRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogSessionRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request LogSessionRequest
+		wantErr bool
+	}{
+		{"pages", LogSessionRequest{Ref: "B56EE03DDD", From: -1, To: 42}, false},
+		{"time only", LogSessionRequest{Ref: "B56EE03DDD", From: -1, To: -1, Duration: "45m"}, false},
+		{"full", LogSessionRequest{Ref: "B56EE03DDD", Date: "2025-11-23", Duration: "1h30m", From: 10, To: 60, Total: 320}, false},
+		{"missing ref", LogSessionRequest{From: -1, To: 42}, true},
+		{"nothing read", LogSessionRequest{Ref: "B56EE03DDD", From: -1, To: -1}, true},
+		{"invalid date", LogSessionRequest{Ref: "B56EE03DDD", Date: "23/11/2025", From: -1, To: 42}, true},
+		{"invalid duration", LogSessionRequest{Ref: "B56EE03DDD", Duration: "45", From: -1, To: -1}, true},
+		{"negative duration", LogSessionRequest{Ref: "B56EE03DDD", Duration: "-5m", From: -1, To: -1}, true},
+		{"negative from", LogSessionRequest{Ref: "B56EE03DDD", From: -2, To: 42}, true},
+		{"negative total", LogSessionRequest{Ref: "B56EE03DDD", From: -1, To: 42, Total: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("LogSessionRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddQuoteRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request AddQuoteRequest
+		wantErr bool
+	}{
+		{"text only", AddQuoteRequest{Ref: "B56EE03DDD", Text: "A quote"}, false},
+		{"full", AddQuoteRequest{Ref: "B56EE03DDD", Text: "A quote\nover lines", Location: "p. 42", Comment: "Why", Tags: "ddd, #modeling"}, false},
+		{"missing ref", AddQuoteRequest{Text: "A quote"}, true},
+		{"whitespace text", AddQuoteRequest{Ref: "B56EE03DDD", Text: " \n "}, true},
+		{"tag with space", AddQuoteRequest{Ref: "B56EE03DDD", Text: "A quote", Tags: "two words"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AddQuoteRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListQuotesRequest_Validate(t *testing.T) {
+	if err := (&ListQuotesRequest{Ref: "B56EE03DDD", Tag: "ddd", Limit: 10}).Validate(); err != nil {
+		t.Errorf("ListQuotesRequest.Validate() error = %v", err)
+	}
+	if err := (&ListQuotesRequest{Limit: -1}).Validate(); err == nil {
+		t.Error("Expected error for negative limit, got nil")
+	}
+	if err := (&ListQuotesRequest{Offset: -1}).Validate(); err == nil {
+		t.Error("Expected error for negative offset, got nil")
+	}
+}
+
+func TestShowBibliographyRequest_Validate(t *testing.T) {
+	if err := (&ShowBibliographyRequest{Ref: "B16MS24MM"}).Validate(); err != nil {
+		t.Errorf("ShowBibliographyRequest.Validate() error = %v", err)
+	}
+	if err := (&ShowBibliographyRequest{}).Validate(); err == nil {
+		t.Error("Expected error for empty ref, got nil")
+	}
+}
+
+func TestUpdateBibliographyRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request UpdateBibliographyRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid request",
+			request: UpdateBibliographyRequest{Ref: "B56EE03DDD", Title: "New"},
+			wantErr: false,
+		},
+		{
+			name:    "missing ref",
+			request: UpdateBibliographyRequest{Title: "New"},
+			wantErr: true,
+		},
+		{
+			name:    "missing update fields",
+			request: UpdateBibliographyRequest{Ref: "B56EE03DDD", RegenerateID: true},
+			wantErr: true,
+		},
+		{
+			name:    "contributors only",
+			request: UpdateBibliographyRequest{Ref: "B56EE03DDD", Contributors: []string{"editor:Someone"}},
+			wantErr: false,
+		},
+		{
+			name:    "contributor without name",
+			request: UpdateBibliographyRequest{Ref: "B56EE03DDD", Contributors: []string{"editor:"}},
+			wantErr: true,
+		},
+		{
+			name:    "manual and regenerated index",
+			request: UpdateBibliographyRequest{Ref: "B56EE03DDD", BibIndex: "X", RegenerateID: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateBibliographyRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateBibliographyRequest_ToUpdate(t *testing.T) {
+	req := UpdateBibliographyRequest{Ref: "B56EE03DDD", Title: "New", Year: 2024}
+	update := req.ToUpdate()
+
+	if update.Title == nil || *update.Title != "New" {
+		t.Errorf("Expected title to be set, got %v", update.Title)
+	}
+	if update.PublishedDate == nil || update.PublishedDate.Year() != 2024 {
+		t.Errorf("Expected published date in 2024, got %v", update.PublishedDate)
+	}
+	if update.Author != nil || update.Contributors != nil || update.ClassCode != nil || update.BibIndex != nil {
+		t.Error("Expected unset fields to be nil")
+	}
+
+	req = UpdateBibliographyRequest{Ref: "B56EE03DDD", Contributors: []string{"Eric Evans", "translator:今関剛=Tsuyoshi Imazeki"}}
+	want := []domain.Contributor{
+		{Name: "Eric Evans", Role: domain.RoleAuthor},
+		{Name: "今関剛", Role: domain.RoleTranslator, NameEn: "Tsuyoshi Imazeki"},
+	}
+	if got := req.ToUpdate().Contributors; !reflect.DeepEqual(got, want) {
+		t.Errorf("Contributors = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeleteBibliographyRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request DeleteBibliographyRequest
+		wantErr bool
+	}{
+		{
+			name:    "default policy",
+			request: DeleteBibliographyRequest{Ref: "B56EE03DDD"},
+			wantErr: false,
+		},
+		{
+			name:    "cascade policy",
+			request: DeleteBibliographyRequest{Ref: "B56EE03DDD", Reviews: "cascade"},
+			wantErr: false,
+		},
+		{
+			name:    "missing ref",
+			request: DeleteBibliographyRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown policy",
+			request: DeleteBibliographyRequest{Ref: "B56EE03DDD", Reviews: "purge"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteBibliographyRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request ImportRequest
+		wantErr bool
+	}{
+		{
+			name:    "default class",
+			request: ImportRequest{Format: "bibtex", File: "refs.bib", ClassCode: "56"},
+			wantErr: false,
+		},
+		{
+			name:    "class map",
+			request: ImportRequest{Format: "bibtex", File: "refs.bib", ClassMap: "classes.csv"},
+			wantErr: false,
+		},
+		{
+			name:    "missing file",
+			request: ImportRequest{Format: "bibtex", ClassCode: "56"},
+			wantErr: true,
+		},
+		{
+			name:    "no classification",
+			request: ImportRequest{Format: "bibtex", File: "refs.bib"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			request: ImportRequest{Format: "ris", File: "refs.ris", ClassCode: "56"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ImportRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportKindleRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request ImportKindleRequest
+		wantErr bool
+	}{
+		{
+			name:    "file",
+			request: ImportKindleRequest{File: "My Clippings.txt", DryRun: true},
+			wantErr: false,
+		},
+		{
+			name:    "missing file",
+			request: ImportKindleRequest{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ImportKindleRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request SearchRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid query",
+			request: SearchRequest{Query: "author:杉本", Limit: 20},
+			wantErr: false,
+		},
+		{
+			name:    "blank query",
+			request: SearchRequest{Query: "  "},
+			wantErr: true,
+		},
+		{
+			name:    "negative limit",
+			request: SearchRequest{Query: "ddd", Limit: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SearchRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRevertReviewRequest_Validate(t *testing.T) {
+	id := "0b4f2c5e-8d1a-4e8b-9c3f-2a6d7e1f0c9b"
+	tests := []struct {
+		name    string
+		request RevertReviewRequest
+		wantErr bool
+	}{
+		{"valid", RevertReviewRequest{ReviewHistoryRequest: ReviewHistoryRequest{ReviewIDStr: id}, To: 2}, false},
+		{"missing review", RevertReviewRequest{To: 2}, true},
+		{"missing revision", RevertReviewRequest{ReviewHistoryRequest: ReviewHistoryRequest{ReviewIDStr: id}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RevertReviewRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiffReviewRequest_Validate(t *testing.T) {
+	id := "0b4f2c5e-8d1a-4e8b-9c3f-2a6d7e1f0c9b"
+	tests := []struct {
+		name    string
+		request DiffReviewRequest
+		wantErr bool
+	}{
+		{"defaults", DiffReviewRequest{ReviewHistoryRequest: ReviewHistoryRequest{ReviewIDStr: id}}, false},
+		{"revisions", DiffReviewRequest{ReviewHistoryRequest: ReviewHistoryRequest{ReviewIDStr: id}, From: 1, To: 3}, false},
+		{"missing review", DiffReviewRequest{}, true},
+		{"negative revision", DiffReviewRequest{ReviewHistoryRequest: ReviewHistoryRequest{ReviewIDStr: id}, From: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("DiffReviewRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSiteBuildRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request SiteBuildRequest
+		wantErr bool
+	}{
+		{"valid", SiteBuildRequest{Out: "public", Title: "Log"}, false},
+		{"base URL", SiteBuildRequest{Out: "public", Title: "Log", BaseURL: "https://example.github.io/log"}, false},
+		{"missing out", SiteBuildRequest{Title: "Log"}, true},
+		{"blank title", SiteBuildRequest{Out: "public", Title: " "}, true},
+		{"relative base URL", SiteBuildRequest{Out: "public", Title: "Log", BaseURL: "/log/"}, true},
+		{"other scheme", SiteBuildRequest{Out: "public", Title: "Log", BaseURL: "ftp://example.com/"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SiteBuildRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := SiteBuildRequest{Out: "public", Title: "Log", BaseURL: "https://example.github.io/log"}
+	if got := req.Options().BaseURL; got != "https://example.github.io/log/" {
+		t.Errorf("Expected the base URL to end in a slash, got %q", got)
+	}
+}
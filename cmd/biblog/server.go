@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRequestBody bounds the JSON bodies accepted by the API; reviews are the largest.
+const maxRequestBody = 1 << 20
+
+// shutdownTimeout is how long 'biblog serve' waits for requests in flight when stopped.
+const shutdownTimeout = 10 * time.Second
+
+// apiServer serves the JSON API of 'biblog serve'. Results use the same views as
+// -output json, and errors the same error object with an HTTP status:
+// 400 for malformed requests, 404 for missing entities, 422 for input the services
+// reject and 500 for storage failures.
+type apiServer struct {
+	app *App
+	// writeMu serializes requests that save, as the services check uniqueness
+	// (of BibIndexes and classification codes) before saving.
+	writeMu sync.Mutex
+}
+
+// newAPIHandler routes the API under /api/.
+func newAPIHandler(app *App) http.Handler {
+	s := &apiServer{app: app}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/bibliographies", s.listBibliographies)
+	mux.HandleFunc("POST /api/bibliographies", s.createBibliography)
+	mux.HandleFunc("GET /api/bibliographies/{ref}", s.getBibliography)
+	mux.HandleFunc("GET /api/bibliographies/{ref}/reviews", s.listBibliographyReviews)
+	mux.HandleFunc("POST /api/bibliographies/{ref}/reviews", s.createReview)
+	mux.HandleFunc("GET /api/classifications", s.listClassifications)
+	mux.HandleFunc("POST /api/classifications", s.createClassification)
+	mux.HandleFunc("GET /api/classifications/{code}", s.getClassification)
+	mux.HandleFunc("GET /api/reviews", s.listReviews)
+	mux.HandleFunc("GET /api/reviews/{id}", s.getReview)
+	mux.HandleFunc("PATCH /api/reviews/{id}", s.updateReview)
+	// Anything else gets a JSON error too, rather than the plain text of ServeMux
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPatch} {
+			probe := r.Clone(r.Context())
+			probe.Method = method
+			if _, pattern := mux.Handler(probe); pattern != "/api/" {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeAPIError(w, http.StatusMethodNotAllowed, errUsage, fmt.Sprintf("%s is not allowed for %s", r.Method, r.URL.Path))
+			return
+		}
+		writeAPIError(w, http.StatusNotFound, errNotFound, fmt.Sprintf("no such endpoint: %s", r.URL.Path))
+	})
+	return mux
+}
+
+// serve runs handler on ln until ctx is cancelled, then waits up to shutdownTimeout for
+// requests in flight before returning.
+func serve(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
+
+func (s *apiServer) listBibliographies(w http.ResponseWriter, r *http.Request) {
+	req, err := listRequestFromQuery(r.URL.Query())
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errValidation, err.Error())
+		return
+	}
+	query, _ := req.Query() // already checked by Validate
+	bibs, err := s.app.BibService.FindBibliographies(query)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newBibliographyViews(bibs))
+}
+
+func (s *apiServer) createBibliography(w http.ResponseWriter, r *http.Request) {
+	var req AddBibliographyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errValidation, err.Error())
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	bib, err := s.app.BibService.AddBibliography(req.Title, req.Author, req.ToContributors(), req.Publisher, req.ISBN, req.Type,
+		req.ClassCode, req.ToPublishedDate(), req.TitleEn, req.AuthorEn, req.BibIndex)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/bibliographies/"+bib.ID.String())
+	writeJSON(w, http.StatusCreated, newBibliographyView(bib))
+}
+
+func (s *apiServer) getBibliography(w http.ResponseWriter, r *http.Request) {
+	bib, ok := s.findBibliography(w, r.PathValue("ref"))
+	if !ok {
+		return
+	}
+	detail, err := s.app.BibliographyDetail(bib)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newBibliographyDetailView(detail))
+}
+
+func (s *apiServer) listBibliographyReviews(w http.ResponseWriter, r *http.Request) {
+	bib, ok := s.findBibliography(w, r.PathValue("ref"))
+	if !ok {
+		return
+	}
+	reviews, err := s.app.ReviewService.ListReviewsByBookID(bib.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newReviewViews(reviews))
+}
+
+// reviewBody is the body of POST /api/bibliographies/{ref}/reviews and PATCH
+// /api/reviews/{id}. For PATCH, fields left out (or null) are not changed.
+type reviewBody struct {
+	Goals   *string `json:"goals"`
+	Summary *string `json:"summary"`
+}
+
+func (s *apiServer) createReview(w http.ResponseWriter, r *http.Request) {
+	bib, ok := s.findBibliography(w, r.PathValue("ref"))
+	if !ok {
+		return
+	}
+	var body reviewBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Goals == nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errValidation, "goals are required")
+		return
+	}
+	var summary string
+	if body.Summary != nil {
+		summary = *body.Summary
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	review, err := s.app.ReviewService.AddReview(bib.ID, *body.Goals, summary)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/reviews/"+review.ID.String())
+	writeJSON(w, http.StatusCreated, newReviewView(review))
+}
+
+func (s *apiServer) listReviews(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageFromQuery(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errValidation, err.Error())
+		return
+	}
+	reviews, err := s.app.ReviewService.ListReviews(limit, offset)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newReviewViews(reviews))
+}
+
+func (s *apiServer) getReview(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReviewIDParam(w, r)
+	if !ok {
+		return
+	}
+	review, err := s.app.ReviewService.FindByID(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if review == nil {
+		writeAPIError(w, http.StatusNotFound, errNotFound, fmt.Sprintf("review with ID %s not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, newReviewView(review))
+}
+
+func (s *apiServer) updateReview(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReviewIDParam(w, r)
+	if !ok {
+		return
+	}
+	var body reviewBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	review, err := s.app.ReviewService.UpdateReview(id, body.Goals, body.Summary)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newReviewView(review))
+}
+
+func (s *apiServer) listClassifications(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageFromQuery(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errValidation, err.Error())
+		return
+	}
+	roots, err := s.app.BibService.ClassificationTree()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page(newClassNodeViews(roots), limit, offset))
+}
+
+func (s *apiServer) createClassification(w http.ResponseWriter, r *http.Request) {
+	var req AddClassificationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errValidation, err.Error())
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	class, err := s.app.BibService.AddClassification(req.Code, req.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/classifications/"+url.PathEscape(class.Code.String()))
+	writeJSON(w, http.StatusCreated, newClassificationView(class))
+}
+
+func (s *apiServer) getClassification(w http.ResponseWriter, r *http.Request) {
+	class, err := s.app.BibService.FindClassificationByCode(r.PathValue("code"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newClassificationView(class))
+}
+
+// findBibliography resolves a UUID or BibIndex, writing a 404 if nothing matches.
+func (s *apiServer) findBibliography(w http.ResponseWriter, ref string) (*domain.Bibliography, bool) {
+	bib, err := s.app.FindBibliography(ref)
+	if err != nil {
+		writeServiceError(w, err)
+		return nil, false
+	}
+	if bib == nil {
+		writeAPIError(w, http.StatusNotFound, errNotFound, fmt.Sprintf("bibliography %s not found", ref))
+		return nil, false
+	}
+	return bib, true
+}
+
+func parseReviewIDParam(w http.ResponseWriter, r *http.Request) (domain.ReviewID, bool) {
+	id, err := domain.ParseReviewID(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errValidation, fmt.Sprintf("invalid review ID: %v", err))
+		return domain.ReviewID{}, false
+	}
+	return id, true
+}
+
+// decodeBody reads a JSON object into v, rejecting unknown fields and bodies over
+// maxRequestBody. It writes the error response and returns false on failure.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil && dec.More() {
+		err = errors.New("unexpected data after the JSON object")
+	}
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &tooLarge):
+		writeAPIError(w, http.StatusRequestEntityTooLarge, errUsage, fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit))
+	case errors.Is(err, io.EOF):
+		writeAPIError(w, http.StatusBadRequest, errUsage, "request body is empty")
+	default:
+		writeAPIError(w, http.StatusBadRequest, errUsage, fmt.Sprintf("invalid JSON body: %v", err))
+	}
+	return false
+}
+
+// listRequestFromQuery reads the filters of 'biblog list' from query parameters, named
+// like its flags with underscores (year_from, has_review). limit defaults to 100.
+func listRequestFromQuery(q url.Values) (*ListBibliographiesRequest, error) {
+	req := &ListBibliographiesRequest{
+		Type:      q.Get("type"),
+		ClassCode: q.Get("class"),
+		Author:    q.Get("author"),
+		Publisher: q.Get("publisher"),
+		HasReview: q.Get("has_review"),
+		HasISBN:   q.Get("has_isbn"),
+		Status:    q.Get("status"),
+		Sort:      q.Get("sort"),
+	}
+	var err error
+	if req.Limit, req.Offset, err = pageFromQuery(q); err != nil {
+		return nil, err
+	}
+	if req.YearFrom, err = intParam(q, "year_from", 0); err != nil {
+		return nil, err
+	}
+	if req.YearTo, err = intParam(q, "year_to", 0); err != nil {
+		return nil, err
+	}
+	if req.Recursive, err = boolParam(q, "recursive"); err != nil {
+		return nil, err
+	}
+	if req.Desc, err = boolParam(q, "desc"); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// pageFromQuery reads limit (default 100, 0 for all) and offset.
+func pageFromQuery(q url.Values) (limit, offset int, err error) {
+	if limit, err = intParam(q, "limit", 100); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = intParam(q, "offset", 0); err != nil {
+		return 0, 0, err
+	}
+	if limit < 0 || offset < 0 {
+		return 0, 0, fmt.Errorf("limit and offset must be non-negative")
+	}
+	return limit, offset, nil
+}
+
+func intParam(q url.Values, name string, def int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number (got %q)", name, v)
+	}
+	return n, nil
+}
+
+func boolParam(q url.Values, name string) (bool, error) {
+	v := q.Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false (got %q)", name, v)
+	}
+	return b, nil
+}
+
+// page returns the items selected by limit (0 for all) and offset.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// writeServiceError maps an error of the services to a status: domain.ErrNotFound to 404,
+// domain.ErrInvalid to 422 and anything else, such as a storage failure, to 500.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeAPIError(w, http.StatusNotFound, errNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalid):
+		writeAPIError(w, http.StatusUnprocessableEntity, errValidation, err.Error())
+	default:
+		log.Printf("API error: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, errFailed, err.Error())
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, kind errorKind, message string) {
+	writeJSON(w, status, errorView{Error: errorDetail{Kind: kind, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAPI serves the API over a CSV library in a temporary directory.
+func newTestAPI(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	dataDir := t.TempDir()
+	app, err := NewApp(Config{DataDir: dataDir})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	srv := httptest.NewServer(newAPIHandler(app))
+	t.Cleanup(func() {
+		srv.Close()
+		app.Close()
+	})
+	return srv, dataDir
+}
+
+// call sends a request and decodes the JSON response into out, if given.
+func call(t *testing.T, srv *httptest.Server, method, path, body string, out any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("%s %s: expected JSON, got Content-Type %q", method, path, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func expectStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+	if resp.StatusCode != want {
+		t.Errorf("%s %s: expected status %d, got %d", resp.Request.Method, resp.Request.URL.Path, want, resp.StatusCode)
+	}
+}
+
+func TestAPI_Bibliographies(t *testing.T) {
+	srv, _ := newTestAPI(t)
+
+	var class classificationView
+	expectStatus(t, call(t, srv, "POST", "/api/classifications", `{"code": "5", "name": "技術"}`, &class), http.StatusCreated)
+	if class.Code != "5" || class.Name != "技術" {
+		t.Errorf("Unexpected classification: %+v", class)
+	}
+	expectStatus(t, call(t, srv, "POST", "/api/classifications", `{"code": "5", "name": "Again"}`, nil), http.StatusUnprocessableEntity)
+	expectStatus(t, call(t, srv, "GET", "/api/classifications/5", "", &class), http.StatusOK)
+	expectStatus(t, call(t, srv, "GET", "/api/classifications/6", "", nil), http.StatusNotFound)
+
+	var bib bibliographyView
+	resp := call(t, srv, "POST", "/api/bibliographies",
+		`{"title": "ドメイン駆動設計入門", "author": "成瀬允宣", "type": "Book", "class": "5", "year": 2020, "bib_index": "B5NM20DD"}`, &bib)
+	expectStatus(t, resp, http.StatusCreated)
+	if bib.BibIndex != "B5NM20DD" || bib.Title != "ドメイン駆動設計入門" {
+		t.Errorf("Unexpected bibliography: %+v", bib)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/api/bibliographies/"+bib.ID {
+		t.Errorf("Unexpected Location %q", loc)
+	}
+	call(t, srv, "POST", "/api/bibliographies", `{"title": "Second", "author": "Someone", "type": "Book", "class": "5", "year": 2021}`, nil)
+
+	// Get by BibIndex or UUID
+	var detail bibliographyDetailView
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies/B5NM20DD", "", &detail), http.StatusOK)
+	if detail.ID != bib.ID || detail.Classification == nil || detail.Classification.Name != "技術" {
+		t.Errorf("Unexpected detail: %+v", detail)
+	}
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies/"+bib.ID, "", &detail), http.StatusOK)
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies/B9XX99XX", "", nil), http.StatusNotFound)
+
+	var list []bibliographyView
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies?limit=1&offset=1", "", &list), http.StatusOK)
+	if len(list) != 1 || list[0].Title != "Second" {
+		t.Errorf("Expected the second bibliography only, got %+v", list)
+	}
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies?year_from=2021", "", &list), http.StatusOK)
+	if len(list) != 1 {
+		t.Errorf("Expected 1 bibliography from 2021, got %d", len(list))
+	}
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies?limit=-1", "", nil), http.StatusBadRequest)
+
+	// Invalid input
+	for name, body := range map[string]string{
+		"unknown class":   `{"title": "T", "author": "A", "type": "Book", "class": "9", "year": 2020}`,
+		"missing title":   `{"author": "A", "type": "Book", "class": "5", "year": 2020}`,
+		"used BibIndex":   `{"title": "T", "author": "A", "type": "Book", "class": "5", "year": 2020, "bib_index": "B5NM20DD"}`,
+		"invalid ISBN":    `{"title": "T", "author": "A", "type": "Book", "class": "5", "year": 2020, "isbn": "123"}`,
+		"bad contributor": `{"title": "T", "contributors": ["pilot:A"], "type": "Book", "class": "5", "year": 2020}`,
+	} {
+		var e errorView
+		resp := call(t, srv, "POST", "/api/bibliographies", body, &e)
+		if resp.StatusCode != http.StatusUnprocessableEntity || e.Error.Kind != errValidation {
+			t.Errorf("%s: expected a 422 validation error, got %d %+v", name, resp.StatusCode, e)
+		}
+	}
+	for name, body := range map[string]string{
+		"malformed":     `{"title": `,
+		"unknown field": `{"titel": "T"}`,
+		"empty":         ``,
+		"two objects":   `{} {}`,
+	} {
+		if resp := call(t, srv, "POST", "/api/bibliographies", body, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", name, resp.StatusCode)
+		}
+	}
+	expectStatus(t, call(t, srv, "DELETE", "/api/bibliographies/B5NM20DD", "", nil), http.StatusMethodNotAllowed)
+	expectStatus(t, call(t, srv, "GET", "/api/books", "", nil), http.StatusNotFound)
+}
+
+func TestAPI_Reviews(t *testing.T) {
+	srv, _ := newTestAPI(t)
+	call(t, srv, "POST", "/api/classifications", `{"code": "5", "name": "Technology"}`, nil)
+	call(t, srv, "POST", "/api/bibliographies", `{"title": "T", "author": "A", "type": "Book", "class": "5", "year": 2020, "bib_index": "B5AT20"}`, nil)
+
+	var review reviewView
+	expectStatus(t, call(t, srv, "POST", "/api/bibliographies/B5AT20/reviews", `{"goals": "目標", "summary": "一行目\n二行目"}`, &review), http.StatusCreated)
+	if review.Summary != "一行目\n二行目" {
+		t.Errorf("Expected line breaks to be kept, got %q", review.Summary)
+	}
+	expectStatus(t, call(t, srv, "POST", "/api/bibliographies/B5AT20/reviews", `{"summary": "no goals"}`, nil), http.StatusUnprocessableEntity)
+	expectStatus(t, call(t, srv, "POST", "/api/bibliographies/B5XX00/reviews", `{"goals": "g"}`, nil), http.StatusNotFound)
+
+	// Only the fields given are updated
+	var updated reviewView
+	expectStatus(t, call(t, srv, "PATCH", "/api/reviews/"+review.ID, `{"summary": "更新後"}`, &updated), http.StatusOK)
+	if updated.Goals != "目標" || updated.Summary != "更新後" {
+		t.Errorf("Unexpected update: %+v", updated)
+	}
+	expectStatus(t, call(t, srv, "PATCH", "/api/reviews/"+review.ID, `{"goals": "  "}`, nil), http.StatusUnprocessableEntity)
+	expectStatus(t, call(t, srv, "PATCH", "/api/reviews/"+review.ID, `{}`, nil), http.StatusUnprocessableEntity)
+	expectStatus(t, call(t, srv, "PATCH", "/api/reviews/0b4f2c5e-8d1a-4e8b-9c3f-2a6d7e1f0c9b", `{"goals": "g"}`, nil), http.StatusNotFound)
+
+	var got reviewView
+	expectStatus(t, call(t, srv, "GET", "/api/reviews/"+review.ID, "", &got), http.StatusOK)
+	if got.Summary != "更新後" {
+		t.Errorf("Expected the updated review, got %+v", got)
+	}
+	expectStatus(t, call(t, srv, "GET", "/api/reviews/not-a-uuid", "", nil), http.StatusBadRequest)
+	expectStatus(t, call(t, srv, "GET", "/api/reviews/0b4f2c5e-8d1a-4e8b-9c3f-2a6d7e1f0c9b", "", nil), http.StatusNotFound)
+
+	var list []reviewView
+	expectStatus(t, call(t, srv, "GET", "/api/reviews?limit=10", "", &list), http.StatusOK)
+	if len(list) != 1 {
+		t.Errorf("Expected 1 review, got %d", len(list))
+	}
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies/B5AT20/reviews", "", &list), http.StatusOK)
+	if len(list) != 1 || list[0].ID != review.ID {
+		t.Errorf("Unexpected reviews of B5AT20: %+v", list)
+	}
+}
+
+func TestAPI_BodyTooLarge(t *testing.T) {
+	srv, _ := newTestAPI(t)
+	body := `{"code": "5", "name": "` + strings.Repeat("x", maxRequestBody) + `"}`
+	expectStatus(t, call(t, srv, "POST", "/api/classifications", body, nil), http.StatusRequestEntityTooLarge)
+}
+
+func TestAPI_StorageError(t *testing.T) {
+	srv, dataDir := newTestAPI(t)
+	// A directory in place of the CSV file cannot be read
+	if err := os.Mkdir(filepath.Join(dataDir, "classifications.csv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var e errorView
+	resp := call(t, srv, "GET", "/api/classifications", "", &e)
+	if resp.StatusCode != http.StatusInternalServerError || e.Error.Kind != errFailed {
+		t.Errorf("Expected a 500 error, got %d %+v", resp.StatusCode, e)
+	}
+}
+
+func TestServe_Shutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, ln, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return after cancellation")
+	}
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// siteManifest lists the files written by the last build of a site, so that the next
+// build removes pages that are gone without touching files it did not write (such as
+// a CNAME for GitHub Pages).
+const siteManifest = ".biblog-site"
+
+// feedSize is the number of reviews in the Atom feed.
+const feedSize = 20
+
+// siteOptions configures 'biblog site build'.
+type siteOptions struct {
+	Out       string // output directory
+	Templates string // directory overriding the built-in templates; empty for none
+	BaseURL   string // absolute URL of the site ending in "/"; empty for relative feed links
+	Title     string
+}
+
+// siteResult counts what a build wrote.
+type siteResult struct {
+	Pages           int
+	Bibliographies  int
+	Classifications int
+	Years           int
+	FeedEntries     int
+	Removed         int // files of the previous build that are gone
+}
+
+type yearArchive struct {
+	Year           int
+	Bibliographies []*domain.Bibliography
+}
+
+type yearsPage struct {
+	webPage
+	Years []yearArchive
+}
+
+type yearPage struct {
+	webPage
+	yearArchive
+}
+
+// siteBuilder writes the pages of a static site. Pages live in directories holding an
+// index.html (bibliographies/B56EE03DDD/), so their URLs do not change with the host.
+type siteBuilder struct {
+	app    *App
+	opts   siteOptions
+	assets fs.FS
+	now    time.Time
+	pages  map[int]map[string]*template.Template // parsed for each depth below the root
+	result siteResult
+	files  []string // written so far, as slash-separated paths below Out
+}
+
+// buildSite renders every bibliography, classification and year of publication into a
+// static site in opts.Out. now dates the feed when no review is in it.
+func buildSite(app *App, opts siteOptions, now time.Time) (siteResult, error) {
+	assets, err := webAssets(opts.Templates)
+	if err != nil {
+		return siteResult{}, err
+	}
+	b := &siteBuilder{app: app, opts: opts, assets: assets, now: now, pages: make(map[int]map[string]*template.Template)}
+	if err := os.MkdirAll(opts.Out, 0o755); err != nil {
+		return siteResult{}, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	bibs, err := app.BibService.ListBibliographies(0, 0)
+	if err != nil {
+		return siteResult{}, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+	for _, step := range []func([]*domain.Bibliography) error{
+		b.writeBibliographies,
+		b.writeClassifications,
+		b.writeYears,
+		b.writeFeed,
+	} {
+		if err := step(bibs); err != nil {
+			return siteResult{}, err
+		}
+	}
+	stylesheet, err := fs.ReadFile(assets, "static/style.css")
+	if err != nil {
+		return siteResult{}, fmt.Errorf("failed to read stylesheet: %w", err)
+	}
+	if err := b.writeFile("static/style.css", stylesheet); err != nil {
+		return siteResult{}, err
+	}
+	if err := b.removeStale(); err != nil {
+		return siteResult{}, err
+	}
+	return b.result, nil
+}
+
+func (b *siteBuilder) writeBibliographies(bibs []*domain.Bibliography) error {
+	index := bibliographiesPage{webPage: webPage{Title: "Bibliographies"}, Bibliographies: bibs, Page: 1}
+	if err := b.writePage("", "bibliographies.html", index); err != nil {
+		return err
+	}
+	for _, bib := range bibs {
+		detail, err := b.app.BibliographyDetail(bib)
+		if err != nil {
+			return err
+		}
+		page := bibliographyPage{webPage: webPage{Title: bib.Title}, bibliographyDetail: detail}
+		if err := b.writePage("bibliographies/"+bibRef(bib), "bibliography.html", page); err != nil {
+			return err
+		}
+		b.result.Bibliographies++
+	}
+	return nil
+}
+
+func (b *siteBuilder) writeClassifications(bibs []*domain.Bibliography) error {
+	roots, err := b.app.BibService.ClassificationTree()
+	if err != nil {
+		return err
+	}
+	if err := b.writePage("classifications", "classifications.html", classificationsPage{webPage: webPage{Title: "Classifications"}, Roots: roots}); err != nil {
+		return err
+	}
+
+	var nodes []*domain.ClassNode
+	domain.Walk(roots, func(n *domain.ClassNode) { nodes = append(nodes, n) })
+	for _, node := range nodes {
+		class := node.Class
+		page := classificationPage{webPage: webPage{Title: fmt.Sprintf("%s %s", class.Code, class.Name)}, Node: node}
+		for p := node.Parent; p != nil; p = p.Parent {
+			page.Ancestors = append([]*domain.ClassNode{p}, page.Ancestors...)
+		}
+		// The same selection as FindBibliographies with ClassRecursive, without reading
+		// the bibliographies again for every class
+		for _, bib := range bibs {
+			if code, err := bib.ClassCode(); err == nil && code.Within(class.Code) {
+				page.Bibliographies = append(page.Bibliographies, bib)
+			}
+		}
+		if err := b.writePage("classifications/"+class.Code.String(), "classification.html", page); err != nil {
+			return err
+		}
+		b.result.Classifications++
+	}
+	return nil
+}
+
+// writeYears writes an archive page for every year bibliographies were published in,
+// newest first.
+func (b *siteBuilder) writeYears(bibs []*domain.Bibliography) error {
+	byYear := make(map[int][]*domain.Bibliography)
+	for _, bib := range bibs {
+		if !bib.PublishedDate.IsZero() {
+			byYear[bib.PublishedDate.Year()] = append(byYear[bib.PublishedDate.Year()], bib)
+		}
+	}
+	years := make([]yearArchive, 0, len(byYear))
+	for year, yearBibs := range byYear {
+		years = append(years, yearArchive{Year: year, Bibliographies: yearBibs})
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year > years[j].Year })
+
+	if err := b.writePage("years", "years.html", yearsPage{webPage: webPage{Title: "Years"}, Years: years}); err != nil {
+		return err
+	}
+	for _, archive := range years {
+		page := yearPage{webPage: webPage{Title: fmt.Sprintf("Published in %d", archive.Year)}, yearArchive: archive}
+		if err := b.writePage(fmt.Sprintf("years/%d", archive.Year), "year.html", page); err != nil {
+			return err
+		}
+		b.result.Years++
+	}
+	return nil
+}
+
+// writePage renders a page into dir/index.html, with links relative to dir.
+func (b *siteBuilder) writePage(dir, page string, data any) error {
+	depth := 0
+	if dir != "" {
+		depth = strings.Count(dir, "/") + 1
+	}
+	pages, ok := b.pages[depth]
+	if !ok {
+		site := webSite{Title: b.opts.Title, Root: strings.Repeat("../", depth), Suffix: "/", Static: true}
+		var err error
+		if pages, err = parseWebPages(b.assets, site); err != nil {
+			return err
+		}
+		b.pages[depth] = pages
+	}
+	tmpl, ok := pages[page]
+	if !ok {
+		return fmt.Errorf("template %s not found", page)
+	}
+	body, err := executePage(tmpl, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", path.Join(dir, "index.html"), err)
+	}
+	b.result.Pages++
+	return b.writeFile(path.Join(dir, "index.html"), body)
+}
+
+func (b *siteBuilder) writeFile(name string, data []byte) error {
+	file := filepath.Join(b.opts.Out, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	b.files = append(b.files, name)
+	return nil
+}
+
+// removeStale deletes the files listed in the manifest of the previous build that this
+// build did not write, with directories left empty, and records the new manifest.
+func (b *siteBuilder) removeStale() error {
+	manifest := filepath.Join(b.opts.Out, siteManifest)
+	previous, err := os.ReadFile(manifest)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", siteManifest, err)
+	}
+	written := make(map[string]bool, len(b.files))
+	for _, name := range b.files {
+		written[name] = true
+	}
+	for _, name := range strings.Split(string(previous), "\n") {
+		// Only plain paths below Out are removed, whatever the manifest says
+		if name == "" || written[name] || !fs.ValidPath(name) {
+			continue
+		}
+		file := filepath.Join(b.opts.Out, filepath.FromSlash(name))
+		if err := os.Remove(file); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		b.result.Removed++
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if os.Remove(filepath.Join(b.opts.Out, filepath.FromSlash(dir))) != nil {
+				break // not empty
+			}
+		}
+	}
+
+	sort.Strings(b.files)
+	if err := os.WriteFile(manifest, []byte(strings.Join(b.files, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", siteManifest, err)
+	}
+	return nil
+}
+
+// atomFeed is an Atom feed (RFC 4287) of reviews.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title     string   `xml:"title"`
+	ID        string   `xml:"id"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Link      atomLink `xml:"link"`
+	Summary   string   `xml:"summary,omitempty"`
+	Content   atomText `xml:"content"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// writeFeed writes feed.xml with the latest finished reviews: those with a Summary,
+// most recently updated first.
+func (b *siteBuilder) writeFeed(bibs []*domain.Bibliography) error {
+	byID := make(map[domain.BibliographyID]*domain.Bibliography, len(bibs))
+	for _, bib := range bibs {
+		byID[bib.ID] = bib
+	}
+	reviews, err := b.app.ReviewService.ListReviews(0, 0)
+	if err != nil {
+		return err
+	}
+	var finished []*domain.Review
+	for _, review := range reviews {
+		if strings.TrimSpace(review.Summary) != "" && byID[review.BookID] != nil {
+			finished = append(finished, review)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool { return finished[i].UpdatedAt.After(finished[j].UpdatedAt) })
+	if len(finished) > feedSize {
+		finished = finished[:feedSize]
+	}
+
+	site := webSite{Root: b.opts.BaseURL, Suffix: "/", Static: true}
+	feed := atomFeed{
+		Title:   b.opts.Title,
+		ID:      "urn:biblog:reviews",
+		Updated: b.now.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: b.opts.Title},
+		Links:   []atomLink{{Href: site.pageURL("")}},
+	}
+	if b.opts.BaseURL != "" {
+		feed.ID = b.opts.BaseURL + "feed.xml"
+		feed.Links = append(feed.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: feed.ID})
+	}
+	if len(finished) > 0 {
+		feed.Updated = finished[0].UpdatedAt.Format(time.RFC3339)
+	}
+	for _, review := range finished {
+		bib := byID[review.BookID]
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:     bib.Title,
+			ID:        "urn:uuid:" + review.ID.String(),
+			Published: review.CreatedAt.Format(time.RFC3339),
+			Updated:   review.UpdatedAt.Format(time.RFC3339),
+			Link:      atomLink{Href: site.pageURL("bibliographies/" + url.PathEscape(bibRef(bib)))},
+			Summary:   review.Goals,
+			Content:   atomText{Type: "text", Body: review.Summary},
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	b.result.FeedEntries = len(feed.Entries)
+	return b.writeFile("feed.xml", append([]byte(xml.Header), append(data, '\n')...))
+}
+
+// writeSiteTemplates copies the built-in templates and stylesheet into dir as a starting
+// point for -templates. Files already in dir are kept. It returns the names written and
+// the names skipped.
+func writeSiteTemplates(dir string) (written, skipped []string, err error) {
+	assets, err := webAssets("")
+	if err != nil {
+		return nil, nil, err
+	}
+	names, err := fs.Glob(assets, "templates/*.html")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	for _, name := range append(names, "static/style.css") {
+		data, err := fs.ReadFile(assets, name)
+		if err != nil {
+			return written, skipped, err
+		}
+		base := path.Base(name)
+		file, err := os.OpenFile(filepath.Join(dir, base), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			skipped = append(skipped, base)
+			continue
+		}
+		if err != nil {
+			return written, skipped, fmt.Errorf("failed to create %s: %w", base, err)
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return written, skipped, fmt.Errorf("failed to write %s: %w", base, err)
+		}
+		written = append(written, base)
+	}
+	return written, skipped, nil
+}
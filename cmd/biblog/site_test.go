@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bibliography_log/internal/service"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readSiteFile(t *testing.T, out, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatalf("Expected %s in the site: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestBuildSite(t *testing.T) {
+	app, err := NewApp(Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	defer app.Close()
+	for _, class := range [][2]string{{"5", "技術"}, {"54", "電気工学"}} {
+		if _, err := app.BibService.AddClassification(class[0], class[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ddd, err := app.BibService.AddBibliography("ドメイン駆動設計入門", "成瀬允宣", nil, "翔泳社", "", "Book", "54",
+		time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), "", "", "B54NM20DD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := app.BibService.AddBibliography("Other", "Someone", nil, "", "", "Book", "5",
+		time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "B5SO19OT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ReviewService.AddReview(ddd.ID, "設計を学ぶ", "まとめ"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ReviewService.AddReview(other.ID, "Only goals so far", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "public")
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "CNAME"), []byte("example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := siteOptions{Out: out, Title: "読書記録", BaseURL: "https://example.github.io/log/"}
+	result, err := buildSite(app, opts, time.Now())
+	if err != nil {
+		t.Fatalf("buildSite failed: %v", err)
+	}
+	if result.Bibliographies != 2 || result.Classifications != 2 || result.Years != 2 || result.FeedEntries != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	index := readSiteFile(t, out, "index.html")
+	for _, want := range []string{`href="bibliographies/B54NM20DD/"`, `href="static/style.css"`, "<title>Bibliographies - 読書記録</title>"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("Expected %q in index.html", want)
+		}
+	}
+	if strings.Contains(index, `name="q"`) {
+		t.Error("Expected no search box in the static site")
+	}
+	page := readSiteFile(t, out, "bibliographies/B54NM20DD/index.html")
+	for _, want := range []string{"まとめ", `href="../../classifications/54/"`, `href="../../years/2020/"`, `href="../../static/style.css"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in the page of B54NM20DD", want)
+		}
+	}
+	if class := readSiteFile(t, out, "classifications/5/index.html"); !strings.Contains(class, "ドメイン駆動設計入門") || !strings.Contains(class, "Other") {
+		t.Error("Expected class 5 to list the bibliographies of its subclasses")
+	}
+	if year := readSiteFile(t, out, "years/2019/index.html"); !strings.Contains(year, "Other") || strings.Contains(year, "ドメイン駆動設計入門") {
+		t.Error("Expected 2019 to list Other only")
+	}
+	readSiteFile(t, out, "static/style.css")
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(readSiteFile(t, out, "feed.xml")), &feed); err != nil {
+		t.Fatalf("Failed to parse feed: %v", err)
+	}
+	if len(feed.Entries) != 1 || feed.Entries[0].Title != "ドメイン駆動設計入門" ||
+		feed.Entries[0].Link.Href != "https://example.github.io/log/bibliographies/B54NM20DD/" {
+		t.Errorf("Expected the finished review only, got %+v", feed.Entries)
+	}
+
+	// Pages that are gone are removed on the next build; other files are left alone
+	if _, _, err := app.BibService.DeleteBibliography(other.ID, service.CascadeReviews); err != nil {
+		t.Fatal(err)
+	}
+	result, err = buildSite(app, opts, time.Now())
+	if err != nil {
+		t.Fatalf("Second buildSite failed: %v", err)
+	}
+	if result.Removed != 2 {
+		t.Errorf("Expected the page of Other and its year to be removed, got %d", result.Removed)
+	}
+	for _, gone := range []string{"bibliographies/B5SO19OT", "years/2019"} {
+		if _, err := os.Stat(filepath.Join(out, gone)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", gone, err)
+		}
+	}
+	readSiteFile(t, out, "CNAME")
+}
+
+func TestBuildSite_Templates(t *testing.T) {
+	app, err := NewApp(Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	defer app.Close()
+
+	templates := t.TempDir()
+	written, skipped, err := writeSiteTemplates(templates)
+	if err != nil || len(written) == 0 || len(skipped) != 0 {
+		t.Fatalf("writeSiteTemplates() = %v, %v, %v", written, skipped, err)
+	}
+	// Files already there are kept
+	custom := `{{define "content"}}<p>Custom years page</p>{{end}}`
+	if err := os.WriteFile(filepath.Join(templates, "years.html"), []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, skipped, err := writeSiteTemplates(templates); err != nil || len(skipped) != len(written) {
+		t.Errorf("Expected every file to be kept, got %v, %v", skipped, err)
+	}
+	// Only years.html is overridden; the others fall back to the built-in templates
+	for _, name := range written {
+		if name != "years.html" {
+			os.Remove(filepath.Join(templates, name))
+		}
+	}
+
+	out := t.TempDir()
+	if _, err := buildSite(app, siteOptions{Out: out, Title: "Log", Templates: templates}, time.Now()); err != nil {
+		t.Fatalf("buildSite failed: %v", err)
+	}
+	if years := readSiteFile(t, out, "years/index.html"); !strings.Contains(years, "Custom years page") {
+		t.Error("Expected the overriding years.html to be used")
+	}
+	if index := readSiteFile(t, out, "index.html"); !strings.Contains(index, "No bibliographies yet") {
+		t.Error("Expected the built-in bibliographies.html to be used")
+	}
+
+	if _, err := buildSite(app, siteOptions{Out: out, Title: "Log", Templates: filepath.Join(templates, "missing")}, time.Now()); err == nil {
+		t.Error("Expected an error for a missing templates directory")
+	}
+}
@@ -0,0 +1,839 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
+	"bibliography_log/internal/textdiff"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Views are the stable, machine-readable shapes of command results. Their JSON
+// names and CSV columns are part of the CLI's interface: add fields, but do not
+// rename or remove them.
+
+type bibliographyView struct {
+	ID            string            `json:"id"`
+	BibIndex      string            `json:"bib_index"`
+	Code          string            `json:"code"`
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Author        string            `json:"author"`
+	Contributors  []contributorView `json:"contributors"` // "[role:]Name[=NameEn]" joined by "; " in CSV
+	Publisher     string            `json:"publisher"`
+	ISBN          string            `json:"isbn"`           // 13 digits, no hyphens
+	PublishedDate string            `json:"published_date"` // YYYY-MM-DD
+}
+
+type contributorView struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	NameEn string `json:"name_en"`
+}
+
+func newBibliographyView(b *domain.Bibliography) bibliographyView {
+	return bibliographyView{
+		ID:            b.ID.String(),
+		BibIndex:      b.BibIndex,
+		Code:          b.Code,
+		Type:          b.Type,
+		Title:         b.Title,
+		Author:        b.Author,
+		Contributors:  newContributorViews(b.Contributors),
+		Publisher:     b.Publisher,
+		ISBN:          b.ISBN.Compact(),
+		PublishedDate: b.PublishedDate.Format(time.DateOnly),
+	}
+}
+
+func newContributorViews(contributors []domain.Contributor) []contributorView {
+	views := make([]contributorView, 0, len(contributors))
+	for _, c := range contributors {
+		views = append(views, contributorView{Name: c.Name, Role: string(c.Role), NameEn: c.NameEn})
+	}
+	return views
+}
+
+func newBibliographyViews(bibs []*domain.Bibliography) []bibliographyView {
+	views := make([]bibliographyView, 0, len(bibs))
+	for _, b := range bibs {
+		views = append(views, newBibliographyView(b))
+	}
+	return views
+}
+
+func (v bibliographyView) columns() []string {
+	return []string{"id", "bib_index", "code", "type", "title", "author", "publisher", "isbn", "published_date", "contributors"}
+}
+
+func (v bibliographyView) values() []string {
+	contributors := make([]string, 0, len(v.Contributors))
+	for _, c := range v.Contributors {
+		contributors = append(contributors, domain.Contributor{Name: c.Name, Role: domain.ContributorRole(c.Role), NameEn: c.NameEn}.String())
+	}
+	return []string{v.ID, v.BibIndex, v.Code, v.Type, v.Title, v.Author, v.Publisher, v.ISBN, v.PublishedDate, strings.Join(contributors, "; ")}
+}
+
+type reviewView struct {
+	ID        string `json:"id"`
+	BookID    string `json:"book_id"`
+	Goals     string `json:"goals"`
+	Summary   string `json:"summary"`
+	CreatedAt string `json:"created_at"` // RFC3339
+	UpdatedAt string `json:"updated_at"` // RFC3339
+}
+
+func newReviewView(r *domain.Review) reviewView {
+	return reviewView{
+		ID:        r.ID.String(),
+		BookID:    r.BookID.String(),
+		Goals:     r.Goals,
+		Summary:   r.Summary,
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func newReviewViews(reviews []*domain.Review) []reviewView {
+	views := make([]reviewView, 0, len(reviews))
+	for _, r := range reviews {
+		views = append(views, newReviewView(r))
+	}
+	return views
+}
+
+func (v reviewView) columns() []string {
+	return []string{"id", "book_id", "goals", "summary", "created_at", "updated_at"}
+}
+
+func (v reviewView) values() []string {
+	return []string{v.ID, v.BookID, v.Goals, v.Summary, v.CreatedAt, v.UpdatedAt}
+}
+
+// reviewRevisionView is one revision of review-history or revert-review. Changed lists
+// the fields that differ from the previous revision.
+type reviewRevisionView struct {
+	ReviewID   string   `json:"review_id"`
+	Revision   int      `json:"revision"`
+	Action     string   `json:"action"`
+	RevertedTo int      `json:"reverted_to,omitempty"`
+	ChangedBy  string   `json:"changed_by"`
+	CreatedAt  string   `json:"created_at"` // RFC3339
+	Changed    []string `json:"changed"`    // comma-separated in CSV
+	Goals      string   `json:"goals"`
+	Summary    string   `json:"summary"`
+}
+
+func newReviewRevisionView(r, prev *domain.ReviewRevision) reviewRevisionView {
+	changed := r.ChangedFields(prev)
+	if changed == nil {
+		changed = []string{} // [] rather than null
+	}
+	return reviewRevisionView{
+		ReviewID:   r.ReviewID.String(),
+		Revision:   r.Number,
+		Action:     string(r.Action),
+		RevertedTo: r.RevertedTo,
+		ChangedBy:  r.ChangedBy,
+		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
+		Changed:    changed,
+		Goals:      r.Goals,
+		Summary:    r.Summary,
+	}
+}
+
+func newReviewRevisionViews(history domain.ReviewHistory) []reviewRevisionView {
+	views := make([]reviewRevisionView, 0, len(history))
+	for _, r := range history {
+		views = append(views, newReviewRevisionView(r, history.Previous(r)))
+	}
+	return views
+}
+
+func (v reviewRevisionView) columns() []string {
+	return []string{"review_id", "revision", "action", "reverted_to", "changed_by", "created_at", "changed", "goals", "summary"}
+}
+
+func (v reviewRevisionView) values() []string {
+	return []string{v.ReviewID, strconv.Itoa(v.Revision), v.Action, strconv.Itoa(v.RevertedTo), v.ChangedBy, v.CreatedAt,
+		strings.Join(v.Changed, ","), v.Goals, v.Summary}
+}
+
+// diffLineView is one line of diff-review. From is 0 when the first revision is compared
+// with an empty review.
+type diffLineView struct {
+	Field string `json:"field"` // "goals" or "summary"
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Op    string `json:"op"` // "equal", "delete" or "insert"
+	Text  string `json:"text"`
+}
+
+func newDiffLineViews(d *service.ReviewDiff) []diffLineView {
+	from := 0
+	if d.From != nil {
+		from = d.From.Number
+	}
+	views := []diffLineView{}
+	for _, field := range []struct {
+		name  string
+		lines []textdiff.Line
+	}{{"goals", d.Goals}, {"summary", d.Summary}} {
+		for _, line := range field.lines {
+			views = append(views, diffLineView{Field: field.name, From: from, To: d.To.Number, Op: line.Op.String(), Text: line.Text})
+		}
+	}
+	return views
+}
+
+func (v diffLineView) columns() []string {
+	return []string{"field", "from", "to", "op", "text"}
+}
+
+func (v diffLineView) values() []string {
+	return []string{v.Field, strconv.Itoa(v.From), strconv.Itoa(v.To), v.Op, v.Text}
+}
+
+type classificationView struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func newClassificationView(c *domain.Classification) classificationView {
+	return classificationView{ID: c.ID.String(), Code: c.Code.String(), Name: c.Name}
+}
+
+func (v classificationView) columns() []string {
+	return []string{"id", "code", "name"}
+}
+
+func (v classificationView) values() []string {
+	return []string{v.ID, v.Code, v.Name}
+}
+
+// reclassificationView is a bibliography changed by renumber-class or delete-class.
+type reclassificationView struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	OldCode     string `json:"old_code"`
+	Code        string `json:"code"`
+	OldBibIndex string `json:"old_bib_index"`
+	BibIndex    string `json:"bib_index"`
+}
+
+// classificationChangeView is the result of renumber-class and delete-class: the
+// classification as renumbered or deleted and the bibliographies that were (or, in a dry
+// run, would be) changed. As a CSV row the bibliographies are reduced to a count.
+type classificationChangeView struct {
+	classificationView
+	DryRun         bool                   `json:"dry_run"`
+	Bibliographies []reclassificationView `json:"bibliographies"`
+}
+
+func newClassificationChangeView(class *domain.Classification, moves []service.Reclassification, dryRun bool) classificationChangeView {
+	v := classificationChangeView{classificationView: newClassificationView(class), DryRun: dryRun, Bibliographies: []reclassificationView{}}
+	for _, move := range moves {
+		v.Bibliographies = append(v.Bibliographies, reclassificationView{
+			ID:          move.Bibliography.ID.String(),
+			Title:       move.Bibliography.Title,
+			OldCode:     move.Bibliography.Code,
+			Code:        move.Code,
+			OldBibIndex: move.Bibliography.BibIndex,
+			BibIndex:    move.BibIndex,
+		})
+	}
+	return v
+}
+
+func (v classificationChangeView) columns() []string {
+	return append(v.classificationView.columns(), "dry_run", "bibliography_count")
+}
+
+func (v classificationChangeView) values() []string {
+	return append(v.classificationView.values(), strconv.FormatBool(v.DryRun), strconv.Itoa(len(v.Bibliographies)))
+}
+
+// classNodeView is one class of list-class. Count is the number of bibliographies filed
+// under the class itself, Total includes its subclasses.
+type classNodeView struct {
+	classificationView
+	Parent string `json:"parent"` // code of the parent class; empty for a top-level class
+	Depth  int    `json:"depth"`
+	Count  int    `json:"count"`
+	Total  int    `json:"total"`
+}
+
+// newClassNodeViews flattens the tree, each class followed by its subclasses.
+func newClassNodeViews(roots []*domain.ClassNode) []classNodeView {
+	var views []classNodeView
+	domain.Walk(roots, func(n *domain.ClassNode) {
+		v := classNodeView{classificationView: newClassificationView(n.Class), Depth: n.Depth(), Count: n.Count, Total: n.Total}
+		if n.Parent != nil {
+			v.Parent = n.Parent.Class.Code.String()
+		}
+		views = append(views, v)
+	})
+	return views
+}
+
+func (v classNodeView) columns() []string {
+	return append(v.classificationView.columns(), "parent", "depth", "count", "total")
+}
+
+func (v classNodeView) values() []string {
+	return append(v.classificationView.values(), v.Parent, strconv.Itoa(v.Depth), strconv.Itoa(v.Count), strconv.Itoa(v.Total))
+}
+
+// classificationImportResultView is one class of a seed-class report. Status is "created",
+// "would_create" (dry run) or "skipped".
+type classificationImportResultView struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func newClassificationImportResultViews(results []service.ClassificationImportResult, dryRun bool) []classificationImportResultView {
+	views := make([]classificationImportResultView, 0, len(results))
+	for _, r := range results {
+		v := classificationImportResultView{Code: r.Item.Code, Name: r.Item.Name}
+		switch {
+		case r.Skipped():
+			v.Status, v.Reason = "skipped", r.SkipReason
+		case dryRun:
+			v.Status = "would_create"
+		default:
+			v.Status, v.ID = "created", r.Classification.ID.String()
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+func (v classificationImportResultView) columns() []string {
+	return []string{"code", "name", "status", "id", "reason"}
+}
+
+func (v classificationImportResultView) values() []string {
+	return []string{v.Code, v.Name, v.Status, v.ID, v.Reason}
+}
+
+// bibliographyDetailView is the result of show. As a CSV row the reviews, sessions and
+// highlights are reduced to counts and the reading history to the current status.
+type bibliographyDetailView struct {
+	bibliographyView
+	Classification *classificationView       `json:"classification"` // null if unresolved
+	ReadingStatus  string                    `json:"reading_status"` // "none" if untracked
+	ReadingHistory []readingStatusChangeView `json:"reading_history"`
+	Progress       *progressView             `json:"progress"` // null if no sessions
+	Sessions       []readingSessionView      `json:"sessions"`
+	Highlights     []highlightView           `json:"highlights"`
+	Reviews        []reviewView              `json:"reviews"`
+}
+
+func newBibliographyDetailView(d bibliographyDetail) bibliographyDetailView {
+	v := bibliographyDetailView{
+		bibliographyView: newBibliographyView(d.Bibliography),
+		ReadingStatus:    d.Reading.Current().String(),
+		ReadingHistory:   []readingStatusChangeView{},
+		Sessions:         []readingSessionView{},
+		Highlights:       []highlightView{},
+		Reviews:          []reviewView{},
+	}
+	if d.Classification != nil {
+		cv := newClassificationView(d.Classification)
+		v.Classification = &cv
+	}
+	for _, change := range d.Reading {
+		v.ReadingHistory = append(v.ReadingHistory, newReadingStatusChangeView(change))
+	}
+	if len(d.Sessions) > 0 {
+		pv := newProgressView(domain.ComputeProgress(d.Sessions))
+		v.Progress = &pv
+	}
+	for _, session := range d.Sessions {
+		v.Sessions = append(v.Sessions, newReadingSessionView(session))
+	}
+	for _, h := range d.Highlights {
+		v.Highlights = append(v.Highlights, newHighlightView(h))
+	}
+	for _, r := range d.Reviews {
+		v.Reviews = append(v.Reviews, newReviewView(r))
+	}
+	return v
+}
+
+func (v bibliographyDetailView) columns() []string {
+	return append(v.bibliographyView.columns(), "classification", "reading_status", "review_count", "session_count", "highlight_count")
+}
+
+func (v bibliographyDetailView) values() []string {
+	className := ""
+	if v.Classification != nil {
+		className = v.Classification.Name
+	}
+	return append(v.bibliographyView.values(), className, v.ReadingStatus, strconv.Itoa(len(v.Reviews)), strconv.Itoa(len(v.Sessions)), strconv.Itoa(len(v.Highlights)))
+}
+
+// readingStatusChangeView is one entry of a reading history. From is "none" for the first change.
+type readingStatusChangeView struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ChangedAt string `json:"changed_at"` // RFC3339
+}
+
+func newReadingStatusChangeView(c *domain.ReadingStatusChange) readingStatusChangeView {
+	return readingStatusChangeView{From: c.From.String(), To: c.To.String(), ChangedAt: c.ChangedAt.Format(time.RFC3339)}
+}
+
+// statusChangeView is the result of queue, start, finish and abandon.
+type statusChangeView struct {
+	ID       string `json:"id"`
+	BibIndex string `json:"bib_index"`
+	Title    string `json:"title"`
+	readingStatusChangeView
+}
+
+func newStatusChangeView(bib *domain.Bibliography, c *domain.ReadingStatusChange) statusChangeView {
+	return statusChangeView{ID: bib.ID.String(), BibIndex: bib.BibIndex, Title: bib.Title, readingStatusChangeView: newReadingStatusChangeView(c)}
+}
+
+func (v statusChangeView) columns() []string {
+	return []string{"id", "bib_index", "title", "from", "to", "changed_at"}
+}
+
+func (v statusChangeView) values() []string {
+	return []string{v.ID, v.BibIndex, v.Title, v.From, v.To, v.ChangedAt}
+}
+
+// deletedBibliographyView is the result of delete-bib.
+type deletedBibliographyView struct {
+	bibliographyView
+	DeletedReviews  int `json:"deleted_reviews"`
+	OrphanedReviews int `json:"orphaned_reviews"`
+}
+
+func (v deletedBibliographyView) columns() []string {
+	return append(v.bibliographyView.columns(), "deleted_reviews", "orphaned_reviews")
+}
+
+func (v deletedBibliographyView) values() []string {
+	return append(v.bibliographyView.values(), strconv.Itoa(v.DeletedReviews), strconv.Itoa(v.OrphanedReviews))
+}
+
+type searchResultView struct {
+	bibliographyView
+	Score         float64  `json:"score"`
+	MatchedFields []string `json:"matched_fields"`
+	ReviewIDs     []string `json:"review_ids"` // reviews that matched
+}
+
+func newSearchResultViews(results []service.SearchResult) []searchResultView {
+	views := make([]searchResultView, 0, len(results))
+	for _, r := range results {
+		v := searchResultView{
+			bibliographyView: newBibliographyView(r.Bibliography),
+			Score:            r.Score,
+			MatchedFields:    r.Fields,
+			ReviewIDs:        []string{},
+		}
+		for _, review := range r.Reviews {
+			v.ReviewIDs = append(v.ReviewIDs, review.ID.String())
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+func (v searchResultView) columns() []string {
+	return append(v.bibliographyView.columns(), "score", "matched_fields", "review_ids")
+}
+
+func (v searchResultView) values() []string {
+	return append(v.bibliographyView.values(),
+		strconv.FormatFloat(v.Score, 'f', 4, 64),
+		strings.Join(v.MatchedFields, ";"),
+		strings.Join(v.ReviewIDs, ";"))
+}
+
+// importResultView is one item of an import report. Status is "created",
+// "would_create" (dry run) or "skipped".
+type importResultView struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	ID       string `json:"id,omitempty"`
+	BibIndex string `json:"bib_index,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func newImportResultViews(results []service.ImportResult, dryRun bool) []importResultView {
+	views := make([]importResultView, 0, len(results))
+	for _, r := range results {
+		v := importResultView{Key: r.Item.Key, Title: r.Item.Title}
+		switch {
+		case r.Skipped():
+			v.Status, v.Reason = "skipped", r.SkipReason
+		case dryRun:
+			v.Status, v.BibIndex = "would_create", r.Bibliography.BibIndex
+		default:
+			v.Status, v.ID, v.BibIndex = "created", r.Bibliography.ID.String(), r.Bibliography.BibIndex
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+func (v importResultView) columns() []string {
+	return []string{"key", "title", "status", "id", "bib_index", "reason"}
+}
+
+func (v importResultView) values() []string {
+	return []string{v.Key, v.Title, v.Status, v.ID, v.BibIndex, v.Reason}
+}
+
+// kindleImportResultView is one clipping of an import-kindle report. Status is
+// "imported", "would_import" (dry run) or "skipped".
+type kindleImportResultView struct {
+	BookTitle string `json:"book_title"`
+	BibIndex  string `json:"bib_index,omitempty"`
+	Location  string `json:"location"`
+	Status    string `json:"status"`
+	ID        string `json:"id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func newKindleImportResultViews(imports []kindleImport, dryRun bool) []kindleImportResultView {
+	var views []kindleImportResultView
+	for _, imp := range imports {
+		if imp.Bibliography == nil {
+			for _, c := range imp.Book.Clippings {
+				views = append(views, kindleImportResultView{BookTitle: imp.Book.Title, Location: c.Label(), Status: "skipped", Reason: imp.SkipReason})
+			}
+			continue
+		}
+		for _, r := range imp.Results {
+			v := kindleImportResultView{BookTitle: imp.Book.Title, BibIndex: imp.Bibliography.BibIndex, Location: r.Item.Location}
+			switch {
+			case r.Skipped():
+				v.Status, v.Reason = "skipped", r.SkipReason
+			case dryRun:
+				v.Status = "would_import"
+			default:
+				v.Status, v.ID = "imported", r.Highlight.ID.String()
+			}
+			views = append(views, v)
+		}
+	}
+	return views
+}
+
+func (v kindleImportResultView) columns() []string {
+	return []string{"book_title", "bib_index", "location", "status", "id", "reason"}
+}
+
+func (v kindleImportResultView) values() []string {
+	return []string{v.BookTitle, v.BibIndex, v.Location, v.Status, v.ID, v.Reason}
+}
+
+// duplicateBibIndexView is one bibliography sharing a BibIndex, reported by check-indexes.
+type duplicateBibIndexView struct {
+	BibIndex string `json:"bib_index"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+}
+
+func newDuplicateBibIndexViews(duplicates []service.DuplicateBibIndex) []duplicateBibIndexView {
+	var views []duplicateBibIndexView
+	for _, dup := range duplicates {
+		for _, b := range dup.Bibliographies {
+			views = append(views, duplicateBibIndexView{BibIndex: dup.BibIndex, ID: b.ID.String(), Title: b.Title})
+		}
+	}
+	return views
+}
+
+func (v duplicateBibIndexView) columns() []string {
+	return []string{"bib_index", "id", "title"}
+}
+
+func (v duplicateBibIndexView) values() []string {
+	return []string{v.BibIndex, v.ID, v.Title}
+}
+
+// reindexView is the result of reindex.
+type reindexView struct {
+	Bibliographies int `json:"bibliographies"`
+	Reviews        int `json:"reviews"`
+}
+
+func (v reindexView) columns() []string {
+	return []string{"bibliographies", "reviews"}
+}
+
+func (v reindexView) values() []string {
+	return []string{strconv.Itoa(v.Bibliographies), strconv.Itoa(v.Reviews)}
+}
+
+// isbnMigrationView is one ISBN reported by migrate-isbn. Status is "normalized",
+// "invalid" (left unchanged) or "cleared".
+type isbnMigrationView struct {
+	ID       string `json:"id"`
+	BibIndex string `json:"bib_index"`
+	Title    string `json:"title"`
+	Old      string `json:"old_isbn"`
+	New      string `json:"new_isbn"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newISBNMigrationViews(migrations []domain.ISBNMigration) []isbnMigrationView {
+	views := make([]isbnMigrationView, 0, len(migrations))
+	for _, m := range migrations {
+		v := isbnMigrationView{ID: m.BibliographyID, BibIndex: m.BibIndex, Title: m.Title, Old: m.Old, New: m.New, Status: "normalized"}
+		if m.Err != nil {
+			v.Status, v.Error = "invalid", m.Err.Error()
+			if m.Cleared {
+				v.Status = "cleared"
+			}
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+func (v isbnMigrationView) columns() []string {
+	return []string{"id", "bib_index", "title", "old_isbn", "new_isbn", "status", "error"}
+}
+
+func (v isbnMigrationView) values() []string {
+	return []string{v.ID, v.BibIndex, v.Title, v.Old, v.New, v.Status, v.Error}
+}
+
+type readingSessionView struct {
+	ID              string `json:"id"`
+	BookID          string `json:"book_id"`
+	Date            string `json:"date"` // YYYY-MM-DD
+	DurationSeconds int    `json:"duration_seconds"`
+	Unit            string `json:"unit"` // pages or percent
+	Start           int    `json:"start"`
+	End             int    `json:"end"`
+	TotalPages      int    `json:"total_pages"` // 0 if unknown
+	Note            string `json:"note"`
+}
+
+func newReadingSessionView(s *domain.ReadingSession) readingSessionView {
+	return readingSessionView{
+		ID:              s.ID.String(),
+		BookID:          s.BookID.String(),
+		Date:            s.Date.Format(time.DateOnly),
+		DurationSeconds: int(s.Duration.Seconds()),
+		Unit:            string(s.Unit),
+		Start:           s.Start,
+		End:             s.End,
+		TotalPages:      s.TotalPages,
+		Note:            s.Note,
+	}
+}
+
+// progressView summarizes the reading sessions of a bibliography.
+type progressView struct {
+	Sessions        int     `json:"sessions"`
+	TotalSeconds    int     `json:"total_seconds"`
+	Unit            string  `json:"unit"` // pages or percent
+	Position        int     `json:"position"`
+	Total           int     `json:"total"`     // 0 if the page count is unknown
+	Remaining       int     `json:"remaining"` // -1 if the page count is unknown
+	PacePerDay      float64 `json:"pace_per_day"`
+	EstimatedFinish string  `json:"estimated_finish"` // YYYY-MM-DD, empty if unknown
+}
+
+func newProgressView(p domain.ReadingProgress) progressView {
+	v := progressView{
+		Sessions:     p.Sessions,
+		TotalSeconds: int(p.TotalTime.Seconds()),
+		Unit:         string(p.Unit),
+		Position:     p.Position,
+		Total:        p.Total,
+		Remaining:    p.Remaining(),
+		PacePerDay:   p.PacePerDay,
+	}
+	if !p.EstimatedFinish.IsZero() {
+		v.EstimatedFinish = p.EstimatedFinish.Format(time.DateOnly)
+	}
+	return v
+}
+
+// sessionLoggedView is the result of log-session.
+type sessionLoggedView struct {
+	readingSessionView
+	BibIndex string       `json:"bib_index"`
+	Title    string       `json:"title"`
+	Progress progressView `json:"progress"`
+}
+
+func newSessionLoggedView(bib *domain.Bibliography, s *domain.ReadingSession, p domain.ReadingProgress) sessionLoggedView {
+	return sessionLoggedView{readingSessionView: newReadingSessionView(s), BibIndex: bib.BibIndex, Title: bib.Title, Progress: newProgressView(p)}
+}
+
+func (v sessionLoggedView) columns() []string {
+	return []string{"id", "book_id", "bib_index", "title", "date", "duration_seconds", "unit", "start", "end", "total_pages", "note", "remaining", "estimated_finish"}
+}
+
+func (v sessionLoggedView) values() []string {
+	return []string{v.ID, v.BookID, v.BibIndex, v.Title, v.Date, strconv.Itoa(v.DurationSeconds), v.Unit, strconv.Itoa(v.Start), strconv.Itoa(v.End),
+		strconv.Itoa(v.TotalPages), v.Note, strconv.Itoa(v.Progress.Remaining), v.Progress.EstimatedFinish}
+}
+
+type highlightView struct {
+	ID        string   `json:"id"`
+	BookID    string   `json:"book_id"`
+	Text      string   `json:"text"`
+	Location  string   `json:"location"`
+	Comment   string   `json:"comment"`
+	Tags      []string `json:"tags"`       // comma-separated in CSV
+	CreatedAt string   `json:"created_at"` // RFC3339
+}
+
+func newHighlightView(h *domain.Highlight) highlightView {
+	tags := h.Tags
+	if tags == nil {
+		tags = []string{} // [] rather than null
+	}
+	return highlightView{
+		ID:        h.ID.String(),
+		BookID:    h.BookID.String(),
+		Text:      h.Text,
+		Location:  h.Location,
+		Comment:   h.Comment,
+		Tags:      tags,
+		CreatedAt: h.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// quoteView is a highlight as reported by add-quote and list-quotes, with the
+// BibIndex of its bibliography.
+type quoteView struct {
+	highlightView
+	BibIndex string `json:"bib_index"`
+}
+
+func (v quoteView) columns() []string {
+	return []string{"id", "book_id", "bib_index", "location", "text", "comment", "tags", "created_at"}
+}
+
+func (v quoteView) values() []string {
+	return []string{v.ID, v.BookID, v.BibIndex, v.Location, v.Text, v.Comment, strings.Join(v.Tags, ","), v.CreatedAt}
+}
+
+// siteView is the result of site build.
+type siteView struct {
+	Out             string `json:"out"`
+	Pages           int    `json:"pages"`
+	Bibliographies  int    `json:"bibliographies"`
+	Classifications int    `json:"classifications"`
+	Years           int    `json:"years"`
+	FeedEntries     int    `json:"feed_entries"`
+	Removed         int    `json:"removed"`
+}
+
+func newSiteView(out string, r siteResult) siteView {
+	return siteView{
+		Out:             out,
+		Pages:           r.Pages,
+		Bibliographies:  r.Bibliographies,
+		Classifications: r.Classifications,
+		Years:           r.Years,
+		FeedEntries:     r.FeedEntries,
+		Removed:         r.Removed,
+	}
+}
+
+func (v siteView) columns() []string {
+	return []string{"out", "pages", "bibliographies", "classifications", "years", "feed_entries", "removed"}
+}
+
+func (v siteView) values() []string {
+	return []string{v.Out, strconv.Itoa(v.Pages), strconv.Itoa(v.Bibliographies), strconv.Itoa(v.Classifications),
+		strconv.Itoa(v.Years), strconv.Itoa(v.FeedEntries), strconv.Itoa(v.Removed)}
+}
+
+// siteTemplateView is one file of site templates. Status is "written" or "skipped"
+// (already in the directory).
+type siteTemplateView struct {
+	File   string `json:"file"`
+	Status string `json:"status"`
+}
+
+func newSiteTemplateViews(written, skipped []string) []siteTemplateView {
+	views := make([]siteTemplateView, 0, len(written)+len(skipped))
+	for _, name := range written {
+		views = append(views, siteTemplateView{File: name, Status: "written"})
+	}
+	for _, name := range skipped {
+		views = append(views, siteTemplateView{File: name, Status: "skipped"})
+	}
+	return views
+}
+
+func (v siteTemplateView) columns() []string {
+	return []string{"file", "status"}
+}
+
+func (v siteTemplateView) values() []string {
+	return []string{v.File, v.Status}
+}
+
+// noteExportView is one note written, or in a dry run to be written, by export-notes.
+// Status is "created", "updated", "unchanged" or "skipped".
+type noteExportView struct {
+	BibIndex string `json:"bib_index"`
+	File     string `json:"file"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func newNoteExportViews(exports []noteExport) []noteExportView {
+	views := make([]noteExportView, 0, len(exports))
+	for _, e := range exports {
+		views = append(views, noteExportView{BibIndex: e.Bibliography.BibIndex, File: e.File, Status: e.Status, Reason: e.SkipReason})
+	}
+	return views
+}
+
+func (v noteExportView) columns() []string {
+	return []string{"bib_index", "file", "status", "reason"}
+}
+
+func (v noteExportView) values() []string {
+	return []string{v.BibIndex, v.File, v.Status, v.Reason}
+}
+
+// noteSyncView is one review pushed back, or a note looked at, by sync-notes. Status is
+// "pushed", "conflict", "invalid", "unchanged" or "skipped"; review_id is empty for
+// notes as a whole.
+type noteSyncView struct {
+	File     string `json:"file"`
+	ReviewID string `json:"review_id,omitempty"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func newNoteSyncViews(results []noteSync) []noteSyncView {
+	views := make([]noteSyncView, 0, len(results))
+	for _, r := range results {
+		views = append(views, noteSyncView{File: r.File, ReviewID: r.ReviewID, Status: r.Status, Reason: r.Reason})
+	}
+	return views
+}
+
+func (v noteSyncView) columns() []string {
+	return []string{"file", "review_id", "status", "reason"}
+}
+
+func (v noteSyncView) values() []string {
+	return []string{v.File, v.ReviewID, v.Status, v.Reason}
+}
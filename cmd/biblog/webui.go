@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/service"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webFS holds the templates and stylesheet of the web UI and static site, so that
+// neither needs files besides the data or loads anything from the network.
+//
+//go:embed web
+var webFS embed.FS
+
+// webPageSize is the number of bibliographies on one page of the table.
+const webPageSize = 50
+
+// webSearchLimit bounds the results shown for a search.
+const webSearchLimit = 50
+
+// webUI serves the read-only HTML pages of 'biblog serve'.
+type webUI struct {
+	app   *App
+	pages map[string]*template.Template
+}
+
+// webPage is what every page passes to the layout.
+type webPage struct {
+	Title string
+	Query string // shown in the search box
+}
+
+type bibliographiesPage struct {
+	webPage
+	Bibliographies []*domain.Bibliography
+	Page           int
+	PrevURL        string
+	NextURL        string
+}
+
+type bibliographyPage struct {
+	webPage
+	bibliographyDetail
+}
+
+type classificationsPage struct {
+	webPage
+	Roots []*domain.ClassNode
+}
+
+type classificationPage struct {
+	webPage
+	Node           *domain.ClassNode
+	Ancestors      []*domain.ClassNode // root first
+	Bibliographies []*domain.Bibliography
+}
+
+type searchPage struct {
+	webPage
+	Results []service.SearchResult
+}
+
+type errorPage struct {
+	webPage
+	Status  int
+	Message string
+}
+
+// newServerHandler serves the JSON API under /api/ and the web UI everywhere else.
+func newServerHandler(app *App) (http.Handler, error) {
+	web, err := newWebHandler(app)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/api/", newAPIHandler(app))
+	mux.Handle("/", web)
+	return mux, nil
+}
+
+// newWebHandler routes the pages of the web UI and its stylesheet.
+func newWebHandler(app *App) (http.Handler, error) {
+	assets, err := webAssets("")
+	if err != nil {
+		return nil, err
+	}
+	pages, err := parseWebPages(assets, webSite{Title: "biblog", Root: "/"})
+	if err != nil {
+		return nil, err
+	}
+	static, err := fs.Sub(assets, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &webUI{app: app, pages: pages}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.listBibliographies)
+	mux.HandleFunc("GET /bibliographies/{ref}", s.showBibliography)
+	mux.HandleFunc("GET /classifications", s.listClassifications)
+	mux.HandleFunc("GET /classifications/{code}", s.showClassification)
+	mux.HandleFunc("GET /search", s.search)
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s.renderError(w, http.StatusNotFound, fmt.Sprintf("no such page: %s", r.URL.Path))
+	})
+	return mux, nil
+}
+
+// webSite is where pages are published, which decides how they link to each other.
+// The server routes pages by absolute path; the static site links relatively to
+// directories holding an index.html, so that it works under any base path.
+type webSite struct {
+	Title  string
+	Root   string // leads from the page to the site root, e.g. "/" or "../../"
+	Suffix string // ends the link to a page, e.g. "/" for a directory
+	Static bool   // the static site, which has no search but year archives and a feed
+}
+
+// pageURL links to the page at path below the site root ("" for the home page).
+func (s webSite) pageURL(path string) string {
+	if path == "" {
+		if s.Root == "" {
+			return "./"
+		}
+		return s.Root
+	}
+	return s.Root + path + s.Suffix
+}
+
+// funcs are the helpers available to the templates.
+func (s webSite) funcs() template.FuncMap {
+	return template.FuncMap{
+		"site":    func() webSite { return s },
+		"pageURL": s.pageURL,
+		"assetURL": func(path string) string {
+			return s.Root + path
+		},
+		"bibURL": func(bib *domain.Bibliography) string {
+			return s.pageURL("bibliographies/" + url.PathEscape(bibRef(bib)))
+		},
+		"classURL": func(code domain.ClassCode) string {
+			return s.pageURL("classifications/" + url.PathEscape(code.String()))
+		},
+		"yearURL": func(year int) string {
+			return s.pageURL("years/" + strconv.Itoa(year))
+		},
+		"date": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(time.DateOnly)
+		},
+		"datetime": func(t time.Time) string {
+			return t.Format("2006-01-02 15:04")
+		},
+		"status":  func(h domain.ReadingHistory) string { return h.Current().String() },
+		"session": formatSession,
+		"progress": func(sessions []*domain.ReadingSession) string {
+			return formatProgress(domain.ComputeProgress(sessions))
+		},
+		"highlight":        func(h *domain.Highlight) string { return highlightHeading(h, "") },
+		"listContributors": contributorsWorthListing,
+	}
+}
+
+// webAssets returns the templates and stylesheet, laid out as templates/*.html and
+// static/style.css. Files in dir, if given, take the place of the built-in ones with
+// the same name.
+func webAssets(dir string) (fs.FS, error) {
+	assets, err := fs.Sub(webFS, "web")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return assets, nil
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to open templates directory: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return overrideFS{dir: dir, base: assets}, nil
+}
+
+// overrideFS reads a file from dir, flattened to its base name, when dir has one,
+// and from base otherwise.
+type overrideFS struct {
+	dir  string
+	base fs.FS
+}
+
+func (o overrideFS) Open(name string) (fs.File, error) {
+	if f, err := os.Open(filepath.Join(o.dir, path.Base(name))); err == nil {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return f, nil
+		}
+		f.Close()
+	}
+	return o.base.Open(name)
+}
+
+// parseWebPages parses every page template together with the layout, keyed by file name.
+func parseWebPages(assets fs.FS, site webSite) (map[string]*template.Template, error) {
+	names, err := fs.Glob(assets, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	const layout = "templates/layout.html"
+	pages := make(map[string]*template.Template)
+	for _, name := range names {
+		if name == layout {
+			continue
+		}
+		tmpl, err := template.New("layout.html").Funcs(site.funcs()).ParseFS(assets, layout, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path.Base(name), err)
+		}
+		pages[path.Base(name)] = tmpl
+	}
+	return pages, nil
+}
+
+// executePage renders a page into memory, so that a template error does not leave
+// half a page behind.
+func executePage(tmpl *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout.html", data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bibRef is how pages link to a bibliography: by BibIndex, or by ID if it has none or
+// one that cannot be a path segment.
+func bibRef(bib *domain.Bibliography) string {
+	if bib.BibIndex != "" && bib.BibIndex != "." && bib.BibIndex != ".." && !strings.ContainsAny(bib.BibIndex, `/\`) {
+		return bib.BibIndex
+	}
+	return bib.ID.String()
+}
+
+func (s *webUI) listBibliographies(w http.ResponseWriter, r *http.Request) {
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			s.renderError(w, http.StatusBadRequest, fmt.Sprintf("invalid page %q", v))
+			return
+		}
+		page = n
+	}
+	// One more than a page tells whether there is a next page
+	bibs, err := s.app.BibService.FindBibliographies(domain.BibliographyQuery{Limit: webPageSize + 1, Offset: (page - 1) * webPageSize})
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+
+	data := bibliographiesPage{webPage: webPage{Title: "Bibliographies"}, Bibliographies: bibs, Page: page}
+	if len(bibs) > webPageSize {
+		data.Bibliographies = bibs[:webPageSize]
+		data.NextURL = fmt.Sprintf("/?page=%d", page+1)
+	}
+	if page > 1 {
+		data.PrevURL = fmt.Sprintf("/?page=%d", page-1)
+	}
+	s.render(w, "bibliographies.html", data)
+}
+
+func (s *webUI) showBibliography(w http.ResponseWriter, r *http.Request) {
+	ref := r.PathValue("ref")
+	bib, err := s.app.FindBibliography(ref)
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+	if bib == nil {
+		s.renderError(w, http.StatusNotFound, fmt.Sprintf("bibliography %s not found", ref))
+		return
+	}
+	detail, err := s.app.BibliographyDetail(bib)
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+	s.render(w, "bibliography.html", bibliographyPage{webPage: webPage{Title: bib.Title}, bibliographyDetail: detail})
+}
+
+func (s *webUI) listClassifications(w http.ResponseWriter, r *http.Request) {
+	roots, err := s.app.BibService.ClassificationTree()
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+	s.render(w, "classifications.html", classificationsPage{webPage: webPage{Title: "Classifications"}, Roots: roots})
+}
+
+func (s *webUI) showClassification(w http.ResponseWriter, r *http.Request) {
+	class, err := s.app.BibService.FindClassificationByCode(r.PathValue("code"))
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+	roots, err := s.app.BibService.ClassificationTree()
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+	var node *domain.ClassNode
+	domain.Walk(roots, func(n *domain.ClassNode) {
+		if n.Class.ID == class.ID {
+			node = n
+		}
+	})
+	if node == nil {
+		s.renderError(w, http.StatusNotFound, fmt.Sprintf("classification with code %s not found", class.Code))
+		return
+	}
+	bibs, err := s.app.BibService.FindBibliographies(domain.BibliographyQuery{ClassCode: &class.Code, ClassRecursive: true})
+	if err != nil {
+		s.renderServiceError(w, err)
+		return
+	}
+
+	data := classificationPage{
+		webPage:        webPage{Title: fmt.Sprintf("%s %s", class.Code, class.Name)},
+		Node:           node,
+		Bibliographies: bibs,
+	}
+	for p := node.Parent; p != nil; p = p.Parent {
+		data.Ancestors = append([]*domain.ClassNode{p}, data.Ancestors...)
+	}
+	s.render(w, "classification.html", data)
+}
+
+func (s *webUI) search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	data := searchPage{webPage: webPage{Title: "Search", Query: query}}
+	if query != "" {
+		results, err := s.app.SearchService.Search(query, webSearchLimit)
+		if err != nil {
+			s.renderServiceError(w, err)
+			return
+		}
+		data.Title = "Search: " + query
+		data.Results = results
+	}
+	s.render(w, "search.html", data)
+}
+
+func (s *webUI) render(w http.ResponseWriter, page string, data any) {
+	s.renderStatus(w, http.StatusOK, page, data)
+}
+
+func (s *webUI) renderStatus(w http.ResponseWriter, status int, page string, data any) {
+	body, err := executePage(s.pages[page], data)
+	if err != nil {
+		log.Printf("Failed to render %s: %v", page, err)
+		http.Error(w, "failed to render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// renderServiceError shows an error page with the status writeServiceError would use.
+func (s *webUI) renderServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		s.renderError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalid):
+		s.renderError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Web UI error: %v", err)
+		s.renderError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (s *webUI) renderError(w http.ResponseWriter, status int, message string) {
+	s.renderStatus(w, status, "error.html", errorPage{webPage: webPage{Title: http.StatusText(status)}, Status: status, Message: message})
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestWeb serves the web UI and API over a CSV library in a temporary directory.
+func newTestWeb(t *testing.T) (*httptest.Server, *App) {
+	t.Helper()
+	app, err := NewApp(Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	handler, err := newServerHandler(app)
+	if err != nil {
+		t.Fatalf("newServerHandler failed: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		srv.Close()
+		app.Close()
+	})
+	return srv, app
+}
+
+// getPage fetches an HTML page and returns its status and body.
+func getPage(t *testing.T, srv *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := srv.Client().Get(srv.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: failed to read body: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestWebUI_Pages(t *testing.T) {
+	srv, app := newTestWeb(t)
+	for code, name := range map[string]string{"5": "技術", "54": "電気工学"} {
+		if _, err := app.BibService.AddClassification(code, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bib, err := app.BibService.AddBibliography("ドメイン駆動設計入門", "成瀬允宣", nil, "翔泳社", "", "Book", "54",
+		time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), "", "", "B54NM20DD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ReviewService.AddReview(bib.ID, "設計を学ぶ", "一行目\n<二行目>"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		want   []string
+	}{
+		{"/", http.StatusOK, []string{`<meta charset="utf-8">`, `href="/bibliographies/B54NM20DD"`, "ドメイン駆動設計入門", "2020"}},
+		{"/bibliographies/B54NM20DD", http.StatusOK, []string{"成瀬允宣", `href="/classifications/54"`, "設計を学ぶ", "一行目\n&lt;二行目&gt;"}},
+		{"/bibliographies/" + bib.ID.String(), http.StatusOK, []string{"ドメイン駆動設計入門"}},
+		{"/classifications", http.StatusOK, []string{`href="/classifications/5"`, "電気工学"}},
+		{"/classifications/5", http.StatusOK, []string{"技術", "電気工学", "ドメイン駆動設計入門"}},
+		{"/search?q=" + "設計", http.StatusOK, []string{`value="設計"`, `href="/bibliographies/B54NM20DD"`}},
+		{"/search?q=xyzzy", http.StatusOK, []string{"Nothing matches"}},
+		{"/bibliographies/B99XX", http.StatusNotFound, []string{"bibliography B99XX not found"}},
+		{"/classifications/9", http.StatusNotFound, []string{"not found"}},
+		{"/classifications/x", http.StatusBadRequest, nil},
+		{"/?page=0", http.StatusBadRequest, nil},
+		{"/books", http.StatusNotFound, []string{"no such page"}},
+	}
+	for _, tt := range tests {
+		status, body := getPage(t, srv, tt.path)
+		if status != tt.status {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.status, status)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("GET %s: expected %q in the page", tt.path, want)
+			}
+		}
+		if strings.Contains(body, "http://") || strings.Contains(body, "https://") {
+			t.Errorf("GET %s: page refers to an external resource", tt.path)
+		}
+	}
+
+	// The API is still served alongside
+	expectStatus(t, call(t, srv, "GET", "/api/bibliographies/B54NM20DD", "", nil), http.StatusOK)
+}
+
+func TestWebUI_Pagination(t *testing.T) {
+	srv, app := newTestWeb(t)
+	if _, err := app.BibService.AddClassification("5", "Technology"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range webPageSize + 1 {
+		_, err := app.BibService.AddBibliography(fmt.Sprintf("Book %02d", i), "A", nil, "", "", "Book", "5",
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "", "", fmt.Sprintf("B5X%02d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, first := getPage(t, srv, "/")
+	if !strings.Contains(first, "Book 49") || strings.Contains(first, "Book 50") || !strings.Contains(first, `href="/?page=2"`) {
+		t.Error("Expected the first page to end at Book 49 and link to page 2")
+	}
+	_, second := getPage(t, srv, "/?page=2")
+	if !strings.Contains(second, "Book 50") || strings.Contains(second, "Book 49") || strings.Contains(second, `rel="next"`) {
+		t.Error("Expected the second page to hold Book 50 only")
+	}
+}
+
+func TestWebUI_Static(t *testing.T) {
+	srv, _ := newTestWeb(t)
+	resp, err := srv.Client().Get(srv.URL + "/static/style.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/css") {
+		t.Errorf("Expected the stylesheet, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
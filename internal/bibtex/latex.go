@@ -0,0 +1,218 @@
+package bibtex
+
+import (
+	"strings"
+	"unicode"
+)
+
+// accents maps LaTeX accent commands to the precomposed characters they produce.
+var accents = map[string]map[rune]rune{
+	`"`: {'a': 'ä', 'e': 'ë', 'i': 'ï', 'o': 'ö', 'u': 'ü', 'y': 'ÿ', 'A': 'Ä', 'E': 'Ë', 'I': 'Ï', 'O': 'Ö', 'U': 'Ü'},
+	`'`: {'a': 'á', 'e': 'é', 'i': 'í', 'o': 'ó', 'u': 'ú', 'y': 'ý', 'c': 'ć', 'n': 'ń', 's': 'ś', 'z': 'ź', 'A': 'Á', 'E': 'É', 'I': 'Í', 'O': 'Ó', 'U': 'Ú', 'Y': 'Ý'},
+	"`": {'a': 'à', 'e': 'è', 'i': 'ì', 'o': 'ò', 'u': 'ù', 'A': 'À', 'E': 'È', 'I': 'Ì', 'O': 'Ò', 'U': 'Ù'},
+	"^": {'a': 'â', 'e': 'ê', 'i': 'î', 'o': 'ô', 'u': 'û', 'A': 'Â', 'E': 'Ê', 'I': 'Î', 'O': 'Ô', 'U': 'Û'},
+	"~": {'a': 'ã', 'n': 'ñ', 'o': 'õ', 'A': 'Ã', 'N': 'Ñ', 'O': 'Õ'},
+	"=": {'a': 'ā', 'e': 'ē', 'i': 'ī', 'o': 'ō', 'u': 'ū', 'A': 'Ā', 'E': 'Ē', 'I': 'Ī', 'O': 'Ō', 'U': 'Ū'},
+	"c": {'c': 'ç', 's': 'ş', 'C': 'Ç', 'S': 'Ş'},
+	"v": {'c': 'č', 'e': 'ě', 'n': 'ň', 'r': 'ř', 's': 'š', 'z': 'ž', 'C': 'Č', 'E': 'Ě', 'N': 'Ň', 'R': 'Ř', 'S': 'Š', 'Z': 'Ž'},
+	"u": {'a': 'ă', 'g': 'ğ', 'A': 'Ă', 'G': 'Ğ'},
+	"H": {'o': 'ő', 'u': 'ű', 'O': 'Ő', 'U': 'Ű'},
+	"r": {'a': 'å', 'u': 'ů', 'A': 'Å', 'U': 'Ů'},
+	".": {'z': 'ż', 'Z': 'Ż'},
+}
+
+// symbols maps argument-less LaTeX commands to text.
+var symbols = map[string]string{
+	"ss": "ß", "o": "ø", "O": "Ø", "ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ",
+	"aa": "å", "AA": "Å", "l": "ł", "L": "Ł", "i": "ı", "j": "ȷ",
+	"textbackslash": `\`, "textasciitilde": "~", "textasciicircum": "^",
+	"&": "&", "%": "%", "$": "$", "#": "#", "_": "_", "{": "{", "}": "}",
+}
+
+// Decode converts a raw BibTeX value to plain text: braces are dropped, escaped
+// specials and common accent commands are resolved, other commands such as
+// \emph are removed while keeping their argument, and whitespace is collapsed.
+func Decode(raw string) string {
+	src := []rune(raw)
+	var b strings.Builder
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch c {
+		case '{', '}':
+			// Grouping only
+		case '~':
+			b.WriteRune(' ')
+		case '-':
+			switch {
+			case strings.HasPrefix(string(src[i:]), "---"):
+				b.WriteRune('—')
+				i += 2
+			case strings.HasPrefix(string(src[i:]), "--"):
+				b.WriteRune('–')
+				i++
+			default:
+				b.WriteRune('-')
+			}
+		case '\\':
+			text, n := decodeCommand(src[i+1:])
+			b.WriteString(text)
+			i += n
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return strings.Join(strings.FieldsFunc(b.String(), isASCIISpace), " ")
+}
+
+// isASCIISpace reports whether r is whitespace that BibTeX collapses. Full-width
+// spaces in Japanese text are content and are preserved.
+func isASCIISpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+}
+
+// decodeCommand decodes the command following a backslash and returns
+// its text and the number of runes consumed.
+func decodeCommand(src []rune) (string, int) {
+	if len(src) == 0 {
+		return "", 0
+	}
+
+	// Command name: a single non-letter, or a run of letters
+	n := 1
+	if unicode.IsLetter(src[0]) {
+		for n < len(src) && unicode.IsLetter(src[n]) {
+			n++
+		}
+	}
+	name := string(src[:n])
+
+	if table, ok := accents[name]; ok {
+		arg, m := accentArgument(src[n:], unicode.IsLetter(src[0]))
+		if arg == 0 {
+			return "", n + m
+		}
+		if r, ok := table[arg]; ok {
+			return string(r), n + m
+		}
+		return string(arg), n + m
+	}
+	if text, ok := symbols[name]; ok {
+		// A space or "{}" after a named command only terminates it
+		if unicode.IsLetter(src[0]) {
+			if n < len(src) && src[n] == ' ' {
+				n++
+			} else if n+1 < len(src) && src[n] == '{' && src[n+1] == '}' {
+				n += 2
+			}
+		}
+		return text, n
+	}
+	if name == `\` {
+		return " ", n
+	}
+	// Unknown command (e.g. \emph, \textit): drop it, keep the argument
+	return "", n
+}
+
+// accentArgument returns the letter an accent applies to, given the runes after the
+// accent command, and the number of runes consumed. The letter may be braced ("{o}"),
+// a dotless i ("\i"), or follow directly (letter-named accents need a space first).
+func accentArgument(src []rune, named bool) (rune, int) {
+	n := 0
+	if named {
+		for n < len(src) && src[n] == ' ' {
+			n++
+		}
+	}
+	braced := n < len(src) && src[n] == '{'
+	if braced {
+		n++
+	}
+	if n+1 < len(src) && src[n] == '\\' && (src[n+1] == 'i' || src[n+1] == 'j') {
+		arg := src[n+1]
+		n += 2
+		if braced && n < len(src) && src[n] == '}' {
+			n++
+		}
+		return arg, n
+	}
+	if n >= len(src) {
+		return 0, n
+	}
+	arg := src[n]
+	n++
+	if braced && n < len(src) && src[n] == '}' {
+		n++
+	}
+	return arg, n
+}
+
+// Encode escapes plain text for use as a BibTeX value.
+func Encode(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '\\':
+			b.WriteString(`\textbackslash{}`)
+		case '~':
+			b.WriteString(`\textasciitilde{}`)
+		case '^':
+			b.WriteString(`\textasciicircum{}`)
+		case '&', '%', '$', '#', '_', '{', '}':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ParseNames splits a raw name list ("Evans, Eric and Martin Fowler") into decoded
+// names in "First von Last Jr" order. Braced groups such as "{Apple Inc.}" are kept intact.
+func ParseNames(raw string) []string {
+	var names []string
+	for _, part := range splitTopLevel(raw, " and ") {
+		parts := splitTopLevel(part, ",")
+		var name string
+		switch len(parts) {
+		case 1:
+			name = parts[0]
+		case 2: // Last, First
+			name = parts[1] + " " + parts[0]
+		default: // Last, Jr, First
+			name = parts[2] + " " + parts[0] + " " + parts[1]
+		}
+		if decoded := Decode(name); decoded != "" {
+			names = append(names, decoded)
+		}
+	}
+	return names
+}
+
+// splitTopLevel splits s at occurrences of sep outside of braces. Separators made
+// of words match case-insensitively and across any run of whitespace.
+func splitTopLevel(s, sep string) []string {
+	normalized := s
+	if strings.TrimSpace(sep) != sep {
+		// Normalize whitespace so " and " also matches across line breaks
+		normalized = strings.Join(strings.FieldsFunc(s, isASCIISpace), " ")
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(normalized); i++ {
+		switch normalized[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		default:
+			if depth == 0 && strings.EqualFold(normalized[i:min(i+len(sep), len(normalized))], sep) {
+				parts = append(parts, strings.TrimSpace(normalized[start:i]))
+				i += len(sep) - 1
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(normalized[start:]))
+}
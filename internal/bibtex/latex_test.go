@@ -0,0 +1,61 @@
+package bibtex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{Domain-Driven Design}}", "Domain-Driven Design"},
+		{`Sch{\"o}n and G\"odel`, "Schön and Gödel"},
+		{"Fran\\c{c}ois Vi\\`{e}te", "François Viète"},
+		{`\'{\i}ndice`, "índice"},
+		{`T\=oky\=o`, "Tōkyō"},
+		{`Stra\ss e`, "Straße"},
+		{`R\&D at 100\% \emph{fun}`, "R&D at 100% fun"},
+		{"pages 10--20 --- roughly", "pages 10–20 — roughly"},
+		{"Donald~E.  Knuth\n and", "Donald E. Knuth and"},
+		{"データモデリング", "データモデリング"},
+		{"マネジメント神話　現代ビジネス哲学", "マネジメント神話　現代ビジネス哲学"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Decode(tt.input); got != tt.expected {
+				t.Errorf("Decode(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, s := range []string{`R&D 100% $5 #1 a_b {x} \ ~ ^`, "マシュー スチュワート(稲岡大志訳)"} {
+		if got := Decode(Encode(s)); got != s {
+			t.Errorf("Decode(Encode(%q)) = %q", s, got)
+		}
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"Evans, Eric", []string{"Eric Evans"}},
+		{"Eric Evans and Fowler, Martin", []string{"Eric Evans", "Martin Fowler"}},
+		{"Steele, Jr., Guy L.", []string{"Guy L. Steele Jr."}},
+		{"{Barnes and Noble, Inc.} AND\n  Knuth, Donald", []string{"Barnes and Noble, Inc.", "Donald Knuth"}},
+		{"杉本啓", []string{"杉本啓"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseNames(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseNames(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Package bibtex reads and writes BibTeX databases.
+//
+// Field values are kept as raw BibTeX text (with braces and LaTeX commands);
+// use Decode, ParseNames and Encode to convert between raw and plain text.
+package bibtex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Field is a single "name = value" pair of an entry.
+type Field struct {
+	Name  string // lower-cased, e.g. "author"
+	Value string // raw BibTeX text without the outer delimiters
+}
+
+// Entry is a BibTeX entry such as "@book{key, ...}".
+type Entry struct {
+	Type   string // lower-cased, e.g. "book"
+	Key    string
+	Fields []Field
+}
+
+// Get returns the raw value of the named field, or "" if the entry does not have it.
+func (e *Entry) Get(name string) string {
+	for _, f := range e.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// standardMacros are predefined by BibTeX styles.
+var standardMacros = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April",
+	"may": "May", "jun": "June", "jul": "July", "aug": "August",
+	"sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// Parse reads all entries from a BibTeX database.
+// @string macros are expanded; @comment and @preamble are skipped,
+// as is any text outside of entries.
+func Parse(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: []rune(string(data)), line: 1, macros: make(map[string]string)}
+	for k, v := range standardMacros {
+		p.macros[k] = v
+	}
+	return p.parse()
+}
+
+type parser struct {
+	src    []rune
+	pos    int
+	line   int
+	macros map[string]string
+}
+
+func (p *parser) parse() ([]Entry, error) {
+	var entries []Entry
+	for {
+		// Anything before the next '@' is a comment
+		for p.pos < len(p.src) && p.src[p.pos] != '@' {
+			p.next()
+		}
+		if p.pos >= len(p.src) {
+			return entries, nil
+		}
+		p.next() // '@'
+
+		p.skipSpace()
+		entryType := strings.ToLower(p.identifier())
+		if entryType == "" {
+			return nil, p.errorf("expected entry type after '@'")
+		}
+		p.skipSpace()
+		open := p.peek()
+		if open != '{' && open != '(' {
+			return nil, p.errorf("expected '{' or '(' after @%s", entryType)
+		}
+		closer := '}'
+		if open == '(' {
+			closer = ')'
+		}
+
+		switch entryType {
+		case "comment", "preamble":
+			if _, err := p.delimited(open, closer); err != nil {
+				return nil, err
+			}
+		case "string":
+			p.next()
+			if err := p.stringMacro(closer); err != nil {
+				return nil, err
+			}
+		default:
+			p.next()
+			entry, err := p.entry(entryType, closer)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+}
+
+func (p *parser) stringMacro(closer rune) error {
+	p.skipSpace()
+	name := strings.ToLower(p.identifier())
+	if name == "" {
+		return p.errorf("expected macro name in @string")
+	}
+	p.skipSpace()
+	if !p.accept('=') {
+		return p.errorf("expected '=' after @string name %s", name)
+	}
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+	p.macros[name] = value
+	p.skipSpace()
+	if !p.accept(closer) {
+		return p.errorf("expected %q to close @string", closer)
+	}
+	return nil
+}
+
+func (p *parser) entry(entryType string, closer rune) (Entry, error) {
+	entry := Entry{Type: entryType}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != closer && !unicode.IsSpace(p.src[p.pos]) {
+		p.next()
+	}
+	entry.Key = string(p.src[start:p.pos])
+	if entry.Key == "" {
+		return Entry{}, p.errorf("missing citation key in @%s", entryType)
+	}
+
+	for {
+		p.skipSpace()
+		if p.accept(closer) {
+			return entry, nil
+		}
+		if !p.accept(',') {
+			return Entry{}, p.errorf("expected ',' or %q in entry %s", closer, entry.Key)
+		}
+		p.skipSpace()
+		if p.accept(closer) { // trailing comma
+			return entry, nil
+		}
+
+		name := strings.ToLower(p.identifier())
+		if name == "" {
+			return Entry{}, p.errorf("expected field name in entry %s", entry.Key)
+		}
+		p.skipSpace()
+		if !p.accept('=') {
+			return Entry{}, p.errorf("expected '=' after field %s in entry %s", name, entry.Key)
+		}
+		value, err := p.value()
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Fields = append(entry.Fields, Field{Name: name, Value: value})
+	}
+}
+
+// value parses a field value: braced or quoted text, a number or a macro name,
+// optionally concatenated with '#'.
+func (p *parser) value() (string, error) {
+	var b strings.Builder
+	for {
+		p.skipSpace()
+		switch c := p.peek(); {
+		case c == '{':
+			s, err := p.delimited('{', '}')
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		case c == '"':
+			s, err := p.quoted()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		case unicode.IsDigit(c):
+			start := p.pos
+			for p.pos < len(p.src) && unicode.IsDigit(p.src[p.pos]) {
+				p.next()
+			}
+			b.WriteString(string(p.src[start:p.pos]))
+		default:
+			name := strings.ToLower(p.identifier())
+			if name == "" {
+				return "", p.errorf("expected field value")
+			}
+			expansion, ok := p.macros[name]
+			if !ok {
+				return "", p.errorf("undefined macro %s", name)
+			}
+			b.WriteString(expansion)
+		}
+
+		p.skipSpace()
+		if !p.accept('#') {
+			return b.String(), nil
+		}
+	}
+}
+
+// delimited consumes text between open and closer, honoring nested braces,
+// and returns the text without the outer delimiters.
+func (p *parser) delimited(open, closer rune) (string, error) {
+	startLine := p.line
+	p.next() // open
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == closer && depth == 0:
+			s := string(p.src[start:p.pos])
+			p.next()
+			return s, nil
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		}
+		p.next()
+	}
+	return "", fmt.Errorf("line %d: unterminated %q", startLine, open)
+}
+
+// quoted consumes a "..." value. Quotes inside braces do not end the value.
+func (p *parser) quoted() (string, error) {
+	startLine := p.line
+	p.next() // opening quote
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"' && depth == 0:
+			s := string(p.src[start:p.pos])
+			p.next()
+			return s, nil
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		}
+		p.next()
+	}
+	return "", fmt.Errorf("line %d: unterminated quoted value", startLine)
+}
+
+// identifier consumes an entry type, field name or macro name.
+func (p *parser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsSpace(c) || strings.ContainsRune(`{}(),="#%'`, c) {
+			break
+		}
+		p.next()
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.next()
+	}
+}
+
+func (p *parser) peek() rune {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) accept(c rune) bool {
+	if p.peek() == c && p.pos < len(p.src) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) next() {
+	if p.src[p.pos] == '\n' {
+		p.line++
+	}
+	p.pos++
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
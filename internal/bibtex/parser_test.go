@@ -0,0 +1,82 @@
+package bibtex
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `
+This text is ignored.
+@comment{ nothing here @book{ignored, title = {x}} }
+@string{ addison = "Addison-Wesley" }
+@preamble{ "\newcommand{\noop}[1]{}" }
+
+@Book{evans2003,
+  author    = {Evans, Eric},
+  TITLE     = "Domain-Driven {Design}: Tackling Complexity",
+  publisher = addison # { Professional},
+  year      = 2003,
+  month     = aug,
+}
+
+@article(fowler2005,
+  author = {Martin Fowler},
+  title  = {{Fluent Interface}},
+  journal = {IEEE Software}
+)
+`
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	book := entries[0]
+	if book.Type != "book" || book.Key != "evans2003" {
+		t.Errorf("Expected @book evans2003, got @%s %s", book.Type, book.Key)
+	}
+	checks := map[string]string{
+		"author":    "Evans, Eric",
+		"title":     "Domain-Driven {Design}: Tackling Complexity",
+		"publisher": "Addison-Wesley Professional",
+		"year":      "2003",
+		"month":     "August",
+	}
+	for name, want := range checks {
+		if got := book.Get(name); got != want {
+			t.Errorf("Field %s = %q, want %q", name, got, want)
+		}
+	}
+
+	article := entries[1]
+	if article.Type != "article" || article.Get("journal") != "IEEE Software" {
+		t.Errorf("Unexpected article entry: %+v", article)
+	}
+	if article.Get("missing") != "" {
+		t.Errorf("Expected empty value for missing field")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unterminated", "@book{key,\n title = {open", "line 2: unterminated"},
+		{"undefined macro", "@book{key, publisher = nobody}", "undefined macro nobody"},
+		{"missing key", "@book{, title = {x}}", "missing citation key"},
+		{"missing equals", "@book{key,\n\n title {x}}", "line 3: expected '='"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package bibtex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Write formats entries as a BibTeX database. Field values are written verbatim
+// inside braces, so plain text must be passed through Encode first.
+func Write(w io.Writer, entries []Entry) error {
+	for i, e := range entries {
+		if err := validateKey(e.Key); err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "@%s{%s", e.Type, e.Key)
+		width := 0
+		for _, f := range e.Fields {
+			width = max(width, len(f.Name))
+		}
+		for _, f := range e.Fields {
+			fmt.Fprintf(&b, ",\n  %-*s = {%s}", width, f.Name, f.Value)
+		}
+		b.WriteString("\n}\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateKey rejects citation keys that BibTeX cannot parse back.
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty citation key")
+	}
+	if strings.ContainsFunc(key, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`{}(),="#%'\`, r)
+	}) {
+		return fmt.Errorf("citation key %q contains characters not allowed in BibTeX", key)
+	}
+	return nil
+}
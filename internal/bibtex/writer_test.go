@@ -0,0 +1,51 @@
+package bibtex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	entries := []Entry{
+		{Type: "book", Key: "B56EE03DDD", Fields: []Field{
+			{Name: "author", Value: "Eric Evans"},
+			{Name: "title", Value: Encode("Domain Driven Design")},
+			{Name: "year", Value: "2003"},
+		}},
+		{Type: "misc", Key: "V10XX24T", Fields: []Field{{Name: "title", Value: Encode("R&D")}}},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, entries); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	expected := `@book{B56EE03DDD,
+  author = {Eric Evans},
+  title  = {Domain Driven Design},
+  year   = {2003}
+}
+
+@misc{V10XX24T,
+  title = {R\&D}
+}
+`
+	if b.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", b.String(), expected)
+	}
+
+	parsed, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, entries) {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", parsed, entries)
+	}
+}
+
+func TestWrite_InvalidKey(t *testing.T) {
+	err := Write(&strings.Builder{}, []Entry{{Type: "book", Key: "has space"}})
+	if err == nil {
+		t.Error("Expected error for key containing a space, got nil")
+	}
+}
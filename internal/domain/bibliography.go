@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,13 +37,67 @@ func ParseBibliographyID(s string) (BibliographyID, error) {
 
 // Bibliography represents a published work.
 type Bibliography struct {
-	ID            BibliographyID
-	BibIndex      string // e.g., "B56SK24DMD"
-	Code          string // e.g., "B56"
-	Type          string // e.g., "Book", "Essay"
-	Title         string
-	Author        string
+	ID       BibliographyID
+	BibIndex string // e.g., "B56SK24DMD"
+	Code     string // e.g., "B56"
+	Type     string // e.g., "Book", "Essay"
+	Title    string
+	Author   string // credit line as displayed, e.g. "マシュー スチュワート(稲岡大志訳)"
+	// Contributors are the people credited, in order. Bibliographies recorded before
+	// contributors were tracked have them derived from Author.
+	Contributors  []Contributor
 	Publisher     string
-	ISBN          string
+	ISBN          ISBN
 	PublishedDate time.Time
 }
+
+// ClassCode extracts the classification code from Code by dropping the leading
+// type prefix (e.g. "B56" -> "56", "B547.48" -> "547.48").
+func (b *Bibliography) ClassCode() (ClassCode, error) {
+	digits := strings.TrimLeftFunc(b.Code, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	code, err := ParseClassCode(digits)
+	if err != nil {
+		return "", fmt.Errorf("invalid bibliography code %q: %w", b.Code, err)
+	}
+	return code, nil
+}
+
+// LeadAuthor returns the lead author of the bibliography (see LeadAuthor). Without
+// contributors they are read from the author line.
+func (b *Bibliography) LeadAuthor() (Contributor, bool) {
+	if len(b.Contributors) == 0 {
+		return LeadAuthor(ParseCreditLine(b.Author))
+	}
+	return LeadAuthor(b.Contributors)
+}
+
+// LeadAuthor returns the first contributor with the author role, which BibIndex
+// generation uses. Works without an author (e.g. edited volumes) fall back to the
+// first contributor. ok is false if there are no contributors.
+func LeadAuthor(contributors []Contributor) (c Contributor, ok bool) {
+	for _, c := range contributors {
+		if c.Role == RoleAuthor {
+			return c, true
+		}
+	}
+	if len(contributors) > 0 {
+		return contributors[0], true
+	}
+	return Contributor{}, false
+}
+
+// HasContributor reports whether the credit line or the name of any contributor,
+// in either script, contains text, ignoring case.
+func (b *Bibliography) HasContributor(text string) bool {
+	if containsFold(b.Author, text) {
+		return true
+	}
+	for _, c := range b.Contributors {
+		if containsFold(c.Name, text) || containsFold(c.NameEn, text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BibliographySort is the order in which bibliographies are listed.
+type BibliographySort string
+
+const (
+	SortByInsertion BibliographySort = "insertion"
+	SortByTitle     BibliographySort = "title"
+	SortByAuthor    BibliographySort = "author"
+	SortByYear      BibliographySort = "year"
+	SortByBibIndex  BibliographySort = "bibindex"
+)
+
+// ParseBibliographySort parses a sort key. The empty string means insertion order.
+func ParseBibliographySort(s string) (BibliographySort, error) {
+	switch sortKey := BibliographySort(strings.ToLower(s)); sortKey {
+	case "":
+		return SortByInsertion, nil
+	case SortByInsertion, SortByTitle, SortByAuthor, SortByYear, SortByBibIndex:
+		return sortKey, nil
+	default:
+		return "", fmt.Errorf("unknown sort key %q (expected insertion, title, author, year or bibindex)", s)
+	}
+}
+
+// BibliographyQuery selects, orders and pages bibliographies.
+// Zero values mean "no filter"; nil pointers mean "either".
+type BibliographyQuery struct {
+	Type           string // case-insensitive exact match
+	ClassCode      *ClassCode
+	ClassRecursive bool   // also select the subclasses of ClassCode (e.g. "547" for "5")
+	YearFrom       int    // inclusive
+	YearTo         int    // inclusive
+	Author         string // case-insensitive substring of the author line or any contributor
+	Publisher      string // case-insensitive substring
+	HasReview      *bool
+	HasISBN        *bool
+	Status         *ReadingStatus // ReadingStatusNone selects untracked bibliographies
+
+	Sort       BibliographySort
+	Descending bool
+	Limit      int // 0 for no limit
+	Offset     int
+}
+
+// Matches reports whether bib passes every filter of q. reviewed tells whether
+// bib has at least one review, and status is its current reading status. Repositories that cannot push a query down to their
+// storage can use Matches and SortBibliographies to evaluate it in memory.
+func (q BibliographyQuery) Matches(bib *Bibliography, reviewed bool, status ReadingStatus) bool {
+	if q.Type != "" && !strings.EqualFold(bib.Type, q.Type) {
+		return false
+	}
+	if q.ClassCode != nil {
+		code, err := bib.ClassCode()
+		if err != nil || !(code == *q.ClassCode || q.ClassRecursive && code.Within(*q.ClassCode)) {
+			return false
+		}
+	}
+	year := bib.PublishedDate.Year()
+	if q.YearFrom != 0 && year < q.YearFrom {
+		return false
+	}
+	if q.YearTo != 0 && year > q.YearTo {
+		return false
+	}
+	if q.Author != "" && !bib.HasContributor(q.Author) {
+		return false
+	}
+	if q.Publisher != "" && !containsFold(bib.Publisher, q.Publisher) {
+		return false
+	}
+	if q.HasReview != nil && reviewed != *q.HasReview {
+		return false
+	}
+	if q.HasISBN != nil && !bib.ISBN.IsZero() != *q.HasISBN {
+		return false
+	}
+	if q.Status != nil && status != *q.Status {
+		return false
+	}
+	return true
+}
+
+// SortBibliographies orders bibliographies, given in insertion order, by q.Sort.
+// Ties keep insertion order.
+func (q BibliographyQuery) SortBibliographies(bibs []*Bibliography) {
+	var less func(a, b *Bibliography) int
+	switch q.Sort {
+	case SortByTitle:
+		less = func(a, b *Bibliography) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		}
+	case SortByAuthor:
+		less = func(a, b *Bibliography) int {
+			return strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author))
+		}
+	case SortByYear:
+		less = func(a, b *Bibliography) int { return a.PublishedDate.Compare(b.PublishedDate) }
+	case SortByBibIndex:
+		less = func(a, b *Bibliography) int { return strings.Compare(a.BibIndex, b.BibIndex) }
+	default:
+		if q.Descending {
+			for i, j := 0, len(bibs)-1; i < j; i, j = i+1, j-1 {
+				bibs[i], bibs[j] = bibs[j], bibs[i]
+			}
+		}
+		return
+	}
+	sort.SliceStable(bibs, func(i, j int) bool {
+		c := less(bibs[i], bibs[j])
+		if q.Descending {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// Page applies q.Offset and q.Limit to already filtered and sorted bibliographies.
+func (q BibliographyQuery) Page(bibs []*Bibliography) []*Bibliography {
+	if q.Offset > 0 {
+		if q.Offset >= len(bibs) {
+			return nil
+		}
+		bibs = bibs[q.Offset:]
+	}
+	if q.Limit > 0 && q.Limit < len(bibs) {
+		bibs = bibs[:q.Limit]
+	}
+	return bibs
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -33,9 +35,141 @@ func ParseClassificationID(s string) (ClassificationID, error) {
 	return ClassificationID(id), nil
 }
 
+// ClassCode is a decimal classification number as used by the Nippon Decimal
+// Classification (NDC) and the Dewey Decimal Classification (DDC), e.g. "5", "56",
+// "007" or "547.48". Each digit narrows the class of the digits before it, so "547"
+// belongs to "54", which belongs to "5". Leading zeros are significant: "007" is not "7".
+type ClassCode string
+
+// maxClassCodeDigits bounds the length of a class code.
+const maxClassCodeDigits = 12
+
+// ParseClassCode parses a class code. A decimal point may only follow the third digit,
+// as in both NDC and DDC.
+func ParseClassCode(s string) (ClassCode, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("classification code is required")
+	}
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if !isDigits(whole) || (hasPoint && (len(whole) != 3 || !isDigits(fraction))) {
+		return "", fmt.Errorf("invalid classification code %q (expected digits such as 5, 56, 007 or 547.48)", s)
+	}
+	if len(whole)+len(fraction) > maxClassCodeDigits {
+		return "", fmt.Errorf("classification code %q is longer than %d digits", s, maxClassCodeDigits)
+	}
+	return ClassCode(s), nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the code as written, e.g. "547.48".
+func (c ClassCode) String() string {
+	return string(c)
+}
+
+// Digits returns the code without its decimal point, which only aids reading.
+func (c ClassCode) Digits() string {
+	return strings.Replace(string(c), ".", "", 1)
+}
+
+// Depth is the number of digits, i.e. the level of the class in a full hierarchy
+// (1 for a main class such as "5").
+func (c ClassCode) Depth() int {
+	return len(c.Digits())
+}
+
+// Within reports whether c is ancestor or one of its subclasses.
+func (c ClassCode) Within(ancestor ClassCode) bool {
+	return strings.HasPrefix(c.Digits(), ancestor.Digits())
+}
+
+// Less orders codes so that every class comes right before its subclasses
+// ("5" < "54" < "547" < "547.4" < "55").
+func (c ClassCode) Less(other ClassCode) bool {
+	return c.Digits() < other.Digits()
+}
+
 // Classification represents a classification category.
 type Classification struct {
-	ID      ClassificationID
-	CodeNum int    // e.g., 56
-	Name    string // e.g., "Technology"
+	ID   ClassificationID
+	Code ClassCode // e.g., "56"
+	Name string    // e.g., "Technology"
+}
+
+// ClassNode is a classification in a tree of classifications, with the number of
+// bibliographies filed directly under it and under its whole subtree.
+type ClassNode struct {
+	Class    *Classification
+	Parent   *ClassNode // nil for a root
+	Children []*ClassNode
+	Count    int // bibliographies classified exactly here
+	Total    int // Count plus the Totals of the children
+}
+
+// Depth is the number of ancestors of the node (0 for a root).
+func (n *ClassNode) Depth() int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// BuildClassTree arranges classifications into trees. The parent of a class is the
+// classification with the longest code that the class is within, so intermediate
+// levels may be missing: without "54", "547" hangs directly under "5". counts gives
+// the number of bibliographies per code; codes without a classification are ignored.
+// Roots and children are ordered by code.
+func BuildClassTree(classes []*Classification, counts map[ClassCode]int) []*ClassNode {
+	sorted := make([]*Classification, len(classes))
+	copy(sorted, classes)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Code.Less(sorted[j].Code) })
+
+	// In code order every ancestor comes before its subclasses, so the nearest
+	// ancestor is on the stack of open nodes when a class is reached.
+	var roots, stack []*ClassNode
+	for _, class := range sorted {
+		node := &ClassNode{Class: class, Count: counts[class.Code]}
+		for len(stack) > 0 && !class.Code.Within(stack[len(stack)-1].Class.Code) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			node.Parent = stack[len(stack)-1]
+			node.Parent.Children = append(node.Parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+		stack = append(stack, node)
+	}
+	for _, root := range roots {
+		root.sumTotals()
+	}
+	return roots
+}
+
+func (n *ClassNode) sumTotals() int {
+	n.Total = n.Count
+	for _, child := range n.Children {
+		n.Total += child.sumTotals()
+	}
+	return n.Total
+}
+
+// Walk calls fn for every node of the trees in depth-first order, parents before children.
+func Walk(nodes []*ClassNode, fn func(*ClassNode)) {
+	for _, n := range nodes {
+		fn(n)
+		Walk(n.Children, fn)
+	}
 }
@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContributorRole is what a person did for a bibliography.
+type ContributorRole string
+
+const (
+	RoleAuthor      ContributorRole = "author"
+	RoleEditor      ContributorRole = "editor"
+	RoleTranslator  ContributorRole = "translator"
+	RoleIllustrator ContributorRole = "illustrator"
+	RoleSpeaker     ContributorRole = "speaker"
+	RoleHost        ContributorRole = "host"
+)
+
+// ContributorRoles lists the roles in the order they are usually credited.
+var ContributorRoles = []ContributorRole{RoleAuthor, RoleEditor, RoleTranslator, RoleIllustrator, RoleSpeaker, RoleHost}
+
+// roleMarkers maps the abbreviations and Japanese suffixes used in credit lines, such as
+// "ed." or the 訳 of "稲岡大志訳", to roles.
+var roleMarkers = map[string]ContributorRole{
+	"著": RoleAuthor, "作": RoleAuthor, "文": RoleAuthor,
+	"ed.": RoleEditor, "eds.": RoleEditor, "編": RoleEditor, "編著": RoleEditor, "編集": RoleEditor, "監修": RoleEditor,
+	"tr.": RoleTranslator, "trans.": RoleTranslator, "訳": RoleTranslator, "翻訳": RoleTranslator, "訳者": RoleTranslator, "監訳": RoleTranslator,
+	"illus.": RoleIllustrator, "絵": RoleIllustrator, "画": RoleIllustrator, "イラスト": RoleIllustrator,
+	"講演": RoleSpeaker, "司会": RoleHost,
+}
+
+// ParseContributorRole parses a role name, ignoring case. The abbreviations and
+// Japanese suffixes used in credit lines (e.g. "ed.", "訳") are accepted as well.
+func ParseContributorRole(s string) (ContributorRole, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, role := range ContributorRoles {
+		if s == string(role) {
+			return role, nil
+		}
+	}
+	if role, ok := roleMarkers[s]; ok {
+		return role, nil
+	}
+	return "", fmt.Errorf("unknown contributor role %q (expected author, editor, translator, illustrator, speaker or host)", s)
+}
+
+// Contributor is a person credited for a bibliography.
+type Contributor struct {
+	Name string
+	Role ContributorRole
+	// NameEn is the name in Latin script (e.g. "Inaoka Taishi" for 稲岡大志); empty if
+	// Name already is or no romanization is given.
+	NameEn string
+}
+
+// ParseContributor parses the "[role:]Name[=NameEn]" form used on the command line,
+// e.g. "translator:稲岡大志=Taishi Inaoka". The role defaults to author.
+func ParseContributor(s string) (Contributor, error) {
+	c := Contributor{Role: RoleAuthor}
+	if role, name, ok := strings.Cut(s, ":"); ok {
+		parsed, err := ParseContributorRole(role)
+		if err != nil {
+			return Contributor{}, err
+		}
+		c.Role, s = parsed, name
+	}
+	name, nameEn, _ := strings.Cut(s, "=")
+	c.Name, c.NameEn = strings.TrimSpace(name), strings.TrimSpace(nameEn)
+	if err := c.Validate(); err != nil {
+		return Contributor{}, err
+	}
+	return c, nil
+}
+
+// Validate checks that the contributor has a name and a known role.
+func (c Contributor) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("contributor name is required")
+	}
+	if _, err := ParseContributorRole(string(c.Role)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// String returns the "[role:]Name[=NameEn]" form accepted by ParseContributor.
+func (c Contributor) String() string {
+	s := c.Name
+	if c.Role != RoleAuthor {
+		s = string(c.Role) + ":" + s
+	}
+	if c.NameEn != "" {
+		s += "=" + c.NameEn
+	}
+	return s
+}
+
+// CreditLine formats contributors as a single author line, e.g.
+// "マシュー スチュワート; 稲岡大志 (translator)". ParseCreditLine reads it back.
+func CreditLine(contributors []Contributor) string {
+	parts := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		if c.Role == RoleAuthor {
+			parts = append(parts, c.Name)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s (%s)", c.Name, c.Role))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseCreditLine reads the contributors from a free-form author line such as
+// "マシュー スチュワート(稲岡大志訳)", "Gamma; Helm (ed.)" or "Knuth and Lamport".
+// Names are separated by ";", "、", " and " or " & "; a role is recognized in
+// parentheses after a name or as a Japanese suffix. Other parenthesized notes are
+// dropped, and everyone without a role is an author.
+func ParseCreditLine(line string) []Contributor {
+	var contributors []Contributor
+	for _, part := range splitCredits(line) {
+		main, notes := splitNotes(part)
+		role := RoleAuthor
+		var extra []Contributor
+		for _, note := range notes {
+			if r, ok := roleMarkers[strings.ToLower(note)]; ok {
+				role = r
+			} else if r, err := ParseContributorRole(note); err == nil {
+				role = r
+			} else if c, ok := parseRoleSuffix(note); ok {
+				extra = append(extra, c)
+			}
+		}
+		if c, ok := parseRoleSuffix(main); ok {
+			main, role = c.Name, c.Role
+		}
+		if main != "" {
+			contributors = append(contributors, Contributor{Name: main, Role: role})
+		}
+		contributors = append(contributors, extra...)
+	}
+	return contributors
+}
+
+// splitCredits splits a credit line at separators outside parentheses.
+func splitCredits(line string) []string {
+	var parts []string
+	var b strings.Builder
+	depth := 0
+	flush := func() {
+		if part := strings.TrimSpace(b.String()); part != "" {
+			parts = append(parts, part)
+		}
+		b.Reset()
+	}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '(' || r == '（':
+			depth++
+		case r == ')' || r == '）':
+			depth = max(depth-1, 0)
+		case depth == 0 && (r == ';' || r == '；' || r == '、'):
+			flush()
+			continue
+		case depth == 0 && r == ' ':
+			if sep := conjunctionAt(runes[i:]); sep != "" {
+				flush()
+				i += len([]rune(sep)) - 1
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	flush()
+	return parts
+}
+
+// conjunctionAt returns the " and " or " & " that runes start with, if any.
+func conjunctionAt(runes []rune) string {
+	rest := string(runes[:min(len(runes), 5)])
+	for _, sep := range []string{" and ", " & "} {
+		if strings.HasPrefix(rest, sep) {
+			return sep
+		}
+	}
+	return ""
+}
+
+// splitNotes separates a name from the contents of its parenthesized notes.
+func splitNotes(s string) (string, []string) {
+	var name strings.Builder
+	var notes []string
+	var note strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(', '（':
+			if depth > 0 {
+				note.WriteRune(r)
+			}
+			depth++
+		case ')', '）':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				notes = append(notes, strings.TrimSpace(note.String()))
+				note.Reset()
+			} else {
+				note.WriteRune(r)
+			}
+		default:
+			if depth > 0 {
+				note.WriteRune(r)
+			} else {
+				name.WriteRune(r)
+			}
+		}
+	}
+	return strings.Join(strings.Fields(name.String()), " "), notes
+}
+
+// parseRoleSuffix recognizes a name followed by a Japanese role suffix, as in
+// "稲岡大志訳" or "山田太郎 編". Suffixes that also end given names (絵 as in 千絵, 画)
+// only count after a space.
+func parseRoleSuffix(s string) (Contributor, bool) {
+	s = strings.TrimSpace(s)
+	for _, marker := range []string{"監訳", "翻訳", "編著", "編集", "監修", "訳", "編", "著", "絵", "画", "イラスト"} {
+		name, ok := strings.CutSuffix(s, marker)
+		if !ok || strings.TrimSpace(name) == "" || !containsJapaneseScript(name) {
+			continue
+		}
+		spaced := strings.TrimRight(name, " 　") != name
+		if !spaced && (marker == "絵" || marker == "画") {
+			continue
+		}
+		return Contributor{Name: strings.TrimSpace(strings.TrimRight(name, " 　")), Role: roleMarkers[marker]}, true
+	}
+	return Contributor{}, false
+}
+
+// containsJapaneseScript reports whether s contains kana or kanji.
+func containsJapaneseScript(s string) bool {
+	for _, r := range s {
+		if (r >= 0x3040 && r <= 0x30FF) || (r >= 0x4E00 && r <= 0x9FFF) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "errors"
+
+// ErrNotFound is wrapped by errors reporting that a requested entity does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalid is wrapped by errors reporting input that is rejected, such as a missing
+// title or a BibIndex already in use, as opposed to failures of the storage.
+var ErrInvalid = errors.New("invalid input")
+
+// Invalid marks err as a rejection of input, so that it matches ErrInvalid, without
+// changing its message.
+func Invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+	return invalidError{err}
+}
+
+type invalidError struct{ err error }
+
+func (e invalidError) Error() string   { return e.err.Error() }
+func (e invalidError) Unwrap() []error { return []error{e.err, ErrInvalid} }
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HighlightID is a domain-specific type for Highlight entity IDs.
+type HighlightID uuid.UUID
+
+// String returns the string representation of the HighlightID.
+func (id HighlightID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// UUID returns the underlying uuid.UUID value.
+func (id HighlightID) UUID() uuid.UUID {
+	return uuid.UUID(id)
+}
+
+// NewHighlightID generates a new random HighlightID.
+func NewHighlightID() HighlightID {
+	return HighlightID(uuid.New())
+}
+
+// ParseHighlightID parses a string into a HighlightID.
+func ParseHighlightID(s string) (HighlightID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return HighlightID{}, fmt.Errorf("invalid highlight ID: %w", err)
+	}
+	return HighlightID(id), nil
+}
+
+// Highlight is a quote or excerpt taken from a bibliography. Like Review.Goals and
+// Review.Summary, Text and Comment keep their whitespace and line breaks.
+type Highlight struct {
+	ID       HighlightID
+	BookID   BibliographyID
+	Text     string
+	Location string // page or e-book location as written, e.g. "p. 42" or "loc. 1234-1240"
+	Comment  string
+	Tags     []string
+	// CreatedAt is when the highlight was made, or when it was added if that is unknown.
+	CreatedAt time.Time
+}
+
+// HasTag reports whether the highlight is tagged with tag, ignoring case.
+func (h *Highlight) HasTag(tag string) bool {
+	for _, t := range h.Tags {
+		if strings.EqualFold(t, strings.TrimPrefix(tag, "#")) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags trims tags and drops empty and duplicate ones, keeping the first
+// spelling. A leading '#' is removed. Tags cannot contain whitespace or commas, which
+// separate tags on the command line and in storage.
+func NormalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" {
+			continue
+		}
+		if strings.ContainsAny(tag, ", \t\r\n") {
+			return nil, fmt.Errorf("tag %q must not contain whitespace or commas", tag)
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+
+	"bibliography_log/internal/isbn"
+)
+
+// ISBN is a validated International Standard Book Number, held in its ISBN-13 form.
+// The zero value means "no ISBN". An ISBN read from storage may instead hold a value
+// that failed validation, kept as stored (see StoredISBN).
+type ISBN struct {
+	digits  string // 13 digits, or empty
+	invalid string // the stored value if it is not a valid ISBN
+}
+
+// ParseISBN parses an ISBN-10 or ISBN-13, with or without hyphens and spaces, and
+// validates its check digit. ISBN-10s are converted to ISBN-13. A blank string yields
+// the zero ISBN.
+func ParseISBN(s string) (ISBN, error) {
+	if strings.TrimSpace(s) == "" {
+		return ISBN{}, nil
+	}
+	digits, err := isbn.Normalize(s)
+	if err != nil {
+		return ISBN{}, err
+	}
+	return ISBN{digits: digits}, nil
+}
+
+// StoredISBN returns the ISBN of a value read from storage. Values that ParseISBN rejects
+// are kept as they are, so that reading and rewriting a record never loses them; only
+// 'biblog migrate-isbn' fixes or clears them.
+func StoredISBN(s string) ISBN {
+	i, err := ParseISBN(s)
+	if err != nil {
+		return ISBN{invalid: s}
+	}
+	return i
+}
+
+// MustParseISBN is like ParseISBN but panics on invalid input. It is meant for constants and tests.
+func MustParseISBN(s string) ISBN {
+	i, err := ParseISBN(s)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+// IsZero reports whether no ISBN is set.
+func (i ISBN) IsZero() bool {
+	return i.digits == "" && i.invalid == ""
+}
+
+// Valid reports whether the ISBN passed validation. Only ISBNs from StoredISBN can fail.
+func (i ISBN) Valid() bool {
+	return i.invalid == ""
+}
+
+// Compact returns the 13 digits without hyphens, the form used for storage and comparison.
+// An invalid ISBN returns its value as stored.
+func (i ISBN) Compact() string {
+	if !i.Valid() {
+		return i.invalid
+	}
+	return i.digits
+}
+
+// String returns the ISBN-13 hyphenated by registration group, registrant and publication,
+// e.g. "978-4-7503-5688-4".
+func (i ISBN) String() string {
+	if !i.Valid() {
+		return i.invalid
+	}
+	if i.IsZero() {
+		return ""
+	}
+	return isbn.Format(i.digits)
+}
+
+// ISBN10 returns the hyphenated ISBN-10 form. ok is false for 979 ISBNs, which have none.
+func (i ISBN) ISBN10() (s string, ok bool) {
+	if i.IsZero() || !i.Valid() {
+		return "", false
+	}
+	s, err := isbn.Default().Hyphenate10(i.digits)
+	return s, err == nil
+}
@@ -0,0 +1,20 @@
+package domain
+
+// BookMetadata is bibliographic data about a book published under an ISBN, as
+// reported by an external catalogue. Fields the catalogue does not know are empty.
+type BookMetadata struct {
+	ISBN      ISBN
+	Title     string
+	Author    string // authors joined by ", ", translators appended as "(name訳)"
+	Publisher string
+	Year      int    // 0 if unknown
+	Source    string // name of the provider that answered
+}
+
+// MetadataProvider looks up books by ISBN in an external catalogue.
+type MetadataProvider interface {
+	// Name identifies the provider in messages, e.g. "openbd".
+	Name() string
+	// LookupISBN returns the metadata for an ISBN, or nil if the catalogue does not know it.
+	LookupISBN(isbn ISBN) (*BookMetadata, error)
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingSessionID is a domain-specific type for ReadingSession entity IDs.
+type ReadingSessionID uuid.UUID
+
+// String returns the string representation of the ReadingSessionID.
+func (id ReadingSessionID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// UUID returns the underlying uuid.UUID value.
+func (id ReadingSessionID) UUID() uuid.UUID {
+	return uuid.UUID(id)
+}
+
+// NewReadingSessionID generates a new random ReadingSessionID.
+func NewReadingSessionID() ReadingSessionID {
+	return ReadingSessionID(uuid.New())
+}
+
+// ParseReadingSessionID parses a string into a ReadingSessionID.
+func ParseReadingSessionID(s string) (ReadingSessionID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return ReadingSessionID{}, fmt.Errorf("invalid reading session ID: %w", err)
+	}
+	return ReadingSessionID(id), nil
+}
+
+// ProgressUnit is how a reading session measures its position in a book.
+type ProgressUnit string
+
+const (
+	ProgressPages   ProgressUnit = "pages"
+	ProgressPercent ProgressUnit = "percent"
+)
+
+// ParseProgressUnit parses a progress unit name.
+func ParseProgressUnit(s string) (ProgressUnit, error) {
+	switch unit := ProgressUnit(s); unit {
+	case ProgressPages, ProgressPercent:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("unknown progress unit %q (expected pages or percent)", s)
+	}
+}
+
+// ErrInvalidSession is returned for sessions that do not describe any reading or
+// that go beyond the book.
+var ErrInvalidSession = errors.New("invalid reading session")
+
+// ReadingSession is one sitting with a bibliography: when, for how long, and from
+// where to where. TotalPages is the page count of the book if known; the latest
+// session that gives it is used for progress.
+type ReadingSession struct {
+	ID         ReadingSessionID
+	BookID     BibliographyID
+	Date       time.Time // day of the session, midnight UTC
+	Duration   time.Duration
+	Unit       ProgressUnit
+	Start      int // page or percentage where the session started
+	End        int // page or percentage where the session ended
+	TotalPages int // 0 if unknown
+	Note       string
+	CreatedAt  time.Time
+}
+
+// Validate checks that the session describes some reading within the bounds of the book.
+func (s *ReadingSession) Validate() error {
+	if _, err := ParseProgressUnit(string(s.Unit)); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSession, err)
+	}
+	if s.Duration < 0 {
+		return fmt.Errorf("%w: duration must not be negative", ErrInvalidSession)
+	}
+	if s.Start < 0 {
+		return fmt.Errorf("%w: start must not be negative", ErrInvalidSession)
+	}
+	if s.End < s.Start {
+		return fmt.Errorf("%w: end (%d) must not be before start (%d)", ErrInvalidSession, s.End, s.Start)
+	}
+	if s.End == s.Start && s.Duration == 0 {
+		return fmt.Errorf("%w: a session needs a duration or a page range", ErrInvalidSession)
+	}
+	if s.Unit == ProgressPercent && s.End > 100 {
+		return fmt.Errorf("%w: end must not exceed 100 percent", ErrInvalidSession)
+	}
+	if s.TotalPages < 0 {
+		return fmt.Errorf("%w: total pages must not be negative", ErrInvalidSession)
+	}
+	if s.Unit == ProgressPages && s.TotalPages > 0 && s.End > s.TotalPages {
+		return fmt.Errorf("%w: end page %d is beyond the last page %d", ErrInvalidSession, s.End, s.TotalPages)
+	}
+	return nil
+}
+
+// ReadingProgress summarizes the sessions of one bibliography.
+type ReadingProgress struct {
+	Sessions  int
+	TotalTime time.Duration
+	Unit      ProgressUnit // unit of Position and Total
+	Position  int          // furthest page or percentage reached
+	Total     int          // page count, or 100 for percent; 0 if unknown
+	// PacePerDay is the pages or percent read per calendar day between the first and
+	// the last session.
+	PacePerDay float64
+	// EstimatedFinish is when the book is finished at the current pace; zero if it
+	// cannot be estimated or the book is already finished.
+	EstimatedFinish time.Time
+}
+
+// Remaining returns the pages or percent left, or -1 if the total is unknown.
+func (p ReadingProgress) Remaining() int {
+	if p.Total == 0 {
+		return -1
+	}
+	return max(p.Total-p.Position, 0)
+}
+
+// ComputeProgress summarizes sessions. Position and pace are measured in the unit of the
+// latest session; sessions in the other unit are converted when the page count is known
+// and otherwise count only towards the session total and time.
+func ComputeProgress(sessions []*ReadingSession) ReadingProgress {
+	var p ReadingProgress
+	if len(sessions) == 0 {
+		return p
+	}
+	sorted := append([]*ReadingSession(nil), sessions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	totalPages := 0
+	for _, s := range sorted {
+		p.Sessions++
+		p.TotalTime += s.Duration
+		if s.TotalPages > 0 {
+			totalPages = s.TotalPages
+		}
+	}
+	p.Unit = sorted[len(sorted)-1].Unit
+	switch {
+	case p.Unit == ProgressPercent:
+		p.Total = 100
+	case totalPages > 0:
+		p.Total = totalPages
+	}
+
+	// convert returns a position in p.Unit, or false if it cannot be converted.
+	convert := func(s *ReadingSession, v int) (int, bool) {
+		switch {
+		case s.Unit == p.Unit:
+			return v, true
+		case totalPages == 0:
+			return 0, false
+		case p.Unit == ProgressPages:
+			return v * totalPages / 100, true
+		default:
+			return v * 100 / totalPages, true
+		}
+	}
+
+	read := 0
+	var first, last time.Time
+	for _, s := range sorted {
+		start, ok := convert(s, s.Start)
+		end, _ := convert(s, s.End)
+		if !ok {
+			continue
+		}
+		read += end - start
+		p.Position = max(p.Position, end)
+		if first.IsZero() {
+			first = s.Date
+		}
+		last = s.Date
+	}
+	if first.IsZero() {
+		return p
+	}
+
+	days := last.Sub(first).Hours()/24 + 1
+	p.PacePerDay = float64(read) / days
+	if remaining := p.Remaining(); remaining > 0 && p.PacePerDay > 0 {
+		p.EstimatedFinish = last.AddDate(0, 0, int(math.Ceil(float64(remaining)/p.PacePerDay)))
+	}
+	return p
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReadingStatus is where a reader is with a bibliography.
+type ReadingStatus string
+
+const (
+	ReadingStatusNone      ReadingStatus = "" // not tracked yet
+	ReadingStatusWishlist  ReadingStatus = "wishlist"
+	ReadingStatusToRead    ReadingStatus = "to-read"
+	ReadingStatusReading   ReadingStatus = "reading"
+	ReadingStatusFinished  ReadingStatus = "finished"
+	ReadingStatusAbandoned ReadingStatus = "abandoned"
+)
+
+// ReadingStatuses lists every tracked status in lifecycle order.
+var ReadingStatuses = []ReadingStatus{ReadingStatusWishlist, ReadingStatusToRead, ReadingStatusReading, ReadingStatusFinished, ReadingStatusAbandoned}
+
+// ParseReadingStatus parses a status name. "none" selects ReadingStatusNone.
+func ParseReadingStatus(s string) (ReadingStatus, error) {
+	status := ReadingStatus(strings.ToLower(strings.TrimSpace(s)))
+	if status == "none" {
+		return ReadingStatusNone, nil
+	}
+	for _, valid := range ReadingStatuses {
+		if status == valid {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown reading status %q (expected none, wishlist, to-read, reading, finished or abandoned)", s)
+}
+
+// String returns the status name, "none" for ReadingStatusNone.
+func (s ReadingStatus) String() string {
+	if s == ReadingStatusNone {
+		return "none"
+	}
+	return string(s)
+}
+
+// readingTransitions lists the statuses each status may move to. Finished books can be
+// read again and abandoned ones picked up again; finishing requires reading first.
+var readingTransitions = map[ReadingStatus][]ReadingStatus{
+	ReadingStatusNone:      {ReadingStatusWishlist, ReadingStatusToRead, ReadingStatusReading},
+	ReadingStatusWishlist:  {ReadingStatusToRead, ReadingStatusReading, ReadingStatusAbandoned},
+	ReadingStatusToRead:    {ReadingStatusWishlist, ReadingStatusReading, ReadingStatusAbandoned},
+	ReadingStatusReading:   {ReadingStatusFinished, ReadingStatusAbandoned},
+	ReadingStatusFinished:  {ReadingStatusReading},
+	ReadingStatusAbandoned: {ReadingStatusToRead, ReadingStatusReading},
+}
+
+// CanTransitionTo reports whether a bibliography in status s may move to status to.
+func (s ReadingStatus) CanTransitionTo(to ReadingStatus) bool {
+	for _, next := range readingTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidTransition is returned for a status change the lifecycle does not allow.
+var ErrInvalidTransition = errors.New("invalid reading status transition")
+
+// ReadingStatusChange records one transition of a bibliography's reading status.
+type ReadingStatusChange struct {
+	BookID    BibliographyID
+	From      ReadingStatus
+	To        ReadingStatus
+	ChangedAt time.Time
+}
+
+// ReadingHistory is the status history of one bibliography, oldest change first.
+type ReadingHistory []*ReadingStatusChange
+
+// Current returns the latest status, or ReadingStatusNone if there is no history.
+func (h ReadingHistory) Current() ReadingStatus {
+	if len(h) == 0 {
+		return ReadingStatusNone
+	}
+	return h[len(h)-1].To
+}
+
+// LastChangedTo returns when the bibliography last entered status, e.g. when the
+// current reading was started. ok is false if it never did.
+func (h ReadingHistory) LastChangedTo(status ReadingStatus) (at time.Time, ok bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].To == status {
+			return h[i].ChangedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Transition returns the change that moves the bibliography to status to at the given
+// time. It fails if the lifecycle does not allow the move, or if at precedes the
+// latest change.
+func (h ReadingHistory) Transition(bookID BibliographyID, to ReadingStatus, at time.Time) (*ReadingStatusChange, error) {
+	from := h.Current()
+	if !from.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidTransition, from, to)
+	}
+	if len(h) > 0 && at.Before(h[len(h)-1].ChangedAt) {
+		return nil, fmt.Errorf("%w: %s is before the last change at %s", ErrInvalidTransition,
+			at.Format(time.RFC3339), h[len(h)-1].ChangedAt.Format(time.RFC3339))
+	}
+	return &ReadingStatusChange{BookID: bookID, From: from, To: to, ChangedAt: at}, nil
+}
@@ -2,17 +2,43 @@ package domain
 
 // BibliographyRepository defines the interface for persistence.
 type BibliographyRepository interface {
+	// Save inserts or updates a bibliography. It fails with ErrInvalid if another
+	// bibliography already has its BibIndex.
 	Save(bibliography *Bibliography) error
+	// SaveAll saves several bibliographies at once: either all of them are saved or none.
+	SaveAll(bibliographies []*Bibliography) error
 	FindAll(limit, offset int) ([]*Bibliography, error)
+	// Find returns the bibliographies selected by query, in the order it asks for.
+	Find(query BibliographyQuery) ([]*Bibliography, error)
 	FindByID(id BibliographyID) (*Bibliography, error)
 	FindByBibIndex(bibIndex string) (*Bibliography, error)
+	// Delete removes the bibliography with the given ID. Deleting a missing ID is a no-op.
+	Delete(id BibliographyID) error
+}
+
+// BibliographyCascadeDeleter is implemented by bibliography repositories that can delete a
+// bibliography together with the records referring to it in a single transaction.
+type BibliographyCascadeDeleter interface {
+	// DeleteWithDependents deletes the bibliography with the given ID, its reading status
+	// history, reading sessions and highlights, and, if withReviews, its reviews and their
+	// revisions. Either all of them are deleted or none; reviews that are not deleted make
+	// it fail. Deleting a missing ID is a no-op.
+	DeleteWithDependents(id BibliographyID, withReviews bool) error
+}
+
+// ReferenceEnforcer is implemented by bibliography repositories whose storage refuses
+// records that refer to a missing bibliography, such as reviews kept as orphans.
+type ReferenceEnforcer interface {
+	EnforcesReferences() bool
 }
 
 // ClassificationRepository defines the interface for persistence.
 type ClassificationRepository interface {
 	Save(classification *Classification) error
 	FindAll(limit, offset int) ([]*Classification, error)
-	FindByCodeNum(codeNum int) (*Classification, error)
+	FindByCode(code ClassCode) (*Classification, error)
+	// Delete removes the classification with the given ID. Deleting a missing ID is a no-op.
+	Delete(id ClassificationID) error
 }
 
 // ReviewRepository defines the interface for persistence.
@@ -21,4 +47,71 @@ type ReviewRepository interface {
 	FindAll(limit, offset int) ([]*Review, error)
 	FindByID(id ReviewID) (*Review, error)
 	FindByBookID(bookID BibliographyID) ([]*Review, error)
+	// Delete removes the review with the given ID. Deleting a missing ID is a no-op.
+	Delete(id ReviewID) error
+}
+
+// ReviewRevisionRepository stores the revision history of reviews. Revisions are never changed.
+type ReviewRevisionRepository interface {
+	// Append adds a revision after the latest one of its review.
+	Append(revision *ReviewRevision) error
+	// FindByReviewID returns the history of a review, oldest revision first.
+	FindByReviewID(reviewID ReviewID) (ReviewHistory, error)
+	// DeleteByReviewID removes the history of a review. Deleting a missing history is a no-op.
+	DeleteByReviewID(reviewID ReviewID) error
+}
+
+// ReadingStatusRepository stores the reading status history of bibliographies.
+type ReadingStatusRepository interface {
+	// Save appends a status change to the history of its bibliography.
+	Save(change *ReadingStatusChange) error
+	// FindByBookID returns the history of a bibliography, oldest change first.
+	FindByBookID(bookID BibliographyID) (ReadingHistory, error)
+	// FindCurrent returns the current status of every bibliography with a history.
+	FindCurrent() (map[BibliographyID]ReadingStatus, error)
+	// DeleteByBookID removes the history of a bibliography. Deleting a missing history is a no-op.
+	DeleteByBookID(bookID BibliographyID) error
+}
+
+// HighlightRepository defines the interface for persistence.
+type HighlightRepository interface {
+	Save(highlight *Highlight) error
+	FindAll(limit, offset int) ([]*Highlight, error)
+	FindByID(id HighlightID) (*Highlight, error)
+	// FindByBookID returns the highlights of a bibliography in the order they were added.
+	FindByBookID(bookID BibliographyID) ([]*Highlight, error)
+	// Delete removes the highlight with the given ID. Deleting a missing ID is a no-op.
+	Delete(id HighlightID) error
+}
+
+// ReadingSessionRepository defines the interface for persistence.
+type ReadingSessionRepository interface {
+	Save(session *ReadingSession) error
+	FindAll(limit, offset int) ([]*ReadingSession, error)
+	FindByID(id ReadingSessionID) (*ReadingSession, error)
+	// FindByBookID returns the sessions of a bibliography in the order they were logged.
+	FindByBookID(bookID BibliographyID) ([]*ReadingSession, error)
+	// Delete removes the session with the given ID. Deleting a missing ID is a no-op.
+	Delete(id ReadingSessionID) error
+}
+
+// ISBNMigration reports how one stored ISBN was rewritten by an ISBNNormalizer.
+type ISBNMigration struct {
+	BibliographyID string
+	BibIndex       string
+	Title          string
+	Old            string
+	New            string // compact ISBN-13; empty if Old is invalid
+	Err            error  // why Old is invalid
+	Cleared        bool   // the invalid ISBN was removed
+}
+
+// ISBNNormalizer is implemented by bibliography repositories that can rewrite stored
+// ISBNs in place. It works on the stored text because bibliographies with invalid
+// ISBNs cannot be loaded.
+type ISBNNormalizer interface {
+	// NormalizeISBNs stores every valid ISBN in compact ISBN-13 form and reports the
+	// ones it changed and the invalid ones, which are removed only if clearInvalid is
+	// set. With dryRun nothing is written.
+	NormalizeISBNs(dryRun, clearInvalid bool) ([]ISBNMigration, error)
 }
@@ -43,3 +43,93 @@ type Review struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// RevisionAction is what produced a review revision.
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionReverted RevisionAction = "reverted"
+	// RevisionRecorded is the state of a review written before revisions were kept, recorded
+	// when it is first updated so that the previous version is not lost.
+	RevisionRecorded RevisionAction = "recorded"
+)
+
+// ReviewRevision is an immutable snapshot of a review's Goals and Summary, taken each
+// time the review is added, updated or reverted.
+type ReviewRevision struct {
+	ReviewID   ReviewID
+	Number     int // 1 for the oldest revision of the review, counting up
+	Goals      string
+	Summary    string
+	Action     RevisionAction
+	RevertedTo int    // for RevisionReverted, the revision that was restored
+	ChangedBy  string // who made the change; empty if unknown
+	CreatedAt  time.Time
+}
+
+// ChangedFields lists the fields ("goals", "summary") that differ from the previous
+// revision; prev is nil for the first revision, where every non-empty field counts.
+func (r *ReviewRevision) ChangedFields(prev *ReviewRevision) []string {
+	var before ReviewRevision
+	if prev != nil {
+		before = *prev
+	}
+	var fields []string
+	if r.Goals != before.Goals {
+		fields = append(fields, "goals")
+	}
+	if r.Summary != before.Summary {
+		fields = append(fields, "summary")
+	}
+	return fields
+}
+
+// ReviewHistory is the revisions of one review, oldest first.
+type ReviewHistory []*ReviewRevision
+
+// Latest returns the newest revision, or nil if there is none.
+func (h ReviewHistory) Latest() *ReviewRevision {
+	if len(h) == 0 {
+		return nil
+	}
+	return h[len(h)-1]
+}
+
+// Revision returns the revision with the given number.
+func (h ReviewHistory) Revision(number int) (*ReviewRevision, error) {
+	for _, revision := range h {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d %w (the review has revisions 1 to %d)", number, ErrNotFound, len(h))
+}
+
+// Previous returns the revision before r, or nil for the first one.
+func (h ReviewHistory) Previous(r *ReviewRevision) *ReviewRevision {
+	for i, revision := range h {
+		if revision == r && i > 0 {
+			return h[i-1]
+		}
+	}
+	return nil
+}
+
+// Next returns the revision that records the review as it is now changed by action.
+func (h ReviewHistory) Next(review *Review, action RevisionAction, changedBy string) *ReviewRevision {
+	number := 1
+	if latest := h.Latest(); latest != nil {
+		number = latest.Number + 1
+	}
+	return &ReviewRevision{
+		ReviewID:  review.ID,
+		Number:    number,
+		Goals:     review.Goals,
+		Summary:   review.Summary,
+		Action:    action,
+		ChangedBy: changedBy,
+		CreatedAt: review.UpdatedAt,
+	}
+}
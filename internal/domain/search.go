@@ -0,0 +1,44 @@
+package domain
+
+import "errors"
+
+// Searchable fields of bibliographies and reviews.
+const (
+	SearchFieldTitle     = "title"
+	SearchFieldAuthor    = "author"
+	SearchFieldPublisher = "publisher"
+	SearchFieldGoals     = "goals"
+	SearchFieldSummary   = "summary"
+)
+
+// SearchFields lists every searchable field.
+var SearchFields = []string{SearchFieldTitle, SearchFieldAuthor, SearchFieldPublisher, SearchFieldGoals, SearchFieldSummary}
+
+// ErrSearchIndexNotBuilt is returned by SearchIndex.Search before the index has been built.
+var ErrSearchIndexNotBuilt = errors.New("search index has not been built")
+
+// SearchHit is a bibliography matched by a search, either directly or through its reviews.
+type SearchHit struct {
+	BookID    BibliographyID
+	Score     float64
+	Fields    []string   // matched fields, in SearchFields order
+	ReviewIDs []ReviewID // reviews that matched
+}
+
+// SearchIndex is a full-text index over bibliographies and their reviews.
+// Until Rebuild has been called, updates are ignored and Search returns ErrSearchIndexNotBuilt,
+// so an index is never built from a partial set of entities.
+type SearchIndex interface {
+	// IndexBibliography adds or replaces a bibliography.
+	IndexBibliography(bibliography *Bibliography) error
+	// IndexReview adds or replaces a review.
+	IndexReview(review *Review) error
+	// RemoveBibliography removes a bibliography. Removing a missing ID is a no-op.
+	RemoveBibliography(id BibliographyID) error
+	// RemoveReview removes a review. Removing a missing ID is a no-op.
+	RemoveReview(id ReviewID) error
+	// Rebuild replaces the index contents with the given entities.
+	Rebuild(bibliographies []*Bibliography, reviews []*Review) error
+	// Search returns the bibliographies matching query, most relevant first.
+	Search(query string) ([]SearchHit, error)
+}
@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestBibliographyRepository_Find runs each query against the CSV and the SQLite backend,
+// which must agree: the CSV backend filters in memory, SQLite pushes the query down.
+func TestBibliographyRepository_Find(t *testing.T) {
+	dir := t.TempDir()
+	csvRepo := NewCSVBibliographyRepository(filepath.Join(dir, "bibliographies.csv"))
+	csvRepo.ReviewFilePath = filepath.Join(dir, "reviews.csv")
+	csvRepo.StatusFilePath = filepath.Join(dir, "reading_status.csv")
+	db := newTestSQLiteDB(t)
+	backends := []struct {
+		name       string
+		bibRepo    domain.BibliographyRepository
+		reviewRepo domain.ReviewRepository
+		statusRepo domain.ReadingStatusRepository
+	}{
+		{"csv", csvRepo, NewCSVReviewRepository(csvRepo.ReviewFilePath), NewCSVReadingStatusRepository(csvRepo.StatusFilePath)},
+		{"sqlite", NewSQLiteBibliographyRepository(db), NewSQLiteReviewRepository(db), NewSQLiteReadingStatusRepository(db)},
+	}
+
+	// The second bibliography is reviewed; the first was read to the end, the fourth is
+	// being read. The last two have non-ASCII titles, authors and publishers.
+	bibs := []*domain.Bibliography{
+		{BibIndex: "B56C", Code: "B56", Type: "Book", Title: "go in practice", Author: "Alice Smith", Publisher: "Manning", ISBN: domain.MustParseISBN("9781633430075"), PublishedDate: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "A56A", Code: "A56", Type: "Article", Title: "Borrowing", Author: "Bob Jones", Publisher: "ACM", PublishedDate: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "B16B", Code: "B16", Type: "Book", Title: "Asia", Author: "carol smith", Publisher: "Iwanami",
+			Contributors: []domain.Contributor{{Name: "carol smith", Role: domain.RoleAuthor}, {Name: "山田花子", Role: domain.RoleTranslator, NameEn: "Hanako Yamada"}}, PublishedDate: time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "B56D", Code: "B56", Type: "Book", Title: "Concurrency", Author: "Dan Brown", Publisher: "O'Reilly", ISBN: domain.MustParseISBN("9781491941195"), PublishedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "B95E", Code: "B95", Type: "Book", Title: "Élan", Author: "ÉMILE ZOLA", Publisher: "Éditions Gallimard", PublishedDate: time.Date(1997, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BibIndex: "A95F", Code: "A95", Type: "Article", Title: "éclat", Author: "Émile Ajar", Publisher: "Mercure de France", PublishedDate: time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, bib := range bibs {
+		bib.ID = domain.NewBibliographyID()
+	}
+	for _, backend := range backends {
+		for _, bib := range bibs {
+			if err := backend.bibRepo.Save(bib); err != nil {
+				t.Fatalf("%s: Failed to save bibliography: %v", backend.name, err)
+			}
+		}
+		review := &domain.Review{ID: domain.NewReviewID(), BookID: bibs[1].ID, Goals: "goals", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := backend.reviewRepo.Save(review); err != nil {
+			t.Fatalf("%s: Failed to save review: %v", backend.name, err)
+		}
+		changes := []*domain.ReadingStatusChange{
+			{BookID: bibs[0].ID, To: domain.ReadingStatusReading, ChangedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{BookID: bibs[3].ID, To: domain.ReadingStatusReading, ChangedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{BookID: bibs[0].ID, From: domain.ReadingStatusReading, To: domain.ReadingStatusFinished, ChangedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		}
+		for _, change := range changes {
+			if err := backend.statusRepo.Save(change); err != nil {
+				t.Fatalf("%s: Failed to save reading status: %v", backend.name, err)
+			}
+		}
+	}
+
+	class56, class5, class1 := domain.ClassCode("56"), domain.ClassCode("5"), domain.ClassCode("1")
+	yes, no := true, false
+	reading, finished, untracked := domain.ReadingStatusReading, domain.ReadingStatusFinished, domain.ReadingStatusNone
+	tests := []struct {
+		name  string
+		query domain.BibliographyQuery
+		want  []string
+	}{
+		{"insertion order", domain.BibliographyQuery{}, []string{"go in practice", "Borrowing", "Asia", "Concurrency", "Élan", "éclat"}},
+		{"insertion order descending", domain.BibliographyQuery{Descending: true}, []string{"éclat", "Élan", "Concurrency", "Asia", "Borrowing", "go in practice"}},
+		{"type is case-insensitive", domain.BibliographyQuery{Type: "book"}, []string{"go in practice", "Asia", "Concurrency", "Élan"}},
+		{"class", domain.BibliographyQuery{ClassCode: &class56}, []string{"go in practice", "Borrowing", "Concurrency"}},
+		{"class excludes subclasses", domain.BibliographyQuery{ClassCode: &class5}, nil},
+		{"class with subclasses", domain.BibliographyQuery{ClassCode: &class5, ClassRecursive: true}, []string{"go in practice", "Borrowing", "Concurrency"}},
+		{"other class with subclasses", domain.BibliographyQuery{ClassCode: &class1, ClassRecursive: true}, []string{"Asia"}},
+		{"year range", domain.BibliographyQuery{YearFrom: 2010, YearTo: 2016}, []string{"go in practice"}},
+		{"year from", domain.BibliographyQuery{YearFrom: 2020}, []string{"Borrowing", "Concurrency"}},
+		{"author substring", domain.BibliographyQuery{Author: "SMITH"}, []string{"go in practice", "Asia"}},
+		{"author folds non-ASCII case", domain.BibliographyQuery{Author: "émile"}, []string{"Élan", "éclat"}},
+		{"author matches any contributor", domain.BibliographyQuery{Author: "山田"}, []string{"Asia"}},
+		{"author matches romanized names", domain.BibliographyQuery{Author: "hanako"}, []string{"Asia"}},
+		{"publisher substring", domain.BibliographyQuery{Publisher: "reilly"}, []string{"Concurrency"}},
+		{"publisher folds non-ASCII case", domain.BibliographyQuery{Publisher: "ÉDITIONS"}, []string{"Élan"}},
+		{"has review", domain.BibliographyQuery{HasReview: &yes}, []string{"Borrowing"}},
+		{"no review", domain.BibliographyQuery{HasReview: &no}, []string{"go in practice", "Asia", "Concurrency", "Élan", "éclat"}},
+		{"has isbn", domain.BibliographyQuery{HasISBN: &yes}, []string{"go in practice", "Concurrency"}},
+		{"no isbn", domain.BibliographyQuery{HasISBN: &no}, []string{"Borrowing", "Asia", "Élan", "éclat"}},
+		{"status reading", domain.BibliographyQuery{Status: &reading}, []string{"Concurrency"}},
+		{"status is the latest change", domain.BibliographyQuery{Status: &finished}, []string{"go in practice"}},
+		{"status none", domain.BibliographyQuery{Status: &untracked}, []string{"Borrowing", "Asia", "Élan", "éclat"}},
+		{"sort by title ignores case", domain.BibliographyQuery{Sort: domain.SortByTitle}, []string{"Asia", "Borrowing", "Concurrency", "go in practice", "éclat", "Élan"}},
+		{"sort by author descending", domain.BibliographyQuery{Sort: domain.SortByAuthor, Descending: true}, []string{"Élan", "éclat", "Concurrency", "Asia", "Borrowing", "go in practice"}},
+		{"sort by year", domain.BibliographyQuery{Sort: domain.SortByYear}, []string{"éclat", "Élan", "Asia", "go in practice", "Concurrency", "Borrowing"}},
+		{"sort by bibindex", domain.BibliographyQuery{Sort: domain.SortByBibIndex}, []string{"Borrowing", "éclat", "Asia", "go in practice", "Concurrency", "Élan"}},
+		{"combined filters and paging", domain.BibliographyQuery{Type: "Book", Sort: domain.SortByTitle, Offset: 1, Limit: 1}, []string{"Concurrency"}},
+		{"no matches", domain.BibliographyQuery{Author: "nobody"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, backend := range backends {
+				found, err := backend.bibRepo.Find(tt.query)
+				if err != nil {
+					t.Fatalf("%s: Find() error = %v", backend.name, err)
+				}
+				var got []string
+				for _, bib := range found {
+					got = append(got, bib.Title)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: Find() = %q, want %q", backend.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVBibliographyRepository_FindByReviewWithoutReviewFile(t *testing.T) {
+	repo := NewCSVBibliographyRepository(filepath.Join(t.TempDir(), "bibliographies.csv"))
+	yes := true
+	if _, err := repo.Find(domain.BibliographyQuery{HasReview: &yes}); err == nil {
+		t.Error("Expected error when filtering by review without a review file, got nil")
+	}
+	reading := domain.ReadingStatusReading
+	if _, err := repo.Find(domain.BibliographyQuery{Status: &reading}); err == nil {
+		t.Error("Expected error when filtering by status without a status file, got nil")
+	}
+}
@@ -2,8 +2,10 @@ package infrastructure
 
 import (
 	"bibliography_log/internal/domain"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -18,9 +20,56 @@ type BibliographyRecord struct {
 	Publisher     string
 	ISBN          string
 	PublishedDate string
+	Contributors  string // JSON array; empty in files written before contributors were tracked
+}
+
+// bibliographyHeader is the header row of the bibliography CSV.
+var bibliographyHeader = []string{"ID", "BibIndex", "Code", "Type", "Title", "Author", "Publisher", "ISBN", "PublishedDate", "Contributors"}
+
+// minBibliographyFields is the number of columns before Contributors was added.
+const minBibliographyFields = 9
+
+// contributorRecord is the stored form of a domain.Contributor.
+type contributorRecord struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	NameEn string `json:"name_en,omitempty"`
+}
+
+func encodeContributors(contributors []domain.Contributor) string {
+	if len(contributors) == 0 {
+		return ""
+	}
+	records := make([]contributorRecord, 0, len(contributors))
+	for _, c := range contributors {
+		records = append(records, contributorRecord{Name: c.Name, Role: string(c.Role), NameEn: c.NameEn})
+	}
+	data, _ := json.Marshal(records) // strings only, cannot fail
+	return string(data)
+}
+
+func decodeContributors(s string) ([]domain.Contributor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var records []contributorRecord
+	if err := json.Unmarshal([]byte(s), &records); err != nil {
+		return nil, err
+	}
+	contributors := make([]domain.Contributor, 0, len(records))
+	for _, rec := range records {
+		role, err := domain.ParseContributorRole(rec.Role)
+		if err != nil {
+			return nil, err
+		}
+		contributors = append(contributors, domain.Contributor{Name: rec.Name, Role: role, NameEn: rec.NameEn})
+	}
+	return contributors, nil
 }
 
 // recordToBibliography converts a BibliographyRecord to a domain.Bibliography.
+// An invalid ISBN is kept as stored; migrate-isbn reports and fixes it. Contributors that
+// cannot be decoded are read from the credit line instead.
 func recordToBibliography(rec *BibliographyRecord) (*domain.Bibliography, error) {
 	id, err := domain.ParseBibliographyID(rec.ID)
 	if err != nil {
@@ -32,6 +81,21 @@ func recordToBibliography(rec *BibliographyRecord) (*domain.Bibliography, error)
 		return nil, fmt.Errorf("failed to parse published date: %w", err)
 	}
 
+	contributors, err := decodeContributors(rec.Contributors)
+	if err != nil {
+		// Never drop the row over it. The author line is formatted from the contributors,
+		// so it recovers them; a cell that is not JSON at all is read as a credit line.
+		slog.Warn("Failed to parse contributors; reading them as a credit line", "bibIndex", rec.BibIndex, "err", err)
+		line := rec.Author
+		if !strings.HasPrefix(strings.TrimSpace(rec.Contributors), "[") {
+			line = rec.Contributors
+		}
+		contributors = domain.ParseCreditLine(line)
+	}
+	if len(contributors) == 0 {
+		contributors = domain.ParseCreditLine(rec.Author)
+	}
+
 	return &domain.Bibliography{
 		ID:            id,
 		BibIndex:      rec.BibIndex,
@@ -39,8 +103,9 @@ func recordToBibliography(rec *BibliographyRecord) (*domain.Bibliography, error)
 		Type:          rec.Type,
 		Title:         rec.Title,
 		Author:        rec.Author,
+		Contributors:  contributors,
 		Publisher:     rec.Publisher,
-		ISBN:          rec.ISBN,
+		ISBN:          domain.StoredISBN(rec.ISBN),
 		PublishedDate: pubDate,
 	}, nil
 }
@@ -55,14 +120,57 @@ func bibliographyToRecord(bib *domain.Bibliography) *BibliographyRecord {
 		Title:         bib.Title,
 		Author:        bib.Author,
 		Publisher:     bib.Publisher,
-		ISBN:          bib.ISBN,
+		ISBN:          bib.ISBN.Compact(),
 		PublishedDate: bib.PublishedDate.Format(time.RFC3339),
+		Contributors:  encodeContributors(bib.Contributors),
+	}
+}
+
+func (rec *BibliographyRecord) fields() []string {
+	return []string{rec.ID, rec.BibIndex, rec.Code, rec.Type, rec.Title, rec.Author, rec.Publisher, rec.ISBN, rec.PublishedDate, rec.Contributors}
+}
+
+// bibliographyRecordFromFields reads a CSV row, which has no Contributors column in
+// files written before contributors were tracked.
+func bibliographyRecordFromFields(record []string) *BibliographyRecord {
+	rec := &BibliographyRecord{
+		ID:            record[0],
+		BibIndex:      record[1],
+		Code:          record[2],
+		Type:          record[3],
+		Title:         record[4],
+		Author:        record[5],
+		Publisher:     record[6],
+		ISBN:          record[7],
+		PublishedDate: record[8],
+	}
+	if len(record) > minBibliographyFields {
+		rec.Contributors = record[9]
+	}
+	return rec
+}
+
+// migrateISBN computes the stored form of a raw ISBN. changed is false if the
+// value is already normalized (or empty).
+func migrateISBN(id, bibIndex, title, old string, clearInvalid bool) (m domain.ISBNMigration, changed bool) {
+	m = domain.ISBNMigration{BibliographyID: id, BibIndex: bibIndex, Title: title, Old: old}
+	isbn, err := domain.ParseISBN(old)
+	if err != nil {
+		m.Err = err
+		m.Cleared = clearInvalid
+		return m, true
 	}
+	m.New = isbn.Compact()
+	return m, m.New != old
 }
 
 // CSVBibliographyRepository implements domain.BibliographyRepository using a CSV file.
 type CSVBibliographyRepository struct {
 	FilePath string
+	// ReviewFilePath is the review CSV consulted by Find for HasReview filters.
+	ReviewFilePath string
+	// StatusFilePath is the reading status CSV consulted by Find for Status filters.
+	StatusFilePath string
 }
 
 func NewCSVBibliographyRepository(filePath string) *CSVBibliographyRepository {
@@ -70,64 +178,71 @@ func NewCSVBibliographyRepository(filePath string) *CSVBibliographyRepository {
 }
 
 // Save implements domain.BibliographyRepository.Save
-// Potential race condition: This method reads all records, modifies them, and writes them back
-// without any locking mechanism. Acceptable for single-user CLI usage, but consider file locking
-// or using a database with proper transaction support for production use.
 func (r *CSVBibliographyRepository) Save(b *domain.Bibliography) error {
-	records, err := ReadCSV(r.FilePath)
+	return withFileLock(r.FilePath, func() error {
+		return r.save(b)
+	})
+}
+
+func (r *CSVBibliographyRepository) save(bibliographies ...*domain.Bibliography) error {
+	all, err := r.loadAll(true)
 	if err != nil {
 		return err
 	}
-
-	// Skip header
-	if len(records) > 0 {
-		records = records[1:]
+	if err := ensureBibIndexesFree(all, bibliographies); err != nil {
+		return err
 	}
 
-	iter := NewCSVRecordIterator(records, 0, 0)
-	var all []*domain.Bibliography
-
-	for iter.Next() {
-		record := iter.Record()
-		if len(record) < 9 {
-			continue
-		}
-		bibRecord := &BibliographyRecord{
-			ID:            record[0],
-			BibIndex:      record[1],
-			Code:          record[2],
-			Type:          record[3],
-			Title:         record[4],
-			Author:        record[5],
-			Publisher:     record[6],
-			ISBN:          record[7],
-			PublishedDate: record[8],
+	for _, b := range bibliographies {
+		updated := false
+		for i, existing := range all {
+			if existing.ID == b.ID {
+				all[i] = b
+				updated = true
+				break
+			}
 		}
-		bib, err := recordToBibliography(bibRecord)
-		if err != nil {
-			slog.Error("Failed to convert bibliography record", "err", err)
-			continue
+		if !updated {
+			all = append(all, b)
 		}
-		all = append(all, bib)
 	}
 
-	if iter.Err() != nil {
-		return iter.Err()
-	}
+	return r.writeAll(all)
+}
 
-	updated := false
-	for i, existing := range all {
-		if existing.ID == b.ID {
-			all[i] = b
-			updated = true
-			break
+// ensureBibIndexesFree returns an ErrInvalid error if a bibliography being saved takes a
+// BibIndex held by another bibliography, either already stored or saved alongside it.
+// It runs under the file lock, so two processes cannot both claim the same BibIndex.
+func ensureBibIndexesFree(stored, saving []*domain.Bibliography) error {
+	owners := make(map[string]domain.BibliographyID)
+	for _, b := range saving {
+		if owner, ok := owners[b.BibIndex]; ok && owner != b.ID {
+			return domain.Invalid(fmt.Errorf("BibIndex %s is already used", b.BibIndex))
 		}
+		owners[b.BibIndex] = b.ID
 	}
-	if !updated {
-		all = append(all, b)
+	savingIDs := make(map[domain.BibliographyID]bool)
+	for _, b := range saving {
+		savingIDs[b.ID] = true
 	}
+	for _, existing := range stored {
+		// A stored record being saved again may move away from its old BibIndex.
+		if savingIDs[existing.ID] {
+			continue
+		}
+		if _, ok := owners[existing.BibIndex]; ok {
+			return domain.Invalid(fmt.Errorf("BibIndex %s is already used by %q (%s)", existing.BibIndex, existing.Title, existing.ID))
+		}
+	}
+	return nil
+}
 
-	return r.writeAll(all)
+// SaveAll implements domain.BibliographyRepository.SaveAll
+// The file is rewritten once, under the same lock as Save.
+func (r *CSVBibliographyRepository) SaveAll(bibliographies []*domain.Bibliography) error {
+	return withFileLock(r.FilePath, func() error {
+		return r.save(bibliographies...)
+	})
 }
 
 func (r *CSVBibliographyRepository) FindAll(limit, offset int) ([]*domain.Bibliography, error) {
@@ -146,23 +261,11 @@ func (r *CSVBibliographyRepository) FindAll(limit, offset int) ([]*domain.Biblio
 
 	for iter.Next() {
 		record := iter.Record()
-		if len(record) < 9 {
+		if len(record) < minBibliographyFields {
 			continue
 		}
 
-		bibRecord := &BibliographyRecord{
-			ID:            record[0],
-			BibIndex:      record[1],
-			Code:          record[2],
-			Type:          record[3],
-			Title:         record[4],
-			Author:        record[5],
-			Publisher:     record[6],
-			ISBN:          record[7],
-			PublishedDate: record[8],
-		}
-
-		bib, err := recordToBibliography(bibRecord)
+		bib, err := recordToBibliography(bibliographyRecordFromFields(record))
 		if err != nil {
 			slog.Error("Failed to convert bibliography record", "err", err)
 			continue
@@ -174,6 +277,68 @@ func (r *CSVBibliographyRepository) FindAll(limit, offset int) ([]*domain.Biblio
 	return bibliographies, iter.Err()
 }
 
+// Find implements domain.BibliographyRepository.Find
+// The CSV file has no query engine, so the query is evaluated in memory.
+func (r *CSVBibliographyRepository) Find(q domain.BibliographyQuery) ([]*domain.Bibliography, error) {
+	all, err := r.loadAll(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewed map[string]bool
+	if q.HasReview != nil {
+		if reviewed, err = r.reviewedBookIDs(); err != nil {
+			return nil, err
+		}
+	}
+
+	var statuses map[domain.BibliographyID]domain.ReadingStatus
+	if q.Status != nil {
+		if r.StatusFilePath == "" {
+			return nil, fmt.Errorf("filtering by reading status requires the status file path")
+		}
+		if statuses, err = NewCSVReadingStatusRepository(r.StatusFilePath).FindCurrent(); err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []*domain.Bibliography
+	for _, bib := range all {
+		if q.Matches(bib, reviewed[bib.ID.String()], statuses[bib.ID]) {
+			matched = append(matched, bib)
+		}
+	}
+	q.SortBibliographies(matched)
+	return q.Page(matched), nil
+}
+
+// reviewedBookIDs returns the IDs of bibliographies that have at least one review.
+func (r *CSVBibliographyRepository) reviewedBookIDs() (map[string]bool, error) {
+	if r.ReviewFilePath == "" {
+		return nil, fmt.Errorf("filtering by review requires the review file path")
+	}
+	records, err := ReadCSV(r.ReviewFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	iter := NewCSVRecordIterator(records, 0, 0)
+	reviewed := make(map[string]bool)
+	for iter.Next() {
+		record := iter.Record()
+		if len(record) < 2 {
+			continue
+		}
+		reviewed[record[1]] = true
+	}
+	return reviewed, iter.Err()
+}
+
 // FindByBibIndex implements domain.BibliographyRepository.FindByBibIndex
 func (r *CSVBibliographyRepository) FindByBibIndex(bibIndex string) (*domain.Bibliography, error) {
 	records, err := ReadCSV(r.FilePath)
@@ -190,23 +355,12 @@ func (r *CSVBibliographyRepository) FindByBibIndex(bibIndex string) (*domain.Bib
 
 	for iter.Next() {
 		record := iter.Record()
-		if len(record) < 9 {
+		if len(record) < minBibliographyFields {
 			continue
 		}
 		// Optimization: Check BibIndex (index 1) before full conversion
 		if record[1] == bibIndex {
-			bibRecord := &BibliographyRecord{
-				ID:            record[0],
-				BibIndex:      record[1],
-				Code:          record[2],
-				Type:          record[3],
-				Title:         record[4],
-				Author:        record[5],
-				Publisher:     record[6],
-				ISBN:          record[7],
-				PublishedDate: record[8],
-			}
-			return recordToBibliography(bibRecord)
+			return recordToBibliography(bibliographyRecordFromFields(record))
 		}
 	}
 
@@ -230,23 +384,12 @@ func (r *CSVBibliographyRepository) FindByID(id domain.BibliographyID) (*domain.
 
 	for iter.Next() {
 		record := iter.Record()
-		if len(record) < 9 {
+		if len(record) < minBibliographyFields {
 			continue
 		}
 		// Optimization: Check ID (index 0) before full conversion
 		if record[0] == idStr {
-			bibRecord := &BibliographyRecord{
-				ID:            record[0],
-				BibIndex:      record[1],
-				Code:          record[2],
-				Type:          record[3],
-				Title:         record[4],
-				Author:        record[5],
-				Publisher:     record[6],
-				ISBN:          record[7],
-				PublishedDate: record[8],
-			}
-			return recordToBibliography(bibRecord)
+			return recordToBibliography(bibliographyRecordFromFields(record))
 		}
 	}
 
@@ -254,24 +397,100 @@ func (r *CSVBibliographyRepository) FindByID(id domain.BibliographyID) (*domain.
 }
 
 func (r *CSVBibliographyRepository) writeAll(bibliographies []*domain.Bibliography) error {
-	var records [][]string
-	// Header
-	records = append(records, []string{"ID", "BibIndex", "Code", "Type", "Title", "Author", "Publisher", "ISBN", "PublishedDate"})
-
+	records := [][]string{bibliographyHeader}
 	for _, b := range bibliographies {
-		rec := bibliographyToRecord(b)
-		record := []string{
-			rec.ID,
-			rec.BibIndex,
-			rec.Code,
-			rec.Type,
-			rec.Title,
-			rec.Author,
-			rec.Publisher,
-			rec.ISBN,
-			rec.PublishedDate,
-		}
-		records = append(records, record)
+		records = append(records, bibliographyToRecord(b).fields())
 	}
 	return WriteCSV(r.FilePath, records)
 }
+
+// Delete implements domain.BibliographyRepository.Delete
+func (r *CSVBibliographyRepository) Delete(id domain.BibliographyID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll(true)
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, existing := range all {
+			if existing.ID != id {
+				kept = append(kept, existing)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+// NormalizeISBNs implements domain.ISBNNormalizer
+// Rows are rewritten as stored, so rows that fail to load for other reasons are kept as they are.
+func (r *CSVBibliographyRepository) NormalizeISBNs(dryRun, clearInvalid bool) ([]domain.ISBNMigration, error) {
+	var migrations []domain.ISBNMigration
+	err := withFileLock(r.FilePath, func() error {
+		records, err := ReadCSV(r.FilePath)
+		if err != nil {
+			return err
+		}
+
+		rewrite := false
+		for i, record := range records {
+			// Skip header
+			if i == 0 || len(record) < minBibliographyFields {
+				continue
+			}
+			m, changed := migrateISBN(record[0], record[1], record[4], record[7], clearInvalid)
+			if !changed {
+				continue
+			}
+			migrations = append(migrations, m)
+			if m.Err == nil || m.Cleared {
+				record[7] = m.New
+				rewrite = true
+			}
+		}
+		if !rewrite || dryRun {
+			return nil
+		}
+		return WriteCSV(r.FilePath, records)
+	})
+	return migrations, err
+}
+
+// loadAll reads every bibliography in file order. Callers writing the result back must hold the file lock
+// and set rewrite: rows that cannot be converted are then an error instead of being logged and skipped,
+// since writing the file back would drop them.
+func (r *CSVBibliographyRepository) loadAll(rewrite bool) ([]*domain.Bibliography, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	iter := NewCSVRecordIterator(records, 0, 0)
+	var all []*domain.Bibliography
+
+	for iter.Next() {
+		record := iter.Record()
+		if len(record) < minBibliographyFields {
+			continue
+		}
+		bib, err := recordToBibliography(bibliographyRecordFromFields(record))
+		if err != nil {
+			if rewrite {
+				return nil, fmt.Errorf("not rewriting %s, which has a bibliography that cannot be read (fix row %q by hand): %w", r.FilePath, record[0], err)
+			}
+			slog.Error("Failed to convert bibliography record", "err", err)
+			continue
+		}
+		all = append(all, bib)
+	}
+
+	return all, iter.Err()
+}
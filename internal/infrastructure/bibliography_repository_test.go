@@ -2,7 +2,10 @@ package infrastructure
 
 import (
 	"bibliography_log/internal/domain"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -33,7 +36,7 @@ func TestCSVBibliographyRepository_SaveAndFind(t *testing.T) {
 		Title:         "Test Book",
 		Author:        "Test Author",
 		Publisher:     "Test Publisher",
-		ISBN:          "1234567890",
+		ISBN:          domain.MustParseISBN("123456789X"),
 		PublishedDate: time.Now().Truncate(time.Second), // Truncate to match CSV precision if needed, though RFC3339 handles it well.
 	}
 
@@ -72,3 +75,333 @@ func TestCSVBibliographyRepository_SaveAndFind(t *testing.T) {
 		t.Errorf("Expected found ID %v, got %v", bib.ID, found.ID)
 	}
 }
+
+func TestCSVBibliographyRepository_Delete(t *testing.T) {
+	repo := NewCSVBibliographyRepository(filepath.Join(t.TempDir(), "bibliographies.csv"))
+
+	var ids []domain.BibliographyID
+	for _, index := range []string{"B56A", "B56B"} {
+		bib := &domain.Bibliography{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      index,
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Title",
+			Author:        "Author",
+			PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Save(bib); err != nil {
+			t.Fatalf("Failed to save bibliography: %v", err)
+		}
+		ids = append(ids, bib.ID)
+	}
+
+	if err := repo.Delete(ids[0]); err != nil {
+		t.Fatalf("Failed to delete bibliography: %v", err)
+	}
+	// Deleting a missing ID is a no-op
+	if err := repo.Delete(domain.NewBibliographyID()); err != nil {
+		t.Fatalf("Expected no error deleting missing ID, got %v", err)
+	}
+
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to find all: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != ids[1] {
+		t.Errorf("Expected only the second bibliography to remain, got %v", all)
+	}
+}
+
+// legacyISBNs are stored ISBNs as written before ISBNs were validated, keyed by BibIndex.
+var legacyISBNs = map[string]string{
+	"B1": "978-4-7503-5688-4", // hyphenated
+	"B2": "0-306-40615-2",     // ISBN-10
+	"B3": "9780804429573",     // already normalized
+	"B4": "978-4-7503-5688-5", // wrong check digit
+	"B5": "",
+}
+
+func checkISBNMigrations(t *testing.T, migrations []domain.ISBNMigration, clearInvalid bool) {
+	t.Helper()
+	got := make(map[string]domain.ISBNMigration)
+	for _, m := range migrations {
+		got[m.BibIndex] = m
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 migrations (B1, B2, B4), got %+v", migrations)
+	}
+	if got["B1"].New != "9784750356884" || got["B2"].New != "9780306406157" {
+		t.Errorf("Unexpected normalized values: %+v, %+v", got["B1"], got["B2"])
+	}
+	if got["B4"].Err == nil || got["B4"].Cleared != clearInvalid {
+		t.Errorf("Expected B4 to be invalid (cleared=%v), got %+v", clearInvalid, got["B4"])
+	}
+}
+
+func TestCSVBibliographyRepository_NormalizeISBNs(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "bibliographies.csv")
+	records := [][]string{{"ID", "BibIndex", "Code", "Type", "Title", "Author", "Publisher", "ISBN", "PublishedDate"}}
+	for _, index := range []string{"B1", "B2", "B3", "B4", "B5"} {
+		records = append(records, []string{domain.NewBibliographyID().String(), index, "B56", "Book", "Title " + index, "Author", "", legacyISBNs[index], "2024-01-01T00:00:00Z"})
+	}
+	if err := WriteCSV(filePath, records); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewCSVBibliographyRepository(filePath)
+
+	// A dry run reports but does not write
+	before, _ := os.ReadFile(filePath)
+	migrations, err := repo.NormalizeISBNs(true, false)
+	if err != nil {
+		t.Fatalf("NormalizeISBNs() error = %v", err)
+	}
+	checkISBNMigrations(t, migrations, false)
+	if after, _ := os.ReadFile(filePath); string(after) != string(before) {
+		t.Error("Expected dry run to leave the file unchanged")
+	}
+
+	// Invalid ISBNs are kept as stored, and their bibliographies still load
+	if _, err := repo.NormalizeISBNs(false, false); err != nil {
+		t.Fatal(err)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("Expected 5 bibliographies, got %d", len(all))
+	}
+	if all[0].ISBN.Compact() != "9784750356884" || all[1].ISBN.Compact() != "9780306406157" {
+		t.Errorf("Expected normalized ISBNs, got %s and %s", all[0].ISBN.Compact(), all[1].ISBN.Compact())
+	}
+	if all[3].ISBN.Valid() || all[3].ISBN.Compact() != legacyISBNs["B4"] {
+		t.Errorf("Expected the invalid ISBN of B4 to be kept as stored, got %q", all[3].ISBN.Compact())
+	}
+
+	// Clearing removes the invalid ISBN; nothing else is left to change
+	migrations, err = repo.NormalizeISBNs(false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 1 || !migrations[0].Cleared {
+		t.Fatalf("Expected only B4 to be cleared, got %+v", migrations)
+	}
+	if all, _ := repo.FindAll(0, 0); len(all) != 5 || !all[3].ISBN.IsZero() {
+		t.Errorf("Expected the ISBN of B4 to be cleared, got %+v", all)
+	}
+}
+
+func TestCSVBibliographyRepository_Contributors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bibliographies.csv")
+	// A file written before contributors were tracked has no Contributors column
+	legacy := "ID,BibIndex,Code,Type,Title,Author,Publisher,ISBN,PublishedDate\n" +
+		"6f1c1a5e-2a76-4d59-a6a4-1f8c4a3f4b11,B10MS23WP,B10,Book,哲学史入門,マシュー スチュワート(稲岡大志訳),,,2023-01-01T00:00:00Z\n"
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewCSVBibliographyRepository(path)
+
+	found, err := repo.FindByBibIndex("B10MS23WP")
+	if err != nil || found == nil {
+		t.Fatalf("FindByBibIndex() = %v, %v", found, err)
+	}
+	want := []domain.Contributor{
+		{Name: "マシュー スチュワート", Role: domain.RoleAuthor},
+		{Name: "稲岡大志", Role: domain.RoleTranslator},
+	}
+	if !reflect.DeepEqual(found.Contributors, want) {
+		t.Errorf("Contributors derived from the author = %+v, want %+v", found.Contributors, want)
+	}
+
+	found.Contributors[1].NameEn = "Taishi Inaoka"
+	if err := repo.Save(found); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	records, err := ReadCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records[0]) != len(bibliographyHeader) || len(records[1]) != len(bibliographyHeader) {
+		t.Errorf("Expected the file to be rewritten with %d columns, got %v", len(bibliographyHeader), records)
+	}
+	reloaded, err := repo.FindByID(found.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.Contributors, found.Contributors) {
+		t.Errorf("Contributors after round trip = %+v, want %+v", reloaded.Contributors, found.Contributors)
+	}
+}
+
+func testBibliographySaveAll(t *testing.T, repo domain.BibliographyRepository) {
+	t.Helper()
+	newBib := func(index string) *domain.Bibliography {
+		return &domain.Bibliography{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      index,
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Title " + index,
+			Author:        "Author",
+			PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	first, second := newBib("B56A"), newBib("B56B")
+	if err := repo.SaveAll([]*domain.Bibliography{first, second}); err != nil {
+		t.Fatalf("SaveAll() error = %v", err)
+	}
+
+	// Existing bibliographies are updated in place, new ones appended
+	moved := *first
+	moved.Code, moved.BibIndex = "B548", "B548A"
+	third := newBib("B56C")
+	if err := repo.SaveAll([]*domain.Bibliography{third, &moved}); err != nil {
+		t.Fatalf("SaveAll() update error = %v", err)
+	}
+
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	var indexes []string
+	for _, bib := range all {
+		indexes = append(indexes, bib.Code+":"+bib.BibIndex)
+	}
+	if want := []string{"B548:B548A", "B56:B56B", "B56:B56C"}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("FindAll() = %v, want %v", indexes, want)
+	}
+}
+
+func TestCSVBibliographyRepository_SaveAll(t *testing.T) {
+	testBibliographySaveAll(t, NewCSVBibliographyRepository(filepath.Join(t.TempDir(), "bibliographies.csv")))
+}
+
+func TestSQLiteBibliographyRepository_SaveAll(t *testing.T) {
+	testBibliographySaveAll(t, NewSQLiteBibliographyRepository(newTestSQLiteDB(t)))
+}
+
+func testBibliographyUniqueBibIndex(t *testing.T, repo domain.BibliographyRepository) {
+	t.Helper()
+	newBib := func(index string) *domain.Bibliography {
+		return &domain.Bibliography{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      index,
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Title",
+			Author:        "Author",
+			PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	first := newBib("B56A")
+	if err := repo.Save(first); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Saving the same bibliography again keeps its BibIndex
+	if err := repo.Save(first); err != nil {
+		t.Fatalf("Save() of the same bibliography error = %v", err)
+	}
+
+	if err := repo.Save(newBib("B56A")); !errors.Is(err, domain.ErrInvalid) {
+		t.Errorf("Save() of a taken BibIndex error = %v, want ErrInvalid", err)
+	}
+	if err := repo.SaveAll([]*domain.Bibliography{newBib("B56B"), newBib("B56B")}); !errors.Is(err, domain.ErrInvalid) {
+		t.Errorf("SaveAll() with a repeated BibIndex error = %v, want ErrInvalid", err)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Errorf("Expected only the first bibliography to be stored, got %d", len(all))
+	}
+}
+
+func TestCSVBibliographyRepository_UniqueBibIndex(t *testing.T) {
+	testBibliographyUniqueBibIndex(t, NewCSVBibliographyRepository(filepath.Join(t.TempDir(), "bibliographies.csv")))
+}
+
+func TestSQLiteBibliographyRepository_UniqueBibIndex(t *testing.T) {
+	testBibliographyUniqueBibIndex(t, NewSQLiteBibliographyRepository(newTestSQLiteDB(t)))
+}
+
+func TestCSVBibliographyRepository_KeepsRowsItCannotValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bibliographies.csv")
+	legacy := "ID,BibIndex,Code,Type,Title,Author,Publisher,ISBN,PublishedDate\n" +
+		"d52ee692-f8ee-49fb-ad30-4e3da68059a8,B56OLD,B56,Book,Old,Someone,,978-0000000001,2024-01-01T00:00:00Z\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewCSVBibliographyRepository(path)
+	added := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "B56NEW", Code: "B56", Type: "Book", Title: "New", Author: "Someone", PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := repo.Save(added); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The invalid ISBN is written back as it was, for migrate-isbn to deal with
+	if len(all) != 2 || all[0].ISBN.Valid() || all[0].ISBN.Compact() != "978-0000000001" {
+		t.Fatalf("Expected the legacy bibliography to keep its ISBN, got %+v", all)
+	}
+
+	// A row that cannot be read at all stops writes instead of being dropped
+	broken := legacy + "not-a-uuid,B56BAD,B56,Book,Bad,Someone,,,2024-01-01T00:00:00Z\n"
+	if err := os.WriteFile(path, []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(added); err == nil {
+		t.Error("Save() with an unreadable row succeeded, want error")
+	}
+	if err := repo.Delete(added.ID); err == nil {
+		t.Error("Delete() with an unreadable row succeeded, want error")
+	}
+	if after, _ := os.ReadFile(path); string(after) != broken {
+		t.Errorf("Expected the file to be left unchanged, got:\n%s", after)
+	}
+}
+
+func TestCSVBibliographyRepository_UndecodableContributors(t *testing.T) {
+	tests := []struct {
+		name string
+		cell string
+		line string // the credit line the contributors are read from
+	}{
+		{"broken JSON", `[{"name":`, "稲岡大志 (translator); マシュー スチュワート"},
+		{"unknown role", `[{"name":"稲岡大志","role":"narrator"}]`, "稲岡大志 (translator); マシュー スチュワート"},
+		{"credit line", "Jane Doe (editor); John Roe", "Jane Doe (editor); John Roe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bibliographies.csv")
+			records := [][]string{
+				bibliographyHeader,
+				{"6f1c1a5e-2a76-4d59-a6a4-1f8c4a3f4b11", "B10MS23WP", "B10", "Book", "哲学史入門", "稲岡大志 (translator); マシュー スチュワート", "", "", "2023-01-01T00:00:00Z", tt.cell},
+			}
+			if err := WriteCSV(path, records); err != nil {
+				t.Fatal(err)
+			}
+			repo := NewCSVBibliographyRepository(path)
+
+			found, err := repo.FindByBibIndex("B10MS23WP")
+			if err != nil || found == nil {
+				t.Fatalf("FindByBibIndex() = %v, %v", found, err)
+			}
+			if want := domain.ParseCreditLine(tt.line); !reflect.DeepEqual(found.Contributors, want) {
+				t.Errorf("Contributors = %+v, want %+v", found.Contributors, want)
+			}
+
+			// Writing the file keeps the bibliography
+			other := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "B56NEW", Code: "B56", Type: "Book", Title: "New", Author: "Someone", PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			if err := repo.Save(other); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			if all, _ := repo.FindAll(0, 0); len(all) != 2 {
+				t.Errorf("Expected 2 bibliographies after saving another, got %d", len(all))
+			}
+		})
+	}
+}
@@ -4,14 +4,26 @@ import (
 	"bibliography_log/internal/domain"
 	"fmt"
 	"log/slog"
-	"strconv"
 )
 
 // ClassificationRecord represents a classification record for CSV persistence.
 type ClassificationRecord struct {
-	ID      string
-	CodeNum string
-	Name    string
+	ID   string
+	Code string
+	Name string
+}
+
+// classificationHeader is the header row of the classifications CSV file. Files written
+// before decimal codes were supported call the code column "CodeNum"; the values are
+// the same.
+var classificationHeader = []string{"ID", "Code", "Name"}
+
+func (rec *ClassificationRecord) fields() []string {
+	return []string{rec.ID, rec.Code, rec.Name}
+}
+
+func classificationRecordFromFields(record []string) *ClassificationRecord {
+	return &ClassificationRecord{ID: record[0], Code: record[1], Name: record[2]}
 }
 
 // recordToClassification converts a ClassificationRecord to a domain.Classification.
@@ -21,24 +33,24 @@ func recordToClassification(rec *ClassificationRecord) (*domain.Classification,
 		return nil, fmt.Errorf("failed to parse classification ID: %w", err)
 	}
 
-	codeNum, err := strconv.Atoi(rec.CodeNum)
+	code, err := domain.ParseClassCode(rec.Code)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse code number: %w", err)
+		return nil, fmt.Errorf("failed to parse classification code: %w", err)
 	}
 
 	return &domain.Classification{
-		ID:      id,
-		CodeNum: codeNum,
-		Name:    rec.Name,
+		ID:   id,
+		Code: code,
+		Name: rec.Name,
 	}, nil
 }
 
 // classificationToRecord converts a domain.Classification to a ClassificationRecord.
 func classificationToRecord(class *domain.Classification) *ClassificationRecord {
 	return &ClassificationRecord{
-		ID:      class.ID.String(),
-		CodeNum: strconv.Itoa(class.CodeNum),
-		Name:    class.Name,
+		ID:   class.ID.String(),
+		Code: class.Code.String(),
+		Name: class.Name,
 	}
 }
 
@@ -52,10 +64,13 @@ func NewCSVClassificationRepository(filePath string) *CSVClassificationRepositor
 }
 
 // Save implements domain.ClassificationRepository.Save
-// Potential race condition: This method reads all records, modifies them, and writes them back
-// without any locking mechanism. Acceptable for single-user CLI usage, but consider file locking
-// or using a database with proper transaction support for production use.
 func (r *CSVClassificationRepository) Save(c *domain.Classification) error {
+	return withFileLock(r.FilePath, func() error {
+		return r.save(c)
+	})
+}
+
+func (r *CSVClassificationRepository) save(c *domain.Classification) error {
 	records, err := ReadCSV(r.FilePath)
 	if err != nil {
 		return err
@@ -74,12 +89,7 @@ func (r *CSVClassificationRepository) Save(c *domain.Classification) error {
 		if len(record) < 3 {
 			continue
 		}
-		classRecord := &ClassificationRecord{
-			ID:      record[0],
-			CodeNum: record[1],
-			Name:    record[2],
-		}
-		class, err := recordToClassification(classRecord)
+		class, err := recordToClassification(classificationRecordFromFields(record))
 		if err != nil {
 			slog.Error("Failed to convert classification record", "err", err)
 			continue
@@ -126,13 +136,7 @@ func (r *CSVClassificationRepository) FindAll(limit, offset int) ([]*domain.Clas
 			continue
 		}
 
-		classRecord := &ClassificationRecord{
-			ID:      record[0],
-			CodeNum: record[1],
-			Name:    record[2],
-		}
-
-		class, err := recordToClassification(classRecord)
+		class, err := recordToClassification(classificationRecordFromFields(record))
 		if err != nil {
 			slog.Error("Failed to convert classification record", "err", err)
 			continue
@@ -144,7 +148,7 @@ func (r *CSVClassificationRepository) FindAll(limit, offset int) ([]*domain.Clas
 	return classifications, iter.Err()
 }
 
-func (r *CSVClassificationRepository) FindByCodeNum(codeNum int) (*domain.Classification, error) {
+func (r *CSVClassificationRepository) FindByCode(code domain.ClassCode) (*domain.Classification, error) {
 	records, err := ReadCSV(r.FilePath)
 	if err != nil {
 		return nil, err
@@ -156,39 +160,46 @@ func (r *CSVClassificationRepository) FindByCodeNum(codeNum int) (*domain.Classi
 	}
 
 	iter := NewCSVRecordIterator(records, 0, 0)
-	codeNumStr := strconv.Itoa(codeNum)
 
 	for iter.Next() {
 		record := iter.Record()
 		if len(record) < 3 {
 			continue
 		}
-		// Optimization: Check CodeNum (index 1) before full conversion
-		if record[1] == codeNumStr {
-			classRecord := &ClassificationRecord{
-				ID:      record[0],
-				CodeNum: record[1],
-				Name:    record[2],
-			}
-			return recordToClassification(classRecord)
+		// Optimization: Check Code (index 1) before full conversion
+		if record[1] == code.String() {
+			return recordToClassification(classificationRecordFromFields(record))
 		}
 	}
 
 	return nil, iter.Err()
 }
 
-func (r *CSVClassificationRepository) writeAll(classifications []*domain.Classification) error {
-	var records [][]string
-	records = append(records, []string{"ID", "CodeNum", "Name"})
+// Delete implements domain.ClassificationRepository.Delete
+func (r *CSVClassificationRepository) Delete(id domain.ClassificationID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.FindAll(0, 0)
+		if err != nil {
+			return err
+		}
 
-	for _, c := range classifications {
-		rec := classificationToRecord(c)
-		record := []string{
-			rec.ID,
-			rec.CodeNum,
-			rec.Name,
+		kept := all[:0]
+		for _, existing := range all {
+			if existing.ID != id {
+				kept = append(kept, existing)
+			}
 		}
-		records = append(records, record)
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+func (r *CSVClassificationRepository) writeAll(classifications []*domain.Classification) error {
+	records := [][]string{classificationHeader}
+	for _, c := range classifications {
+		records = append(records, classificationToRecord(c).fields())
 	}
 	return WriteCSV(r.FilePath, records)
 }
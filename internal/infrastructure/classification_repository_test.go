@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"testing"
+)
+
+func testClassificationRepository(t *testing.T, repo domain.ClassificationRepository) {
+	t.Helper()
+	classes := []*domain.Classification{
+		{ID: domain.NewClassificationID(), Code: "5", Name: "技術"},
+		{ID: domain.NewClassificationID(), Code: "56", Name: "Technology"},
+		{ID: domain.NewClassificationID(), Code: "547.48", Name: "情報通信"},
+	}
+	for _, class := range classes {
+		if err := repo.Save(class); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// Renumbering and renaming keep the ID
+	renumbered := *classes[1]
+	renumbered.Code, renumbered.Name = "548", "情報工学"
+	if err := repo.Save(&renumbered); err != nil {
+		t.Fatalf("Save() update error = %v", err)
+	}
+	if old, _ := repo.FindByCode("56"); old != nil {
+		t.Errorf("Expected the old code to be gone, got %v", old)
+	}
+	if got, err := repo.FindByCode("548"); err != nil || got == nil || got.ID != classes[1].ID || got.Name != "情報工学" {
+		t.Errorf("FindByCode(548) = %v, %v", got, err)
+	}
+
+	if err := repo.Delete(classes[0].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	// Deleting a missing ID is a no-op
+	if err := repo.Delete(domain.NewClassificationID()); err != nil {
+		t.Fatalf("Expected no error deleting missing ID, got %v", err)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(all) != 2 || all[0].Code != "548" || all[1].Code != "547.48" {
+		t.Errorf("Expected 548 and 547.48 to remain in insertion order, got %v", all)
+	}
+}
+
+func TestCSVClassificationRepository(t *testing.T) {
+	testClassificationRepository(t, NewCSVClassificationRepository(filepath.Join(t.TempDir(), "classifications.csv")))
+}
+
+func TestSQLiteClassificationRepository(t *testing.T) {
+	testClassificationRepository(t, NewSQLiteClassificationRepository(newTestSQLiteDB(t)))
+}
@@ -2,8 +2,11 @@ package infrastructure
 
 import (
 	"encoding/csv"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 // ReadCSV reads all records from a CSV file.
@@ -27,23 +30,87 @@ func ReadCSV(filePath string) ([][]string, error) {
 }
 
 // WriteCSV writes records to a CSV file.
-// It overwrites the file.
+// It replaces the file atomically: records are written and fsynced to a temporary
+// file in the same directory, which is then renamed over the target. A crash or
+// interrupt mid-write leaves the previous file intact.
 func WriteCSV(filePath string, records [][]string) error {
-	file, err := os.Create(filePath)
+	return writeFileAtomic(filePath, func(w io.Writer) error {
+		return csv.NewWriter(w).WriteAll(records)
+	})
+}
+
+// writeFileAtomic replaces filePath with the output of write, using a fsynced
+// temporary file that is renamed over the target.
+func writeFileAtomic(filePath string, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(filePath)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
 	if err != nil {
 		return err
 	}
+	tmpPath := tmp.Name()
 	defer func() {
-		if err := file.Close(); err != nil {
-			log.Printf("Failed to close file: %v", err)
+		if err != nil {
+			if rmErr := os.Remove(tmpPath); rmErr != nil && !os.IsNotExist(rmErr) {
+				log.Printf("Failed to remove temp file: %v", rmErr)
+			}
 		}
 	}()
 
-	writer := csv.NewWriter(file)
-
-	if err := writer.WriteAll(records); err != nil {
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(fileModeFor(filePath)); err != nil {
+		_ = tmp.Close()
 		return err
 	}
-	writer.Flush()
-	return writer.Error()
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filePath, err)
+	}
+	return syncDir(dir)
+}
+
+// fileModeFor returns the permissions of the existing file, or 0o644 for a new one.
+func fileModeFor(filePath string) os.FileMode {
+	if info, err := os.Stat(filePath); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0o644
+}
+
+// withFileLock runs fn while holding an exclusive advisory lock on a sidecar
+// "<filePath>.lock" file, so read-modify-write cycles from separate biblog
+// processes are serialized. The CSV repositories both read and rewrite their
+// file inside fn, so concurrent processes (e.g. a cron import and an
+// interactive add) cannot lose each other's updates.
+// The lock is separate from the data file because writes replace that file.
+func withFileLock(filePath string, fn func() error) error {
+	lockFile, err := os.OpenFile(filePath+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	defer func() {
+		if err := lockFile.Close(); err != nil {
+			log.Printf("Failed to close lock file: %v", err)
+		}
+	}()
+
+	if err := lockExclusive(lockFile); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", filePath, err)
+	}
+	defer func() {
+		if err := unlock(lockFile); err != nil {
+			log.Printf("Failed to unlock file: %v", err)
+		}
+	}()
+
+	return fn()
 }
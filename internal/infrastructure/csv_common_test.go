@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV_ReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.csv")
+
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	records := [][]string{{"ID", "Name"}, {"1", "複数行\nテキスト"}}
+	if err := WriteCSV(path, records); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	got, err := ReadCSV(path)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(got) != 2 || got[1][1] != "複数行\nテキスト" {
+		t.Errorf("Unexpected records: %v", got)
+	}
+
+	// Existing permissions are kept
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the target file in %s, got %d entries", dir, len(entries))
+	}
+}
+
+func TestCSVBibliographyRepository_ConcurrentSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bibliographies.csv")
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate repository instances model separate processes.
+			repo := NewCSVBibliographyRepository(path)
+			errs <- repo.Save(&domain.Bibliography{
+				ID:            domain.NewBibliographyID(),
+				BibIndex:      fmt.Sprintf("B56X%d", i),
+				Code:          "B56",
+				Type:          "Book",
+				Title:         "Title",
+				Author:        "Author",
+				PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	all, err := NewCSVBibliographyRepository(path).FindAll(0, 0)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(all) != n {
+		t.Errorf("Expected %d bibliographies, got %d (lost updates)", n, len(all))
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package infrastructure
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockExclusive blocks until an exclusive lock is held on f.
+func lockExclusive(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, ol)
+}
+
+// unlock releases the lock held on f.
+func unlock(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
+
+// syncDir is a no-op on Windows, where directories cannot be opened for fsync.
+func syncDir(_ string) error {
+	return nil
+}
@@ -0,0 +1,205 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// HighlightRecord represents a highlight record for CSV persistence.
+type HighlightRecord struct {
+	ID        string
+	BookID    string
+	Text      string
+	Location  string
+	Comment   string
+	Tags      string // comma-separated
+	CreatedAt string
+}
+
+// highlightHeader is the header row of the highlight CSV.
+var highlightHeader = []string{"ID", "BookID", "Text", "Location", "Comment", "Tags", "CreatedAt"}
+
+// recordToHighlight converts a HighlightRecord to a domain.Highlight.
+func recordToHighlight(rec *HighlightRecord) (*domain.Highlight, error) {
+	id, err := domain.ParseHighlightID(rec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookID, err := domain.ParseBibliographyID(rec.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse book ID: %w", err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, rec.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	var tags []string
+	if rec.Tags != "" {
+		tags = strings.Split(rec.Tags, ",")
+	}
+
+	return &domain.Highlight{
+		ID:        id,
+		BookID:    bookID,
+		Text:      rec.Text,
+		Location:  rec.Location,
+		Comment:   rec.Comment,
+		Tags:      tags,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// highlightToRecord converts a domain.Highlight to a HighlightRecord.
+func highlightToRecord(h *domain.Highlight) *HighlightRecord {
+	return &HighlightRecord{
+		ID:        h.ID.String(),
+		BookID:    h.BookID.String(),
+		Text:      h.Text,
+		Location:  h.Location,
+		Comment:   h.Comment,
+		Tags:      strings.Join(h.Tags, ","),
+		CreatedAt: h.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (rec *HighlightRecord) fields() []string {
+	return []string{rec.ID, rec.BookID, rec.Text, rec.Location, rec.Comment, rec.Tags, rec.CreatedAt}
+}
+
+func highlightRecordFromFields(record []string) *HighlightRecord {
+	return &HighlightRecord{
+		ID:        record[0],
+		BookID:    record[1],
+		Text:      record[2],
+		Location:  record[3],
+		Comment:   record[4],
+		Tags:      record[5],
+		CreatedAt: record[6],
+	}
+}
+
+// CSVHighlightRepository implements domain.HighlightRepository using a CSV file.
+type CSVHighlightRepository struct {
+	FilePath string
+}
+
+func NewCSVHighlightRepository(filePath string) *CSVHighlightRepository {
+	return &CSVHighlightRepository{FilePath: filePath}
+}
+
+// Save implements domain.HighlightRepository.Save
+func (r *CSVHighlightRepository) Save(highlight *domain.Highlight) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		updated := false
+		for i, existing := range all {
+			if existing.ID == highlight.ID {
+				all[i] = highlight
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			all = append(all, highlight)
+		}
+		return r.writeAll(all)
+	})
+}
+
+func (r *CSVHighlightRepository) FindAll(limit, offset int) ([]*domain.Highlight, error) {
+	return r.find(limit, offset, func([]string) bool { return true })
+}
+
+// FindByID implements domain.HighlightRepository.FindByID
+func (r *CSVHighlightRepository) FindByID(id domain.HighlightID) (*domain.Highlight, error) {
+	idStr := id.String()
+	highlights, err := r.find(1, 0, func(record []string) bool { return record[0] == idStr })
+	if err != nil || len(highlights) == 0 {
+		return nil, err
+	}
+	return highlights[0], nil
+}
+
+// FindByBookID implements domain.HighlightRepository.FindByBookID
+func (r *CSVHighlightRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.Highlight, error) {
+	bookIDStr := bookID.String()
+	return r.find(0, 0, func(record []string) bool { return record[1] == bookIDStr })
+}
+
+// Delete implements domain.HighlightRepository.Delete
+func (r *CSVHighlightRepository) Delete(id domain.HighlightID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, existing := range all {
+			if existing.ID != id {
+				kept = append(kept, existing)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+func (r *CSVHighlightRepository) writeAll(highlights []*domain.Highlight) error {
+	records := [][]string{highlightHeader}
+	for _, highlight := range highlights {
+		records = append(records, highlightToRecord(highlight).fields())
+	}
+	return WriteCSV(r.FilePath, records)
+}
+
+// loadAll reads every highlight in file order. Callers writing the result back must hold the file lock.
+func (r *CSVHighlightRepository) loadAll() ([]*domain.Highlight, error) {
+	return r.find(0, 0, func([]string) bool { return true })
+}
+
+// find returns the highlights whose raw records match, checking the record before full
+// conversion. limit and offset apply to the matching highlights.
+func (r *CSVHighlightRepository) find(limit, offset int, match func(record []string) bool) ([]*domain.Highlight, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	var matching [][]string
+	for _, record := range records {
+		if len(record) >= len(highlightHeader) && match(record) {
+			matching = append(matching, record)
+		}
+	}
+
+	iter := NewCSVRecordIterator(matching, limit, offset)
+	var highlights []*domain.Highlight
+	for iter.Next() {
+		highlight, err := recordToHighlight(highlightRecordFromFields(iter.Record()))
+		if err != nil {
+			slog.Error("Failed to convert highlight record", "err", err)
+			continue
+		}
+		highlights = append(highlights, highlight)
+	}
+
+	return highlights, iter.Err()
+}
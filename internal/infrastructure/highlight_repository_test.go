@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testHighlightRepository(t *testing.T, repo domain.HighlightRepository, bookA, bookB domain.BibliographyID) {
+	t.Helper()
+	at := func(d int) time.Time { return time.Date(2025, 11, d, 9, 0, 0, 0, time.UTC) }
+	highlights := []*domain.Highlight{
+		{ID: domain.NewHighlightID(), BookID: bookA, Text: "  A model is a selectively simplified\n\nand consciously structured form of knowledge.  ", Location: "p. 3", Comment: "Definition\n of a model", Tags: []string{"model", "DDD"}, CreatedAt: at(1)},
+		{ID: domain.NewHighlightID(), BookID: bookB, Text: "境界づけられたコンテキスト", CreatedAt: at(2)},
+		{ID: domain.NewHighlightID(), BookID: bookA, Text: "Ubiquitous language", Location: "loc. 1234-1240", CreatedAt: at(3)},
+	}
+	for _, highlight := range highlights {
+		if err := repo.Save(highlight); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByBookID(bookA)
+	if err != nil {
+		t.Fatalf("FindByBookID() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 highlights, got %d", len(found))
+	}
+	// Whitespace and line breaks are preserved exactly
+	if !reflect.DeepEqual(found[0], highlights[0]) || !reflect.DeepEqual(found[1], highlights[2]) {
+		t.Errorf("Unexpected highlights %+v, %+v", *found[0], *found[1])
+	}
+
+	highlights[1].Comment = "Bounded context"
+	if err := repo.Save(highlights[1]); err != nil {
+		t.Fatalf("Save() update error = %v", err)
+	}
+	got, err := repo.FindByID(highlights[1].ID)
+	if err != nil || got == nil || got.Comment != "Bounded context" {
+		t.Fatalf("FindByID() = %+v, %v; want the updated comment", got, err)
+	}
+	if all, _ := repo.FindAll(0, 0); len(all) != 3 {
+		t.Errorf("Expected 3 highlights after update, got %d", len(all))
+	}
+	if page, _ := repo.FindAll(1, 2); len(page) != 1 || page[0].ID != highlights[2].ID {
+		t.Errorf("FindAll(1, 2) returned unexpected page %v", page)
+	}
+
+	if err := repo.Delete(highlights[0].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, err := repo.FindByID(highlights[0].ID); err != nil || got != nil {
+		t.Errorf("FindByID() after delete = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestCSVHighlightRepository(t *testing.T) {
+	repo := NewCSVHighlightRepository(filepath.Join(t.TempDir(), "highlights.csv"))
+	testHighlightRepository(t, repo, domain.NewBibliographyID(), domain.NewBibliographyID())
+}
+
+func TestSQLiteHighlightRepository(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	var ids []domain.BibliographyID
+	for _, title := range []string{"A", "B"} {
+		bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: title, Code: "B56", Type: "Book", Title: title, Author: "X", PublishedDate: time.Now()}
+		if err := bibRepo.Save(bib); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, bib.ID)
+	}
+	testHighlightRepository(t, NewSQLiteHighlightRepository(db), ids[0], ids[1])
+
+	orphan := &domain.Highlight{ID: domain.NewHighlightID(), BookID: domain.NewBibliographyID(), Text: "x", CreatedAt: time.Now()}
+	if err := NewSQLiteHighlightRepository(db).Save(orphan); err == nil {
+		t.Error("Expected foreign key error for unknown bibliography, got nil")
+	}
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// defaultMetadataTimeout bounds each request to a metadata catalogue.
+const defaultMetadataTimeout = 10 * time.Second
+
+// MetadataCache keeps catalogue responses on disk, one file per request URL.
+// Cached responses are served without contacting the catalogue, so a book that has
+// been found once can be looked up again offline. Delete the directory to refresh.
+type MetadataCache struct {
+	Dir string
+}
+
+// NewMetadataCache creates a MetadataCache stored in dir.
+func NewMetadataCache(dir string) *MetadataCache {
+	return &MetadataCache{Dir: dir}
+}
+
+func (c *MetadataCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached response for url. A nil cache never hits.
+func (c *MetadataCache) get(url string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	body, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// put stores the response for url. A nil cache stores nothing.
+func (c *MetadataCache) put(url string, body []byte) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create metadata cache: %w", err)
+	}
+	return writeFileAtomic(c.path(url), func(w io.Writer) error {
+		_, err := w.Write(body)
+		return err
+	})
+}
+
+// maxMetadataResponseSize bounds the catalogue responses read into memory.
+const maxMetadataResponseSize = 1 << 20
+
+// fetchMetadata looks up a book with a GET request to url, answering from the cache if possible.
+// decode converts a response into metadata, or nil if the catalogue has no entry for the book.
+// nil is also returned if the catalogue answered 404. Only responses with an entry are cached,
+// so a book the catalogue does not know yet is looked up again next time; failing to write the
+// cache does not fail the request.
+func fetchMetadata(client *http.Client, cache *MetadataCache, url string, decode func(body []byte) (*domain.BookMetadata, error)) (*domain.BookMetadata, error) {
+	if body, ok := cache.get(url); ok {
+		// Responses cached before empty answers were skipped are looked up again
+		if meta, err := decode(body); err == nil && meta != nil {
+			return meta, nil
+		}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: defaultMetadataTimeout}
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "biblog")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+	if len(body) > maxMetadataResponseSize {
+		return nil, fmt.Errorf("response from %s is larger than %d bytes", url, maxMetadataResponseSize)
+	}
+	meta, err := decode(body)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	if err := cache.put(url, body); err != nil {
+		log.Printf("Failed to cache metadata response: %v", err)
+	}
+	return meta, nil
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// parseYear extracts the year from catalogue dates such as "20240115", "2003-08-30"
+// or "August 30, 2003". It returns 0 if there is none.
+func parseYear(date string) int {
+	year, err := strconv.Atoi(yearPattern.FindString(date))
+	if err != nil {
+		return 0
+	}
+	return year
+}
@@ -0,0 +1,244 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Default base URLs of the supported catalogues.
+const (
+	DefaultOpenBDURL      = "https://api.openbd.jp/v1"
+	DefaultGoogleBooksURL = "https://www.googleapis.com/books/v1"
+	DefaultOpenLibraryURL = "https://openlibrary.org"
+)
+
+// Names of the supported catalogues.
+const (
+	MetadataProviderOpenBD      = "openbd"
+	MetadataProviderGoogleBooks = "googlebooks"
+	MetadataProviderOpenLibrary = "openlibrary"
+)
+
+// NewMetadataProvider creates the provider called name (one of the MetadataProvider*
+// constants). An empty baseURL selects the catalogue's public API.
+func NewMetadataProvider(name, baseURL string, cache *MetadataCache) (domain.MetadataProvider, error) {
+	switch name {
+	case MetadataProviderOpenBD:
+		return NewOpenBDProvider(baseURL, cache), nil
+	case MetadataProviderGoogleBooks:
+		return NewGoogleBooksProvider(baseURL, cache), nil
+	case MetadataProviderOpenLibrary:
+		return NewOpenLibraryProvider(baseURL, cache), nil
+	default:
+		return nil, fmt.Errorf("unknown metadata provider %q (expected %s, %s or %s)",
+			name, MetadataProviderOpenBD, MetadataProviderGoogleBooks, MetadataProviderOpenLibrary)
+	}
+}
+
+// OpenBDProvider looks up books in openBD (https://openbd.jp), which covers books
+// published in Japan.
+type OpenBDProvider struct {
+	BaseURL string
+	Client  *http.Client // nil for a client with a default timeout
+	Cache   *MetadataCache
+}
+
+// NewOpenBDProvider creates an OpenBDProvider. An empty baseURL selects DefaultOpenBDURL.
+func NewOpenBDProvider(baseURL string, cache *MetadataCache) *OpenBDProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenBDURL
+	}
+	return &OpenBDProvider{BaseURL: strings.TrimSuffix(baseURL, "/"), Cache: cache}
+}
+
+func (p *OpenBDProvider) Name() string {
+	return MetadataProviderOpenBD
+}
+
+// openBDBook is an element of the openBD /get response; unknown ISBNs are null.
+type openBDBook struct {
+	Summary struct {
+		Title     string `json:"title"`
+		Volume    string `json:"volume"`
+		Publisher string `json:"publisher"`
+		PubDate   string `json:"pubdate"`
+		Author    string `json:"author"`
+	} `json:"summary"`
+}
+
+func (p *OpenBDProvider) LookupISBN(isbn domain.ISBN) (*domain.BookMetadata, error) {
+	endpoint := p.BaseURL + "/get?isbn=" + url.QueryEscape(isbn.Compact())
+	return fetchMetadata(p.Client, p.Cache, endpoint, func(body []byte) (*domain.BookMetadata, error) {
+		var books []*openBDBook
+		if err := json.Unmarshal(body, &books); err != nil {
+			return nil, fmt.Errorf("invalid openBD response: %w", err)
+		}
+		if len(books) == 0 || books[0] == nil || books[0].Summary.Title == "" {
+			return nil, nil
+		}
+		s := books[0].Summary
+		title := s.Title
+		if s.Volume != "" {
+			title += " " + s.Volume
+		}
+		return &domain.BookMetadata{
+			ISBN:      isbn,
+			Title:     title,
+			Author:    openBDAuthor(s.Author),
+			Publisher: s.Publisher,
+			Year:      parseYear(s.PubDate),
+			Source:    p.Name(),
+		}, nil
+	})
+}
+
+// openBDCredit matches one "name／role" credit of an openBD author string.
+var openBDCredit = regexp.MustCompile(`\s*([^／]+?)／(\S+)`)
+
+// openBDAuthor converts openBD credits such as "マシュー・スチュワート／著 稲岡大志／訳"
+// into the form used by biblog: "マシュー・スチュワート(稲岡大志訳)". Credits without
+// roles are returned unchanged.
+func openBDAuthor(credits string) string {
+	matches := openBDCredit.FindAllStringSubmatch(credits, -1)
+	if matches == nil {
+		return strings.TrimSpace(credits)
+	}
+	var authors, translators []string
+	for _, m := range matches {
+		name, role := strings.TrimSpace(m[1]), m[2]
+		if strings.HasSuffix(role, "訳") {
+			translators = append(translators, "("+name+role+")")
+		} else {
+			authors = append(authors, name)
+		}
+	}
+	return strings.Join(authors, ", ") + strings.Join(translators, "")
+}
+
+// GoogleBooksProvider looks up books in the Google Books API.
+type GoogleBooksProvider struct {
+	BaseURL string
+	Client  *http.Client // nil for a client with a default timeout
+	Cache   *MetadataCache
+}
+
+// NewGoogleBooksProvider creates a GoogleBooksProvider. An empty baseURL selects DefaultGoogleBooksURL.
+func NewGoogleBooksProvider(baseURL string, cache *MetadataCache) *GoogleBooksProvider {
+	if baseURL == "" {
+		baseURL = DefaultGoogleBooksURL
+	}
+	return &GoogleBooksProvider{BaseURL: strings.TrimSuffix(baseURL, "/"), Cache: cache}
+}
+
+func (p *GoogleBooksProvider) Name() string {
+	return MetadataProviderGoogleBooks
+}
+
+type googleBooksResponse struct {
+	Items []struct {
+		VolumeInfo struct {
+			Title         string   `json:"title"`
+			Subtitle      string   `json:"subtitle"`
+			Authors       []string `json:"authors"`
+			Publisher     string   `json:"publisher"`
+			PublishedDate string   `json:"publishedDate"`
+		} `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+func (p *GoogleBooksProvider) LookupISBN(isbn domain.ISBN) (*domain.BookMetadata, error) {
+	endpoint := p.BaseURL + "/volumes?q=" + url.QueryEscape("isbn:"+isbn.Compact())
+	return fetchMetadata(p.Client, p.Cache, endpoint, func(body []byte) (*domain.BookMetadata, error) {
+		var resp googleBooksResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("invalid Google Books response: %w", err)
+		}
+		if len(resp.Items) == 0 || resp.Items[0].VolumeInfo.Title == "" {
+			return nil, nil
+		}
+		v := resp.Items[0].VolumeInfo
+		return &domain.BookMetadata{
+			ISBN:      isbn,
+			Title:     joinSubtitle(v.Title, v.Subtitle),
+			Author:    strings.Join(v.Authors, ", "),
+			Publisher: v.Publisher,
+			Year:      parseYear(v.PublishedDate),
+			Source:    p.Name(),
+		}, nil
+	})
+}
+
+// OpenLibraryProvider looks up books in Open Library (https://openlibrary.org).
+type OpenLibraryProvider struct {
+	BaseURL string
+	Client  *http.Client // nil for a client with a default timeout
+	Cache   *MetadataCache
+}
+
+// NewOpenLibraryProvider creates an OpenLibraryProvider. An empty baseURL selects DefaultOpenLibraryURL.
+func NewOpenLibraryProvider(baseURL string, cache *MetadataCache) *OpenLibraryProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenLibraryURL
+	}
+	return &OpenLibraryProvider{BaseURL: strings.TrimSuffix(baseURL, "/"), Cache: cache}
+}
+
+func (p *OpenLibraryProvider) Name() string {
+	return MetadataProviderOpenLibrary
+}
+
+// openLibraryBook is a value of the Open Library books API response (jscmd=data),
+// which is keyed by "ISBN:<isbn>" and empty for unknown ISBNs.
+type openLibraryBook struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Authors  []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Publishers []struct {
+		Name string `json:"name"`
+	} `json:"publishers"`
+	PublishDate string `json:"publish_date"`
+}
+
+func (p *OpenLibraryProvider) LookupISBN(isbn domain.ISBN) (*domain.BookMetadata, error) {
+	key := "ISBN:" + isbn.Compact()
+	endpoint := p.BaseURL + "/api/books?format=json&jscmd=data&bibkeys=" + url.QueryEscape(key)
+	return fetchMetadata(p.Client, p.Cache, endpoint, func(body []byte) (*domain.BookMetadata, error) {
+		var resp map[string]openLibraryBook
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("invalid Open Library response: %w", err)
+		}
+		book, ok := resp[key]
+		if !ok || book.Title == "" {
+			return nil, nil
+		}
+		var authors []string
+		for _, a := range book.Authors {
+			authors = append(authors, a.Name)
+		}
+		meta := &domain.BookMetadata{
+			ISBN:   isbn,
+			Title:  joinSubtitle(book.Title, book.Subtitle),
+			Author: strings.Join(authors, ", "),
+			Year:   parseYear(book.PublishDate),
+			Source: p.Name(),
+		}
+		if len(book.Publishers) > 0 {
+			meta.Publisher = book.Publishers[0].Name
+		}
+		return meta, nil
+	})
+}
+
+func joinSubtitle(title, subtitle string) string {
+	if subtitle == "" {
+		return title
+	}
+	return title + ": " + subtitle
+}
@@ -0,0 +1,182 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newCatalogueServer serves canned responses by request path, counting the requests it receives.
+func newCatalogueServer(t *testing.T, responses map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestMetadataProviders(t *testing.T) {
+	isbn := domain.MustParseISBN("978-4-7503-5688-4")
+
+	tests := []struct {
+		name     string
+		path     string
+		response string
+		want     domain.BookMetadata
+	}{
+		{
+			name:     MetadataProviderOpenBD,
+			path:     "/get",
+			response: `[{"summary":{"isbn":"9784750356884","title":"ホモ・サピエンスの本性","volume":"","publisher":"明石書店","pubdate":"20230315","author":"マシュー・スチュワート／著 稲岡大志／訳"}}]`,
+			want:     domain.BookMetadata{Title: "ホモ・サピエンスの本性", Author: "マシュー・スチュワート(稲岡大志訳)", Publisher: "明石書店", Year: 2023},
+		},
+		{
+			name:     MetadataProviderGoogleBooks,
+			path:     "/volumes",
+			response: `{"totalItems":1,"items":[{"volumeInfo":{"title":"Domain-Driven Design","subtitle":"Tackling Complexity","authors":["Eric Evans"],"publisher":"Addison-Wesley","publishedDate":"2003-08-30"}}]}`,
+			want:     domain.BookMetadata{Title: "Domain-Driven Design: Tackling Complexity", Author: "Eric Evans", Publisher: "Addison-Wesley", Year: 2003},
+		},
+		{
+			name:     MetadataProviderOpenLibrary,
+			path:     "/api/books",
+			response: `{"ISBN:9784750356884":{"title":"Go in Practice","authors":[{"name":"Matt Butcher"},{"name":"Matt Farina"}],"publishers":[{"name":"Manning"}],"publish_date":"August 30, 2016"}}`,
+			want:     domain.BookMetadata{Title: "Go in Practice", Author: "Matt Butcher, Matt Farina", Publisher: "Manning", Year: 2016},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newCatalogueServer(t, map[string]string{tt.path: tt.response})
+			cache := NewMetadataCache(filepath.Join(t.TempDir(), "cache"))
+			provider, err := NewMetadataProvider(tt.name, server.URL+"/", cache)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := provider.LookupISBN(isbn)
+			if err != nil {
+				t.Fatalf("LookupISBN() error = %v", err)
+			}
+			if got == nil {
+				t.Fatal("LookupISBN() = nil, want metadata")
+			}
+			tt.want.ISBN, tt.want.Source = isbn, tt.name
+			if *got != tt.want {
+				t.Errorf("LookupISBN() = %+v, want %+v", *got, tt.want)
+			}
+
+			// The second lookup is answered from the cache, even with the catalogue down
+			server.Close()
+			again, err := provider.LookupISBN(isbn)
+			if err != nil || again == nil || *again != tt.want {
+				t.Errorf("cached LookupISBN() = %+v, %v", again, err)
+			}
+			if *requests != 1 {
+				t.Errorf("catalogue received %d requests, want 1", *requests)
+			}
+		})
+	}
+}
+
+func TestMetadataProviders_NotFound(t *testing.T) {
+	isbn := domain.MustParseISBN("978-0321125217")
+	server, _ := newCatalogueServer(t, map[string]string{
+		"/get":       `[null]`,
+		"/volumes":   `{"kind":"books#volumes","totalItems":0}`,
+		"/api/books": `{}`,
+	})
+	cache := NewMetadataCache(filepath.Join(t.TempDir(), "cache"))
+	for _, name := range []string{MetadataProviderOpenBD, MetadataProviderGoogleBooks, MetadataProviderOpenLibrary} {
+		provider, err := NewMetadataProvider(name, server.URL, cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := provider.LookupISBN(isbn)
+		if err != nil || got != nil {
+			t.Errorf("%s: LookupISBN() = %+v, %v; want nil, nil", name, got, err)
+		}
+	}
+	// Empty answers are not cached, so the book is looked up again once the catalogue has it
+	if entries, _ := os.ReadDir(cache.Dir); len(entries) != 0 {
+		t.Errorf("Expected empty answers not to be cached, got %d cache files", len(entries))
+	}
+
+	// A 404 is "not found" rather than an error
+	provider := NewOpenBDProvider(server.URL+"/missing", nil)
+	if got, err := provider.LookupISBN(isbn); err != nil || got != nil {
+		t.Errorf("LookupISBN() on 404 = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestMetadataProviders_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cache := NewMetadataCache(filepath.Join(t.TempDir(), "cache"))
+	provider := NewGoogleBooksProvider(server.URL, cache)
+	if _, err := provider.LookupISBN(domain.MustParseISBN("978-0321125217")); err == nil {
+		t.Fatal("Expected error for 503, got nil")
+	}
+	// Failed responses are not cached
+	if _, ok := cache.get(server.URL + "/volumes?q=isbn%3A9780321125217"); ok {
+		t.Error("Expected the failed response not to be cached")
+	}
+}
+
+func TestMetadataProviders_ResponseTooLarge(t *testing.T) {
+	server, _ := newCatalogueServer(t, map[string]string{
+		"/volumes": `{"totalItems":1,"items":[{"volumeInfo":{"title":"` + strings.Repeat("x", maxMetadataResponseSize) + `"}}]}`,
+	})
+	cache := NewMetadataCache(filepath.Join(t.TempDir(), "cache"))
+	provider := NewGoogleBooksProvider(server.URL, cache)
+	if _, err := provider.LookupISBN(domain.MustParseISBN("978-0321125217")); err == nil {
+		t.Fatal("Expected error for an oversized response, got nil")
+	}
+	if _, ok := cache.get(server.URL + "/volumes?q=isbn%3A9780321125217"); ok {
+		t.Error("Expected the oversized response not to be cached")
+	}
+}
+
+func TestNewMetadataProvider_Unknown(t *testing.T) {
+	if _, err := NewMetadataProvider("worldcat", "", nil); err == nil {
+		t.Error("Expected error for unknown provider, got nil")
+	}
+}
+
+func TestOpenBDAuthor(t *testing.T) {
+	tests := map[string]string{
+		"杉本啓／著": "杉本啓",
+		"Eric Evans／著 今関剛／監訳 和智右桂／訳": "Eric Evans(今関剛監訳)(和智右桂訳)",
+		"山田太郎／著 鈴木花子／著":              "山田太郎, 鈴木花子",
+		"著者不明":                       "著者不明",
+	}
+	for in, want := range tests {
+		if got := openBDAuthor(in); got != want {
+			t.Errorf("openBDAuthor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseYear(t *testing.T) {
+	tests := map[string]int{"20240115": 2024, "2003-08-30": 2003, "August 30, 2003": 2003, "c1999": 1999, "": 0, "n.d.": 0}
+	for in, want := range tests {
+		if got := parseYear(in); got != want {
+			t.Errorf("parseYear(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
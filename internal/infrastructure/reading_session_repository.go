@@ -0,0 +1,234 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// ReadingSessionRecord represents a reading session record for CSV persistence.
+type ReadingSessionRecord struct {
+	ID         string
+	BookID     string
+	Date       string // YYYY-MM-DD
+	Duration   string // e.g. "1h30m0s"
+	Unit       string
+	Start      string
+	End        string
+	TotalPages string
+	Note       string
+	CreatedAt  string
+}
+
+// readingSessionHeader is the header row of the reading session CSV.
+var readingSessionHeader = []string{"ID", "BookID", "Date", "Duration", "Unit", "Start", "End", "TotalPages", "Note", "CreatedAt"}
+
+// recordToReadingSession converts a ReadingSessionRecord to a domain.ReadingSession.
+func recordToReadingSession(rec *ReadingSessionRecord) (*domain.ReadingSession, error) {
+	id, err := domain.ParseReadingSessionID(rec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookID, err := domain.ParseBibliographyID(rec.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse book ID: %w", err)
+	}
+
+	date, err := time.Parse(time.DateOnly, rec.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date: %w", err)
+	}
+
+	duration, err := time.ParseDuration(rec.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %w", err)
+	}
+
+	unit, err := domain.ParseProgressUnit(rec.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions [3]int
+	for i, s := range []string{rec.Start, rec.End, rec.TotalPages} {
+		if positions[i], err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("failed to parse position %q: %w", s, err)
+		}
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, rec.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	return &domain.ReadingSession{
+		ID:         id,
+		BookID:     bookID,
+		Date:       date,
+		Duration:   duration,
+		Unit:       unit,
+		Start:      positions[0],
+		End:        positions[1],
+		TotalPages: positions[2],
+		Note:       rec.Note,
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// readingSessionToRecord converts a domain.ReadingSession to a ReadingSessionRecord.
+func readingSessionToRecord(s *domain.ReadingSession) *ReadingSessionRecord {
+	return &ReadingSessionRecord{
+		ID:         s.ID.String(),
+		BookID:     s.BookID.String(),
+		Date:       s.Date.Format(time.DateOnly),
+		Duration:   s.Duration.String(),
+		Unit:       string(s.Unit),
+		Start:      strconv.Itoa(s.Start),
+		End:        strconv.Itoa(s.End),
+		TotalPages: strconv.Itoa(s.TotalPages),
+		Note:       s.Note,
+		CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (rec *ReadingSessionRecord) fields() []string {
+	return []string{rec.ID, rec.BookID, rec.Date, rec.Duration, rec.Unit, rec.Start, rec.End, rec.TotalPages, rec.Note, rec.CreatedAt}
+}
+
+func readingSessionRecordFromFields(record []string) *ReadingSessionRecord {
+	return &ReadingSessionRecord{
+		ID:         record[0],
+		BookID:     record[1],
+		Date:       record[2],
+		Duration:   record[3],
+		Unit:       record[4],
+		Start:      record[5],
+		End:        record[6],
+		TotalPages: record[7],
+		Note:       record[8],
+		CreatedAt:  record[9],
+	}
+}
+
+// CSVReadingSessionRepository implements domain.ReadingSessionRepository using a CSV file.
+type CSVReadingSessionRepository struct {
+	FilePath string
+}
+
+func NewCSVReadingSessionRepository(filePath string) *CSVReadingSessionRepository {
+	return &CSVReadingSessionRepository{FilePath: filePath}
+}
+
+// Save implements domain.ReadingSessionRepository.Save
+func (r *CSVReadingSessionRepository) Save(session *domain.ReadingSession) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		updated := false
+		for i, existing := range all {
+			if existing.ID == session.ID {
+				all[i] = session
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			all = append(all, session)
+		}
+		return r.writeAll(all)
+	})
+}
+
+func (r *CSVReadingSessionRepository) FindAll(limit, offset int) ([]*domain.ReadingSession, error) {
+	return r.find(limit, offset, func([]string) bool { return true })
+}
+
+// FindByID implements domain.ReadingSessionRepository.FindByID
+func (r *CSVReadingSessionRepository) FindByID(id domain.ReadingSessionID) (*domain.ReadingSession, error) {
+	idStr := id.String()
+	sessions, err := r.find(1, 0, func(record []string) bool { return record[0] == idStr })
+	if err != nil || len(sessions) == 0 {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// FindByBookID implements domain.ReadingSessionRepository.FindByBookID
+func (r *CSVReadingSessionRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.ReadingSession, error) {
+	bookIDStr := bookID.String()
+	return r.find(0, 0, func(record []string) bool { return record[1] == bookIDStr })
+}
+
+// Delete implements domain.ReadingSessionRepository.Delete
+func (r *CSVReadingSessionRepository) Delete(id domain.ReadingSessionID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, existing := range all {
+			if existing.ID != id {
+				kept = append(kept, existing)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+func (r *CSVReadingSessionRepository) writeAll(sessions []*domain.ReadingSession) error {
+	records := [][]string{readingSessionHeader}
+	for _, session := range sessions {
+		records = append(records, readingSessionToRecord(session).fields())
+	}
+	return WriteCSV(r.FilePath, records)
+}
+
+// loadAll reads every session in file order. Callers writing the result back must hold the file lock.
+func (r *CSVReadingSessionRepository) loadAll() ([]*domain.ReadingSession, error) {
+	return r.find(0, 0, func([]string) bool { return true })
+}
+
+// find returns the sessions whose raw records match, checking the record before full
+// conversion. limit and offset apply to the matching sessions.
+func (r *CSVReadingSessionRepository) find(limit, offset int, match func(record []string) bool) ([]*domain.ReadingSession, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	var matching [][]string
+	for _, record := range records {
+		if len(record) >= len(readingSessionHeader) && match(record) {
+			matching = append(matching, record)
+		}
+	}
+
+	iter := NewCSVRecordIterator(matching, limit, offset)
+	var sessions []*domain.ReadingSession
+	for iter.Next() {
+		session, err := recordToReadingSession(readingSessionRecordFromFields(iter.Record()))
+		if err != nil {
+			slog.Error("Failed to convert reading session record", "err", err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, iter.Err()
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testReadingSessionRepository(t *testing.T, repo domain.ReadingSessionRepository, bookA, bookB domain.BibliographyID) {
+	t.Helper()
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.UTC) }
+	sessions := []*domain.ReadingSession{
+		{ID: domain.NewReadingSessionID(), BookID: bookA, Date: day(1), Duration: 45 * time.Minute, Unit: domain.ProgressPages, Start: 0, End: 30, TotalPages: 300, Note: "Preface, chapter 1", CreatedAt: day(1)},
+		{ID: domain.NewReadingSessionID(), BookID: bookB, Date: day(2), Unit: domain.ProgressPercent, Start: 0, End: 12, CreatedAt: day(2)},
+		{ID: domain.NewReadingSessionID(), BookID: bookA, Date: day(3), Duration: time.Hour, Unit: domain.ProgressPages, Start: 30, End: 75, CreatedAt: day(3)},
+	}
+	for _, session := range sessions {
+		if err := repo.Save(session); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByBookID(bookA)
+	if err != nil {
+		t.Fatalf("FindByBookID() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(found))
+	}
+	if *found[0] != *sessions[0] || *found[1] != *sessions[2] {
+		t.Errorf("Unexpected sessions %+v, %+v", *found[0], *found[1])
+	}
+
+	// Saving an existing ID updates it in place
+	sessions[1].End = 20
+	if err := repo.Save(sessions[1]); err != nil {
+		t.Fatalf("Save() update error = %v", err)
+	}
+	got, err := repo.FindByID(sessions[1].ID)
+	if err != nil || got == nil || got.End != 20 {
+		t.Fatalf("FindByID() = %+v, %v; want End 20", got, err)
+	}
+	if all, _ := repo.FindAll(0, 0); len(all) != 3 {
+		t.Errorf("Expected 3 sessions after update, got %d", len(all))
+	}
+	if page, _ := repo.FindAll(1, 1); len(page) != 1 || page[0].ID != sessions[1].ID {
+		t.Errorf("FindAll(1, 1) returned unexpected page %v", page)
+	}
+
+	if err := repo.Delete(sessions[0].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, err := repo.FindByID(sessions[0].ID); err != nil || got != nil {
+		t.Errorf("FindByID() after delete = %v, %v; want nil, nil", got, err)
+	}
+	if found, _ := repo.FindByBookID(bookA); len(found) != 1 {
+		t.Errorf("Expected 1 session left, got %d", len(found))
+	}
+}
+
+func TestCSVReadingSessionRepository(t *testing.T) {
+	repo := NewCSVReadingSessionRepository(filepath.Join(t.TempDir(), "reading_sessions.csv"))
+	testReadingSessionRepository(t, repo, domain.NewBibliographyID(), domain.NewBibliographyID())
+}
+
+func TestSQLiteReadingSessionRepository(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	var ids []domain.BibliographyID
+	for _, title := range []string{"A", "B"} {
+		bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: title, Code: "B56", Type: "Book", Title: title, Author: "X", PublishedDate: time.Now()}
+		if err := bibRepo.Save(bib); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, bib.ID)
+	}
+	testReadingSessionRepository(t, NewSQLiteReadingSessionRepository(db), ids[0], ids[1])
+
+	orphan := &domain.ReadingSession{ID: domain.NewReadingSessionID(), BookID: domain.NewBibliographyID(), Date: time.Now(), Unit: domain.ProgressPages, End: 10, CreatedAt: time.Now()}
+	if err := NewSQLiteReadingSessionRepository(db).Save(orphan); err == nil {
+		t.Error("Expected foreign key error for unknown bibliography, got nil")
+	}
+}
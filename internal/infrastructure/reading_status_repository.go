@@ -0,0 +1,171 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ReadingStatusRecord represents a reading status change for CSV persistence.
+// From is empty for the first change of a bibliography.
+type ReadingStatusRecord struct {
+	BookID    string
+	From      string
+	To        string
+	ChangedAt string
+}
+
+// recordToReadingStatusChange converts a ReadingStatusRecord to a domain.ReadingStatusChange.
+func recordToReadingStatusChange(rec *ReadingStatusRecord) (*domain.ReadingStatusChange, error) {
+	bookID, err := domain.ParseBibliographyID(rec.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse book ID: %w", err)
+	}
+
+	var from domain.ReadingStatus
+	if rec.From != "" {
+		if from, err = domain.ParseReadingStatus(rec.From); err != nil {
+			return nil, err
+		}
+	}
+	to, err := domain.ParseReadingStatus(rec.To)
+	if err != nil {
+		return nil, err
+	}
+
+	changedAt, err := time.Parse(time.RFC3339, rec.ChangedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse changed at: %w", err)
+	}
+
+	return &domain.ReadingStatusChange{BookID: bookID, From: from, To: to, ChangedAt: changedAt}, nil
+}
+
+// readingStatusChangeToRecord converts a domain.ReadingStatusChange to a ReadingStatusRecord.
+func readingStatusChangeToRecord(change *domain.ReadingStatusChange) *ReadingStatusRecord {
+	return &ReadingStatusRecord{
+		BookID:    change.BookID.String(),
+		From:      string(change.From),
+		To:        string(change.To),
+		ChangedAt: change.ChangedAt.Format(time.RFC3339),
+	}
+}
+
+// CSVReadingStatusRepository implements domain.ReadingStatusRepository using a CSV file
+// with one row per status change, in the order they were made.
+type CSVReadingStatusRepository struct {
+	FilePath string
+}
+
+func NewCSVReadingStatusRepository(filePath string) *CSVReadingStatusRepository {
+	return &CSVReadingStatusRepository{FilePath: filePath}
+}
+
+// Save implements domain.ReadingStatusRepository.Save
+func (r *CSVReadingStatusRepository) Save(change *domain.ReadingStatusChange) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+		return r.writeAll(append(all, change))
+	})
+}
+
+// FindByBookID implements domain.ReadingStatusRepository.FindByBookID
+func (r *CSVReadingStatusRepository) FindByBookID(bookID domain.BibliographyID) (domain.ReadingHistory, error) {
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	var history domain.ReadingHistory
+	for _, change := range all {
+		if change.BookID == bookID {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+// FindCurrent implements domain.ReadingStatusRepository.FindCurrent
+func (r *CSVReadingStatusRepository) FindCurrent() (map[domain.BibliographyID]domain.ReadingStatus, error) {
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[domain.BibliographyID]domain.ReadingStatus)
+	for _, change := range all {
+		current[change.BookID] = change.To
+	}
+	return current, nil
+}
+
+// DeleteByBookID implements domain.ReadingStatusRepository.DeleteByBookID
+func (r *CSVReadingStatusRepository) DeleteByBookID(bookID domain.BibliographyID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, change := range all {
+			if change.BookID != bookID {
+				kept = append(kept, change)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+func (r *CSVReadingStatusRepository) writeAll(changes []*domain.ReadingStatusChange) error {
+	var records [][]string
+	records = append(records, []string{"BookID", "From", "To", "ChangedAt"})
+
+	for _, change := range changes {
+		rec := readingStatusChangeToRecord(change)
+		records = append(records, []string{rec.BookID, rec.From, rec.To, rec.ChangedAt})
+	}
+	return WriteCSV(r.FilePath, records)
+}
+
+// loadAll reads every status change in file order. Callers writing the result back must hold the file lock.
+func (r *CSVReadingStatusRepository) loadAll() ([]*domain.ReadingStatusChange, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	iter := NewCSVRecordIterator(records, 0, 0)
+	var all []*domain.ReadingStatusChange
+
+	for iter.Next() {
+		record := iter.Record()
+		if len(record) < 4 {
+			continue
+		}
+		rec := &ReadingStatusRecord{
+			BookID:    record[0],
+			From:      record[1],
+			To:        record[2],
+			ChangedAt: record[3],
+		}
+		change, err := recordToReadingStatusChange(rec)
+		if err != nil {
+			slog.Error("Failed to convert reading status record", "err", err)
+			continue
+		}
+		all = append(all, change)
+	}
+
+	return all, iter.Err()
+}
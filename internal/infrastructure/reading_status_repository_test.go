@@ -0,0 +1,77 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testReadingStatusRepository(t *testing.T, repo domain.ReadingStatusRepository, bookA, bookB domain.BibliographyID) {
+	t.Helper()
+	at := func(d int) time.Time { return time.Date(2025, 11, d, 9, 0, 0, 0, time.UTC) }
+	changes := []*domain.ReadingStatusChange{
+		{BookID: bookA, To: domain.ReadingStatusToRead, ChangedAt: at(1)},
+		{BookID: bookB, To: domain.ReadingStatusReading, ChangedAt: at(2)},
+		{BookID: bookA, From: domain.ReadingStatusToRead, To: domain.ReadingStatusReading, ChangedAt: at(3)},
+	}
+	for _, change := range changes {
+		if err := repo.Save(change); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	history, err := repo.FindByBookID(bookA)
+	if err != nil {
+		t.Fatalf("FindByBookID() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(history))
+	}
+	if *history[0] != *changes[0] || *history[1] != *changes[2] {
+		t.Errorf("Unexpected history %+v, %+v", *history[0], *history[1])
+	}
+
+	current, err := repo.FindCurrent()
+	if err != nil {
+		t.Fatalf("FindCurrent() error = %v", err)
+	}
+	if len(current) != 2 || current[bookA] != domain.ReadingStatusReading || current[bookB] != domain.ReadingStatusReading {
+		t.Errorf("Unexpected current statuses %v", current)
+	}
+
+	if err := repo.DeleteByBookID(bookA); err != nil {
+		t.Fatalf("DeleteByBookID() error = %v", err)
+	}
+	if history, _ := repo.FindByBookID(bookA); len(history) != 0 {
+		t.Errorf("Expected no history after delete, got %d changes", len(history))
+	}
+	if history, _ := repo.FindByBookID(bookB); len(history) != 1 {
+		t.Errorf("Expected the other history to be kept, got %d changes", len(history))
+	}
+}
+
+func TestCSVReadingStatusRepository(t *testing.T) {
+	repo := NewCSVReadingStatusRepository(filepath.Join(t.TempDir(), "reading_status.csv"))
+	testReadingStatusRepository(t, repo, domain.NewBibliographyID(), domain.NewBibliographyID())
+}
+
+func TestSQLiteReadingStatusRepository(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	var ids []domain.BibliographyID
+	for _, title := range []string{"A", "B"} {
+		bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: title, Code: "B56", Type: "Book", Title: title, Author: "X", PublishedDate: time.Now()}
+		if err := bibRepo.Save(bib); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, bib.ID)
+	}
+	testReadingStatusRepository(t, NewSQLiteReadingStatusRepository(db), ids[0], ids[1])
+
+	// The history references its bibliography
+	orphan := &domain.ReadingStatusChange{BookID: domain.NewBibliographyID(), To: domain.ReadingStatusReading, ChangedAt: time.Now()}
+	if err := NewSQLiteReadingStatusRepository(db).Save(orphan); err == nil {
+		t.Error("Expected foreign key error for unknown bibliography, got nil")
+	}
+}
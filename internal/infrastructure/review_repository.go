@@ -71,46 +71,18 @@ func NewCSVReviewRepository(filePath string) *CSVReviewRepository {
 }
 
 // Save implements domain.ReviewRepository.Save
-// This contains potential race condition. But, it is not a problem in this cli application.
 func (r *CSVReviewRepository) Save(review *domain.Review) error {
-	records, err := ReadCSV(r.FilePath)
+	return withFileLock(r.FilePath, func() error {
+		return r.save(review)
+	})
+}
+
+func (r *CSVReviewRepository) save(review *domain.Review) error {
+	all, err := r.loadAll()
 	if err != nil {
 		return err
 	}
 
-	// Skip header
-	if len(records) > 0 {
-		records = records[1:]
-	}
-
-	iter := NewCSVRecordIterator(records, 0, 0)
-	var all []*domain.Review
-
-	for iter.Next() {
-		record := iter.Record()
-		if len(record) < 6 {
-			continue
-		}
-		revRecord := &ReviewRecord{
-			ID:        record[0],
-			BookID:    record[1],
-			Goals:     record[2],
-			Summary:   record[3],
-			CreatedAt: record[4],
-			UpdatedAt: record[5],
-		}
-		rev, err := recordToReview(revRecord)
-		if err != nil {
-			slog.Error("Failed to convert review record", "err", err)
-			continue
-		}
-		all = append(all, rev)
-	}
-
-	if iter.Err() != nil {
-		return iter.Err()
-	}
-
 	updated := false
 	for i, existing := range all {
 		if existing.ID == review.ID {
@@ -264,3 +236,63 @@ func (r *CSVReviewRepository) writeAll(reviews []*domain.Review) error {
 	}
 	return WriteCSV(r.FilePath, records)
 }
+
+// Delete implements domain.ReviewRepository.Delete
+func (r *CSVReviewRepository) Delete(id domain.ReviewID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, existing := range all {
+			if existing.ID != id {
+				kept = append(kept, existing)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+// loadAll reads every review in file order. Callers writing the result back must hold the file lock.
+func (r *CSVReviewRepository) loadAll() ([]*domain.Review, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	iter := NewCSVRecordIterator(records, 0, 0)
+	var all []*domain.Review
+
+	for iter.Next() {
+		record := iter.Record()
+		if len(record) < 6 {
+			continue
+		}
+		revRecord := &ReviewRecord{
+			ID:        record[0],
+			BookID:    record[1],
+			Goals:     record[2],
+			Summary:   record[3],
+			CreatedAt: record[4],
+			UpdatedAt: record[5],
+		}
+		rev, err := recordToReview(revRecord)
+		if err != nil {
+			slog.Error("Failed to convert review record", "err", err)
+			continue
+		}
+		all = append(all, rev)
+	}
+
+	return all, iter.Err()
+}
@@ -0,0 +1,198 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// ReviewRevisionRecord represents a review revision for CSV persistence.
+// RevertedTo is empty unless the revision restored an older one.
+type ReviewRevisionRecord struct {
+	ReviewID   string
+	Revision   string
+	Goals      string
+	Summary    string
+	Action     string
+	RevertedTo string
+	ChangedBy  string
+	CreatedAt  string
+}
+
+var reviewRevisionHeader = []string{"ReviewID", "Revision", "Goals", "Summary", "Action", "RevertedTo", "ChangedBy", "CreatedAt"}
+
+func (rec *ReviewRevisionRecord) fields() []string {
+	return []string{rec.ReviewID, rec.Revision, rec.Goals, rec.Summary, rec.Action, rec.RevertedTo, rec.ChangedBy, rec.CreatedAt}
+}
+
+func reviewRevisionRecordFromFields(record []string) *ReviewRevisionRecord {
+	return &ReviewRevisionRecord{
+		ReviewID:   record[0],
+		Revision:   record[1],
+		Goals:      record[2],
+		Summary:    record[3],
+		Action:     record[4],
+		RevertedTo: record[5],
+		ChangedBy:  record[6],
+		CreatedAt:  record[7],
+	}
+}
+
+// recordToReviewRevision converts a ReviewRevisionRecord to a domain.ReviewRevision.
+func recordToReviewRevision(rec *ReviewRevisionRecord) (*domain.ReviewRevision, error) {
+	reviewID, err := domain.ParseReviewID(rec.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	number, err := strconv.Atoi(rec.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse revision number: %w", err)
+	}
+	revertedTo := 0
+	if rec.RevertedTo != "" {
+		if revertedTo, err = strconv.Atoi(rec.RevertedTo); err != nil {
+			return nil, fmt.Errorf("failed to parse reverted revision: %w", err)
+		}
+	}
+	createdAt, err := time.Parse(time.RFC3339, rec.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	return &domain.ReviewRevision{
+		ReviewID:   reviewID,
+		Number:     number,
+		Goals:      rec.Goals,
+		Summary:    rec.Summary,
+		Action:     domain.RevisionAction(rec.Action),
+		RevertedTo: revertedTo,
+		ChangedBy:  rec.ChangedBy,
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// reviewRevisionToRecord converts a domain.ReviewRevision to a ReviewRevisionRecord.
+func reviewRevisionToRecord(revision *domain.ReviewRevision) *ReviewRevisionRecord {
+	rec := &ReviewRevisionRecord{
+		ReviewID:  revision.ReviewID.String(),
+		Revision:  strconv.Itoa(revision.Number),
+		Goals:     revision.Goals,
+		Summary:   revision.Summary,
+		Action:    string(revision.Action),
+		ChangedBy: revision.ChangedBy,
+		CreatedAt: revision.CreatedAt.Format(time.RFC3339),
+	}
+	if revision.RevertedTo > 0 {
+		rec.RevertedTo = strconv.Itoa(revision.RevertedTo)
+	}
+	return rec
+}
+
+// CSVReviewRevisionRepository implements domain.ReviewRevisionRepository using a CSV file
+// with one row per revision, in the order they were made.
+type CSVReviewRevisionRepository struct {
+	FilePath string
+}
+
+func NewCSVReviewRevisionRepository(filePath string) *CSVReviewRevisionRepository {
+	return &CSVReviewRevisionRepository{FilePath: filePath}
+}
+
+// Append implements domain.ReviewRevisionRepository.Append
+// The revision number must follow the latest one of the review, so that two processes
+// updating the same review cannot both record the same revision.
+func (r *CSVReviewRevisionRepository) Append(revision *domain.ReviewRevision) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+		latest := 0
+		for _, existing := range all {
+			if existing.ReviewID == revision.ReviewID {
+				latest = existing.Number
+			}
+		}
+		if revision.Number != latest+1 {
+			return fmt.Errorf("review %s already has revision %d", revision.ReviewID, revision.Number)
+		}
+		return r.writeAll(append(all, revision))
+	})
+}
+
+// FindByReviewID implements domain.ReviewRevisionRepository.FindByReviewID
+func (r *CSVReviewRevisionRepository) FindByReviewID(reviewID domain.ReviewID) (domain.ReviewHistory, error) {
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	var history domain.ReviewHistory
+	for _, revision := range all {
+		if revision.ReviewID == reviewID {
+			history = append(history, revision)
+		}
+	}
+	return history, nil
+}
+
+// DeleteByReviewID implements domain.ReviewRevisionRepository.DeleteByReviewID
+func (r *CSVReviewRevisionRepository) DeleteByReviewID(reviewID domain.ReviewID) error {
+	return withFileLock(r.FilePath, func() error {
+		all, err := r.loadAll()
+		if err != nil {
+			return err
+		}
+
+		kept := all[:0]
+		for _, revision := range all {
+			if revision.ReviewID != reviewID {
+				kept = append(kept, revision)
+			}
+		}
+		if len(kept) == len(all) {
+			return nil
+		}
+		return r.writeAll(kept)
+	})
+}
+
+func (r *CSVReviewRevisionRepository) writeAll(revisions []*domain.ReviewRevision) error {
+	records := [][]string{reviewRevisionHeader}
+	for _, revision := range revisions {
+		records = append(records, reviewRevisionToRecord(revision).fields())
+	}
+	return WriteCSV(r.FilePath, records)
+}
+
+// loadAll reads every revision in file order. Callers writing the result back must hold the file lock.
+func (r *CSVReviewRevisionRepository) loadAll() ([]*domain.ReviewRevision, error) {
+	records, err := ReadCSV(r.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip header
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	iter := NewCSVRecordIterator(records, 0, 0)
+	var all []*domain.ReviewRevision
+
+	for iter.Next() {
+		record := iter.Record()
+		if len(record) < len(reviewRevisionHeader) {
+			continue
+		}
+		revision, err := recordToReviewRevision(reviewRevisionRecordFromFields(record))
+		if err != nil {
+			slog.Error("Failed to convert review revision record", "err", err)
+			continue
+		}
+		all = append(all, revision)
+	}
+
+	return all, iter.Err()
+}
@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testReviewRevisionRepository(t *testing.T, repo domain.ReviewRevisionRepository, reviewA, reviewB domain.ReviewID) {
+	t.Helper()
+	at := func(d int) time.Time { return time.Date(2025, 11, d, 9, 0, 0, 0, time.UTC) }
+	revisions := []*domain.ReviewRevision{
+		{ReviewID: reviewA, Number: 1, Goals: "目的\n二行目", Summary: "", Action: domain.RevisionCreated, ChangedBy: "alice", CreatedAt: at(1)},
+		{ReviewID: reviewB, Number: 1, Goals: "Other", Action: domain.RevisionRecorded, CreatedAt: at(2)},
+		{ReviewID: reviewA, Number: 2, Goals: "目的\n二行目", Summary: "段落1\n\n段落2", Action: domain.RevisionUpdated, ChangedBy: "alice", CreatedAt: at(3)},
+		{ReviewID: reviewA, Number: 3, Goals: "目的\n二行目", Action: domain.RevisionReverted, RevertedTo: 1, ChangedBy: "bob", CreatedAt: at(4)},
+	}
+	for _, revision := range revisions {
+		if err := repo.Append(revision); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	// Revisions are numbered without gaps or duplicates
+	for _, number := range []int{3, 5} {
+		if err := repo.Append(&domain.ReviewRevision{ReviewID: reviewA, Number: number, Goals: "x", Action: domain.RevisionUpdated, CreatedAt: at(5)}); err == nil {
+			t.Errorf("Expected error appending revision %d", number)
+		}
+	}
+
+	history, err := repo.FindByReviewID(reviewA)
+	if err != nil {
+		t.Fatalf("FindByReviewID() error = %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 revisions, got %d", len(history))
+	}
+	for i, want := range []*domain.ReviewRevision{revisions[0], revisions[2], revisions[3]} {
+		if *history[i] != *want {
+			t.Errorf("revision %d = %+v, want %+v", i+1, *history[i], *want)
+		}
+	}
+
+	if err := repo.DeleteByReviewID(reviewA); err != nil {
+		t.Fatalf("DeleteByReviewID() error = %v", err)
+	}
+	if history, _ := repo.FindByReviewID(reviewA); len(history) != 0 {
+		t.Errorf("Expected no history after delete, got %d revisions", len(history))
+	}
+	if history, _ := repo.FindByReviewID(reviewB); len(history) != 1 {
+		t.Errorf("Expected the other history to be kept, got %d revisions", len(history))
+	}
+}
+
+func TestCSVReviewRevisionRepository(t *testing.T) {
+	repo := NewCSVReviewRevisionRepository(filepath.Join(t.TempDir(), "review_revisions.csv"))
+	testReviewRevisionRepository(t, repo, domain.NewReviewID(), domain.NewReviewID())
+}
+
+func TestSQLiteReviewRevisionRepository(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "A", Code: "B56", Type: "Book", Title: "A", Author: "X", PublishedDate: time.Now()}
+	if err := NewSQLiteBibliographyRepository(db).Save(bib); err != nil {
+		t.Fatal(err)
+	}
+	reviewRepo := NewSQLiteReviewRepository(db)
+	var ids []domain.ReviewID
+	for range 2 {
+		review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "Goals", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := reviewRepo.Save(review); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, review.ID)
+	}
+	testReviewRevisionRepository(t, NewSQLiteReviewRevisionRepository(db), ids[0], ids[1])
+
+	// The history references its review
+	orphan := &domain.ReviewRevision{ReviewID: domain.NewReviewID(), Number: 1, Goals: "x", Action: domain.RevisionCreated, CreatedAt: time.Now()}
+	if err := NewSQLiteReviewRevisionRepository(db).Append(orphan); err == nil {
+		t.Error("Expected foreign key error for unknown review, got nil")
+	}
+}
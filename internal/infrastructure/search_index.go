@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"bibliography_log/internal/search"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	bibDocPrefix    = "bib:"
+	reviewDocPrefix = "review:"
+)
+
+// searchFieldWeights ranks matches in titles and authors above matches in other fields.
+var searchFieldWeights = map[string]float64{
+	domain.SearchFieldTitle:  3,
+	domain.SearchFieldAuthor: 2,
+}
+
+// FileSearchIndex is a domain.SearchIndex persisted as a gob-encoded file.
+// It works with either storage backend. Updates are read-modify-write cycles
+// under the same kind of exclusive lock as the CSV repositories.
+type FileSearchIndex struct {
+	FilePath string
+}
+
+// NewFileSearchIndex creates a FileSearchIndex stored at filePath.
+func NewFileSearchIndex(filePath string) *FileSearchIndex {
+	return &FileSearchIndex{FilePath: filePath}
+}
+
+func (x *FileSearchIndex) IndexBibliography(bib *domain.Bibliography) error {
+	return x.update(func(ix *search.Index) {
+		addBibliography(ix, bib)
+	})
+}
+
+func (x *FileSearchIndex) IndexReview(review *domain.Review) error {
+	return x.update(func(ix *search.Index) {
+		addReview(ix, review)
+	})
+}
+
+func (x *FileSearchIndex) RemoveBibliography(id domain.BibliographyID) error {
+	return x.update(func(ix *search.Index) {
+		ix.Remove(bibDocPrefix + id.String())
+	})
+}
+
+func (x *FileSearchIndex) RemoveReview(id domain.ReviewID) error {
+	return x.update(func(ix *search.Index) {
+		ix.Remove(reviewDocPrefix + id.String())
+	})
+}
+
+func (x *FileSearchIndex) Rebuild(bibs []*domain.Bibliography, reviews []*domain.Review) error {
+	return withFileLock(x.FilePath, func() error {
+		ix := search.NewIndex()
+		for _, bib := range bibs {
+			addBibliography(ix, bib)
+		}
+		for _, review := range reviews {
+			addReview(ix, review)
+		}
+		return x.save(ix)
+	})
+}
+
+func (x *FileSearchIndex) Search(query string) ([]domain.SearchHit, error) {
+	clauses, err := search.ParseQuery(query, domain.SearchFields)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := x.load()
+	if err != nil {
+		return nil, err
+	}
+	if ix == nil {
+		return nil, domain.ErrSearchIndexNotBuilt
+	}
+
+	results := ix.Search(clauses, searchFieldWeights)
+	hits := make([]domain.SearchHit, 0, len(results))
+	for _, r := range results {
+		bookID, err := domain.ParseBibliographyID(r.Group)
+		if err != nil {
+			log.Printf("Skipping invalid search index entry %q: %v", r.Group, err)
+			continue
+		}
+		hit := domain.SearchHit{BookID: bookID, Score: r.Score}
+		matched := make(map[string]bool)
+		for _, m := range r.Matches {
+			matched[m.Field] = true
+			if idStr, ok := strings.CutPrefix(m.DocID, reviewDocPrefix); ok {
+				if id, err := domain.ParseReviewID(idStr); err == nil && !containsReviewID(hit.ReviewIDs, id) {
+					hit.ReviewIDs = append(hit.ReviewIDs, id)
+				}
+			}
+		}
+		for _, field := range domain.SearchFields {
+			if matched[field] {
+				hit.Fields = append(hit.Fields, field)
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// addBibliography indexes a bibliography in the group named by its ID. Contributor
+// names missing from the author line and romanized names are indexed as author text.
+func addBibliography(ix *search.Index, bib *domain.Bibliography) {
+	authors := []string{bib.Author}
+	for _, c := range bib.Contributors {
+		if !strings.Contains(bib.Author, c.Name) {
+			authors = append(authors, c.Name)
+		}
+		if c.NameEn != "" {
+			authors = append(authors, c.NameEn)
+		}
+	}
+	ix.Add(bibDocPrefix+bib.ID.String(), bib.ID.String(), map[string]string{
+		domain.SearchFieldTitle:     bib.Title,
+		domain.SearchFieldAuthor:    strings.Join(authors, "\n"),
+		domain.SearchFieldPublisher: bib.Publisher,
+	})
+}
+
+// addReview indexes a review in the group of its bibliography.
+func addReview(ix *search.Index, review *domain.Review) {
+	ix.Add(reviewDocPrefix+review.ID.String(), review.BookID.String(), map[string]string{
+		domain.SearchFieldGoals:   review.Goals,
+		domain.SearchFieldSummary: review.Summary,
+	})
+}
+
+func containsReviewID(ids []domain.ReviewID, id domain.ReviewID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+// update applies fn to the stored index under an exclusive lock.
+// If the index has not been built yet, nothing happens.
+func (x *FileSearchIndex) update(fn func(ix *search.Index)) error {
+	return withFileLock(x.FilePath, func() error {
+		ix, err := x.load()
+		if err != nil || ix == nil {
+			return err
+		}
+		fn(ix)
+		return x.save(ix)
+	})
+}
+
+// load reads the index, returning nil if it has not been built.
+func (x *FileSearchIndex) load() (*search.Index, error) {
+	file, err := os.Open(x.FilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("Failed to close file: %v", err)
+		}
+	}()
+
+	ix := search.NewIndex()
+	if err := gob.NewDecoder(file).Decode(ix); err != nil {
+		return nil, fmt.Errorf("failed to read search index %s (rebuild it with 'biblog reindex'): %w", x.FilePath, err)
+	}
+	return ix, nil
+}
+
+func (x *FileSearchIndex) save(ix *search.Index) error {
+	return writeFileAtomic(x.FilePath, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(ix)
+	})
+}
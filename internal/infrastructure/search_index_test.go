@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSearchIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.idx")
+	index := NewFileSearchIndex(path)
+
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), Title: "データモデリングでドメインを駆動する", Author: "杉本啓", Publisher: "技術評論社"}
+	review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "設計について学ぶ", Summary: "疎結合なシステム", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	// Updates before the first build are ignored so that the index is never partial
+	if err := index.IndexBibliography(bib); err != nil {
+		t.Fatalf("Failed to index bibliography: %v", err)
+	}
+	if _, err := index.Search("杉本"); !errors.Is(err, domain.ErrSearchIndexNotBuilt) {
+		t.Fatalf("Expected ErrSearchIndexNotBuilt, got %v", err)
+	}
+
+	if err := index.Rebuild([]*domain.Bibliography{bib}, nil); err != nil {
+		t.Fatalf("Failed to rebuild index: %v", err)
+	}
+	if err := index.IndexReview(review); err != nil {
+		t.Fatalf("Failed to index review: %v", err)
+	}
+
+	// A new instance reads the persisted index
+	hits, err := NewFileSearchIndex(path).Search("杉本 疎結合")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].BookID != bib.ID {
+		t.Fatalf("Expected 1 hit for %v, got %+v", bib.ID, hits)
+	}
+	if len(hits[0].Fields) != 2 || hits[0].Fields[0] != domain.SearchFieldAuthor || hits[0].Fields[1] != domain.SearchFieldSummary {
+		t.Errorf("Expected fields [author summary], got %v", hits[0].Fields)
+	}
+	if len(hits[0].ReviewIDs) != 1 || hits[0].ReviewIDs[0] != review.ID {
+		t.Errorf("Expected matching review %v, got %v", review.ID, hits[0].ReviewIDs)
+	}
+
+	// Updates replace the previous text
+	review.Summary = "弾性のあるシステム"
+	if err := index.IndexReview(review); err != nil {
+		t.Fatalf("Failed to re-index review: %v", err)
+	}
+	if hits, _ := index.Search("疎結合"); len(hits) != 0 {
+		t.Errorf("Expected no hits for replaced text, got %+v", hits)
+	}
+
+	if err := index.RemoveReview(review.ID); err != nil {
+		t.Fatalf("Failed to remove review: %v", err)
+	}
+	if err := index.RemoveBibliography(bib.ID); err != nil {
+		t.Fatalf("Failed to remove bibliography: %v", err)
+	}
+	if hits, _ := index.Search("杉本"); len(hits) != 0 {
+		t.Errorf("Expected no hits after removal, got %+v", hits)
+	}
+}
@@ -0,0 +1,316 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const bibliographyColumns = "id, bib_index, code, type, title, author, publisher, isbn, published_date, contributors"
+
+// SQLiteBibliographyRepository implements domain.BibliographyRepository using SQLite.
+type SQLiteBibliographyRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteBibliographyRepository(db *sql.DB) *SQLiteBibliographyRepository {
+	return &SQLiteBibliographyRepository{DB: db}
+}
+
+// EnforcesReferences implements domain.ReferenceEnforcer. Reviews, reading history,
+// sessions and highlights reference their bibliography by foreign key.
+func (r *SQLiteBibliographyRepository) EnforcesReferences() bool {
+	return true
+}
+
+// Save implements domain.BibliographyRepository.Save
+// Inserts a new row or updates the existing row with the same ID, keeping its insertion order.
+func (r *SQLiteBibliographyRepository) Save(b *domain.Bibliography) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		return saveBibliography(tx, b)
+	})
+}
+
+// SaveAll implements domain.BibliographyRepository.SaveAll
+func (r *SQLiteBibliographyRepository) SaveAll(bibliographies []*domain.Bibliography) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		for _, b := range bibliographies {
+			if err := saveBibliography(tx, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func saveBibliography(tx *sql.Tx, b *domain.Bibliography) error {
+	rec := bibliographyToRecord(b)
+	_, err := tx.Exec(`INSERT INTO bibliographies (`+bibliographyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			bib_index = excluded.bib_index,
+			code = excluded.code,
+			type = excluded.type,
+			title = excluded.title,
+			author = excluded.author,
+			publisher = excluded.publisher,
+			isbn = excluded.isbn,
+			published_date = excluded.published_date,
+			contributors = excluded.contributors`,
+		rec.ID, rec.BibIndex, rec.Code, rec.Type, rec.Title, rec.Author, rec.Publisher, rec.ISBN, rec.PublishedDate, rec.Contributors)
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		// idx_bibliographies_bib_index is the only unique constraint left after the id upsert
+		return domain.Invalid(fmt.Errorf("BibIndex %s is already used", rec.BibIndex))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save bibliography: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteBibliographyRepository) FindAll(limit, offset int) ([]*domain.Bibliography, error) {
+	rows, err := r.DB.Query(`SELECT `+bibliographyColumns+` FROM bibliographies ORDER BY rowid LIMIT ? OFFSET ?`,
+		sqliteLimit(limit), sqliteOffset(offset))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var bibliographies []*domain.Bibliography
+	for rows.Next() {
+		bib, err := scanBibliography(rows)
+		if err != nil {
+			slog.Error("Failed to convert bibliography record", "err", err)
+			continue
+		}
+		bibliographies = append(bibliographies, bib)
+	}
+	return bibliographies, rows.Err()
+}
+
+// bibliographySortColumns maps sort keys to ORDER BY expressions.
+// Text columns are compared case-insensitively, like domain.BibliographyQuery.SortBibliographies;
+// unicode_lower folds them the same way as strings.ToLower.
+var bibliographySortColumns = map[domain.BibliographySort]string{
+	domain.SortByTitle:    "unicode_lower(title)",
+	domain.SortByAuthor:   "unicode_lower(author)",
+	domain.SortByYear:     "published_date",
+	domain.SortByBibIndex: "bib_index",
+}
+
+// Find implements domain.BibliographyRepository.Find
+// Filters, ordering and paging are all evaluated by SQLite. Text is case-folded with
+// unicode_lower rather than SQLite's ASCII-only lower(), so results match the CSV backend.
+func (r *SQLiteBibliographyRepository) Find(q domain.BibliographyQuery) ([]*domain.Bibliography, error) {
+	var where []string
+	var args []any
+	if q.Type != "" {
+		where = append(where, "unicode_lower(type) = unicode_lower(?)")
+		args = append(args, q.Type)
+	}
+	if q.ClassCode != nil {
+		// Codes are a type prefix followed by the class code, e.g. "B56" or "B547.48"
+		const classCode = "ltrim(code, 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz')"
+		if q.ClassRecursive {
+			where = append(where, "substr(replace("+classCode+", '.', ''), 1, ?) = ?")
+			args = append(args, len(q.ClassCode.Digits()), q.ClassCode.Digits())
+		} else {
+			where = append(where, classCode+" = ?")
+			args = append(args, q.ClassCode.String())
+		}
+	}
+	if q.YearFrom != 0 {
+		where = append(where, "CAST(substr(published_date, 1, 4) AS INTEGER) >= ?")
+		args = append(args, q.YearFrom)
+	}
+	if q.YearTo != 0 {
+		where = append(where, "CAST(substr(published_date, 1, 4) AS INTEGER) <= ?")
+		args = append(args, q.YearTo)
+	}
+	if q.Author != "" {
+		where = append(where, `(instr(unicode_lower(author), unicode_lower(?)) > 0 OR EXISTS (SELECT 1 FROM json_each(nullif(contributors, ''))
+			WHERE instr(unicode_lower(json_extract(value, '$.name')), unicode_lower(?)) > 0
+				OR instr(unicode_lower(coalesce(json_extract(value, '$.name_en'), '')), unicode_lower(?)) > 0))`)
+		args = append(args, q.Author, q.Author, q.Author)
+	}
+	if q.Publisher != "" {
+		where = append(where, "instr(unicode_lower(publisher), unicode_lower(?)) > 0")
+		args = append(args, q.Publisher)
+	}
+	if q.HasReview != nil {
+		exists := "EXISTS (SELECT 1 FROM reviews WHERE reviews.book_id = bibliographies.id)"
+		if !*q.HasReview {
+			exists = "NOT " + exists
+		}
+		where = append(where, exists)
+	}
+	if q.HasISBN != nil {
+		if *q.HasISBN {
+			where = append(where, "isbn != ''")
+		} else {
+			where = append(where, "isbn = ''")
+		}
+	}
+	if q.Status != nil {
+		// The current status is the latest change; untracked bibliographies have none
+		where = append(where, `COALESCE((SELECT to_status FROM reading_status_changes
+			WHERE reading_status_changes.book_id = bibliographies.id
+			ORDER BY reading_status_changes.rowid DESC LIMIT 1), '') = ?`)
+		args = append(args, string(*q.Status))
+	}
+
+	query := `SELECT ` + bibliographyColumns + ` FROM bibliographies`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	direction := "ASC"
+	if q.Descending {
+		direction = "DESC"
+	}
+	if column, ok := bibliographySortColumns[q.Sort]; ok {
+		// Ties keep insertion order
+		query += ` ORDER BY ` + column + ` ` + direction + `, rowid`
+	} else {
+		query += ` ORDER BY rowid ` + direction
+	}
+	query += ` LIMIT ? OFFSET ?`
+	args = append(args, sqliteLimit(q.Limit), sqliteOffset(q.Offset))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var bibliographies []*domain.Bibliography
+	for rows.Next() {
+		bib, err := scanBibliography(rows)
+		if err != nil {
+			slog.Error("Failed to convert bibliography record", "err", err)
+			continue
+		}
+		bibliographies = append(bibliographies, bib)
+	}
+	return bibliographies, rows.Err()
+}
+
+// FindByID implements domain.BibliographyRepository.FindByID
+func (r *SQLiteBibliographyRepository) FindByID(id domain.BibliographyID) (*domain.Bibliography, error) {
+	row := r.DB.QueryRow(`SELECT `+bibliographyColumns+` FROM bibliographies WHERE id = ?`, id.String())
+	return scanOptionalBibliography(row)
+}
+
+// FindByBibIndex implements domain.BibliographyRepository.FindByBibIndex
+func (r *SQLiteBibliographyRepository) FindByBibIndex(bibIndex string) (*domain.Bibliography, error) {
+	row := r.DB.QueryRow(`SELECT `+bibliographyColumns+` FROM bibliographies WHERE bib_index = ? ORDER BY rowid LIMIT 1`, bibIndex)
+	return scanOptionalBibliography(row)
+}
+
+// Delete implements domain.BibliographyRepository.Delete
+// Reviews still referencing the bibliography make this fail with a foreign key violation.
+func (r *SQLiteBibliographyRepository) Delete(id domain.BibliographyID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM bibliographies WHERE id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to delete bibliography: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteWithDependents implements domain.BibliographyCascadeDeleter
+// Without withReviews, a remaining review fails the final delete on its foreign key and
+// the transaction is rolled back.
+func (r *SQLiteBibliographyRepository) DeleteWithDependents(id domain.BibliographyID, withReviews bool) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		var stmts []string
+		if withReviews {
+			stmts = append(stmts,
+				`DELETE FROM review_revisions WHERE review_id IN (SELECT id FROM reviews WHERE book_id = ?)`,
+				`DELETE FROM reviews WHERE book_id = ?`)
+		}
+		stmts = append(stmts,
+			`DELETE FROM reading_status_changes WHERE book_id = ?`,
+			`DELETE FROM reading_sessions WHERE book_id = ?`,
+			`DELETE FROM highlights WHERE book_id = ?`,
+			`DELETE FROM bibliographies WHERE id = ?`)
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt, id.String()); err != nil {
+				return fmt.Errorf("failed to delete bibliography: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// NormalizeISBNs implements domain.ISBNNormalizer
+func (r *SQLiteBibliographyRepository) NormalizeISBNs(dryRun, clearInvalid bool) ([]domain.ISBNMigration, error) {
+	var migrations []domain.ISBNMigration
+	err := withTx(r.DB, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, bib_index, title, isbn FROM bibliographies WHERE isbn != '' ORDER BY rowid`)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id, bibIndex, title, old string
+			if err := rows.Scan(&id, &bibIndex, &title, &old); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if m, changed := migrateISBN(id, bibIndex, title, old, clearInvalid); changed {
+				migrations = append(migrations, m)
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
+		}
+		for _, m := range migrations {
+			if m.Err != nil && !m.Cleared {
+				continue
+			}
+			if _, err := tx.Exec(`UPDATE bibliographies SET isbn = ? WHERE id = ?`, m.New, m.BibliographyID); err != nil {
+				return fmt.Errorf("failed to update ISBN of %s: %w", m.BibIndex, err)
+			}
+		}
+		return nil
+	})
+	return migrations, err
+}
+
+// scanOptionalBibliography returns (nil, nil) when no row matched, mirroring the CSV repository.
+func scanOptionalBibliography(row *sql.Row) (*domain.Bibliography, error) {
+	bib, err := scanBibliography(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return bib, err
+}
+
+func scanBibliography(s rowScanner) (*domain.Bibliography, error) {
+	var rec BibliographyRecord
+	if err := s.Scan(&rec.ID, &rec.BibIndex, &rec.Code, &rec.Type, &rec.Title, &rec.Author, &rec.Publisher, &rec.ISBN, &rec.PublishedDate, &rec.Contributors); err != nil {
+		return nil, err
+	}
+	return recordToBibliography(&rec)
+}
@@ -0,0 +1,87 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const classificationColumns = "id, code, name"
+
+// SQLiteClassificationRepository implements domain.ClassificationRepository using SQLite.
+type SQLiteClassificationRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteClassificationRepository(db *sql.DB) *SQLiteClassificationRepository {
+	return &SQLiteClassificationRepository{DB: db}
+}
+
+// Save implements domain.ClassificationRepository.Save
+func (r *SQLiteClassificationRepository) Save(c *domain.Classification) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO classifications (`+classificationColumns+`)
+			VALUES (?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				code = excluded.code,
+				name = excluded.name`,
+			c.ID.String(), c.Code.String(), c.Name)
+		if err != nil {
+			return fmt.Errorf("failed to save classification: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteClassificationRepository) FindAll(limit, offset int) ([]*domain.Classification, error) {
+	rows, err := r.DB.Query(`SELECT `+classificationColumns+` FROM classifications ORDER BY rowid LIMIT ? OFFSET ?`,
+		sqliteLimit(limit), sqliteOffset(offset))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var classifications []*domain.Classification
+	for rows.Next() {
+		class, err := scanClassification(rows)
+		if err != nil {
+			slog.Error("Failed to convert classification record", "err", err)
+			continue
+		}
+		classifications = append(classifications, class)
+	}
+	return classifications, rows.Err()
+}
+
+func (r *SQLiteClassificationRepository) FindByCode(code domain.ClassCode) (*domain.Classification, error) {
+	row := r.DB.QueryRow(`SELECT `+classificationColumns+` FROM classifications WHERE code = ?`, code.String())
+	class, err := scanClassification(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return class, err
+}
+
+// Delete implements domain.ClassificationRepository.Delete
+func (r *SQLiteClassificationRepository) Delete(id domain.ClassificationID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM classifications WHERE id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to delete classification: %w", err)
+		}
+		return nil
+	})
+}
+
+func scanClassification(s rowScanner) (*domain.Classification, error) {
+	var rec ClassificationRecord
+	if err := s.Scan(&rec.ID, &rec.Code, &rec.Name); err != nil {
+		return nil, err
+	}
+	return recordToClassification(&rec)
+}
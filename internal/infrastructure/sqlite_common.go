@@ -0,0 +1,256 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	// Pure-Go SQLite driver (no cgo), registered as "sqlite".
+	"modernc.org/sqlite"
+)
+
+// unicode_lower is available to every connection. SQLite's built-in lower() folds ASCII
+// letters only, so queries use it to fold case like strings.ToLower, matching the
+// in-memory filtering of the CSV backend for text such as "Émile" or "ΑΒΓ".
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []byte:
+			return strings.ToLower(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
+
+// sqliteSchema creates the tables and indexes used by the SQLite repositories.
+// Timestamps are stored as RFC3339 text, matching the CSV representation.
+// rowid order is used as insertion order so FindAll behaves like the CSV backend.
+var sqliteSchema = []string{
+	createClassificationsTable,
+	`CREATE TABLE IF NOT EXISTS bibliographies (
+		id             TEXT PRIMARY KEY,
+		bib_index      TEXT NOT NULL,
+		code           TEXT NOT NULL,
+		type           TEXT NOT NULL,
+		title          TEXT NOT NULL,
+		author         TEXT NOT NULL,
+		publisher      TEXT NOT NULL DEFAULT '',
+		isbn           TEXT NOT NULL DEFAULT '',
+		published_date TEXT NOT NULL,
+		contributors   TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS reviews (
+		id         TEXT PRIMARY KEY,
+		book_id    TEXT NOT NULL REFERENCES bibliographies(id),
+		goals      TEXT NOT NULL,
+		summary    TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reviews_book_id ON reviews(book_id)`,
+	`CREATE TABLE IF NOT EXISTS review_revisions (
+		review_id   TEXT NOT NULL REFERENCES reviews(id),
+		revision    INTEGER NOT NULL,
+		goals       TEXT NOT NULL,
+		summary     TEXT NOT NULL DEFAULT '',
+		action      TEXT NOT NULL,
+		reverted_to INTEGER NOT NULL DEFAULT 0,
+		changed_by  TEXT NOT NULL DEFAULT '',
+		created_at  TEXT NOT NULL,
+		PRIMARY KEY (review_id, revision)
+	)`,
+	`CREATE TABLE IF NOT EXISTS reading_status_changes (
+		book_id     TEXT NOT NULL REFERENCES bibliographies(id),
+		from_status TEXT NOT NULL DEFAULT '',
+		to_status   TEXT NOT NULL,
+		changed_at  TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_status_changes_book_id ON reading_status_changes(book_id)`,
+	`CREATE TABLE IF NOT EXISTS reading_sessions (
+		id          TEXT PRIMARY KEY,
+		book_id     TEXT NOT NULL REFERENCES bibliographies(id),
+		date        TEXT NOT NULL,
+		duration    TEXT NOT NULL,
+		unit        TEXT NOT NULL,
+		start_pos   INTEGER NOT NULL,
+		end_pos     INTEGER NOT NULL,
+		total_pages INTEGER NOT NULL DEFAULT 0,
+		note        TEXT NOT NULL DEFAULT '',
+		created_at  TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_sessions_book_id ON reading_sessions(book_id)`,
+	`CREATE TABLE IF NOT EXISTS highlights (
+		id         TEXT PRIMARY KEY,
+		book_id    TEXT NOT NULL REFERENCES bibliographies(id),
+		text       TEXT NOT NULL,
+		location   TEXT NOT NULL DEFAULT '',
+		comment    TEXT NOT NULL DEFAULT '',
+		tags       TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_highlights_book_id ON highlights(book_id)`,
+}
+
+const createClassificationsTable = `CREATE TABLE IF NOT EXISTS classifications (
+		id   TEXT PRIMARY KEY,
+		code TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL
+	)`
+
+// sqliteAddedColumns are columns added to tables after their CREATE TABLE statement was
+// first released. Databases created before that get them through ALTER TABLE.
+var sqliteAddedColumns = []struct{ table, column, definition string }{
+	{"bibliographies", "contributors", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// OpenSQLiteDB opens (or creates) the SQLite database at filePath and ensures the schema exists.
+// Foreign keys are enforced on every pooled connection via the DSN pragmas.
+func OpenSQLiteDB(filePath string) (*sql.DB, error) {
+	dsn := "file:" + filePath + "?" + url.Values{
+		"_pragma": []string{"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if err := migrateSQLite(db); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Failed to close database: %v", closeErr)
+		}
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	return withTx(db, func(tx *sql.Tx) error {
+		if err := migrateClassificationCodes(tx); err != nil {
+			return err
+		}
+		for _, stmt := range sqliteSchema {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("failed to apply sqlite schema: %w", err)
+			}
+		}
+		if err := migrateUniqueBibIndex(tx); err != nil {
+			return err
+		}
+		for _, c := range sqliteAddedColumns {
+			var exists bool
+			if err := tx.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to inspect sqlite schema: %w", err)
+			}
+			if exists {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+				return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+			}
+		}
+		return nil
+	})
+}
+
+// migrateClassificationCodes converts the integer code_num column of databases created
+// before decimal class codes (e.g. "547.48", "007") to the text column code. SQLite cannot
+// change a column's type in place, so the table is rebuilt, keeping rowid order.
+func migrateClassificationCodes(tx *sql.Tx) error {
+	var legacy bool
+	if err := tx.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info('classifications') WHERE name = 'code_num'`).Scan(&legacy); err != nil {
+		return fmt.Errorf("failed to inspect sqlite schema: %w", err)
+	}
+	if !legacy {
+		return nil
+	}
+	for _, stmt := range []string{
+		`ALTER TABLE classifications RENAME TO classifications_legacy`,
+		createClassificationsTable,
+		`INSERT INTO classifications (id, code, name)
+			SELECT id, CAST(code_num AS TEXT), name FROM classifications_legacy ORDER BY rowid`,
+		`DROP TABLE classifications_legacy`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to migrate classification codes: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateUniqueBibIndex makes idx_bibliographies_bib_index UNIQUE. Databases created before
+// it was unique may already hold duplicate BibIndexes; the plain index is then kept until
+// they are renamed, and the next open upgrades it. Duplicates are reported by check-indexes,
+// not here, so that every command does not warn about them.
+func migrateUniqueBibIndex(tx *sql.Tx) error {
+	var unique bool
+	err := tx.QueryRow(`SELECT "unique" FROM pragma_index_list('bibliographies') WHERE name = 'idx_bibliographies_bib_index'`).Scan(&unique)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to inspect sqlite schema: %w", err)
+	}
+	if unique {
+		return nil
+	}
+
+	var duplicated bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM bibliographies GROUP BY bib_index HAVING COUNT(*) > 1)`).Scan(&duplicated); err != nil {
+		return fmt.Errorf("failed to check BibIndex uniqueness: %w", err)
+	}
+	stmts := []string{
+		`DROP INDEX IF EXISTS idx_bibliographies_bib_index`,
+		`CREATE UNIQUE INDEX idx_bibliographies_bib_index ON bibliographies(bib_index)`,
+	}
+	if duplicated {
+		stmts = []string{`CREATE INDEX IF NOT EXISTS idx_bibliographies_bib_index ON bibliographies(bib_index)`}
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to migrate BibIndex index: %w", err)
+		}
+	}
+	return nil
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on error.
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %v", rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteLimit converts the repository convention (limit <= 0 means no limit)
+// to SQLite's (LIMIT -1 means no limit).
+func sqliteLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// sqliteOffset clamps negative offsets to zero, as NewCSVRecordIterator does.
+func sqliteOffset(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const highlightColumns = "id, book_id, text, location, comment, tags, created_at"
+
+// SQLiteHighlightRepository implements domain.HighlightRepository using SQLite.
+// highlights.book_id is a foreign key to bibliographies.id. Tags are stored
+// comma-separated, as in the CSV file.
+type SQLiteHighlightRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteHighlightRepository(db *sql.DB) *SQLiteHighlightRepository {
+	return &SQLiteHighlightRepository{DB: db}
+}
+
+// Save implements domain.HighlightRepository.Save
+func (r *SQLiteHighlightRepository) Save(highlight *domain.Highlight) error {
+	rec := highlightToRecord(highlight)
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO highlights (`+highlightColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				book_id = excluded.book_id,
+				text = excluded.text,
+				location = excluded.location,
+				comment = excluded.comment,
+				tags = excluded.tags,
+				created_at = excluded.created_at`,
+			rec.ID, rec.BookID, rec.Text, rec.Location, rec.Comment, rec.Tags, rec.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save highlight: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteHighlightRepository) FindAll(limit, offset int) ([]*domain.Highlight, error) {
+	rows, err := r.DB.Query(`SELECT `+highlightColumns+` FROM highlights ORDER BY rowid LIMIT ? OFFSET ?`,
+		sqliteLimit(limit), sqliteOffset(offset))
+	if err != nil {
+		return nil, err
+	}
+	return collectHighlights(rows)
+}
+
+// FindByID implements domain.HighlightRepository.FindByID
+func (r *SQLiteHighlightRepository) FindByID(id domain.HighlightID) (*domain.Highlight, error) {
+	row := r.DB.QueryRow(`SELECT `+highlightColumns+` FROM highlights WHERE id = ?`, id.String())
+	highlight, err := scanHighlight(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return highlight, err
+}
+
+// FindByBookID implements domain.HighlightRepository.FindByBookID
+func (r *SQLiteHighlightRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.Highlight, error) {
+	rows, err := r.DB.Query(`SELECT `+highlightColumns+` FROM highlights WHERE book_id = ? ORDER BY rowid`, bookID.String())
+	if err != nil {
+		return nil, err
+	}
+	return collectHighlights(rows)
+}
+
+// Delete implements domain.HighlightRepository.Delete
+func (r *SQLiteHighlightRepository) Delete(id domain.HighlightID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM highlights WHERE id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to delete highlight: %w", err)
+		}
+		return nil
+	})
+}
+
+func collectHighlights(rows *sql.Rows) ([]*domain.Highlight, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var highlights []*domain.Highlight
+	for rows.Next() {
+		highlight, err := scanHighlight(rows)
+		if err != nil {
+			slog.Error("Failed to convert highlight record", "err", err)
+			continue
+		}
+		highlights = append(highlights, highlight)
+	}
+	return highlights, rows.Err()
+}
+
+func scanHighlight(s rowScanner) (*domain.Highlight, error) {
+	var rec HighlightRecord
+	if err := s.Scan(&rec.ID, &rec.BookID, &rec.Text, &rec.Location, &rec.Comment, &rec.Tags, &rec.CreatedAt); err != nil {
+		return nil, err
+	}
+	return recordToHighlight(&rec)
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const readingSessionColumns = "id, book_id, date, duration, unit, start_pos, end_pos, total_pages, note, created_at"
+
+// SQLiteReadingSessionRepository implements domain.ReadingSessionRepository using SQLite.
+// reading_sessions.book_id is a foreign key to bibliographies.id.
+type SQLiteReadingSessionRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteReadingSessionRepository(db *sql.DB) *SQLiteReadingSessionRepository {
+	return &SQLiteReadingSessionRepository{DB: db}
+}
+
+// Save implements domain.ReadingSessionRepository.Save
+func (r *SQLiteReadingSessionRepository) Save(session *domain.ReadingSession) error {
+	rec := readingSessionToRecord(session)
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO reading_sessions (`+readingSessionColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				book_id = excluded.book_id,
+				date = excluded.date,
+				duration = excluded.duration,
+				unit = excluded.unit,
+				start_pos = excluded.start_pos,
+				end_pos = excluded.end_pos,
+				total_pages = excluded.total_pages,
+				note = excluded.note,
+				created_at = excluded.created_at`,
+			rec.ID, rec.BookID, rec.Date, rec.Duration, rec.Unit, session.Start, session.End, session.TotalPages, rec.Note, rec.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save reading session: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteReadingSessionRepository) FindAll(limit, offset int) ([]*domain.ReadingSession, error) {
+	rows, err := r.DB.Query(`SELECT `+readingSessionColumns+` FROM reading_sessions ORDER BY rowid LIMIT ? OFFSET ?`,
+		sqliteLimit(limit), sqliteOffset(offset))
+	if err != nil {
+		return nil, err
+	}
+	return collectReadingSessions(rows)
+}
+
+// FindByID implements domain.ReadingSessionRepository.FindByID
+func (r *SQLiteReadingSessionRepository) FindByID(id domain.ReadingSessionID) (*domain.ReadingSession, error) {
+	row := r.DB.QueryRow(`SELECT `+readingSessionColumns+` FROM reading_sessions WHERE id = ?`, id.String())
+	session, err := scanReadingSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return session, err
+}
+
+// FindByBookID implements domain.ReadingSessionRepository.FindByBookID
+func (r *SQLiteReadingSessionRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.ReadingSession, error) {
+	rows, err := r.DB.Query(`SELECT `+readingSessionColumns+` FROM reading_sessions WHERE book_id = ? ORDER BY rowid`, bookID.String())
+	if err != nil {
+		return nil, err
+	}
+	return collectReadingSessions(rows)
+}
+
+// Delete implements domain.ReadingSessionRepository.Delete
+func (r *SQLiteReadingSessionRepository) Delete(id domain.ReadingSessionID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM reading_sessions WHERE id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to delete reading session: %w", err)
+		}
+		return nil
+	})
+}
+
+func collectReadingSessions(rows *sql.Rows) ([]*domain.ReadingSession, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var sessions []*domain.ReadingSession
+	for rows.Next() {
+		session, err := scanReadingSession(rows)
+		if err != nil {
+			slog.Error("Failed to convert reading session record", "err", err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func scanReadingSession(s rowScanner) (*domain.ReadingSession, error) {
+	var rec ReadingSessionRecord
+	if err := s.Scan(&rec.ID, &rec.BookID, &rec.Date, &rec.Duration, &rec.Unit, &rec.Start, &rec.End, &rec.TotalPages, &rec.Note, &rec.CreatedAt); err != nil {
+		return nil, err
+	}
+	return recordToReadingSession(&rec)
+}
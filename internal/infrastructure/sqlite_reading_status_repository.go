@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"fmt"
+	"log/slog"
+)
+
+const readingStatusColumns = "book_id, from_status, to_status, changed_at"
+
+// SQLiteReadingStatusRepository implements domain.ReadingStatusRepository using SQLite.
+// Changes are kept in insertion (rowid) order, so the latest row of a bibliography
+// holds its current status.
+type SQLiteReadingStatusRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteReadingStatusRepository(db *sql.DB) *SQLiteReadingStatusRepository {
+	return &SQLiteReadingStatusRepository{DB: db}
+}
+
+// Save implements domain.ReadingStatusRepository.Save
+func (r *SQLiteReadingStatusRepository) Save(change *domain.ReadingStatusChange) error {
+	rec := readingStatusChangeToRecord(change)
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO reading_status_changes (`+readingStatusColumns+`) VALUES (?, ?, ?, ?)`,
+			rec.BookID, rec.From, rec.To, rec.ChangedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save reading status: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByBookID implements domain.ReadingStatusRepository.FindByBookID
+func (r *SQLiteReadingStatusRepository) FindByBookID(bookID domain.BibliographyID) (domain.ReadingHistory, error) {
+	rows, err := r.DB.Query(`SELECT `+readingStatusColumns+` FROM reading_status_changes WHERE book_id = ? ORDER BY rowid`, bookID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var history domain.ReadingHistory
+	for rows.Next() {
+		var rec ReadingStatusRecord
+		if err := rows.Scan(&rec.BookID, &rec.From, &rec.To, &rec.ChangedAt); err != nil {
+			return nil, err
+		}
+		change, err := recordToReadingStatusChange(&rec)
+		if err != nil {
+			slog.Error("Failed to convert reading status record", "err", err)
+			continue
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+// FindCurrent implements domain.ReadingStatusRepository.FindCurrent
+func (r *SQLiteReadingStatusRepository) FindCurrent() (map[domain.BibliographyID]domain.ReadingStatus, error) {
+	rows, err := r.DB.Query(`SELECT book_id, to_status FROM reading_status_changes
+		WHERE rowid IN (SELECT max(rowid) FROM reading_status_changes GROUP BY book_id)`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	current := make(map[domain.BibliographyID]domain.ReadingStatus)
+	for rows.Next() {
+		var bookIDStr, statusStr string
+		if err := rows.Scan(&bookIDStr, &statusStr); err != nil {
+			return nil, err
+		}
+		bookID, err := domain.ParseBibliographyID(bookIDStr)
+		if err != nil {
+			slog.Error("Failed to convert reading status record", "err", err)
+			continue
+		}
+		status, err := domain.ParseReadingStatus(statusStr)
+		if err != nil {
+			slog.Error("Failed to convert reading status record", "err", err)
+			continue
+		}
+		current[bookID] = status
+	}
+	return current, rows.Err()
+}
+
+// DeleteByBookID implements domain.ReadingStatusRepository.DeleteByBookID
+func (r *SQLiteReadingStatusRepository) DeleteByBookID(bookID domain.BibliographyID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM reading_status_changes WHERE book_id = ?`, bookID.String()); err != nil {
+			return fmt.Errorf("failed to delete reading status: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,525 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := OpenSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return db
+}
+
+func TestSQLiteBibliographyRepository_SaveAndFind(t *testing.T) {
+	repo := NewSQLiteBibliographyRepository(newTestSQLiteDB(t))
+
+	bib := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56TEST",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Test Book",
+		Author:        "Test Author",
+		Publisher:     "Test Publisher",
+		ISBN:          domain.MustParseISBN("123456789X"),
+		PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := repo.Save(bib); err != nil {
+		t.Fatalf("Failed to save bibliography: %v", err)
+	}
+
+	found, err := repo.FindByBibIndex("B56TEST")
+	if err != nil {
+		t.Fatalf("Failed to find by index: %v", err)
+	}
+	if found == nil || found.ID != bib.ID {
+		t.Fatalf("Expected to find bibliography %v by index, got %v", bib.ID, found)
+	}
+	if !found.PublishedDate.Equal(bib.PublishedDate) {
+		t.Errorf("Expected PublishedDate %v, got %v", bib.PublishedDate, found.PublishedDate)
+	}
+
+	// Updating keeps a single row
+	bib.Title = "Updated Title"
+	if err := repo.Save(bib); err != nil {
+		t.Fatalf("Failed to update bibliography: %v", err)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to find all: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("Expected 1 bibliography, got %d", len(all))
+	}
+	if all[0].Title != "Updated Title" {
+		t.Errorf("Expected updated title, got %s", all[0].Title)
+	}
+
+	// Not found returns nil without error
+	missing, err := repo.FindByID(domain.NewBibliographyID())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for missing bibliography, got %v", missing)
+	}
+}
+
+func TestSQLiteBibliographyRepository_FindAllPagination(t *testing.T) {
+	repo := NewSQLiteBibliographyRepository(newTestSQLiteDB(t))
+
+	var ids []domain.BibliographyID
+	for i := 0; i < 5; i++ {
+		bib := &domain.Bibliography{
+			ID:            domain.NewBibliographyID(),
+			BibIndex:      fmt.Sprintf("B56X%d", i),
+			Code:          "B56",
+			Type:          "Book",
+			Title:         "Title",
+			Author:        "Author",
+			PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Save(bib); err != nil {
+			t.Fatalf("Failed to save bibliography: %v", err)
+		}
+		ids = append(ids, bib.ID)
+	}
+
+	page, err := repo.FindAll(2, 1)
+	if err != nil {
+		t.Fatalf("Failed to find all: %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("Expected 2 bibliographies, got %d", len(page))
+	}
+	// Insertion order is preserved
+	if page[0].ID != ids[1] || page[1].ID != ids[2] {
+		t.Errorf("Expected insertion order to be preserved")
+	}
+}
+
+func TestSQLiteClassificationRepository_SaveAndFind(t *testing.T) {
+	repo := NewSQLiteClassificationRepository(newTestSQLiteDB(t))
+
+	for _, class := range []*domain.Classification{
+		{ID: domain.NewClassificationID(), Code: "56", Name: "Technology"},
+		{ID: domain.NewClassificationID(), Code: "007", Name: "情報科学"},
+		{ID: domain.NewClassificationID(), Code: "547.48", Name: "情報通信"},
+	} {
+		if err := repo.Save(class); err != nil {
+			t.Fatalf("Failed to save classification: %v", err)
+		}
+	}
+
+	found, err := repo.FindByCode("56")
+	if err != nil {
+		t.Fatalf("Failed to find by code: %v", err)
+	}
+	if found == nil || found.Name != "Technology" {
+		t.Fatalf("Expected to find classification, got %v", found)
+	}
+	// Leading zeros and decimals are kept as written
+	for code, name := range map[domain.ClassCode]string{"007": "情報科学", "547.48": "情報通信"} {
+		if found, err := repo.FindByCode(code); err != nil || found == nil || found.Name != name {
+			t.Errorf("FindByCode(%s) = %v, %v", code, found, err)
+		}
+	}
+	if found, _ := repo.FindByCode("7"); found != nil {
+		t.Errorf("Expected 7 not to match 007, got %v", found)
+	}
+
+	missing, err := repo.FindByCode("99")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for missing classification, got %v", missing)
+	}
+}
+
+func TestSQLiteReviewRepository_ForeignKey(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	reviewRepo := NewSQLiteReviewRepository(db)
+
+	// Review for an unknown bibliography is rejected
+	orphan := &domain.Review{
+		ID:        domain.NewReviewID(),
+		BookID:    domain.NewBibliographyID(),
+		Goals:     "Goals",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := reviewRepo.Save(orphan); err == nil {
+		t.Fatal("Expected foreign key violation for unknown bibliography, got nil")
+	}
+
+	bib := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56TEST",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Test Book",
+		Author:        "Test Author",
+		PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := bibRepo.Save(bib); err != nil {
+		t.Fatalf("Failed to save bibliography: %v", err)
+	}
+
+	review := &domain.Review{
+		ID:        domain.NewReviewID(),
+		BookID:    bib.ID,
+		Goals:     "一行目\n二行目",
+		Summary:   "  preserved  ",
+		CreatedAt: time.Now().Truncate(time.Second),
+		UpdatedAt: time.Now().Truncate(time.Second),
+	}
+	if err := reviewRepo.Save(review); err != nil {
+		t.Fatalf("Failed to save review: %v", err)
+	}
+
+	reviews, err := reviewRepo.FindByBookID(bib.ID)
+	if err != nil {
+		t.Fatalf("Failed to find by book ID: %v", err)
+	}
+	if len(reviews) != 1 {
+		t.Fatalf("Expected 1 review, got %d", len(reviews))
+	}
+	if reviews[0].Goals != review.Goals || reviews[0].Summary != review.Summary {
+		t.Errorf("Expected text to be preserved, got %q / %q", reviews[0].Goals, reviews[0].Summary)
+	}
+}
+
+func TestSQLiteBibliographyRepository_DeleteWithReviews(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	reviewRepo := NewSQLiteReviewRepository(db)
+
+	bib := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56TEST",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Test Book",
+		Author:        "Test Author",
+		PublishedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := bibRepo.Save(bib); err != nil {
+		t.Fatalf("Failed to save bibliography: %v", err)
+	}
+	review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "Goals", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := reviewRepo.Save(review); err != nil {
+		t.Fatalf("Failed to save review: %v", err)
+	}
+
+	// Referenced bibliography cannot be deleted
+	if err := bibRepo.Delete(bib.ID); err == nil {
+		t.Fatal("Expected foreign key violation deleting a reviewed bibliography, got nil")
+	}
+
+	if err := reviewRepo.Delete(review.ID); err != nil {
+		t.Fatalf("Failed to delete review: %v", err)
+	}
+	if err := bibRepo.Delete(bib.ID); err != nil {
+		t.Fatalf("Failed to delete bibliography: %v", err)
+	}
+	found, err := bibRepo.FindByID(bib.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found != nil {
+		t.Error("Expected bibliography to be deleted")
+	}
+}
+
+func TestSQLiteBibliographyRepository_DeleteWithDependents(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	bibRepo := NewSQLiteBibliographyRepository(db)
+	now := time.Now().Truncate(time.Second)
+
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "B56TEST", Code: "B56", Type: "Book", Title: "Test Book", Author: "Test Author", PublishedDate: now}
+	if err := bibRepo.Save(bib); err != nil {
+		t.Fatalf("Failed to save bibliography: %v", err)
+	}
+	review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "Goals", CreatedAt: now, UpdatedAt: now}
+	if err := NewSQLiteReviewRepository(db).Save(review); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSQLiteReviewRevisionRepository(db).Append(&domain.ReviewRevision{ReviewID: review.ID, Number: 1, Goals: "Goals", Action: domain.RevisionCreated, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSQLiteReadingStatusRepository(db).Save(&domain.ReadingStatusChange{BookID: bib.ID, To: domain.ReadingStatusReading, ChangedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSQLiteReadingSessionRepository(db).Save(&domain.ReadingSession{ID: domain.NewReadingSessionID(), BookID: bib.ID, Date: now, Unit: domain.ProgressPages, Start: 0, End: 10, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSQLiteHighlightRepository(db).Save(&domain.Highlight{ID: domain.NewHighlightID(), BookID: bib.ID, Text: "Quote", CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	tables := []string{"bibliographies", "reviews", "review_revisions", "reading_status_changes", "reading_sessions", "highlights"}
+	countRows := func() []int {
+		t.Helper()
+		var counts []int
+		for _, table := range tables {
+			var n int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			counts = append(counts, n)
+		}
+		return counts
+	}
+
+	// A review that is not deleted rolls everything back
+	if err := bibRepo.DeleteWithDependents(bib.ID, false); err == nil {
+		t.Fatal("Expected foreign key violation deleting a reviewed bibliography without its reviews, got nil")
+	}
+	if got, want := countRows(), []int{1, 1, 1, 1, 1, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Row counts of %v after failed delete = %v, want %v", tables, got, want)
+	}
+
+	if err := bibRepo.DeleteWithDependents(bib.ID, true); err != nil {
+		t.Fatalf("DeleteWithDependents() error = %v", err)
+	}
+	if got, want := countRows(), []int{0, 0, 0, 0, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Row counts of %v after delete = %v, want %v", tables, got, want)
+	}
+	if err := bibRepo.DeleteWithDependents(bib.ID, true); err != nil {
+		t.Errorf("DeleteWithDependents() of a missing ID error = %v", err)
+	}
+}
+
+func TestSQLiteBibliographyRepository_NormalizeISBNs(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	for _, index := range []string{"B1", "B2", "B3", "B4", "B5"} {
+		if _, err := db.Exec(`INSERT INTO bibliographies (`+bibliographyColumns+`) VALUES (?, ?, 'B56', 'Book', ?, 'Author', '', ?, '2024-01-01T00:00:00Z', '')`,
+			domain.NewBibliographyID().String(), index, "Title "+index, legacyISBNs[index]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := NewSQLiteBibliographyRepository(db)
+
+	migrations, err := repo.NormalizeISBNs(true, false)
+	if err != nil {
+		t.Fatalf("NormalizeISBNs() error = %v", err)
+	}
+	checkISBNMigrations(t, migrations, false)
+	if found, _ := repo.FindByBibIndex("B1"); found == nil || found.ISBN.Compact() != "9784750356884" {
+		// Hyphenated values load fine; only the stored text differs
+		t.Errorf("Expected B1 to load, got %v", found)
+	}
+
+	migrations, err = repo.NormalizeISBNs(false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkISBNMigrations(t, migrations, true)
+	var stored string
+	if err := db.QueryRow(`SELECT isbn FROM bibliographies WHERE bib_index = 'B2'`).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != "9780306406157" {
+		t.Errorf("Expected stored ISBN 9780306406157, got %q", stored)
+	}
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Errorf("Expected all 5 bibliographies to load, got %d", len(all))
+	}
+}
+
+func TestOpenSQLiteDB_MigratesClassificationCodes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The classifications table as created before decimal codes were supported
+	if _, err := old.Exec(`CREATE TABLE classifications (id TEXT PRIMARY KEY, code_num INTEGER NOT NULL UNIQUE, name TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	ids := []domain.ClassificationID{domain.NewClassificationID(), domain.NewClassificationID()}
+	if _, err := old.Exec(`INSERT INTO classifications VALUES (?, 56, 'Technology'), (?, 16, 'Philosophy')`, ids[0].String(), ids[1].String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := old.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := OpenSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB() error = %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	repo := NewSQLiteClassificationRepository(db)
+	all, err := repo.FindAll(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].ID != ids[0] || all[0].Code != "56" || all[1].Code != "16" {
+		t.Fatalf("Expected migrated classifications in insertion order, got %+v", all)
+	}
+	if err := repo.Save(&domain.Classification{ID: domain.NewClassificationID(), Code: "007", Name: "情報科学"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if found, err := repo.FindByCode("007"); err != nil || found == nil {
+		t.Errorf("FindByCode(007) = %v, %v", found, err)
+	}
+}
+
+func TestOpenSQLiteDB_AddsContributorsColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The bibliographies table as created before contributors were tracked
+	if _, err := old.Exec(`CREATE TABLE bibliographies (
+		id TEXT PRIMARY KEY, bib_index TEXT NOT NULL, code TEXT NOT NULL, type TEXT NOT NULL, title TEXT NOT NULL,
+		author TEXT NOT NULL, publisher TEXT NOT NULL DEFAULT '', isbn TEXT NOT NULL DEFAULT '', published_date TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	id := domain.NewBibliographyID()
+	if _, err := old.Exec(`INSERT INTO bibliographies VALUES (?, 'B56EE03DDD', 'B56', 'Book', 'DDD', 'Eric Evans', '', '', '2003-01-01T00:00:00Z')`, id.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := old.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := OpenSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB() error = %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	repo := NewSQLiteBibliographyRepository(db)
+	bib, err := repo.FindByID(id)
+	if err != nil || bib == nil {
+		t.Fatalf("FindByID() = %v, %v", bib, err)
+	}
+	if len(bib.Contributors) != 1 || bib.Contributors[0].Name != "Eric Evans" {
+		t.Errorf("Expected contributors derived from the author, got %+v", bib.Contributors)
+	}
+
+	bib.Contributors = append(bib.Contributors, domain.Contributor{Name: "山田花子", Role: domain.RoleTranslator, NameEn: "Hanako Yamada"})
+	if err := repo.Save(bib); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := repo.FindByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Contributors) != 2 || reloaded.Contributors[1] != bib.Contributors[1] {
+		t.Errorf("Contributors after round trip = %+v", reloaded.Contributors)
+	}
+}
+
+func TestOpenSQLiteDB_MakesBibIndexUnique(t *testing.T) {
+	tests := []struct {
+		name       string
+		indexes    []string
+		wantUnique bool
+	}{
+		{"distinct BibIndexes", []string{"B56A", "B56B"}, true},
+		{"duplicate BibIndexes are kept", []string{"B56A", "B56A"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "old.db")
+			old, err := OpenSQLiteDB(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The plain index used before BibIndex uniqueness was enforced
+			for _, stmt := range []string{
+				`DROP INDEX idx_bibliographies_bib_index`,
+				`CREATE INDEX idx_bibliographies_bib_index ON bibliographies(bib_index)`,
+			} {
+				if _, err := old.Exec(stmt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, index := range tt.indexes {
+				if _, err := old.Exec(`INSERT INTO bibliographies (`+bibliographyColumns+`) VALUES (?, ?, 'B56', 'Book', 'Title', 'Author', '', '', '2024-01-01T00:00:00Z', '')`,
+					domain.NewBibliographyID().String(), index); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := old.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			db, err := OpenSQLiteDB(path)
+			if err != nil {
+				t.Fatalf("OpenSQLiteDB() error = %v", err)
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					t.Error(err)
+				}
+			}()
+			var unique bool
+			if err := db.QueryRow(`SELECT "unique" FROM pragma_index_list('bibliographies') WHERE name = 'idx_bibliographies_bib_index'`).Scan(&unique); err != nil {
+				t.Fatal(err)
+			}
+			if unique != tt.wantUnique {
+				t.Errorf("unique = %v, want %v", unique, tt.wantUnique)
+			}
+			all, err := NewSQLiteBibliographyRepository(db).FindAll(0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != len(tt.indexes) {
+				t.Errorf("Expected %d bibliographies after migration, got %d", len(tt.indexes), len(all))
+			}
+			if tt.wantUnique {
+				return
+			}
+
+			// Once the duplicates are renamed, the next open makes the index unique
+			all[1].BibIndex += "b"
+			if err := NewSQLiteBibliographyRepository(db).Save(all[1]); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if db, err = OpenSQLiteDB(path); err != nil {
+				t.Fatalf("OpenSQLiteDB() error = %v", err)
+			}
+			if err := db.QueryRow(`SELECT "unique" FROM pragma_index_list('bibliographies') WHERE name = 'idx_bibliographies_bib_index'`).Scan(&unique); err != nil {
+				t.Fatal(err)
+			}
+			if !unique {
+				t.Error("Expected the index to be unique after the duplicates were fixed")
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const reviewColumns = "id, book_id, goals, summary, created_at, updated_at"
+
+// SQLiteReviewRepository implements domain.ReviewRepository using SQLite.
+// reviews.book_id is a foreign key to bibliographies.id, so saving a review
+// for an unknown bibliography fails at the storage level.
+type SQLiteReviewRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteReviewRepository(db *sql.DB) *SQLiteReviewRepository {
+	return &SQLiteReviewRepository{DB: db}
+}
+
+// Save implements domain.ReviewRepository.Save
+func (r *SQLiteReviewRepository) Save(review *domain.Review) error {
+	rec := reviewToRecord(review)
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO reviews (`+reviewColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				book_id = excluded.book_id,
+				goals = excluded.goals,
+				summary = excluded.summary,
+				created_at = excluded.created_at,
+				updated_at = excluded.updated_at`,
+			rec.ID, rec.BookID, rec.Goals, rec.Summary, rec.CreatedAt, rec.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save review: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteReviewRepository) FindAll(limit, offset int) ([]*domain.Review, error) {
+	rows, err := r.DB.Query(`SELECT `+reviewColumns+` FROM reviews ORDER BY rowid LIMIT ? OFFSET ?`,
+		sqliteLimit(limit), sqliteOffset(offset))
+	if err != nil {
+		return nil, err
+	}
+	return collectReviews(rows)
+}
+
+// FindByID implements domain.ReviewRepository.FindByID
+func (r *SQLiteReviewRepository) FindByID(id domain.ReviewID) (*domain.Review, error) {
+	row := r.DB.QueryRow(`SELECT `+reviewColumns+` FROM reviews WHERE id = ?`, id.String())
+	rev, err := scanReview(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return rev, err
+}
+
+func (r *SQLiteReviewRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.Review, error) {
+	rows, err := r.DB.Query(`SELECT `+reviewColumns+` FROM reviews WHERE book_id = ? ORDER BY rowid`, bookID.String())
+	if err != nil {
+		return nil, err
+	}
+	return collectReviews(rows)
+}
+
+// Delete implements domain.ReviewRepository.Delete
+func (r *SQLiteReviewRepository) Delete(id domain.ReviewID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM reviews WHERE id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		return nil
+	})
+}
+
+func collectReviews(rows *sql.Rows) ([]*domain.Review, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var reviews []*domain.Review
+	for rows.Next() {
+		rev, err := scanReview(rows)
+		if err != nil {
+			slog.Error("Failed to convert review record", "err", err)
+			continue
+		}
+		reviews = append(reviews, rev)
+	}
+	return reviews, rows.Err()
+}
+
+func scanReview(s rowScanner) (*domain.Review, error) {
+	var rec ReviewRecord
+	if err := s.Scan(&rec.ID, &rec.BookID, &rec.Goals, &rec.Summary, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return recordToReview(&rec)
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"bibliography_log/internal/domain"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const reviewRevisionColumns = "review_id, revision, goals, summary, action, reverted_to, changed_by, created_at"
+
+// SQLiteReviewRevisionRepository implements domain.ReviewRevisionRepository using SQLite.
+type SQLiteReviewRevisionRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteReviewRevisionRepository(db *sql.DB) *SQLiteReviewRevisionRepository {
+	return &SQLiteReviewRevisionRepository{DB: db}
+}
+
+// Append implements domain.ReviewRevisionRepository.Append
+// The revision number must follow the latest one of the review, so that two processes
+// updating the same review cannot both record the same revision.
+func (r *SQLiteReviewRevisionRepository) Append(revision *domain.ReviewRevision) error {
+	rec := reviewRevisionToRecord(revision)
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		var latest int
+		if err := tx.QueryRow(`SELECT COALESCE(MAX(revision), 0) FROM review_revisions WHERE review_id = ?`, rec.ReviewID).Scan(&latest); err != nil {
+			return fmt.Errorf("failed to find latest revision: %w", err)
+		}
+		if revision.Number != latest+1 {
+			return fmt.Errorf("review %s already has revision %d", revision.ReviewID, revision.Number)
+		}
+		_, err := tx.Exec(`INSERT INTO review_revisions (`+reviewRevisionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			rec.ReviewID, revision.Number, rec.Goals, rec.Summary, rec.Action, revision.RevertedTo, rec.ChangedBy, rec.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save review revision: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByReviewID implements domain.ReviewRevisionRepository.FindByReviewID
+func (r *SQLiteReviewRevisionRepository) FindByReviewID(reviewID domain.ReviewID) (domain.ReviewHistory, error) {
+	rows, err := r.DB.Query(`SELECT `+reviewRevisionColumns+` FROM review_revisions WHERE review_id = ? ORDER BY revision`, reviewID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Error("Failed to close rows", "err", err)
+		}
+	}()
+
+	var history domain.ReviewHistory
+	for rows.Next() {
+		var rec ReviewRevisionRecord
+		var revertedTo int
+		if err := rows.Scan(&rec.ReviewID, &rec.Revision, &rec.Goals, &rec.Summary, &rec.Action, &revertedTo, &rec.ChangedBy, &rec.CreatedAt); err != nil {
+			return nil, err
+		}
+		if revertedTo > 0 {
+			rec.RevertedTo = strconv.Itoa(revertedTo)
+		}
+		revision, err := recordToReviewRevision(&rec)
+		if err != nil {
+			slog.Error("Failed to convert review revision record", "err", err)
+			continue
+		}
+		history = append(history, revision)
+	}
+	return history, rows.Err()
+}
+
+// DeleteByReviewID implements domain.ReviewRevisionRepository.DeleteByReviewID
+func (r *SQLiteReviewRevisionRepository) DeleteByReviewID(reviewID domain.ReviewID) error {
+	return withTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM review_revisions WHERE review_id = ?`, reviewID.String()); err != nil {
+			return fmt.Errorf("failed to delete review revisions: %w", err)
+		}
+		return nil
+	})
+}
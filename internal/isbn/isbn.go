@@ -0,0 +1,121 @@
+// Package isbn parses, validates, converts and hyphenates International Standard Book Numbers.
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize parses an ISBN-10 or ISBN-13, with or without hyphens and spaces and an
+// optional "ISBN" label, validates its check digit and returns it as 13 digits.
+func Normalize(s string) (string, error) {
+	digits := compact(s)
+	switch len(digits) {
+	case 10:
+		if err := validate10(digits); err != nil {
+			return "", err
+		}
+		return To13(digits)
+	case 13:
+		if err := validate13(digits); err != nil {
+			return "", err
+		}
+		return digits, nil
+	default:
+		return "", fmt.Errorf("ISBN %q must have 10 or 13 digits", s)
+	}
+}
+
+// compact strips the label and separators, upper-casing an ISBN-10 check digit "x".
+func compact(s string) string {
+	s = strings.TrimSpace(strings.ToUpper(s))
+	for _, label := range []string{"ISBN-13", "ISBN-10", "ISBN13", "ISBN10", "ISBN"} {
+		if rest, ok := strings.CutPrefix(s, label); ok {
+			s = strings.TrimLeft(rest, ": ")
+			break
+		}
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '-', ' ', '‐', '‑', '‒', '–', '−':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func validate10(digits string) error {
+	for i, r := range digits {
+		if (r < '0' || r > '9') && !(r == 'X' && i == 9) {
+			return fmt.Errorf("ISBN-10 %q contains an invalid character %q", digits, r)
+		}
+	}
+	if want := checkDigit10(digits[:9]); digits[9] != want {
+		return fmt.Errorf("ISBN-10 %s has check digit %c, expected %c", digits, digits[9], want)
+	}
+	return nil
+}
+
+func validate13(digits string) error {
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("ISBN-13 %q contains an invalid character %q", digits, r)
+		}
+	}
+	if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+		return fmt.Errorf("ISBN-13 %s must start with 978 or 979", digits)
+	}
+	if want := checkDigit13(digits[:12]); digits[12] != want {
+		return fmt.Errorf("ISBN-13 %s has check digit %c, expected %c", digits, digits[12], want)
+	}
+	return nil
+}
+
+// checkDigit10 computes the ISBN-10 check digit (mod 11, "X" for 10) of 9 digits.
+func checkDigit10(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// checkDigit13 computes the ISBN-13 (EAN-13) check digit of 12 digits.
+func checkDigit13(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// To13 converts a valid compact ISBN-10 to ISBN-13 by adding the 978 prefix.
+func To13(isbn10 string) (string, error) {
+	if len(isbn10) != 10 {
+		return "", fmt.Errorf("ISBN-10 %q must have 10 digits", isbn10)
+	}
+	body := "978" + isbn10[:9]
+	return body + string(checkDigit13(body)), nil
+}
+
+// To10 converts a compact ISBN-13 to ISBN-10. Only 978-prefixed ISBNs have an ISBN-10 form.
+func To10(isbn13 string) (string, error) {
+	if len(isbn13) != 13 {
+		return "", fmt.Errorf("ISBN-13 %q must have 13 digits", isbn13)
+	}
+	if !strings.HasPrefix(isbn13, "978") {
+		return "", fmt.Errorf("ISBN-13 %s has no ISBN-10 form (only 978 ISBNs do)", isbn13)
+	}
+	body := isbn13[3:12]
+	return body + string(checkDigit10(body)), nil
+}
@@ -0,0 +1,77 @@
+package isbn
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"978-4-7503-5688-4", "9784750356884", false},
+		{"9784750356884", "9784750356884", false},
+		{"978 0 306 40615 7", "9780306406157", false},
+		{"ISBN 978-0-306-40615-7", "9780306406157", false},
+		{"ISBN-13: 978-0-306-40615-7", "9780306406157", false},
+		{"0-306-40615-2", "9780306406157", false},
+		{"4-7503-5688-3", "9784750356884", false},
+		{"0-8044-2957-X", "9780804429573", false},
+		{"080442957x", "9780804429573", false},
+		{"979-10-90636-07-1", "9791090636071", false},
+		{"978-4-7503-5688-5", "", true}, // wrong check digit
+		{"0-306-40615-3", "", true},     // wrong check digit
+		{"977-4-7503-5688-4", "", true}, // not a Bookland prefix
+		{"X-306-40615-2", "", true},     // X only allowed as check digit
+		{"12345", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"9780306406157", "0306406152", false},
+		{"9784750356884", "4750356883", false},
+		{"9780804429573", "080442957X", false},
+		{"9791090636071", "", true},
+	}
+	for _, tt := range tests {
+		got, err := To10(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("To10(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("To10(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTo13RoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "4750356883", "080442957X"} {
+		isbn13, err := To13(isbn10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := To10(isbn13)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back != isbn10 {
+			t.Errorf("To10(To13(%q)) = %q", isbn10, back)
+		}
+	}
+}
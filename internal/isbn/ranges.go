@@ -0,0 +1,200 @@
+package isbn
+
+import (
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// rangeDigits is the width of the windows the range table is defined over.
+const rangeDigits = 7
+
+// rule assigns an element length to a window of the digits following a prefix.
+// A length of 0 marks a range that is not in use.
+type rule struct {
+	low, high int
+	length    int
+}
+
+// Ranges is an ISBN range table: how long the registration group is after each
+// EAN prefix, and how long the registrant is within each registration group.
+type Ranges struct {
+	prefixes map[string][]rule // "978" -> group lengths
+	groups   map[string][]rule // "978-4" -> registrant lengths
+}
+
+// rangeMessage mirrors the parts of the International ISBN Agency's RangeMessage.xml
+// (https://www.isbn-international.org/range_file_generation) that are needed for hyphenation.
+type rangeMessage struct {
+	Prefixes []rangeGroup `xml:"EAN.UCCPrefixes>EAN.UCC"`
+	Groups   []rangeGroup `xml:"RegistrationGroups>Group"`
+}
+
+type rangeGroup struct {
+	Prefix string      `xml:"Prefix"`
+	Rules  []rangeRule `xml:"Rules>Rule"`
+}
+
+type rangeRule struct {
+	Range  string `xml:"Range"`  // e.g. "0000000-1999999"
+	Length int    `xml:"Length"` // e.g. 2
+}
+
+// ParseRangeMessage reads a range table in the RangeMessage.xml format.
+func ParseRangeMessage(r io.Reader) (*Ranges, error) {
+	var msg rangeMessage
+	if err := xml.NewDecoder(r).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("failed to parse ISBN range message: %w", err)
+	}
+	ranges := &Ranges{prefixes: make(map[string][]rule), groups: make(map[string][]rule)}
+	for _, p := range msg.Prefixes {
+		rules, err := parseRules(p)
+		if err != nil {
+			return nil, err
+		}
+		ranges.prefixes[p.Prefix] = rules
+	}
+	for _, g := range msg.Groups {
+		rules, err := parseRules(g)
+		if err != nil {
+			return nil, err
+		}
+		ranges.groups[g.Prefix] = rules
+	}
+	if len(ranges.prefixes) == 0 {
+		return nil, fmt.Errorf("ISBN range message defines no EAN.UCC prefixes")
+	}
+	return ranges, nil
+}
+
+func parseRules(g rangeGroup) ([]rule, error) {
+	rules := make([]rule, 0, len(g.Rules))
+	for _, r := range g.Rules {
+		lowStr, highStr, ok := strings.Cut(r.Range, "-")
+		low, errLow := strconv.Atoi(lowStr)
+		high, errHigh := strconv.Atoi(highStr)
+		if !ok || errLow != nil || errHigh != nil || len(lowStr) != rangeDigits || len(highStr) != rangeDigits {
+			return nil, fmt.Errorf("invalid range %q for prefix %s", r.Range, g.Prefix)
+		}
+		if r.Length < 0 || r.Length > rangeDigits {
+			return nil, fmt.Errorf("invalid length %d for range %s of prefix %s", r.Length, r.Range, g.Prefix)
+		}
+		rules = append(rules, rule{low: low, high: high, length: r.Length})
+	}
+	return rules, nil
+}
+
+// LoadRangesFile reads a RangeMessage.xml file.
+func LoadRangesFile(path string) (*Ranges, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	ranges, err := ParseRangeMessage(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ISBN ranges %s: %w", path, err)
+	}
+	return ranges, nil
+}
+
+// lookup returns the length assigned to the window starting at digits, or 0 if
+// the digits fall outside every defined range.
+func lookup(rules []rule, digits string) int {
+	if len(digits) < rangeDigits {
+		digits += strings.Repeat("0", rangeDigits-len(digits))
+	}
+	value, err := strconv.Atoi(digits[:rangeDigits])
+	if err != nil {
+		return 0
+	}
+	for _, r := range rules {
+		if value >= r.low && value <= r.high {
+			return r.length
+		}
+	}
+	return 0
+}
+
+// Split divides a compact ISBN-13 into prefix, registration group, registrant,
+// publication and check digit. ok is false if the table does not cover the ISBN.
+func (r *Ranges) Split(isbn13 string) (parts [5]string, ok bool) {
+	if len(isbn13) != 13 {
+		return parts, false
+	}
+	prefix, rest := isbn13[:3], isbn13[3:12]
+	groupLen := lookup(r.prefixes[prefix], rest)
+	if groupLen == 0 {
+		return parts, false
+	}
+	group, rest := rest[:groupLen], rest[groupLen:]
+	registrantLen := lookup(r.groups[prefix+"-"+group], rest)
+	if registrantLen == 0 || registrantLen >= len(rest) {
+		return parts, false
+	}
+	return [5]string{prefix, group, rest[:registrantLen], rest[registrantLen:], isbn13[12:]}, true
+}
+
+// Hyphenate formats a compact ISBN-13 as e.g. "978-4-7503-5688-4". ISBNs in ranges
+// the table does not cover are returned unhyphenated.
+func (r *Ranges) Hyphenate(isbn13 string) string {
+	parts, ok := r.Split(isbn13)
+	if !ok {
+		return isbn13
+	}
+	return strings.Join(parts[:], "-")
+}
+
+// Hyphenate10 formats the ISBN-10 form of a compact 978 ISBN-13 as e.g. "4-7503-5688-2".
+func (r *Ranges) Hyphenate10(isbn13 string) (string, error) {
+	isbn10, err := To10(isbn13)
+	if err != nil {
+		return "", err
+	}
+	parts, ok := r.Split(isbn13)
+	if !ok {
+		return isbn10, nil
+	}
+	return strings.Join([]string{parts[1], parts[2], parts[3], isbn10[9:]}, "-"), nil
+}
+
+//go:embed ranges.xml
+var bundledRanges string
+
+// Bundled returns the range table shipped with biblog. It covers the 978 and 979
+// prefixes and the English (978-0, 978-1) and Japanese (978-4) registration groups;
+// load the International ISBN Agency's full RangeMessage.xml for other groups.
+var Bundled = sync.OnceValue(func() *Ranges {
+	ranges, err := ParseRangeMessage(strings.NewReader(bundledRanges))
+	if err != nil {
+		panic(fmt.Sprintf("isbn: invalid bundled range table: %v", err))
+	}
+	return ranges
+})
+
+var defaultRanges atomic.Pointer[Ranges]
+
+// Default returns the table used by Format: the one passed to SetDefault, or Bundled.
+func Default() *Ranges {
+	if r := defaultRanges.Load(); r != nil {
+		return r
+	}
+	return Bundled()
+}
+
+// SetDefault replaces the table used by Format, e.g. with a full RangeMessage.xml.
+func SetDefault(r *Ranges) {
+	defaultRanges.Store(r)
+}
+
+// Format hyphenates a compact ISBN-13 using the default table.
+func Format(isbn13 string) string {
+	return Default().Hyphenate(isbn13)
+}
@@ -0,0 +1,99 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBundledHyphenate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"9784750356884", "978-4-7503-5688-4"},
+		{"9784774196046", "978-4-7741-9604-6"},
+		{"9784000000000", "978-4-00-000000-0"},
+		{"9780306406157", "978-0-306-40615-7"},
+		{"9780321125217", "978-0-321-12521-7"},
+		{"9780804429573", "978-0-8044-2957-3"},
+		{"9781491941195", "978-1-4919-4119-5"},
+		{"9781633430075", "978-1-63343-007-5"},
+		{"9780639800004", "978-0-6398000-0-4"}, // 7-digit registrant
+		{"9783161484100", "9783161484100"},     // group not in the bundled table
+		{"9791090636071", "9791090636071"},
+	}
+	for _, tt := range tests {
+		if got := Bundled().Hyphenate(tt.in); got != tt.want {
+			t.Errorf("Hyphenate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHyphenate10(t *testing.T) {
+	got, err := Bundled().Hyphenate10("9784750356884")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "4-7503-5688-3" {
+		t.Errorf("Hyphenate10() = %q, want 4-7503-5688-3", got)
+	}
+	if _, err := Bundled().Hyphenate10("9791090636071"); err == nil {
+		t.Error("Expected error for a 979 ISBN, got nil")
+	}
+}
+
+const germanRanges = `<ISBNRangeMessage>
+  <EAN.UCCPrefixes>
+    <EAN.UCC><Prefix>978</Prefix><Rules>
+      <Rule><Range>0000000-5999999</Range><Length>1</Length></Rule>
+    </Rules></EAN.UCC>
+  </EAN.UCCPrefixes>
+  <RegistrationGroups>
+    <Group><Prefix>978-3</Prefix><Rules>
+      <Rule><Range>0000000-0299999</Range><Length>2</Length></Rule>
+      <Rule><Range>0300000-0339999</Range><Length>3</Length></Rule>
+      <Rule><Range>0340000-0369999</Range><Length>4</Length></Rule>
+      <Rule><Range>0370000-0399999</Range><Length>5</Length></Rule>
+      <Rule><Range>0400000-1999999</Range><Length>2</Length></Rule>
+      <Rule><Range>2000000-6999999</Range><Length>3</Length></Rule>
+    </Rules></Group>
+  </RegistrationGroups>
+</ISBNRangeMessage>`
+
+func TestParseRangeMessage(t *testing.T) {
+	ranges, err := ParseRangeMessage(strings.NewReader(germanRanges))
+	if err != nil {
+		t.Fatalf("ParseRangeMessage() error = %v", err)
+	}
+	if got := ranges.Hyphenate("9783161484100"); got != "978-3-16-148410-0" {
+		t.Errorf("Hyphenate() = %q, want 978-3-16-148410-0", got)
+	}
+	if got := ranges.Hyphenate("9784750356884"); got != "9784750356884" {
+		t.Errorf("Hyphenate() = %q, want it unhyphenated", got)
+	}
+}
+
+func TestParseRangeMessage_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"not xml":     "{",
+		"no prefixes": "<ISBNRangeMessage></ISBNRangeMessage>",
+		"bad range": `<ISBNRangeMessage><EAN.UCCPrefixes><EAN.UCC><Prefix>978</Prefix><Rules>
+			<Rule><Range>0-5</Range><Length>1</Length></Rule></Rules></EAN.UCC></EAN.UCCPrefixes></ISBNRangeMessage>`,
+	} {
+		if _, err := ParseRangeMessage(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	ranges, err := ParseRangeMessage(strings.NewReader(germanRanges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetDefault(ranges)
+	t.Cleanup(func() { SetDefault(nil) })
+	if got := Format("9783161484100"); got != "978-3-16-148410-0" {
+		t.Errorf("Format() = %q with a custom default", got)
+	}
+}
@@ -0,0 +1,291 @@
+// Package kindle reads the "My Clippings.txt" file that Kindle devices append
+// highlights, notes and bookmarks to.
+//
+// Each clipping is a title line, a metadata line, a blank line and the clipped
+// text, followed by a "==========" separator:
+//
+//	Domain-Driven Design (Evans, Eric)
+//	- Your Highlight on page 12 | Location 180-181 | Added on Monday, February 11, 2019 2:44:09 PM
+//
+//	A model is a selectively simplified and consciously structured form of knowledge.
+//	==========
+//
+// Devices set to Japanese write the metadata line in Japanese, e.g.
+// "- 12ページ|位置No. 180-181のハイライト |作成日: 2019年2月11日月曜日 14:44:09".
+package kindle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of a clipping.
+type Kind string
+
+const (
+	KindHighlight Kind = "highlight"
+	KindNote      Kind = "note"
+	KindBookmark  Kind = "bookmark"
+)
+
+// Clipping is one entry of a clippings file.
+type Clipping struct {
+	Title    string
+	Author   string // as written by the device, e.g. "Evans, Eric"; empty if not given
+	Kind     Kind
+	Page     string // e.g. "12" or "xii"; empty if not given
+	Location string // e.g. "180-181"; empty if not given
+	AddedAt  time.Time
+	Text     string
+	// Note is the text of a note attached to this highlight by AttachNotes.
+	Note string
+}
+
+// separator ends every clipping.
+const separator = "=========="
+
+var (
+	pagePattern     = regexp.MustCompile(`(?i)\bpage\s+([0-9ivxlcdm]+)|([0-9ivxlcdm]+)\s*ページ`)
+	locationPattern = regexp.MustCompile(`(?i)\b(?:location|loc\.)\s*([0-9]+(?:-[0-9]+)?)|位置No\.\s*([0-9]+(?:-[0-9]+)?)`)
+	japaneseDate    = regexp.MustCompile(`(\d{4})年(\d{1,2})月(\d{1,2})日.*?(午前|午後)?\s*(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+)
+
+// englishDateLayouts are the "Added on" formats written by different Kindle generations.
+var englishDateLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, January 2, 2006, 03:04 PM",
+	"Monday, January 2, 2006 15:04:05",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, 2 January 06 15:04:05",
+}
+
+// Parse reads all clippings from r. Times are interpreted in loc, the time zone of
+// the device. Entries that cannot be parsed are skipped; if there are any, the other
+// clippings are returned together with an error listing them.
+func Parse(r io.Reader, loc *time.Location) ([]Clipping, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		clippings []Clipping
+		errs      []error
+		entry     []string
+		startLine = 1
+		lineNo    = 0
+	)
+	flush := func() {
+		if len(entry) > 0 && strings.TrimSpace(strings.Join(entry, "")) != "" {
+			c, err := parseEntry(entry, loc)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", startLine, err))
+			} else {
+				clippings = append(clippings, c)
+			}
+		}
+		entry = nil
+		startLine = lineNo + 1
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.TrimSpace(line) == separator {
+			flush()
+			continue
+		}
+		entry = append(entry, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return clippings, errors.Join(errs...)
+}
+
+func parseEntry(lines []string, loc *time.Location) (Clipping, error) {
+	// A previous entry may have left blank lines or a byte order mark behind
+	for len(lines) > 0 && strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff")) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return Clipping{}, fmt.Errorf("clipping has no metadata line")
+	}
+
+	var c Clipping
+	c.Title, c.Author = splitTitleAuthor(strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff")))
+	if err := parseMetadata(&c, lines[1], loc); err != nil {
+		return Clipping{}, err
+	}
+	c.Text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	return c, nil
+}
+
+// splitTitleAuthor splits "Title (Author)" at the last balanced parenthesis group,
+// which may use full-width parentheses.
+func splitTitleAuthor(line string) (title, author string) {
+	runes := []rune(line)
+	n := len(runes)
+	if n == 0 || (runes[n-1] != ')' && runes[n-1] != '）') {
+		return line, ""
+	}
+	depth := 0
+	for i := n - 1; i >= 0; i-- {
+		switch runes[i] {
+		case ')', '）':
+			depth++
+		case '(', '（':
+			depth--
+			if depth == 0 {
+				title = strings.TrimSpace(string(runes[:i]))
+				if title == "" {
+					return line, ""
+				}
+				return title, strings.TrimSpace(string(runes[i+1 : n-1]))
+			}
+		}
+	}
+	return line, ""
+}
+
+func parseMetadata(c *Clipping, line string, loc *time.Location) error {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "-") {
+		return fmt.Errorf("metadata line %q does not start with '-'", line)
+	}
+
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "highlight") || strings.Contains(line, "ハイライト"):
+		c.Kind = KindHighlight
+	case strings.Contains(lower, "note") || strings.Contains(line, "メモ"):
+		c.Kind = KindNote
+	case strings.Contains(lower, "bookmark") || strings.Contains(line, "ブックマーク"):
+		c.Kind = KindBookmark
+	default:
+		return fmt.Errorf("unknown clipping type in %q", line)
+	}
+
+	for _, part := range strings.Split(line, "|") {
+		part = strings.TrimSpace(part)
+		if m := pagePattern.FindStringSubmatch(part); m != nil && c.Page == "" {
+			c.Page = m[1] + m[2]
+		}
+		if m := locationPattern.FindStringSubmatch(part); m != nil && c.Location == "" {
+			c.Location = m[1] + m[2]
+		}
+		if _, date, ok := strings.Cut(part, "Added on "); ok {
+			c.AddedAt = parseEnglishDate(strings.TrimSpace(date), loc)
+		} else if m := japaneseDate.FindStringSubmatch(part); m != nil {
+			c.AddedAt = parseJapaneseDate(m, loc)
+		}
+	}
+	return nil
+}
+
+func parseEnglishDate(s string, loc *time.Location) time.Time {
+	for _, layout := range englishDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseJapaneseDate converts a japaneseDate match such as "2019年2月11日月曜日 午後2:44:09".
+func parseJapaneseDate(m []string, loc *time.Location) time.Time {
+	n := make([]int, 7)
+	for i, idx := range []int{1, 2, 3, 5, 6, 7} {
+		n[i], _ = strconv.Atoi(m[idx]) // the pattern only matches digits; seconds may be empty
+	}
+	year, month, day, hour, minute, second := n[0], n[1], n[2], n[3], n[4], n[5]
+	switch {
+	case m[4] == "午後" && hour < 12:
+		hour += 12
+	case m[4] == "午前" && hour == 12:
+		hour = 0
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+}
+
+// LocationRange returns the first and last location of the clipping. Old devices
+// abbreviate the end, e.g. "1180-95" for 1180-1195.
+func (c Clipping) LocationRange() (start, end int, ok bool) {
+	first, last, hasEnd := strings.Cut(c.Location, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !hasEnd {
+		return start, start, true
+	}
+	if len(last) < len(first) {
+		last = first[:len(first)-len(last)] + last
+	}
+	end, err = strconv.Atoi(last)
+	if err != nil || end < start {
+		return start, start, true
+	}
+	return start, end, true
+}
+
+// Label describes where the clipping is in the book, e.g. "p. 12, loc. 180-181".
+func (c Clipping) Label() string {
+	var parts []string
+	if c.Page != "" {
+		parts = append(parts, "p. "+c.Page)
+	}
+	if c.Location != "" {
+		parts = append(parts, "loc. "+c.Location)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AttachNotes moves each note onto the highlight it was written on: the latest
+// earlier highlight of the same book whose location range contains the note's
+// location. Notes without such a highlight are kept as clippings of their own;
+// bookmarks, which carry no text, are dropped.
+func AttachNotes(clippings []Clipping) []Clipping {
+	var result []Clipping
+	for _, c := range clippings {
+		switch c.Kind {
+		case KindBookmark:
+			continue
+		case KindNote:
+			if i := findHighlightFor(result, c); i >= 0 {
+				if result[i].Note != "" {
+					result[i].Note += "\n\n"
+				}
+				result[i].Note += c.Text
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func findHighlightFor(clippings []Clipping, note Clipping) int {
+	at, _, ok := note.LocationRange()
+	if !ok {
+		return -1
+	}
+	for i := len(clippings) - 1; i >= 0; i-- {
+		h := clippings[i]
+		if h.Kind != KindHighlight || h.Title != note.Title || h.Author != note.Author {
+			continue
+		}
+		if start, end, ok := h.LocationRange(); ok && start <= at && at <= end {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,147 @@
+package kindle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := "\ufeffDomain-Driven Design: Tackling Complexity in the Heart of Software (Evans, Eric)\r\n" +
+		"- Your Highlight on page 12 | Location 180-181 | Added on Monday, February 11, 2019 2:44:09 PM\r\n" +
+		"\r\n" +
+		"A model is a selectively simplified and consciously structured form of knowledge.\r\n" +
+		"==========\r\n" +
+		"Domain-Driven Design: Tackling Complexity in the Heart of Software (Evans, Eric)\r\n" +
+		"- Your Note on page 12 | Location 181 | Added on Monday, February 11, 2019 2:45:00 PM\r\n" +
+		"\r\n" +
+		"Compare with the map analogy\r\n" +
+		"==========\r\n" +
+		"Refactoring (Fowler, Martin)\r\n" +
+		"- Your Bookmark on Location 70 | Added on Tuesday, 12 February 2019 09:01:02\r\n" +
+		"\r\n" +
+		"\r\n" +
+		"==========\r\n" +
+		"ドメイン駆動設計 (翻訳版) （エリック・エヴァンス）\r\n" +
+		"- 12ページ|位置No. 1180-95のハイライト |作成日: 2019年2月11日月曜日 午後2:44:09\r\n" +
+		"\r\n" +
+		"モデルとは、\r\n知識を選択的に単純化したものだ。\r\n" +
+		"==========\r\n" +
+		"ドメイン駆動設計 (翻訳版) （エリック・エヴァンス）\r\n" +
+		"- 位置No. 1190のメモ |作成日: 2019年2月11日 月曜日 14:50\r\n" +
+		"\r\n" +
+		"地図のたとえ\r\n" +
+		"==========\r\n"
+
+	clippings, err := Parse(strings.NewReader(input), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(clippings) != 5 {
+		t.Fatalf("Expected 5 clippings, got %d: %+v", len(clippings), clippings)
+	}
+
+	tests := []struct {
+		title, author, page, location, text string
+		kind                                Kind
+		added                               time.Time
+	}{
+		{"Domain-Driven Design: Tackling Complexity in the Heart of Software", "Evans, Eric", "12", "180-181",
+			"A model is a selectively simplified and consciously structured form of knowledge.", KindHighlight, time.Date(2019, 2, 11, 14, 44, 9, 0, time.UTC)},
+		{"Domain-Driven Design: Tackling Complexity in the Heart of Software", "Evans, Eric", "12", "181",
+			"Compare with the map analogy", KindNote, time.Date(2019, 2, 11, 14, 45, 0, 0, time.UTC)},
+		{"Refactoring", "Fowler, Martin", "", "70", "", KindBookmark, time.Date(2019, 2, 12, 9, 1, 2, 0, time.UTC)},
+		{"ドメイン駆動設計 (翻訳版)", "エリック・エヴァンス", "12", "1180-95",
+			"モデルとは、\n知識を選択的に単純化したものだ。", KindHighlight, time.Date(2019, 2, 11, 14, 44, 9, 0, time.UTC)},
+		{"ドメイン駆動設計 (翻訳版)", "エリック・エヴァンス", "", "1190", "地図のたとえ", KindNote, time.Date(2019, 2, 11, 14, 50, 0, 0, time.UTC)},
+	}
+	for i, tt := range tests {
+		c := clippings[i]
+		if c.Title != tt.title || c.Author != tt.author || c.Page != tt.page || c.Location != tt.location || c.Text != tt.text || c.Kind != tt.kind {
+			t.Errorf("clipping %d = %+v, want %+v", i, c, tt)
+		}
+		if !c.AddedAt.Equal(tt.added) {
+			t.Errorf("clipping %d added at %v, want %v", i, c.AddedAt, tt.added)
+		}
+	}
+}
+
+func TestParse_SkipsMalformedEntries(t *testing.T) {
+	input := "Book (Author)\n" +
+		"no metadata here\n" +
+		"==========\n" +
+		"Book (Author)\n" +
+		"- Your Highlight on Location 10-12 | Added on Monday, February 11, 2019 2:44:09 PM\n" +
+		"\n" +
+		"kept\n" +
+		"==========\n"
+
+	clippings, err := Parse(strings.NewReader(input), time.UTC)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected an error for the entry at line 1, got %v", err)
+	}
+	if len(clippings) != 1 || clippings[0].Text != "kept" {
+		t.Errorf("Expected the valid clipping to be returned, got %+v", clippings)
+	}
+}
+
+func TestSplitTitleAuthor(t *testing.T) {
+	tests := []struct {
+		line, title, author string
+	}{
+		{"Refactoring (Fowler, Martin)", "Refactoring", "Fowler, Martin"},
+		{"Clean Code (Robert C. Martin (Uncle Bob))", "Clean Code", "Robert C. Martin (Uncle Bob)"},
+		{"No Author Here", "No Author Here", ""},
+		{"(Only Parens)", "(Only Parens)", ""},
+		{"吾輩は猫である （夏目漱石）", "吾輩は猫である", "夏目漱石"},
+	}
+	for _, tt := range tests {
+		title, author := splitTitleAuthor(tt.line)
+		if title != tt.title || author != tt.author {
+			t.Errorf("splitTitleAuthor(%q) = %q, %q; want %q, %q", tt.line, title, author, tt.title, tt.author)
+		}
+	}
+}
+
+func TestClipping_LocationRange(t *testing.T) {
+	tests := []struct {
+		location   string
+		start, end int
+		ok         bool
+	}{
+		{"180-181", 180, 181, true},
+		{"1180-95", 1180, 1195, true},
+		{"70", 70, 70, true},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, end, ok := Clipping{Location: tt.location}.LocationRange()
+		if start != tt.start || end != tt.end || ok != tt.ok {
+			t.Errorf("LocationRange(%q) = %d, %d, %v; want %d, %d, %v", tt.location, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestAttachNotes(t *testing.T) {
+	clippings := []Clipping{
+		{Title: "A", Kind: KindHighlight, Location: "100-110", Text: "first"},
+		{Title: "B", Kind: KindHighlight, Location: "100-110", Text: "other book"},
+		{Title: "A", Kind: KindNote, Location: "110", Text: "note on first"},
+		{Title: "A", Kind: KindBookmark, Location: "120"},
+		{Title: "A", Kind: KindNote, Location: "300", Text: "standalone"},
+		{Title: "A", Kind: KindNote, Location: "105", Text: "second note"},
+	}
+	got := AttachNotes(clippings)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 clippings, got %d: %+v", len(got), got)
+	}
+	if got[0].Note != "note on first\n\nsecond note" || got[1].Note != "" {
+		t.Errorf("Unexpected notes %q, %q", got[0].Note, got[1].Note)
+	}
+	if got[2].Kind != KindNote || got[2].Text != "standalone" {
+		t.Errorf("Expected the unmatched note to be kept, got %+v", got[2])
+	}
+	if got := (Clipping{Page: "12", Location: "180-181"}).Label(); got != "p. 12, loc. 180-181" {
+		t.Errorf("Label() = %q", got)
+	}
+}
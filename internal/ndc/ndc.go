@@ -0,0 +1,64 @@
+// Package ndc bundles the top levels of the Nippon Decimal Classification (NDC,
+// 日本十進分類法), 10th edition: the ten main classes (類, e.g. "5" 技術. 工学) and
+// the hundred divisions (綱, e.g. "54" 電気工学).
+package ndc
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Class is a class of the NDC.
+type Class struct {
+	Code string // "5" for a main class, "54" for a division
+	Name string
+}
+
+// Depth is the number of digits of the code: 1 for a main class, 2 for a division.
+func (c Class) Depth() int {
+	return len(c.Code)
+}
+
+//go:embed ndc.tsv
+var bundledTSV string
+
+var bundled = sync.OnceValue(func() []Class {
+	classes, err := parse(bundledTSV)
+	if err != nil {
+		panic(fmt.Sprintf("ndc: invalid bundled classes: %v", err))
+	}
+	return classes
+})
+
+// Classes returns the classes down to depth (1 for the main classes only, 2 to include
+// the divisions), each main class followed by its divisions.
+func Classes(depth int) []Class {
+	var classes []Class
+	for _, c := range bundled() {
+		if c.Depth() <= depth {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+// parse reads "code<TAB>name" lines; blank lines and lines starting with '#' are skipped.
+func parse(tsv string) ([]Class, error) {
+	var classes []Class
+	scanner := bufio.NewScanner(strings.NewReader(tsv))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, name, ok := strings.Cut(line, "\t")
+		if !ok || code == "" || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("line %d: expected code and name separated by a tab", lineNo)
+		}
+		classes = append(classes, Class{Code: code, Name: strings.TrimSpace(name)})
+	}
+	return classes, scanner.Err()
+}
@@ -0,0 +1,50 @@
+package ndc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClasses(t *testing.T) {
+	main := Classes(1)
+	if len(main) != 10 {
+		t.Fatalf("Expected 10 main classes, got %d", len(main))
+	}
+	if main[5] != (Class{Code: "5", Name: "技術. 工学"}) {
+		t.Errorf("Unexpected main class 5: %+v", main[5])
+	}
+
+	all := Classes(2)
+	if len(all) != 110 {
+		t.Fatalf("Expected 110 classes, got %d", len(all))
+	}
+	seen := make(map[string]bool)
+	for i, c := range all {
+		if seen[c.Code] {
+			t.Errorf("Duplicate code %s", c.Code)
+		}
+		seen[c.Code] = true
+		for _, r := range c.Code {
+			if r < '0' || r > '9' {
+				t.Errorf("Invalid code %q", c.Code)
+			}
+		}
+		// Every division follows its main class
+		if c.Depth() == 2 && !seen[c.Code[:1]] {
+			t.Errorf("Division %s (index %d) comes before its main class", c.Code, i)
+		}
+	}
+	if !seen["54"] || !seen["00"] || !seen["99"] {
+		t.Error("Expected divisions 00 to 99")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := parse("# comment\n5\n"); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected error for line 2, got %v", err)
+	}
+	classes, err := parse("# comment\n\n5\t技術\n")
+	if err != nil || len(classes) != 1 {
+		t.Errorf("parse() = %v, %v", classes, err)
+	}
+}
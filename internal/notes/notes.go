@@ -0,0 +1,272 @@
+// Package notes writes bibliographies as Markdown literature notes for note-taking tools
+// such as Obsidian, updates them without losing what was written by hand, and reads
+// edited reviews back.
+//
+// A note starts with a generated part, YAML front matter followed by the reviews, and
+// ends it with EndMarker. Everything after the marker belongs to the user:
+//
+//	---
+//	title: "ドメイン駆動設計入門"
+//	year: 2020
+//	---
+//
+//	# ドメイン駆動設計入門
+//
+//	<!-- biblog:review 7b758c17-0c48-4d95-9f80-19c551240c09 -->
+//	## Goals
+//
+//	設計を学ぶ
+//
+//	## Summary
+//
+//	...
+//
+//	<!-- biblog:end - notes below this line are kept when the note is exported again -->
+//
+//	Hand-written notes.
+package notes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EndMarker ends the generated part of a note.
+const EndMarker = "<!-- biblog:end - notes below this line are kept when the note is exported again -->"
+
+// endMarkerPrefix identifies the marker even if the user edited its explanation.
+const endMarkerPrefix = "<!-- biblog:end"
+
+// reviewMarker introduces the sections of one review, with its ID.
+const reviewMarker = "<!-- biblog:review %s -->"
+
+// reviewMarkerLine matches reviewMarker.
+var reviewMarkerLine = regexp.MustCompile(`^<!-- biblog:review (\S+) -->$`)
+
+// ErrNoMarker is returned by Merge for notes without EndMarker, whose generated part
+// cannot be told apart from the user's notes.
+var ErrNoMarker = errors.New("no biblog:end marker")
+
+// Note is the generated part of a literature note.
+type Note struct {
+	Title   string
+	Fields  []Field // front matter, in order
+	Reviews []Review
+}
+
+// Field is a front matter field. Value is a string or an int; zero values are left out.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Review is the text of one review.
+type Review struct {
+	ID      string
+	Goals   string
+	Summary string
+}
+
+// Render formats the generated part of n, ending with EndMarker and a newline.
+func Render(n Note) []byte {
+	return append(RenderDocument(n), EndMarker+"\n"...)
+}
+
+// RenderDocument formats n like Render but without EndMarker, as a document to be edited
+// and read back with Parse.
+func RenderDocument(n Note) []byte {
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	for _, f := range n.Fields {
+		if value, ok := formatValue(f.Value); ok {
+			fmt.Fprintf(&b, "%s: %s\n", f.Key, value)
+		}
+	}
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s\n\n", oneLine(n.Title))
+	for _, r := range n.Reviews {
+		fmt.Fprintf(&b, reviewMarker+"\n", r.ID)
+		// An empty Summary keeps its heading, so that it can be written in the note
+		writeSection(&b, "Goals", r.Goals)
+		writeSection(&b, "Summary", r.Summary)
+	}
+	return b.Bytes()
+}
+
+func writeSection(b *bytes.Buffer, heading, text string) {
+	fmt.Fprintf(b, "## %s\n\n", heading)
+	if text = strings.TrimSpace(text); text != "" {
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+}
+
+// formatValue writes a string as a double-quoted YAML scalar, which JSON strings are,
+// and an int as is.
+func formatValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return "", false
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(v) // a string always encodes
+		return strings.TrimSuffix(buf.String(), "\n"), true
+	case int:
+		return fmt.Sprint(v), v != 0
+	default:
+		panic(fmt.Sprintf("notes: unsupported front matter value %T", v))
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Parse reads the reviews of a note written by Render or RenderDocument: the "## Goals"
+// and "## Summary" sections after each review marker, up to the next marker. Text
+// before the first review marker and after EndMarker is ignored, as are other headings,
+// which are kept as part of the section they are in. Sections that were removed read
+// as empty.
+func Parse(data []byte) ([]Review, error) {
+	var reviews []Review
+	var section *[]string
+	var goals, summary []string
+	finish := func() {
+		if len(reviews) > 0 {
+			last := &reviews[len(reviews)-1]
+			last.Goals = strings.TrimSpace(strings.Join(goals, "\n"))
+			last.Summary = strings.TrimSpace(strings.Join(summary, "\n"))
+		}
+		section, goals, summary = nil, nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, endMarkerPrefix) {
+			break
+		}
+		if m := reviewMarkerLine.FindStringSubmatch(trimmed); m != nil {
+			finish()
+			if seen[m[1]] {
+				return nil, fmt.Errorf("review %s appears twice", m[1])
+			}
+			seen[m[1]] = true
+			reviews = append(reviews, Review{ID: m[1]})
+			continue
+		}
+		if len(reviews) == 0 {
+			continue
+		}
+		switch strings.TrimRight(line, " \t") {
+		case "## Goals":
+			section = &goals
+		case "## Summary":
+			section = &summary
+		default:
+			if section != nil {
+				*section = append(*section, line)
+			}
+		}
+	}
+	finish()
+	return reviews, nil
+}
+
+// New returns a new note: the generated part and room for the user's notes.
+func New(generated []byte) []byte {
+	return append(append([]byte(nil), generated...), '\n')
+}
+
+// Merge replaces the generated part of existing, everything up to its EndMarker, with
+// generated and keeps the rest. Front matter fields the user added, such as tags or
+// aliases, are kept after the generated ones.
+func Merge(existing, generated []byte) ([]byte, error) {
+	lines := strings.SplitAfter(string(existing), "\n")
+	end := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), endMarkerPrefix) {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, ErrNoMarker
+	}
+
+	merged := string(generated)
+	if extra := extraFields(lines[:end], generated); extra != "" {
+		frontEnd := frontMatterEnd(merged)
+		merged = merged[:frontEnd] + extra + merged[frontEnd:]
+	}
+	rest := strings.Join(lines[end+1:], "")
+	return []byte(merged + rest), nil
+}
+
+// frontMatterEnd returns the offset of the "---" line closing the front matter of a
+// rendered note.
+func frontMatterEnd(rendered string) int {
+	offset := 0
+	for i, line := range strings.SplitAfter(rendered, "\n") {
+		if i > 0 && line == "---\n" {
+			return offset
+		}
+		offset += len(line)
+	}
+	return offset
+}
+
+// fieldKey matches the first line of a top-level front matter field.
+var fieldKey = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_ -]*):(\s|$)`)
+
+// extraFields returns the front matter fields of the lines before the marker whose keys
+// generated does not have, with their nested lines, in their original order.
+func extraFields(lines []string, generated []byte) string {
+	generatedKeys := make(map[string]bool)
+	for _, block := range frontMatterBlocks(strings.SplitAfter(string(generated), "\n")) {
+		generatedKeys[block.key] = true
+	}
+	var b strings.Builder
+	for _, block := range frontMatterBlocks(lines) {
+		if block.key != "" && !generatedKeys[block.key] {
+			b.WriteString(block.text)
+		}
+	}
+	return b.String()
+}
+
+type frontMatterBlock struct {
+	key  string // empty for lines before the first key
+	text string
+}
+
+// frontMatterBlocks splits the front matter at the start of lines into top-level fields.
+func frontMatterBlocks(lines []string) []frontMatterBlock {
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r\n") != "---" {
+		return nil
+	}
+	var blocks []frontMatterBlock
+	current := frontMatterBlock{}
+	for _, line := range lines[1:] {
+		if strings.TrimRight(line, "\r\n") == "---" {
+			break
+		}
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		if m := fieldKey.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, current)
+			current = frontMatterBlock{key: m[1]}
+		}
+		current.text += line
+	}
+	return append(blocks, current)
+}
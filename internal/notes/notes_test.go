@@ -0,0 +1,152 @@
+package notes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testNote() Note {
+	return Note{
+		Title: "ドメイン駆動設計入門",
+		Fields: []Field{
+			{Key: "title", Value: `ドメイン駆動設計入門 "DDD"`},
+			{Key: "isbn", Value: ""},
+			{Key: "year", Value: 2020},
+		},
+		Reviews: []Review{
+			{ID: "r1", Goals: "設計を学ぶ\n", Summary: "一行目\n二行目"},
+			{ID: "r2", Goals: "Reread"},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	want := `---
+title: "ドメイン駆動設計入門 \"DDD\""
+year: 2020
+---
+
+# ドメイン駆動設計入門
+
+<!-- biblog:review r1 -->
+## Goals
+
+設計を学ぶ
+
+## Summary
+
+一行目
+二行目
+
+<!-- biblog:review r2 -->
+## Goals
+
+Reread
+
+## Summary
+
+` + EndMarker + "\n"
+	if got := string(Render(testNote())); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	existing := `---
+title: "Old title"
+tags:
+  - literature
+  - ddd
+year: 2019
+aliases: [DDD入門]
+---
+
+# Old title
+
+` + EndMarker + `
+
+## My notes
+
+- [[Bounded Context]]
+`
+	generated := Render(Note{Title: "New title", Fields: []Field{{Key: "title", Value: "New title"}, {Key: "year", Value: 2020}}})
+	got, err := Merge([]byte(existing), generated)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := `---
+title: "New title"
+year: 2020
+tags:
+  - literature
+  - ddd
+aliases: [DDD入門]
+---
+
+# New title
+
+` + EndMarker + `
+
+## My notes
+
+- [[Bounded Context]]
+`
+	if string(got) != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Merging again changes nothing
+	again, err := Merge(got, generated)
+	if err != nil || string(again) != want {
+		t.Errorf("Expected merging twice to be stable, got\n%s (%v)", again, err)
+	}
+}
+
+func TestMerge_EditedMarker(t *testing.T) {
+	existing := "# Title\n<!-- biblog:end (do not remove) -->\nnotes\n"
+	got, err := Merge([]byte(existing), Render(Note{Title: "Title"}))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if want := "---\n---\n\n# Title\n\n" + EndMarker + "\nnotes\n"; string(got) != want {
+		t.Errorf("Merge() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge_NoMarker(t *testing.T) {
+	if _, err := Merge([]byte("# Title\n\nnotes\n"), Render(Note{Title: "Title"})); !errors.Is(err, ErrNoMarker) {
+		t.Errorf("Expected ErrNoMarker, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	note := string(Render(testNote())) + "\n## My notes\n\n## Goals\n\nnot a review\n"
+	reviews, err := Parse([]byte(note))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []Review{
+		{ID: "r1", Goals: "設計を学ぶ", Summary: "一行目\n二行目"},
+		{ID: "r2", Goals: "Reread"},
+	}
+	if len(reviews) != len(want) {
+		t.Fatalf("Expected %d reviews, got %+v", len(want), reviews)
+	}
+	for i := range want {
+		if reviews[i] != want[i] {
+			t.Errorf("Review %d: got %+v, want %+v", i, reviews[i], want[i])
+		}
+	}
+
+	// Edits, including headings of the user's own, are read back
+	edited := strings.Replace(string(RenderDocument(testNote())), "一行目", "一行目\r\n\n### 感想\n", 1)
+	reviews, err = Parse([]byte(edited))
+	if err != nil || reviews[0].Summary != "一行目\n\n### 感想\n\n二行目" {
+		t.Errorf("Unexpected edited summary %q (%v)", reviews[0].Summary, err)
+	}
+
+	if _, err := Parse([]byte("<!-- biblog:review r1 -->\n<!-- biblog:review r1 -->\n")); err == nil {
+		t.Error("Expected an error for a review appearing twice")
+	}
+}
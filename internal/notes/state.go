@@ -0,0 +1,93 @@
+package notes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StateFile is the name of the file in a vault that records the last sync.
+const StateFile = ".biblog-notes.json"
+
+// State records each note as it was when last exported or synced, so that a later sync
+// can tell which side changed a review since.
+type State struct {
+	Notes map[string]*NoteState `json:"notes"` // by file name within the vault
+}
+
+// NoteState is a note as last written or read.
+type NoteState struct {
+	BibliographyID string                 `json:"bibliography_id"`
+	ModTime        time.Time              `json:"mtime"`
+	Size           int64                  `json:"size"`
+	Hash           string                 `json:"sha256"`
+	Reviews        map[string]ReviewState `json:"reviews"` // by review ID
+}
+
+// ReviewState holds the hashes (see Hash) of the review text both sides agreed on.
+type ReviewState struct {
+	Goals   string `json:"goals"`
+	Summary string `json:"summary"`
+}
+
+// NewReviewState returns the state of a review with the given text.
+func NewReviewState(goals, summary string) ReviewState {
+	return ReviewState{Goals: Hash(strings.TrimSpace(goals)), Summary: Hash(strings.TrimSpace(summary))}
+}
+
+// Hash returns the hex SHA-256 of s.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether the file still has the modification time and size recorded,
+// in which case it is taken not to have changed without reading it.
+func (n *NoteState) Matches(info fs.FileInfo) bool {
+	return info.ModTime().Equal(n.ModTime) && info.Size() == n.Size
+}
+
+// SetFile records the content of the note and its file info.
+func (n *NoteState) SetFile(content []byte, info fs.FileInfo) {
+	n.Hash = Hash(string(content))
+	n.ModTime = info.ModTime()
+	n.Size = info.Size()
+}
+
+// LoadState reads the state of vault; a vault without one has an empty state.
+func LoadState(vault string) (*State, error) {
+	state := &State{Notes: make(map[string]*NoteState)}
+	data, err := os.ReadFile(filepath.Join(vault, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", StateFile, err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", StateFile, err)
+	}
+	if state.Notes == nil {
+		state.Notes = make(map[string]*NoteState)
+	}
+	return state, nil
+}
+
+// Save writes the state into vault.
+func (s *State) Save(vault string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", StateFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(vault, StateFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", StateFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	vault := t.TempDir()
+	state, err := LoadState(vault)
+	if err != nil || len(state.Notes) != 0 {
+		t.Fatalf("Expected an empty state for a new vault, got %+v, %v", state, err)
+	}
+
+	file := filepath.Join(vault, "note.md")
+	content := []byte("# Note\n")
+	if err := os.WriteFile(file, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := &NoteState{BibliographyID: "b1", Reviews: map[string]ReviewState{"r1": NewReviewState("Goals\n", "")}}
+	note.SetFile(content, info)
+	state.Notes["note.md"] = note
+	if err := state.Save(vault); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadState(vault)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	got := loaded.Notes["note.md"]
+	if got == nil || got.Hash != Hash("# Note\n") || got.Reviews["r1"] != NewReviewState("Goals", "") {
+		t.Fatalf("Unexpected loaded state %+v", got)
+	}
+	if !got.Matches(info) {
+		t.Error("Expected the unchanged file to match")
+	}
+	if err := os.WriteFile(file, []byte("# Edited note\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(file); got.Matches(info) {
+		t.Error("Expected the edited file not to match")
+	}
+}
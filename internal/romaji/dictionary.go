@@ -0,0 +1,163 @@
+package romaji
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Dictionary maps kanji words to their readings in hiragana.
+// Keys may include trailing kana; lookups use the longest matching key.
+type Dictionary struct {
+	entries map[string]string
+	maxLen  int // length of the longest key in runes
+}
+
+// NewDictionary creates an empty dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{entries: make(map[string]string)}
+}
+
+// Add registers the reading for word. Katakana readings are stored as hiragana.
+// If the word already has a reading, the existing one is kept.
+func (d *Dictionary) Add(word, reading string) {
+	if word == "" || reading == "" {
+		return
+	}
+	if _, exists := d.entries[word]; exists {
+		return
+	}
+	d.entries[word] = strings.Map(toHiragana, reading)
+	if n := utf8.RuneCountInString(word); n > d.maxLen {
+		d.maxLen = n
+	}
+}
+
+// Lookup returns the hiragana reading of word.
+func (d *Dictionary) Lookup(word string) (string, bool) {
+	reading, ok := d.entries[word]
+	return reading, ok
+}
+
+// Len returns the number of entries.
+func (d *Dictionary) Len() int {
+	return len(d.entries)
+}
+
+// WithOverrides returns a new dictionary containing the entries of d,
+// with the entries of other taking precedence.
+func (d *Dictionary) WithOverrides(other *Dictionary) *Dictionary {
+	merged := NewDictionary()
+	for word, reading := range other.entries {
+		merged.Add(word, reading)
+	}
+	for word, reading := range d.entries {
+		merged.Add(word, reading)
+	}
+	return merged
+}
+
+// ParseSKK parses an SKK-JISYO style dictionary ("よみ /候補1/候補2;注釈/").
+// Okuri-ari keys ("せまr /迫/") drop their trailing romaji so that "迫る" reads "せまる".
+// Lines starting with ';' are comments. The input must be UTF-8.
+func ParseSKK(r io.Reader) (*Dictionary, error) {
+	d := NewDictionary()
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if !utf8.ValidString(line) {
+			return nil, fmt.Errorf("line %d: dictionary must be UTF-8 encoded (convert with: iconv -f EUC-JP -t UTF-8)", lineNum)
+		}
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		key, candidates, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		// Strip the okurigana consonant of okuri-ari entries
+		if n := len(key); n > 1 && isASCIILetter(key[n-1]) {
+			key = key[:n-1]
+		}
+		for _, cand := range strings.Split(strings.Trim(candidates, "/"), "/") {
+			cand, _, _ = strings.Cut(cand, ";") // drop annotation
+			d.Add(cand, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ParseIPADIC parses an IPADIC/MeCab style CSV dictionary, using the surface form
+// (column 1) and the katakana reading (column 12). The input must be UTF-8.
+func ParseIPADIC(r io.Reader) (*Dictionary, error) {
+	d := NewDictionary()
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if !utf8.ValidString(line) {
+			return nil, fmt.Errorf("line %d: dictionary must be UTF-8 encoded (convert with: iconv -f EUC-JP -t UTF-8)", lineNum)
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 12 || fields[11] == "*" {
+			continue
+		}
+		d.Add(fields[0], fields[11])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// LoadDictionaryFile loads a user-supplied reading dictionary.
+// Files ending in .csv are parsed as IPADIC, anything else as SKK.
+func LoadDictionaryFile(path string) (*Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("Failed to close file: %v", err)
+		}
+	}()
+
+	var d *Dictionary
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		d, err = ParseIPADIC(file)
+	} else {
+		d, err = ParseSKK(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reading dictionary %s: %w", path, err)
+	}
+	return d, nil
+}
+
+//go:embed bundled.skk
+var bundledSKK string
+
+// Bundled returns the small dictionary shipped with biblog, covering common
+// surnames and words found in book titles. It must not be modified.
+var Bundled = sync.OnceValue(func() *Dictionary {
+	d, err := ParseSKK(strings.NewReader(bundledSKK))
+	if err != nil {
+		panic(fmt.Sprintf("romaji: invalid bundled dictionary: %v", err))
+	}
+	return d
+})
@@ -0,0 +1,80 @@
+package romaji
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSKK(t *testing.T) {
+	input := `;; comment
+;; okuri-ari entries.
+せまr /迫/
+;; okuri-nasi entries.
+しんわ /神話/
+かがく /科学;science/化学/
+`
+	d, err := ParseSKK(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSKK failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"迫":  "せま",
+		"神話": "しんわ",
+		"科学": "かがく",
+		"化学": "かがく",
+	}
+	for word, want := range tests {
+		if got, ok := d.Lookup(word); !ok || got != want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", word, got, ok, want)
+		}
+	}
+}
+
+func TestParseSKK_RejectsNonUTF8(t *testing.T) {
+	// "しんわ" in EUC-JP
+	input := "\xa4\xb7\xa4\xf3\xa4\xef /\xbf\xc0\xcf\xc3/\n"
+	if _, err := ParseSKK(strings.NewReader(input)); err == nil {
+		t.Fatal("Expected error for non UTF-8 input, got nil")
+	}
+}
+
+func TestParseIPADIC(t *testing.T) {
+	input := "駆動,1285,1285,5000,名詞,サ変接続,*,*,*,*,駆動,クドウ,クドー\n" +
+		"記号,1,1,1,記号,*,*,*,*,*,記号,*,*\n"
+	d, err := ParseIPADIC(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseIPADIC failed: %v", err)
+	}
+	if got, ok := d.Lookup("駆動"); !ok || got != "くどう" {
+		t.Errorf("Lookup(駆動) = %q, %v; want くどう", got, ok)
+	}
+	if d.Len() != 1 {
+		t.Errorf("Expected entries without reading to be skipped, got %d entries", d.Len())
+	}
+}
+
+func TestLoadDictionaryFile_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.skk")
+	if err := os.WriteFile(path, []byte("けいすけ /啓/\nうつ /鬱/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := LoadDictionaryFile(path)
+	if err != nil {
+		t.Fatalf("LoadDictionaryFile failed: %v", err)
+	}
+	merged := Bundled().WithOverrides(user)
+
+	if got, _ := merged.Lookup("啓"); got != "けいすけ" {
+		t.Errorf("Expected user reading to override bundled, got %q", got)
+	}
+	if got, _ := merged.Lookup("神話"); got != "しんわ" {
+		t.Errorf("Expected bundled reading to be kept, got %q", got)
+	}
+	if got, _ := Bundled().Lookup("啓"); got != "けい" {
+		t.Errorf("Expected bundled dictionary to be unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+package romaji
+
+import (
+	"strings"
+	"unicode"
+)
+
+// asciiFolds maps Latin letters with diacritics, and ligatures, to ASCII.
+var asciiFolds = map[rune]string{}
+
+func init() {
+	for ascii, letters := range map[string]string{
+		"A": "ÀÁÂÃÄÅĀĂĄǍ", "a": "àáâãäåāăąǎ",
+		"C": "ÇĆĈĊČ", "c": "çćĉċč",
+		"D": "ĎĐÐ", "d": "ďđð",
+		"E": "ÈÉÊËĒĔĖĘĚ", "e": "èéêëēĕėęě",
+		"G": "ĜĞĠĢ", "g": "ĝğġģ",
+		"H": "ĤĦ", "h": "ĥħ",
+		"I": "ÌÍÎÏĨĪĬĮİǏ", "i": "ìíîïĩīĭįıǐ",
+		"J": "Ĵ", "j": "ĵ",
+		"K": "Ķ", "k": "ķ",
+		"L": "ĹĻĽĿŁ", "l": "ĺļľŀł",
+		"N": "ÑŃŅŇ", "n": "ñńņňŉ",
+		"O": "ÒÓÔÕÖØŌŎŐǑ", "o": "òóôõöøōŏőǒ",
+		"R": "ŔŖŘ", "r": "ŕŗř",
+		"S": "ŚŜŞŠȘ", "s": "śŝşšș",
+		"T": "ŢŤŦȚ", "t": "ţťŧț",
+		"U": "ÙÚÛÜŨŪŬŮŰŲǓ", "u": "ùúûüũūŭůűųǔ",
+		"W": "Ŵ", "w": "ŵ",
+		"Y": "ÝŶŸ", "y": "ýÿŷ",
+		"Z": "ŹŻŽ", "z": "źżž",
+		"AE": "Æ", "ae": "æ", "OE": "Œ", "oe": "œ", "TH": "Þ", "th": "þ", "ss": "ß",
+	} {
+		for _, r := range letters {
+			asciiFolds[r] = ascii
+		}
+	}
+}
+
+// FoldASCII replaces Latin letters with diacritics by the plain letters ("Gödel" ->
+// "Godel", "Ichirō" -> "Ichiro") and ligatures by their letters ("ß" -> "ss").
+// Combining marks are removed; other runes are kept.
+func FoldASCII(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if folded, ok := asciiFolds[r]; ok {
+			b.WriteString(folded)
+		} else if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package romaji
+
+import "testing"
+
+func TestFoldASCII(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Gödel", "Godel"},
+		{"Émile Zola", "Emile Zola"},
+		{"Sasaki Ichirō", "Sasaki Ichiro"},
+		{"Ōsaka", "Osaka"},
+		{"Straße", "Strasse"},
+		{"Łukasiewicz", "Lukasiewicz"},
+		{"Cœur", "Coeur"},
+		{"Caf\u00e9", "Cafe"},
+		{"Cafe\u0301", "Cafe"},
+		{"杉本", "杉本"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := FoldASCII(tt.input); got != tt.expected {
+				t.Errorf("FoldASCII(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Package romaji converts Japanese text to Hepburn romanization.
+//
+// Kana is converted directly. Kanji has no reading of its own, so it is looked up
+// in a Dictionary (a bundled subset, optionally extended by a user-supplied
+// SKK or IPADIC file). The output is modified Hepburn: long vowels carry a macron
+// ("マシュー" -> "mashū", "とうきょう" -> "tōkyō"), except for "ii", which is written
+// as is. FoldASCII removes the macrons where plain ASCII is needed.
+package romaji
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// digraphs are two-kana combinations (a kana followed by a small kana) in hiragana.
+var digraphs = map[string]string{
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	// Combinations used for loanwords (usually written in katakana)
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "ふゅ": "fyu",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"てゅ": "tyu", "でゅ": "dyu",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"いぇ": "ye",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+	"くぁ": "kwa", "ぐぁ": "gwa",
+	"すぃ": "si", "ずぃ": "zi",
+}
+
+// monographs are single hiragana.
+var monographs = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu", 'ゕ': "ka", 'ゖ': "ke",
+	// Small kana on their own
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+const (
+	sokuon    = 'っ' // doubles the following consonant
+	syllabicN = 'ん'
+	chouon    = 'ー' // long vowel mark
+)
+
+// IsHiragana reports whether r is a hiragana letter.
+func IsHiragana(r rune) bool {
+	return r >= 0x3041 && r <= 0x3096
+}
+
+// IsKatakana reports whether r is a katakana letter or the long vowel mark.
+func IsKatakana(r rune) bool {
+	return (r >= 0x30A1 && r <= 0x30FA) || r == chouon
+}
+
+// toHiragana maps katakana letters to the corresponding hiragana, leaving other runes unchanged.
+func toHiragana(r rune) rune {
+	if r >= 0x30A1 && r <= 0x30F6 {
+		return r - 0x60
+	}
+	return r
+}
+
+// KanaToHepburn converts hiragana and katakana to lowercase Hepburn romaji.
+// Runes that are not kana are copied through unchanged.
+func KanaToHepburn(kana string) string {
+	runes := []rune(kana)
+	for i, r := range runes {
+		runes[i] = toHiragana(r)
+	}
+
+	var b strings.Builder
+	doubleNext := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		var syllable string
+		switch {
+		case r == sokuon:
+			doubleNext = true
+			continue
+		case r == chouon:
+			lengthenVowel(&b)
+			continue
+		case r == syllabicN:
+			syllable = "n"
+			// Separate from a following vowel or y-sound to avoid ambiguity (e.g. "kan'i")
+			if i+1 < len(runes) {
+				next := nextSyllable(runes, i+1)
+				if next != "" && strings.IndexByte("aeiouy", next[0]) >= 0 {
+					syllable = "n'"
+				}
+			}
+		default:
+			if i+1 < len(runes) {
+				if d, ok := digraphs[string(runes[i:i+2])]; ok {
+					syllable = d
+					i++
+					break
+				}
+			}
+			if m, ok := monographs[r]; ok {
+				syllable = m
+			} else {
+				syllable = string(r)
+			}
+		}
+
+		if doubleNext {
+			doubleNext = false
+			switch {
+			case strings.HasPrefix(syllable, "ch"):
+				b.WriteByte('t')
+			case syllable != "" && strings.IndexByte("aeiou'", syllable[0]) < 0 && isASCIILetter(syllable[0]):
+				b.WriteByte(syllable[0])
+			}
+		}
+		// A vowel lengthening the one before: "ou" and "oo" -> "ō", "uu" -> "ū", and so on
+		if len(syllable) == 1 && endsWithLengthenedBy(b.String(), syllable[0]) && lengthenVowel(&b) {
+			continue
+		}
+		b.WriteString(syllable)
+	}
+	return b.String()
+}
+
+// macrons are the long forms of the vowels.
+var macrons = map[byte]rune{'a': 'ā', 'i': 'ī', 'u': 'ū', 'e': 'ē', 'o': 'ō'}
+
+// lengthenVowel replaces a vowel at the end of b by its long form and reports whether
+// there was one.
+func lengthenVowel(b *strings.Builder) bool {
+	out := b.String()
+	n := len(out)
+	if n == 0 {
+		return false
+	}
+	long, ok := macrons[out[n-1]]
+	if !ok {
+		return false
+	}
+	b.Reset()
+	b.WriteString(out[:n-1])
+	b.WriteRune(long)
+	return true
+}
+
+// endsWithLengthenedBy reports whether the vowel v written after out makes the vowel at
+// its end long. "ii" and "ei" are two vowels in modified Hepburn.
+func endsWithLengthenedBy(out string, v byte) bool {
+	last, _ := utf8.DecodeLastRuneInString(out)
+	switch v {
+	case 'a', 'e', 'o':
+		return last == rune(v)
+	case 'u':
+		return last == 'o' || last == 'u'
+	default:
+		return false
+	}
+}
+
+// nextSyllable returns the romaji of the syllable starting at runes[i] without consuming it.
+func nextSyllable(runes []rune, i int) string {
+	if i+1 < len(runes) {
+		if d, ok := digraphs[string(runes[i:i+2])]; ok {
+			return d
+		}
+	}
+	return monographs[runes[i]]
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
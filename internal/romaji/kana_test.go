@@ -0,0 +1,38 @@
+package romaji
+
+import "testing"
+
+func TestKanaToHepburn(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"すぎもと", "sugimoto"},
+		{"マシュー", "mashū"},
+		{"スチュワート", "suchuwāto"},
+		{"しんぶん", "shinbun"},
+		{"がっこう", "gakkō"},
+		{"きっちり", "kitchiri"},
+		{"しんいち", "shin'ichi"},
+		{"ほんや", "hon'ya"},
+		{"ちゃづけ", "chazuke"},
+		{"ティーカップ", "tīkappu"},
+		{"とうきょう", "tōkyō"},
+		{"おおさか", "ōsaka"},
+		{"いいえ", "iie"},
+		{"けいさん", "keisan"},
+		{"ファイル", "fairu"},
+		{"ヴァイオリン", "vaiorin"},
+		{"ぁ", "a"},
+		{"Go", "Go"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := KanaToHepburn(tt.input); got != tt.expected {
+				t.Errorf("KanaToHepburn(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package romaji
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UnknownReadingError reports kanji that have no entry in the dictionary.
+type UnknownReadingError struct {
+	Words []string
+}
+
+func (e *UnknownReadingError) Error() string {
+	return fmt.Sprintf("no reading for %s", strings.Join(e.Words, ", "))
+}
+
+// Romanizer converts mixed Japanese/Latin text into space-separated Hepburn words.
+type Romanizer struct {
+	dict *Dictionary
+}
+
+// NewRomanizer creates a Romanizer that resolves kanji with dict.
+func NewRomanizer(dict *Dictionary) *Romanizer {
+	return &Romanizer{dict: dict}
+}
+
+type runKind int
+
+const (
+	kindNone runKind = iota
+	kindLatin
+	kindKatakana
+	kindHiragana
+	kindKanji
+)
+
+// Romanize returns the Hepburn romanization of s as capitalized words separated by single spaces.
+// Latin text is kept as it is, including letters with diacritics; see FoldASCII.
+//
+// Words are split at whitespace, punctuation and "・", at the start of every katakana
+// or Latin run, and at every dictionary word, so "杉本啓" becomes "Sugimoto Kei".
+// Hiragana is attached to the preceding word as okurigana or a particle
+// ("哲学の" -> "Tetsugakuno"). If any kanji has no dictionary reading,
+// an *UnknownReadingError listing them is returned.
+func (r *Romanizer) Romanize(s string) (string, error) {
+	runes := []rune(foldWidth(s))
+
+	var (
+		words   []string
+		current strings.Builder
+		prev    = kindNone
+		unknown []string
+	)
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, capitalize(current.String()))
+			current.Reset()
+		}
+		prev = kindNone
+	}
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case isKanji(c):
+			word, reading, ok := r.longestMatch(runes[i:])
+			if !ok {
+				// Collect the whole run of unknown kanji for the error message
+				j := i + 1
+				for j < len(runes) && isKanji(runes[j]) {
+					if _, _, ok := r.longestMatch(runes[j:]); ok {
+						break
+					}
+					j++
+				}
+				unknown = append(unknown, string(runes[i:j]))
+				i = j
+				continue
+			}
+			flush()
+			current.WriteString(KanaToHepburn(reading))
+			prev = kindKanji
+			i += word
+
+		case IsKatakana(c):
+			j := i
+			for j < len(runes) && IsKatakana(runes[j]) {
+				j++
+			}
+			flush()
+			current.WriteString(KanaToHepburn(string(runes[i:j])))
+			prev = kindKatakana
+			i = j
+
+		case IsHiragana(c):
+			j := i
+			for j < len(runes) && IsHiragana(runes[j]) {
+				j++
+			}
+			current.WriteString(KanaToHepburn(string(runes[i:j])))
+			prev = kindHiragana
+			i = j
+
+		case unicode.Is(unicode.Latin, c) || (c < unicode.MaxASCII && unicode.IsDigit(c)) ||
+			(prev == kindLatin && unicode.Is(unicode.Mn, c)):
+			// Letters with diacritics ("Gödel") are kept, as are combining marks after them
+			if prev != kindLatin {
+				flush()
+			}
+			current.WriteRune(c)
+			prev = kindLatin
+			i++
+
+		default:
+			// Whitespace, punctuation, "・" and anything else separate words
+			flush()
+			i++
+		}
+	}
+	flush()
+
+	if len(unknown) > 0 {
+		return "", &UnknownReadingError{Words: unknown}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// longestMatch finds the longest dictionary word at the start of runes.
+// It returns the matched length in runes and its reading.
+func (r *Romanizer) longestMatch(runes []rune) (int, string, bool) {
+	maxLen := min(r.dict.maxLen, len(runes))
+	for n := maxLen; n > 0; n-- {
+		if reading, ok := r.dict.Lookup(string(runes[:n])); ok {
+			return n, reading, true
+		}
+	}
+	return 0, "", false
+}
+
+// isKanji reports whether c is a CJK ideograph or an iteration mark such as "々".
+func isKanji(c rune) bool {
+	return unicode.Is(unicode.Han, c) || c == '々' || c == '〆'
+}
+
+// foldWidth converts full-width ASCII variants (e.g. "Ｇｏ") to ASCII.
+func foldWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			return r - 0xFEE0
+		}
+		return r
+	}, s)
+}
+
+// capitalize upper-cases the first letter of word, which may have a macron ("ōsaka").
+func capitalize(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	if size == 0 {
+		return word
+	}
+	return string(unicode.ToUpper(first)) + word[size:]
+}
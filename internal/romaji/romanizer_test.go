@@ -0,0 +1,52 @@
+package romaji
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRomanizer_Romanize(t *testing.T) {
+	r := NewRomanizer(Bundled())
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"マシュー スチュワート", "Mashū Suchuwāto"},
+		{"マシュー・スチュワート", "Mashū Suchuwāto"},
+		{"杉本啓", "Sugimoto Kei"},
+		{"佐々木一郎", "Sasaki Ichirō"},
+		{"データモデリングでドメインを駆動する", "Dētamoderingude Domeino Kudōsuru"},
+		{"マネジメント神話　現代ビジネス哲学の真実に迫る", "Manejimento Shinwa Gendai Bijinesu Tetsugakuno Shinjitsuni Semaru"},
+		{"Ｇｏプログラミング実践入門", "Go Puroguramingu Jissen Nyūmon"},
+		{"こころ", "Kokoro"},
+		{"Domain Driven Design", "Domain Driven Design"},
+		{"ゲーデル Gödel", "Gēderu Gödel"},
+		{"Émile Zola", "Émile Zola"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := r.Romanize(tt.input)
+			if err != nil {
+				t.Fatalf("Romanize(%q) error = %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Romanize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRomanizer_UnknownReading(t *testing.T) {
+	r := NewRomanizer(Bundled())
+
+	_, err := r.Romanize("鬱の本と薔薇")
+	var unknown *UnknownReadingError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected UnknownReadingError, got %v", err)
+	}
+	if len(unknown.Words) != 2 || unknown.Words[0] != "鬱" || unknown.Words[1] != "薔薇" {
+		t.Errorf("Expected unknown words [鬱 薔薇], got %v", unknown.Words)
+	}
+}
@@ -0,0 +1,300 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Index is an inverted index over documents made of named text fields.
+// Documents belong to a group (e.g. a bibliography and its reviews), and
+// search results are reported per group.
+//
+// All fields are exported so that the index can be persisted with encoding/gob.
+type Index struct {
+	Docs map[string]*Document
+	// Postings maps term -> document ID -> field -> token positions.
+	Postings map[string]map[string]map[string][]int
+	// FieldTokens is the total number of tokens per field, for average field lengths.
+	FieldTokens map[string]int
+}
+
+// Document records what is needed to score and remove an indexed document.
+type Document struct {
+	Group   string
+	Lengths map[string]int // tokens per field
+	Terms   []string       // distinct terms
+}
+
+// Match is a field of a document that satisfied at least one clause.
+type Match struct {
+	DocID string
+	Field string
+}
+
+// Result is a group in which every clause of a query matched.
+type Result struct {
+	Group   string
+	Score   float64
+	Matches []Match
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{
+		Docs:        make(map[string]*Document),
+		Postings:    make(map[string]map[string]map[string][]int),
+		FieldTokens: make(map[string]int),
+	}
+}
+
+// Len returns the number of indexed documents.
+func (ix *Index) Len() int {
+	return len(ix.Docs)
+}
+
+// Add indexes a document, replacing any previous version with the same ID.
+func (ix *Index) Add(id, group string, fields map[string]string) {
+	ix.Remove(id)
+
+	doc := &Document{Group: group, Lengths: make(map[string]int)}
+	seen := make(map[string]bool)
+	for field, text := range fields {
+		tokens := Tokenize(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		doc.Lengths[field] = len(tokens)
+		ix.FieldTokens[field] += len(tokens)
+		for _, tok := range tokens {
+			byDoc := ix.Postings[tok.Term]
+			if byDoc == nil {
+				byDoc = make(map[string]map[string][]int)
+				ix.Postings[tok.Term] = byDoc
+			}
+			byField := byDoc[id]
+			if byField == nil {
+				byField = make(map[string][]int)
+				byDoc[id] = byField
+			}
+			byField[field] = append(byField[field], tok.Pos)
+			if !seen[tok.Term] {
+				seen[tok.Term] = true
+				doc.Terms = append(doc.Terms, tok.Term)
+			}
+		}
+	}
+	ix.Docs[id] = doc
+}
+
+// Remove deletes a document from the index. Removing a missing ID is a no-op.
+func (ix *Index) Remove(id string) {
+	doc, ok := ix.Docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.Terms {
+		delete(ix.Postings[term], id)
+		if len(ix.Postings[term]) == 0 {
+			delete(ix.Postings, term)
+		}
+	}
+	for field, n := range doc.Lengths {
+		ix.FieldTokens[field] -= n
+	}
+	delete(ix.Docs, id)
+}
+
+// Search returns the groups in which every clause matches some document, ranked by
+// the sum of the BM25 scores of the matching fields multiplied by their weight.
+// Fields without a weight count as 1. Ties are ordered by group.
+func (ix *Index) Search(clauses []Clause, weights map[string]float64) []Result {
+	type groupState struct {
+		score   float64
+		clauses int
+		matches []Match
+		seen    map[Match]bool
+	}
+	groups := make(map[string]*groupState)
+
+	for ci, clause := range clauses {
+		// Positions of the phrase in each candidate document and field
+		hits := ix.phraseHits(clause)
+
+		// Document frequency per field, for IDF
+		df := make(map[string]int)
+		for _, byField := range hits {
+			for field := range byField {
+				df[field]++
+			}
+		}
+
+		matchedGroups := make(map[string]bool)
+		for docID, byField := range hits {
+			doc := ix.Docs[docID]
+			g := groups[doc.Group]
+			if g == nil {
+				if ci > 0 {
+					continue // an earlier clause did not match this group
+				}
+				g = &groupState{seen: make(map[Match]bool)}
+				groups[doc.Group] = g
+			} else if g.clauses < ci {
+				continue
+			}
+
+			for field, tf := range byField {
+				weight, ok := weights[field]
+				if !ok {
+					weight = 1
+				}
+				g.score += weight * ix.bm25(field, tf, doc.Lengths[field], df[field])
+				m := Match{DocID: docID, Field: field}
+				if !g.seen[m] {
+					g.seen[m] = true
+					g.matches = append(g.matches, m)
+				}
+			}
+			matchedGroups[doc.Group] = true
+		}
+		for group := range matchedGroups {
+			groups[group].clauses = ci + 1
+		}
+	}
+
+	var results []Result
+	for group, g := range groups {
+		if g.clauses == len(clauses) {
+			sort.Slice(g.matches, func(i, j int) bool {
+				if g.matches[i].DocID != g.matches[j].DocID {
+					return g.matches[i].DocID < g.matches[j].DocID
+				}
+				return g.matches[i].Field < g.matches[j].Field
+			})
+			results = append(results, Result{Group: group, Score: g.score, Matches: g.matches})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Group < results[j].Group
+	})
+	return results
+}
+
+// bm25 scores a field containing a phrase tf times.
+func (ix *Index) bm25(field string, tf, fieldLen, df int) float64 {
+	n := float64(len(ix.Docs))
+	idf := math.Log(1 + (n-float64(df)+0.5)/(float64(df)+0.5))
+	avgLen := float64(ix.FieldTokens[field]) / n
+	if avgLen == 0 {
+		avgLen = 1
+	}
+	f := float64(tf)
+	return idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(fieldLen)/avgLen))
+}
+
+// termShift is an indexed term that can stand for a query token, with the offset
+// from the term's position to the position of the token.
+type termShift struct {
+	term  string
+	shift int
+}
+
+// candidates returns the indexed terms matching a query token. A single CJK
+// character also matches the bigrams that start or end with it.
+func (ix *Index) candidates(term string) []termShift {
+	cands := []termShift{{term: term}}
+	r, size := utf8.DecodeRuneInString(term)
+	if size != len(term) || !isCJK(r) {
+		return cands
+	}
+	for t := range ix.Postings {
+		if utf8.RuneCountInString(t) != 2 {
+			continue
+		}
+		first, n := utf8.DecodeRuneInString(t)
+		second, _ := utf8.DecodeRuneInString(t[n:])
+		if first == r {
+			cands = append(cands, termShift{term: t})
+		}
+		if second == r {
+			cands = append(cands, termShift{term: t, shift: 1})
+		}
+	}
+	return cands
+}
+
+// positions returns the sorted, distinct positions at which a query token occurs in a field.
+func (ix *Index) positions(cands []termShift, docID, field string) []int {
+	set := make(map[int]bool)
+	for _, c := range cands {
+		for _, p := range ix.Postings[c.term][docID][field] {
+			set[p+c.shift] = true
+		}
+	}
+	positions := make([]int, 0, len(set))
+	for p := range set {
+		positions = append(positions, p)
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// phraseHits returns, for each document and field containing the clause's phrase,
+// the number of occurrences.
+func (ix *Index) phraseHits(clause Clause) map[string]map[string]int {
+	tokenCands := make([][]termShift, len(clause.Tokens))
+	for i, tok := range clause.Tokens {
+		tokenCands[i] = ix.candidates(tok.Term)
+	}
+
+	// Candidate documents and fields are those containing the first token
+	type docField struct{ doc, field string }
+	var firsts []docField
+	seen := make(map[docField]bool)
+	for _, c := range tokenCands[0] {
+		for docID, byField := range ix.Postings[c.term] {
+			for field := range byField {
+				df := docField{docID, field}
+				if (clause.Field == "" || clause.Field == field) && !seen[df] {
+					seen[df] = true
+					firsts = append(firsts, df)
+				}
+			}
+		}
+	}
+
+	hits := make(map[string]map[string]int)
+	for _, df := range firsts {
+		starts := ix.positions(tokenCands[0], df.doc, df.field)
+		for i := 1; i < len(clause.Tokens) && len(starts) > 0; i++ {
+			offset := clause.Tokens[i].Pos - clause.Tokens[0].Pos
+			next := make(map[int]bool)
+			for _, p := range ix.positions(tokenCands[i], df.doc, df.field) {
+				next[p-offset] = true
+			}
+			kept := starts[:0]
+			for _, p := range starts {
+				if next[p] {
+					kept = append(kept, p)
+				}
+			}
+			starts = kept
+		}
+		if len(starts) > 0 {
+			if hits[df.doc] == nil {
+				hits[df.doc] = make(map[string]int)
+			}
+			hits[df.doc][df.field] = len(starts)
+		}
+	}
+	return hits
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"testing"
+)
+
+func newTestIndex() *Index {
+	ix := NewIndex()
+	ix.Add("bib:1", "1", map[string]string{
+		"title":  "データモデリングでドメインを駆動する",
+		"author": "杉本啓",
+	})
+	ix.Add("review:1", "1", map[string]string{
+		"summary": "分散・非同期・疎結合なシステムを作る",
+	})
+	ix.Add("bib:2", "2", map[string]string{
+		"title":  "Domain-Driven Design",
+		"author": "Eric Evans",
+	})
+	ix.Add("bib:3", "3", map[string]string{
+		"title":  "マネジメント神話",
+		"author": "マシュー スチュワート",
+	})
+	return ix
+}
+
+func search(t *testing.T, ix *Index, query string) []Result {
+	t.Helper()
+	clauses, err := ParseQuery(query, []string{"title", "author", "summary"})
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) failed: %v", query, err)
+	}
+	return ix.Search(clauses, map[string]float64{"title": 3, "author": 2})
+}
+
+func groups(results []Result) []string {
+	var gs []string
+	for _, r := range results {
+		gs = append(gs, r.Group)
+	}
+	return gs
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix := newTestIndex()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"ドメイン", []string{"1"}},
+		{"ドメイン駆動", nil}, // not a substring of any title
+		{"DOMAIN driven", []string{"2"}},
+		{`"driven domain"`, nil},
+		{"author:杉本", []string{"1"}},
+		{"title:杉本", nil},
+		{"駆", []string{"1"}},      // single character inside a run
+		{"る", []string{"1"}},      // single character at the end of a run
+		{"杉本 疎結合", []string{"1"}}, // clauses may match different documents of a group
+		{"杉本 evans", nil},
+		{"マ", []string{"3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := groups(search(t, ix, tt.query))
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_SearchMatches(t *testing.T) {
+	results := search(t, newTestIndex(), "杉本 システム")
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	expected := []Match{{DocID: "bib:1", Field: "author"}, {DocID: "review:1", Field: "summary"}}
+	if len(results[0].Matches) != len(expected) {
+		t.Fatalf("Matches = %+v, want %+v", results[0].Matches, expected)
+	}
+	for i, m := range expected {
+		if results[0].Matches[i] != m {
+			t.Errorf("Matches = %+v, want %+v", results[0].Matches, expected)
+		}
+	}
+}
+
+func TestIndex_Ranking(t *testing.T) {
+	ix := NewIndex()
+	ix.Add("a", "a", map[string]string{"summary": "ドメインについて"})
+	ix.Add("b", "b", map[string]string{"title": "ドメイン"})
+	ix.Add("c", "c", map[string]string{"summary": "ドメイン、ドメイン、ドメイン"})
+
+	got := groups(search(t, ix, "ドメイン"))
+	// Title is weighted higher; more occurrences rank higher
+	if len(got) != 3 || got[0] != "b" || got[1] != "c" || got[2] != "a" {
+		t.Errorf("Expected ranking [b c a], got %v", got)
+	}
+}
+
+func TestIndex_ReplaceAndRemove(t *testing.T) {
+	ix := newTestIndex()
+
+	ix.Add("bib:2", "2", map[string]string{"title": "Implementing DDD"})
+	if got := search(t, ix, "evans"); len(got) != 0 {
+		t.Errorf("Expected replaced document not to match old text, got %v", groups(got))
+	}
+	if got := search(t, ix, "implementing"); len(got) != 1 {
+		t.Errorf("Expected replaced document to match new text, got %v", groups(got))
+	}
+
+	ix.Remove("bib:2")
+	ix.Remove("missing")
+	if ix.Len() != 3 {
+		t.Errorf("Expected 3 documents, got %d", ix.Len())
+	}
+	if _, ok := ix.Postings["ddd"]; ok {
+		t.Error("Expected postings of removed document to be deleted")
+	}
+}
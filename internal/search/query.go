@@ -0,0 +1,93 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clause is one required part of a query: a phrase, optionally restricted to a field.
+type Clause struct {
+	Field  string // "" matches any field
+	Tokens []Token
+}
+
+// ParseQuery parses a query made of whitespace-separated clauses, all of which must match.
+//
+//	ドメイン駆動          phrase in any field
+//	"domain driven"      quoted phrase (may contain spaces)
+//	author:杉本           phrase restricted to a field
+//	title:"data model"   quoted phrase restricted to a field
+//
+// Field names must be one of fields.
+func ParseQuery(query string, fields []string) ([]Clause, error) {
+	var clauses []Clause
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' || runes[i] == '\n' || runes[i] == '　' {
+			i++
+			continue
+		}
+
+		// Read one clause: an unquoted word, possibly with a field prefix and a quoted phrase
+		var (
+			text   strings.Builder
+			field  string
+			quoted bool
+		)
+		for i < len(runes) {
+			c := runes[i]
+			if !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '　') {
+				break
+			}
+			switch {
+			case c == '"':
+				quoted = !quoted
+			case c == ':' && !quoted && field == "" && isField(text.String(), fields):
+				field = strings.ToLower(text.String())
+				text.Reset()
+			default:
+				text.WriteRune(c)
+			}
+			i++
+		}
+		if quoted {
+			return nil, fmt.Errorf("unterminated quote in query %q", query)
+		}
+		if field == "" {
+			if name, _, ok := strings.Cut(text.String(), ":"); ok && isFieldName(name) {
+				return nil, fmt.Errorf("unknown field %q (expected one of %s)", name, strings.Join(fields, ", "))
+			}
+		}
+
+		if tokens := Tokenize(text.String()); len(tokens) > 0 {
+			clauses = append(clauses, Clause{Field: field, Tokens: tokens})
+		}
+	}
+	if len(clauses) == 0 {
+		return nil, fmt.Errorf("query %q contains no searchable terms", query)
+	}
+	return clauses, nil
+}
+
+func isField(name string, fields []string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFieldName reports whether name looks like a field prefix (ASCII letters only),
+// so that "author:" typos are reported while text such as "12:30" is searched as is.
+func isFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+var testFields = []string{"title", "author", "summary"}
+
+func TestParseQuery(t *testing.T) {
+	clauses, err := ParseQuery(`author:杉本 "domain driven"　title:"data model" 12:30`, testFields)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if len(clauses) != 4 {
+		t.Fatalf("Expected 4 clauses, got %d: %+v", len(clauses), clauses)
+	}
+
+	expected := []struct {
+		field  string
+		tokens int
+	}{
+		{"author", 1},
+		{"", 2},
+		{"title", 2},
+		{"", 2},
+	}
+	for i, want := range expected {
+		if clauses[i].Field != want.field || len(clauses[i].Tokens) != want.tokens {
+			t.Errorf("Clause %d = %+v, want field %q with %d tokens", i, clauses[i], want.field, want.tokens)
+		}
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`"unterminated`, "unterminated quote"},
+		{"publisher:技術評論社", `unknown field "publisher"`},
+		{"  、。 ", "no searchable terms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query, testFields)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
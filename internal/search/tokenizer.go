@@ -0,0 +1,89 @@
+// Package search implements a small full-text index for mixed Japanese and Latin text.
+//
+// Japanese has no spaces between words, so runs of kanji and kana are indexed as
+// overlapping character bigrams ("ドメイン" -> "ドメ", "メイ", "イン"); other scripts
+// are split into lower-cased words. Queries are tokenized the same way and matched
+// as phrases, so a query finds exactly the documents containing it as a substring
+// (for Japanese) or word sequence (for Latin text).
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is an indexed term and its position within a field.
+type Token struct {
+	Term string
+	Pos  int
+}
+
+type runeClass int
+
+const (
+	classSeparator runeClass = iota
+	classWord
+	classCJK
+)
+
+func classify(r rune) runeClass {
+	switch {
+	case isCJK(r):
+		return classCJK
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return classWord
+	default:
+		return classSeparator
+	}
+}
+
+// isCJK reports whether r belongs to a script written without word separators.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		r == 'ー' || r == '々' || r == '〆'
+}
+
+// Tokenize splits text into terms. Full-width ASCII is folded to ASCII and
+// Latin text is lower-cased. CJK runs yield bigrams, or a single unigram for
+// a one-character run; positions are consecutive across the whole text.
+func Tokenize(text string) []Token {
+	runes := []rune(normalize(text))
+	var tokens []Token
+	pos := 0
+	for i := 0; i < len(runes); {
+		class := classify(runes[i])
+		if class == classSeparator {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && classify(runes[j]) == class {
+			j++
+		}
+
+		if class == classWord {
+			tokens = append(tokens, Token{Term: string(runes[i:j]), Pos: pos})
+			pos++
+		} else if j-i == 1 {
+			tokens = append(tokens, Token{Term: string(runes[i]), Pos: pos})
+			pos++
+		} else {
+			for k := i; k+1 < j; k++ {
+				tokens = append(tokens, Token{Term: string(runes[k : k+2]), Pos: pos})
+				pos++
+			}
+		}
+		i = j
+	}
+	return tokens
+}
+
+// normalize folds full-width ASCII variants (e.g. "ＤＤＤ") to ASCII and lower-cases text.
+func normalize(text string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			return r - 0xFEE0
+		}
+		return r
+	}, text))
+}
@@ -0,0 +1,36 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"Domain-Driven Design", []string{"domain", "driven", "design"}},
+		{"ドメイン", []string{"ドメ", "メイ", "イン"}},
+		{"杉本啓", []string{"杉本", "本啓"}},
+		{"鬱の本", []string{"鬱の", "の本"}},
+		{"ＤＤＤ入門", []string{"ddd", "入門"}},
+		{"DDD本、Go言語", []string{"ddd", "本", "go", "言語"}},
+		{"80年代〜90年代", []string{"80", "年代", "90", "年代"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var terms []string
+			for i, tok := range Tokenize(tt.input) {
+				if tok.Pos != i {
+					t.Errorf("Token %q has position %d, want %d", tok.Term, tok.Pos, i)
+				}
+				terms = append(terms, tok.Term)
+			}
+			if !reflect.DeepEqual(terms, tt.expected) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.input, terms, tt.expected)
+			}
+		})
+	}
+}
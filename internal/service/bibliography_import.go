@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BibliographyImport is a bibliography read from an external source such as a BibTeX file.
+type BibliographyImport struct {
+	Key           string // identifier in the source, e.g. the citation key
+	Title         string
+	Author        string
+	Contributors  []domain.Contributor // derived from Author if empty
+	Publisher     string
+	ISBN          string
+	Type          string
+	ClassCode     string // empty if no classification could be determined
+	PublishedDate time.Time
+}
+
+// ImportResult reports what happened, or would happen in a dry run, to one imported item.
+type ImportResult struct {
+	Item         BibliographyImport
+	Bibliography *domain.Bibliography // created (or to be created); nil if skipped
+	SkipReason   string
+}
+
+// Skipped reports whether the item was not imported.
+func (r ImportResult) Skipped() bool {
+	return r.Bibliography == nil
+}
+
+// ImportBibliographies adds items with the same validation as AddBibliography.
+// Items are skipped if their Key is already used as a BibIndex, if a bibliography with the
+// same ISBN, or the same title, author and year, is already recorded (including earlier
+// in the same batch), or if they fail validation.
+// Every item is checked before any is saved, and the new bibliographies are saved together,
+// so an import is applied completely or not at all.
+// With dryRun nothing is saved, but the results show the BibIndexes that would be assigned.
+// An error is returned only if storage fails, in which case nothing was imported.
+func (s *BibliographyService) ImportBibliographies(items []BibliographyImport, dryRun bool) ([]ImportResult, error) {
+	existing, err := s.bibRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+	byIndex := make(map[string]*domain.Bibliography)
+	byISBN := make(map[string]*domain.Bibliography)
+	byWork := make(map[string]*domain.Bibliography)
+	for _, bib := range existing {
+		byIndex[bib.BibIndex] = bib
+		if !bib.ISBN.IsZero() {
+			byISBN[bib.ISBN.Compact()] = bib
+		}
+		byWork[workKey(bib.Title, bib.Author, bib.PublishedDate)] = bib
+	}
+
+	// BibIndexes assigned earlier in this batch; in a dry run they are not in the repository
+	reserved := make(map[string]bool)
+	results := make([]ImportResult, 0, len(items))
+	var created []*domain.Bibliography
+	for _, item := range items {
+		result := ImportResult{Item: item}
+		// Invalid ISBNs are reported by newBibliography
+		isbn, _ := domain.ParseISBN(item.ISBN)
+		work := workKey(item.Title, item.Author, item.PublishedDate)
+
+		switch {
+		case byIndex[item.Key] != nil:
+			result.SkipReason = fmt.Sprintf("already exists as %s", item.Key)
+		case !isbn.IsZero() && byISBN[isbn.Compact()] != nil:
+			result.SkipReason = fmt.Sprintf("ISBN %s already recorded as %s", isbn, byISBN[isbn.Compact()].BibIndex)
+		case byWork[work] != nil:
+			result.SkipReason = fmt.Sprintf("same title, author and year already recorded as %s", byWork[work].BibIndex)
+		case item.ClassCode == "":
+			result.SkipReason = "no classification mapped"
+		case item.PublishedDate.IsZero():
+			result.SkipReason = "published year is required"
+		default:
+			bib, err := s.newBibliography(item.Title, item.Author, item.Contributors, item.Publisher, item.ISBN, item.Type,
+				item.ClassCode, item.PublishedDate, "", "", "", reserved)
+			if err != nil {
+				result.SkipReason = err.Error()
+				break
+			}
+			result.Bibliography = bib
+			created = append(created, bib)
+			reserved[bib.BibIndex] = true
+			byIndex[bib.BibIndex] = bib
+			if !bib.ISBN.IsZero() {
+				byISBN[bib.ISBN.Compact()] = bib
+			}
+			byWork[work] = bib
+		}
+		results = append(results, result)
+	}
+
+	if dryRun || len(created) == 0 {
+		return results, nil
+	}
+	if err := s.bibRepo.SaveAll(created); err != nil {
+		return nil, fmt.Errorf("failed to save imported bibliographies: %w", err)
+	}
+	for _, bib := range created {
+		updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+			return index.IndexBibliography(bib)
+		})
+	}
+	return results, nil
+}
+
+// workKey identifies a work by case-insensitive title and author and the publication year.
+func workKey(title, author string, publishedDate time.Time) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", strings.ToLower(strings.TrimSpace(title)), strings.ToLower(strings.TrimSpace(author)), publishedDate.Year())
+}
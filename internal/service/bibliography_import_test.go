@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportBibliographies(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+	existing := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "B56SK24DMD", Code: "B56", Type: "Book", Title: "Existing", ISBN: domain.MustParseISBN("978-4-297-11820-4")}
+	bibRepo.Bibliographies[existing.ID] = existing
+
+	year := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []BibliographyImport{
+		{Key: "evans2003", Title: "Domain Driven Design", Author: "Eric Evans", Type: "Book", ClassCode: "56", PublishedDate: year},
+		{Key: "evans2015", Title: "Domain Driven Design Reference", Author: "Eric Evans", Type: "Book", ClassCode: "56", PublishedDate: year},
+		{Key: "B56SK24DMD", Title: "Already there", Author: "Someone", Type: "Book", ClassCode: "56", PublishedDate: year},
+		{Key: "sugimoto", Title: "Same ISBN", Author: "Someone", Type: "Book", ISBN: "9784297118204", ClassCode: "56", PublishedDate: year},
+		{Key: "unmapped", Title: "No Class", Author: "Someone", Type: "Book", PublishedDate: year},
+		{Key: "evans-again", Title: "domain driven design", Author: "Eric Evans", Type: "Book", ClassCode: "56", PublishedDate: year},
+		{Key: "noauthor", Title: "No Author", Type: "Book", ClassCode: "56", PublishedDate: year},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		results, err := svc.ImportBibliographies(items, dryRun)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(results) != len(items) {
+			t.Fatalf("Expected %d results, got %d", len(items), len(results))
+		}
+
+		// Collisions within the batch get suffixes even when nothing is saved
+		if results[0].Skipped() || results[0].Bibliography.BibIndex != "B56EE03DDD" {
+			t.Errorf("dryRun=%v: expected B56EE03DDD, got %+v", dryRun, results[0])
+		}
+		if results[1].Skipped() || results[1].Bibliography.BibIndex != "B56EE03DDDa" {
+			t.Errorf("dryRun=%v: expected B56EE03DDDa, got %+v", dryRun, results[1])
+		}
+		for i := 2; i < len(items); i++ {
+			if !results[i].Skipped() || results[i].SkipReason == "" {
+				t.Errorf("dryRun=%v: expected %s to be skipped with a reason, got %+v", dryRun, items[i].Key, results[i])
+			}
+		}
+
+		wantSaved := 3
+		if dryRun {
+			wantSaved = 1
+		}
+		if len(bibRepo.Bibliographies) != wantSaved {
+			t.Errorf("dryRun=%v: expected %d stored bibliographies, got %d", dryRun, wantSaved, len(bibRepo.Bibliographies))
+		}
+	}
+	if bibRepo.SaveAllCalls != 1 {
+		t.Errorf("Expected the import to be saved in one SaveAll call, got %d", bibRepo.SaveAllCalls)
+	}
+}
+
+func TestImportBibliographies_SaveFails(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{},
+		SaveAllErr:     errors.New("disk full"),
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	year := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []BibliographyImport{
+		{Key: "evans2003", Title: "Domain Driven Design", Author: "Eric Evans", Type: "Book", ClassCode: "56", PublishedDate: year},
+		{Key: "fowler2002", Title: "Patterns of Enterprise Application Architecture", Author: "Martin Fowler", Type: "Book", ClassCode: "56", PublishedDate: year},
+	}
+	results, err := svc.ImportBibliographies(items, false)
+	if err == nil {
+		t.Fatal("Expected an error when saving fails")
+	}
+	if results != nil {
+		t.Errorf("Expected no results when nothing was imported, got %+v", results)
+	}
+	if len(bibRepo.Bibliographies) != 0 {
+		t.Errorf("Expected nothing to be stored, got %d bibliographies", len(bibRepo.Bibliographies))
+	}
+}
@@ -2,24 +2,95 @@ package service
 
 import (
 	"bibliography_log/internal/domain"
+	"bibliography_log/internal/romaji"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
 
 type BibliographyService struct {
-	bibRepo   domain.BibliographyRepository
-	classRepo domain.ClassificationRepository
+	bibRepo    domain.BibliographyRepository
+	classRepo  domain.ClassificationRepository
+	reviewRepo domain.ReviewRepository
+	romanizer  *romaji.Romanizer
+	// searchIndex is kept in sync with saved bibliographies if set.
+	searchIndex domain.SearchIndex
+	// statusRepo, if set, loses a bibliography's reading status history when it is deleted.
+	statusRepo domain.ReadingStatusRepository
+	// sessionRepo, if set, loses a bibliography's reading sessions when it is deleted.
+	sessionRepo domain.ReadingSessionRepository
+	// highlightRepo, if set, loses a bibliography's highlights when it is deleted.
+	highlightRepo domain.HighlightRepository
+	// revisionRepo, if set, loses the revisions of reviews deleted along with a bibliography.
+	revisionRepo domain.ReviewRevisionRepository
 }
 
-func NewBibliographyService(bibRepo domain.BibliographyRepository, classRepo domain.ClassificationRepository) *BibliographyService {
+// NewBibliographyService creates the service. Japanese titles and authors are
+// romanized with the bundled reading dictionary unless SetRomanizer is called.
+func NewBibliographyService(bibRepo domain.BibliographyRepository, classRepo domain.ClassificationRepository, reviewRepo domain.ReviewRepository) *BibliographyService {
 	return &BibliographyService{
-		bibRepo:   bibRepo,
-		classRepo: classRepo,
+		bibRepo:    bibRepo,
+		classRepo:  classRepo,
+		reviewRepo: reviewRepo,
+		romanizer:  romaji.NewRomanizer(romaji.Bundled()),
 	}
 }
 
-func (s *BibliographyService) AddBibliography(title, author, publisher, isbn, typeStr string, classCodeNum int, publishedDate time.Time, titleEn, authorEn, manualBibIndex string) (*domain.Bibliography, error) {
+// SetRomanizer replaces the romanizer used for BibIndex generation,
+// e.g. to use a user-supplied reading dictionary.
+func (s *BibliographyService) SetRomanizer(r *romaji.Romanizer) {
+	s.romanizer = r
+}
+
+// SetSearchIndex makes the service update index whenever bibliographies are saved or deleted.
+func (s *BibliographyService) SetSearchIndex(index domain.SearchIndex) {
+	s.searchIndex = index
+}
+
+// SetReadingStatusRepository makes DeleteBibliography remove the reading status history
+// of the deleted bibliography.
+func (s *BibliographyService) SetReadingStatusRepository(repo domain.ReadingStatusRepository) {
+	s.statusRepo = repo
+}
+
+// SetReadingSessionRepository makes DeleteBibliography remove the reading sessions
+// of the deleted bibliography.
+func (s *BibliographyService) SetReadingSessionRepository(repo domain.ReadingSessionRepository) {
+	s.sessionRepo = repo
+}
+
+// SetHighlightRepository makes DeleteBibliography remove the highlights of the deleted
+// bibliography. Highlights are excerpts of the bibliography and are not kept as orphans.
+func (s *BibliographyService) SetHighlightRepository(repo domain.HighlightRepository) {
+	s.highlightRepo = repo
+}
+
+// SetReviewRevisionRepository makes DeleteBibliography remove the revisions of the reviews
+// it deletes.
+func (s *BibliographyService) SetReviewRevisionRepository(repo domain.ReviewRevisionRepository) {
+	s.revisionRepo = repo
+}
+
+// AddBibliography records a new bibliography. Either the author line or contributors must be
+// given; the one left out is derived from the other (see resolveContributors).
+func (s *BibliographyService) AddBibliography(title, author string, contributors []domain.Contributor, publisher, isbn, typeStr, classCode string, publishedDate time.Time, titleEn, authorEn, manualBibIndex string) (*domain.Bibliography, error) {
+	bib, err := s.newBibliography(title, author, contributors, publisher, isbn, typeStr, classCode, publishedDate, titleEn, authorEn, manualBibIndex, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.bibRepo.Save(bib); err != nil {
+		return nil, fmt.Errorf("failed to save bibliography: %w", err)
+	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.IndexBibliography(bib)
+	})
+	return bib, nil
+}
+
+// newBibliography validates the input and builds a bibliography with a unique BibIndex
+// without saving it. BibIndexes in reserved count as taken in addition to stored ones.
+func (s *BibliographyService) newBibliography(title, author string, contributors []domain.Contributor, publisher, isbnStr, typeStr, classCodeStr string, publishedDate time.Time, titleEn, authorEn, manualBibIndex string, reserved map[string]bool) (*domain.Bibliography, error) {
 	// Normalize inputs by trimming whitespace
 	title = strings.TrimSpace(title)
 	author = strings.TrimSpace(author)
@@ -31,82 +102,83 @@ func (s *BibliographyService) AddBibliography(title, author, publisher, isbn, ty
 
 	// Input validation for required fields
 	if title == "" {
-		return nil, fmt.Errorf("title is required and cannot be empty")
+		return nil, domain.Invalid(fmt.Errorf("title is required and cannot be empty"))
+	}
+	if author == "" && len(contributors) == 0 {
+		return nil, domain.Invalid(fmt.Errorf("author is required and cannot be empty"))
 	}
-	if author == "" {
-		return nil, fmt.Errorf("author is required and cannot be empty")
+	author, contributors, err := resolveContributors(author, contributors)
+	if err != nil {
+		return nil, domain.Invalid(err)
 	}
 	if typeStr == "" {
-		return nil, fmt.Errorf("type is required and cannot be empty")
+		return nil, domain.Invalid(fmt.Errorf("type is required and cannot be empty"))
+	}
+	isbn, err := domain.ParseISBN(isbnStr)
+	if err != nil {
+		return nil, domain.Invalid(err)
+	}
+	classCode, err := domain.ParseClassCode(classCodeStr)
+	if err != nil {
+		return nil, domain.Invalid(err)
 	}
 
-	// Check for Japanese text and require English translations
-	// Only required if manualBibIndex is NOT provided
+	// Resolve the Latin text used for BibIndex generation. Japanese text is romanized
+	// unless an English translation is given. Only needed if manualBibIndex is NOT provided
+	var titleForIndex, authorForIndex string
 	if manualBibIndex == "" {
-		if containsJapanese(title) && titleEn == "" {
-			return nil, fmt.Errorf("title contains Japanese characters; please provide English translation via -title-en flag")
-		}
-		if containsJapanese(author) && authorEn == "" {
-			return nil, fmt.Errorf("author contains Japanese characters; please provide English translation via -author-en flag")
+		lead, _ := domain.LeadAuthor(contributors)
+		titleForIndex, authorForIndex, err = s.indexSources(title, lead, titleEn, authorEn)
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	// 1. Find Classification
-	class, err := s.classRepo.FindByCodeNum(classCodeNum)
+	class, err := s.classRepo.FindByCode(classCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find classification: %w", err)
 	}
 	if class == nil {
-		return nil, fmt.Errorf("classification with code %d not found", classCodeNum)
+		return nil, domain.Invalid(fmt.Errorf("classification with code %s not found", classCode))
 	}
 
 	// 2. Generate BibIndex
 	// Format: Code + AuthorInitials + Year + TitleInitials
 	// The Code is constructed by concatenating a type prefix (first letter of the type string, e.g. "B" for "Book")
-	// with the classification code number (e.g. 56 for "Technology").
-	// Example: "Book" type and classification code 56 yields "B56".
-	typePrefix := string(typeStr[0])
-	code := fmt.Sprintf("%s%d", typePrefix, class.CodeNum)
+	// with the classification code (e.g. 56 for "Technology").
+	// Example: "Book" type and classification code 56 yields "B56", and code 547.48 yields "B547.48".
+	code := generateCode(typeStr, class.Code)
 
+	// BibIndex must be unique: manual indexes are rejected on collision,
+	// generated ones get a disambiguating suffix.
+	id := domain.NewBibliographyID()
 	var bibIndex string
 	if manualBibIndex != "" {
+		if err := s.ensureBibIndexAvailable(manualBibIndex, id, reserved); err != nil {
+			return nil, err
+		}
 		bibIndex = manualBibIndex
 	} else {
-		// Use English versions for BibIndex generation if provided, otherwise use original
-		authorForIndex := author
-		if authorEn != "" {
-			authorForIndex = authorEn
-		}
-		titleForIndex := title
-		if titleEn != "" {
-			titleForIndex = titleEn
+		bibIndex, err = s.uniqueBibIndex(generateBibIndex(code, titleForIndex, authorForIndex, publishedDate), id, reserved)
+		if err != nil {
+			return nil, err
 		}
-
-		authorInitials := generateAuthorInitials(authorForIndex)
-		yearSuffix := publishedDate.Format("06") // Last 2 digits of year
-		titleInitials := generateTitleInitials(titleForIndex)
-
-		bibIndex = fmt.Sprintf("%s%s%s%s", code, authorInitials, yearSuffix, titleInitials)
 	}
 
 	// 3. Create Entity
 	bib := &domain.Bibliography{
-		ID:            domain.NewBibliographyID(),
+		ID:            id,
 		BibIndex:      bibIndex,
 		Code:          code,
 		Type:          typeStr,
 		Title:         title,
 		Author:        author,
+		Contributors:  contributors,
 		Publisher:     publisher,
 		ISBN:          isbn,
 		PublishedDate: publishedDate,
 	}
-
-	// 4. Save
-	if err := s.bibRepo.Save(bib); err != nil {
-		return nil, fmt.Errorf("failed to save bibliography: %w", err)
-	}
-
 	return bib, nil
 }
 
@@ -114,33 +186,394 @@ func (s *BibliographyService) ListBibliographies(limit, offset int) ([]*domain.B
 	return s.bibRepo.FindAll(limit, offset)
 }
 
+// FindBibliographies returns the bibliographies selected by query.
+func (s *BibliographyService) FindBibliographies(query domain.BibliographyQuery) ([]*domain.Bibliography, error) {
+	if query.YearFrom != 0 && query.YearTo != 0 && query.YearFrom > query.YearTo {
+		return nil, fmt.Errorf("year range is empty: %d is after %d", query.YearFrom, query.YearTo)
+	}
+	return s.bibRepo.Find(query)
+}
+
 func (s *BibliographyService) FindByBibIndex(bibIndex string) (*domain.Bibliography, error) {
 	return s.bibRepo.FindByBibIndex(bibIndex)
 }
 
-func (s *BibliographyService) AddClassification(codeNum int, name string) (*domain.Classification, error) {
+func (s *BibliographyService) FindByID(id domain.BibliographyID) (*domain.Bibliography, error) {
+	return s.bibRepo.FindByID(id)
+}
+
+// FindClassification resolves the classification a bibliography belongs to from its Code.
+// It returns nil if the classification no longer exists.
+func (s *BibliographyService) FindClassification(bib *domain.Bibliography) (*domain.Classification, error) {
+	code, err := bib.ClassCode()
+	if err != nil {
+		return nil, err
+	}
+	return s.classRepo.FindByCode(code)
+}
+
+// BibliographyUpdate describes a partial update to a bibliography.
+// Nil fields are left unchanged. TitleEn/AuthorEn are only used when the BibIndex is regenerated.
+type BibliographyUpdate struct {
+	Title  *string
+	Author *string
+	// Contributors replaces the contributors. If only one of Author and Contributors is
+	// set, the other is derived from it.
+	Contributors  []domain.Contributor
+	Publisher     *string
+	ISBN          *string
+	Type          *string
+	ClassCode     *string
+	PublishedDate *time.Time
+
+	TitleEn  string
+	AuthorEn string
+	// BibIndex sets the BibIndex manually and takes precedence over RegenerateBibIndex.
+	BibIndex *string
+	// RegenerateBibIndex regenerates the BibIndex when author, title, year, type or classification change.
+	RegenerateBibIndex bool
+}
+
+// UpdateBibliography applies a partial update to an existing bibliography.
+// String fields are trimmed, and title, author and type cannot be set to empty,
+// following the same rules as AddBibliography. Changing the type or classification
+// always recomputes Code; the BibIndex is only changed when requested.
+func (s *BibliographyService) UpdateBibliography(id domain.BibliographyID, update BibliographyUpdate) (*domain.Bibliography, error) {
+	bib, err := s.bibRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bibliography: %w", err)
+	}
+	if bib == nil {
+		return nil, fmt.Errorf("bibliography with ID %s %w", id, domain.ErrNotFound)
+	}
+
+	updated := *bib
+	indexFieldsChanged := false
+
+	if update.Title != nil {
+		title := strings.TrimSpace(*update.Title)
+		if title == "" {
+			return nil, domain.Invalid(fmt.Errorf("title cannot be empty"))
+		}
+		indexFieldsChanged = indexFieldsChanged || title != bib.Title
+		updated.Title = title
+	}
+	if update.Author != nil || update.Contributors != nil {
+		var author string
+		if update.Author != nil {
+			author = strings.TrimSpace(*update.Author)
+			if author == "" && len(update.Contributors) == 0 {
+				return nil, domain.Invalid(fmt.Errorf("author cannot be empty"))
+			}
+		}
+		author, contributors, err := resolveContributors(author, update.Contributors)
+		if err != nil {
+			return nil, domain.Invalid(err)
+		}
+		updated.Author, updated.Contributors = author, contributors
+		oldLead, _ := bib.LeadAuthor()
+		newLead, _ := updated.LeadAuthor()
+		indexFieldsChanged = indexFieldsChanged || oldLead != newLead
+	}
+	if update.Type != nil {
+		typeStr := strings.TrimSpace(*update.Type)
+		if typeStr == "" {
+			return nil, domain.Invalid(fmt.Errorf("type cannot be empty"))
+		}
+		updated.Type = typeStr
+	}
+	if update.Publisher != nil {
+		updated.Publisher = strings.TrimSpace(*update.Publisher)
+	}
+	if update.ISBN != nil {
+		isbn, err := domain.ParseISBN(*update.ISBN)
+		if err != nil {
+			return nil, domain.Invalid(err)
+		}
+		updated.ISBN = isbn
+	}
+	if update.PublishedDate != nil {
+		indexFieldsChanged = indexFieldsChanged || update.PublishedDate.Year() != bib.PublishedDate.Year()
+		updated.PublishedDate = *update.PublishedDate
+	}
+
+	// Recompute Code from the (possibly new) type and classification
+	classCode, err := bib.ClassCode()
+	if err != nil {
+		return nil, err
+	}
+	if update.ClassCode != nil {
+		code, err := domain.ParseClassCode(*update.ClassCode)
+		if err != nil {
+			return nil, domain.Invalid(err)
+		}
+		class, err := s.classRepo.FindByCode(code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find classification: %w", err)
+		}
+		if class == nil {
+			return nil, domain.Invalid(fmt.Errorf("classification with code %s not found", code))
+		}
+		classCode = class.Code
+	}
+	updated.Code = generateCode(updated.Type, classCode)
+	indexFieldsChanged = indexFieldsChanged || updated.Code != bib.Code
+
+	switch {
+	case update.BibIndex != nil:
+		bibIndex := strings.TrimSpace(*update.BibIndex)
+		if bibIndex == "" {
+			return nil, domain.Invalid(fmt.Errorf("bib-index cannot be empty"))
+		}
+		if err := s.ensureBibIndexAvailable(bibIndex, bib.ID, nil); err != nil {
+			return nil, err
+		}
+		updated.BibIndex = bibIndex
+	case update.RegenerateBibIndex && indexFieldsChanged:
+		lead, _ := updated.LeadAuthor()
+		titleForIndex, authorForIndex, err := s.indexSources(updated.Title, lead, strings.TrimSpace(update.TitleEn), strings.TrimSpace(update.AuthorEn))
+		if err != nil {
+			return nil, err
+		}
+		updated.BibIndex, err = s.uniqueBibIndex(generateBibIndex(updated.Code, titleForIndex, authorForIndex, updated.PublishedDate), bib.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.bibRepo.Save(&updated); err != nil {
+		return nil, fmt.Errorf("failed to update bibliography: %w", err)
+	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.IndexBibliography(&updated)
+	})
+	return &updated, nil
+}
+
+// ReviewDeletePolicy decides what happens to reviews when their bibliography is deleted.
+type ReviewDeletePolicy int
+
+const (
+	// RefuseIfReviewed refuses to delete a bibliography that still has reviews.
+	RefuseIfReviewed ReviewDeletePolicy = iota
+	// CascadeReviews deletes the bibliography's reviews along with it.
+	CascadeReviews
+	// KeepOrphanReviews deletes only the bibliography and leaves its reviews in place.
+	// It is refused by backends enforcing references (see OrphanReviewsAllowed).
+	KeepOrphanReviews
+)
+
+// OrphanReviewsAllowed reports whether DeleteBibliography can keep the reviews of a deleted
+// bibliography. Storage that enforces references to bibliographies, such as SQLite, cannot.
+func (s *BibliographyService) OrphanReviewsAllowed() bool {
+	enforcer, ok := s.bibRepo.(domain.ReferenceEnforcer)
+	return !ok || !enforcer.EnforcesReferences()
+}
+
+// DeleteBibliography deletes a bibliography, handling its reviews according to policy, along
+// with its reading status history, reading sessions and highlights. It returns the deleted
+// bibliography and the reviews that were deleted or orphaned.
+// The policy is checked before anything is deleted. Repositories implementing
+// domain.BibliographyCascadeDeleter delete everything in one transaction; otherwise the
+// dependent records are deleted first and the bibliography last, so a failure never leaves
+// records pointing at a missing bibliography.
+func (s *BibliographyService) DeleteBibliography(id domain.BibliographyID, policy ReviewDeletePolicy) (*domain.Bibliography, []*domain.Review, error) {
+	bib, err := s.bibRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find bibliography: %w", err)
+	}
+	if bib == nil {
+		return nil, nil, fmt.Errorf("bibliography with ID %s %w", id, domain.ErrNotFound)
+	}
+
+	reviews, err := s.reviewRepo.FindByBookID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find reviews: %w", err)
+	}
+
+	switch policy {
+	case RefuseIfReviewed:
+		if len(reviews) > 0 {
+			return nil, nil, fmt.Errorf("bibliography %s has %d review(s); delete them first or choose to cascade or keep them", bib.BibIndex, len(reviews))
+		}
+	case CascadeReviews:
+		// The reviews are deleted along with the bibliography below.
+	case KeepOrphanReviews:
+		if !s.OrphanReviewsAllowed() {
+			return nil, nil, domain.Invalid(fmt.Errorf("this storage backend cannot keep reviews without their bibliography; delete them along with it instead"))
+		}
+		// Reviews are intentionally left in place.
+	default:
+		return nil, nil, fmt.Errorf("unknown review delete policy %d", policy)
+	}
+
+	withReviews := policy == CascadeReviews
+	if deleter, ok := s.bibRepo.(domain.BibliographyCascadeDeleter); ok {
+		if err := deleter.DeleteWithDependents(id, withReviews); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete bibliography: %w", err)
+		}
+		if withReviews {
+			for _, review := range reviews {
+				updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+					return index.RemoveReview(review.ID)
+				})
+			}
+		}
+	} else {
+		if err := s.deleteDependents(id, reviews, withReviews); err != nil {
+			return nil, nil, err
+		}
+		if err := s.bibRepo.Delete(id); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete bibliography: %w", err)
+		}
+	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.RemoveBibliography(id)
+	})
+	return bib, reviews, nil
+}
+
+// deleteDependents deletes the records referring to a bibliography, one repository at a
+// time, for repositories that cannot delete them in one transaction with the bibliography.
+func (s *BibliographyService) deleteDependents(id domain.BibliographyID, reviews []*domain.Review, withReviews bool) error {
+	if withReviews {
+		for _, review := range reviews {
+			if s.revisionRepo != nil {
+				if err := s.revisionRepo.DeleteByReviewID(review.ID); err != nil {
+					return fmt.Errorf("failed to delete revisions of review %s: %w", review.ID, err)
+				}
+			}
+			if err := s.reviewRepo.Delete(review.ID); err != nil {
+				return fmt.Errorf("failed to delete review %s: %w", review.ID, err)
+			}
+			updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+				return index.RemoveReview(review.ID)
+			})
+		}
+	}
+	if s.statusRepo != nil {
+		if err := s.statusRepo.DeleteByBookID(id); err != nil {
+			return fmt.Errorf("failed to delete reading status: %w", err)
+		}
+	}
+	if s.sessionRepo != nil {
+		sessions, err := s.sessionRepo.FindByBookID(id)
+		if err != nil {
+			return fmt.Errorf("failed to find reading sessions: %w", err)
+		}
+		for _, session := range sessions {
+			if err := s.sessionRepo.Delete(session.ID); err != nil {
+				return fmt.Errorf("failed to delete reading session %s: %w", session.ID, err)
+			}
+		}
+	}
+	if s.highlightRepo != nil {
+		highlights, err := s.highlightRepo.FindByBookID(id)
+		if err != nil {
+			return fmt.Errorf("failed to find highlights: %w", err)
+		}
+		for _, highlight := range highlights {
+			if err := s.highlightRepo.Delete(highlight.ID); err != nil {
+				return fmt.Errorf("failed to delete highlight %s: %w", highlight.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DuplicateBibIndex is a BibIndex shared by more than one bibliography.
+type DuplicateBibIndex struct {
+	BibIndex       string
+	Bibliographies []*domain.Bibliography
+}
+
+// FindDuplicateBibIndexes reports every BibIndex used by more than one bibliography,
+// sorted by BibIndex. Bibliographies within a group keep their storage order.
+func (s *BibliographyService) FindDuplicateBibIndexes() ([]DuplicateBibIndex, error) {
+	all, err := s.bibRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+
+	groups := make(map[string][]*domain.Bibliography)
+	for _, bib := range all {
+		groups[bib.BibIndex] = append(groups[bib.BibIndex], bib)
+	}
+
+	var duplicates []DuplicateBibIndex
+	for bibIndex, bibs := range groups {
+		if len(bibs) > 1 {
+			duplicates = append(duplicates, DuplicateBibIndex{BibIndex: bibIndex, Bibliographies: bibs})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].BibIndex < duplicates[j].BibIndex
+	})
+	return duplicates, nil
+}
+
+// ensureBibIndexAvailable returns an error if bibIndex is used by a bibliography other than self
+// or is in reserved.
+// The repository enforces uniqueness again when saving, which catches concurrent writers.
+func (s *BibliographyService) ensureBibIndexAvailable(bibIndex string, self domain.BibliographyID, reserved map[string]bool) error {
+	if reserved[bibIndex] {
+		return domain.Invalid(fmt.Errorf("BibIndex %s is already used", bibIndex))
+	}
+	existing, err := s.bibRepo.FindByBibIndex(bibIndex)
+	if err != nil {
+		return fmt.Errorf("failed to check BibIndex uniqueness: %w", err)
+	}
+	if existing != nil && existing.ID != self {
+		return domain.Invalid(fmt.Errorf("BibIndex %s is already used by %q (%s)", bibIndex, existing.Title, existing.ID))
+	}
+	return nil
+}
+
+// uniqueBibIndex returns base if it is free (or already belongs to self), otherwise the
+// first free candidate among base+"a", base+"b", ... base+"z". Candidates in reserved are skipped.
+func (s *BibliographyService) uniqueBibIndex(base string, self domain.BibliographyID, reserved map[string]bool) (string, error) {
+	candidate := base
+	for suffix := 'a'; ; suffix++ {
+		existing, err := s.bibRepo.FindByBibIndex(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check BibIndex uniqueness: %w", err)
+		}
+		if !reserved[candidate] && (existing == nil || existing.ID == self) {
+			return candidate, nil
+		}
+		if suffix > 'z' {
+			return "", domain.Invalid(fmt.Errorf("too many bibliographies share BibIndex %s; please provide one via -bib-index", base))
+		}
+		candidate = base + string(suffix)
+	}
+}
+
+// AddClassification records a classification. Its place in the hierarchy follows from the
+// code (see domain.BuildClassTree), so parents need not exist.
+func (s *BibliographyService) AddClassification(codeStr string, name string) (*domain.Classification, error) {
 	// Validate name is not empty or whitespace
 	if strings.TrimSpace(name) == "" {
-		return nil, fmt.Errorf("classification name must not be empty")
+		return nil, domain.Invalid(fmt.Errorf("classification name must not be empty"))
 	}
 
-	if codeNum < 0 || codeNum >= 100000 {
-		return nil, fmt.Errorf("classification code number must be between 0 and 999999")
+	code, err := domain.ParseClassCode(codeStr)
+	if err != nil {
+		return nil, domain.Invalid(err)
 	}
 
 	// Check if classification already exists
-	existing, err := s.classRepo.FindByCodeNum(codeNum)
+	existing, err := s.classRepo.FindByCode(code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for existing classification: %w", err)
 	}
 	if existing != nil {
-		return nil, fmt.Errorf("classification with code %d already exists", codeNum)
+		return nil, domain.Invalid(fmt.Errorf("classification with code %s already exists", code))
 	}
 
 	class := &domain.Classification{
-		ID:      domain.NewClassificationID(),
-		CodeNum: codeNum,
-		Name:    name,
+		ID:   domain.NewClassificationID(),
+		Code: code,
+		Name: name,
 	}
 	if err := s.classRepo.Save(class); err != nil {
 		return nil, fmt.Errorf("failed to save classification: %w", err)
@@ -148,6 +581,98 @@ func (s *BibliographyService) AddClassification(codeNum int, name string) (*doma
 	return class, nil
 }
 
+// indexSources returns the Latin title and author used for BibIndex generation. Only the
+// lead author counts, not translators or co-authors.
+// English translations take precedence, then the lead author's NameEn; otherwise Japanese
+// text is romanized. Parenthesized notes in the name are ignored before romanizing.
+// Both are folded to ASCII, so "Gödel" and "Ichirō" give the initials G and I.
+func (s *BibliographyService) indexSources(title string, lead domain.Contributor, titleEn, authorEn string) (string, string, error) {
+	titleForIndex := titleEn
+	if titleForIndex == "" {
+		romanized, err := s.romanize(title)
+		if err != nil {
+			return "", "", domain.Invalid(fmt.Errorf("title contains Japanese characters that could not be romanized (%w); please provide English translation via -title-en flag", err))
+		}
+		titleForIndex = romanized
+	}
+
+	authorForIndex := authorEn
+	if authorForIndex == "" {
+		authorForIndex = lead.NameEn
+	}
+	if authorForIndex == "" {
+		romanized, err := s.romanize(stripParenthesized(lead.Name))
+		if err != nil {
+			return "", "", domain.Invalid(fmt.Errorf("author contains Japanese characters that could not be romanized (%w); please provide English translation via -author-en flag", err))
+		}
+		authorForIndex = romanized
+	}
+	return romaji.FoldASCII(titleForIndex), romaji.FoldASCII(authorForIndex), nil
+}
+
+// resolveContributors validates contributors and completes the author line and the
+// contributors from each other: without contributors they are read from the author line
+// (e.g. "マシュー スチュワート(稲岡大志訳)"), and without an author line it is formatted
+// from the contributors.
+func resolveContributors(author string, contributors []domain.Contributor) (string, []domain.Contributor, error) {
+	for _, c := range contributors {
+		if err := c.Validate(); err != nil {
+			return "", nil, err
+		}
+	}
+	switch {
+	case len(contributors) == 0:
+		contributors = domain.ParseCreditLine(author)
+	case author == "":
+		author = domain.CreditLine(contributors)
+	}
+	return author, contributors, nil
+}
+
+// romanize returns text unchanged unless it contains Japanese, which is converted to Hepburn.
+func (s *BibliographyService) romanize(text string) (string, error) {
+	if !containsJapanese(text) {
+		return text, nil
+	}
+	return s.romanizer.Romanize(text)
+}
+
+// stripParenthesized removes text in half- or full-width parentheses, e.g. translator notes.
+func stripParenthesized(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(', '（':
+			depth++
+			b.WriteRune(' ')
+		case ')', '）':
+			if depth > 0 {
+				depth--
+			}
+		default:
+			if depth == 0 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// generateCode concatenates the type prefix (first letter of the type) with the classification code number.
+func generateCode(typeStr string, classCode domain.ClassCode) string {
+	return string(typeStr[0]) + classCode.String()
+}
+
+// generateBibIndex builds Code + AuthorInitials + Year + TitleInitials from Latin title and author.
+func generateBibIndex(code, titleForIndex, authorForIndex string, publishedDate time.Time) string {
+	authorInitials := generateAuthorInitials(authorForIndex)
+	yearSuffix := publishedDate.Format("06") // Last 2 digits of year
+	titleInitials := generateTitleInitials(titleForIndex)
+
+	return fmt.Sprintf("%s%s%s%s", code, authorInitials, yearSuffix, titleInitials)
+}
+
 func generateAuthorInitials(author string) string {
 	parts := strings.Fields(author)
 	if len(parts) == 0 {
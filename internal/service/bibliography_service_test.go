@@ -2,6 +2,8 @@ package service
 
 import (
 	"bibliography_log/internal/domain"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -10,6 +12,8 @@ import (
 type MockBibliographyRepository struct {
 	SavedBibliography *domain.Bibliography
 	Bibliographies    map[domain.BibliographyID]*domain.Bibliography
+	SaveAllErr        error // returned by SaveAll without saving anything
+	SaveAllCalls      int
 }
 
 func (m *MockBibliographyRepository) Save(b *domain.Bibliography) error {
@@ -21,6 +25,19 @@ func (m *MockBibliographyRepository) Save(b *domain.Bibliography) error {
 	return nil
 }
 
+func (m *MockBibliographyRepository) SaveAll(bibs []*domain.Bibliography) error {
+	m.SaveAllCalls++
+	if m.SaveAllErr != nil {
+		return m.SaveAllErr
+	}
+	for _, b := range bibs {
+		if err := m.Save(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *MockBibliographyRepository) FindAll(limit, offset int) ([]*domain.Bibliography, error) {
 	var bibs []*domain.Bibliography
 	for _, b := range m.Bibliographies {
@@ -40,6 +57,19 @@ func (m *MockBibliographyRepository) FindAll(limit, offset int) ([]*domain.Bibli
 	return bibs, nil
 }
 
+// Find evaluates query in memory. The mock knows nothing about reviews, so every
+// bibliography counts as unreviewed.
+func (m *MockBibliographyRepository) Find(query domain.BibliographyQuery) ([]*domain.Bibliography, error) {
+	var bibs []*domain.Bibliography
+	for _, b := range m.Bibliographies {
+		if query.Matches(b, false, domain.ReadingStatusNone) {
+			bibs = append(bibs, b)
+		}
+	}
+	query.SortBibliographies(bibs)
+	return query.Page(bibs), nil
+}
+
 func (m *MockBibliographyRepository) FindByID(id domain.BibliographyID) (*domain.Bibliography, error) {
 	if m.Bibliographies == nil {
 		return nil, nil
@@ -47,54 +77,83 @@ func (m *MockBibliographyRepository) FindByID(id domain.BibliographyID) (*domain
 	return m.Bibliographies[id], nil
 }
 
-func (m *MockBibliographyRepository) FindByBibIndex(_ string) (*domain.Bibliography, error) {
+func (m *MockBibliographyRepository) FindByBibIndex(bibIndex string) (*domain.Bibliography, error) {
+	for _, b := range m.Bibliographies {
+		if b.BibIndex == bibIndex {
+			return b, nil
+		}
+	}
 	return nil, nil
 }
 
+func (m *MockBibliographyRepository) Delete(id domain.BibliographyID) error {
+	delete(m.Bibliographies, id)
+	return nil
+}
+
 // MockClassificationRepository is a mock implementation of domain.ClassificationRepository
 type MockClassificationRepository struct {
-	Classifications map[int]*domain.Classification
+	Classifications map[domain.ClassCode]*domain.Classification
 }
 
 func (m *MockClassificationRepository) Save(c *domain.Classification) error {
 	if m.Classifications == nil {
-		m.Classifications = make(map[int]*domain.Classification)
+		m.Classifications = make(map[domain.ClassCode]*domain.Classification)
+	}
+	// A renumbered classification keeps its ID
+	for code, existing := range m.Classifications {
+		if existing.ID == c.ID {
+			delete(m.Classifications, code)
+		}
 	}
-	m.Classifications[c.CodeNum] = c
+	m.Classifications[c.Code] = c
 	return nil
 }
 
 func (m *MockClassificationRepository) FindAll(limit, offset int) ([]*domain.Classification, error) {
-	return nil, nil
+	var all []*domain.Classification
+	for _, c := range m.Classifications {
+		all = append(all, c)
+	}
+	return all, nil
 }
 
-func (m *MockClassificationRepository) FindByCodeNum(codeNum int) (*domain.Classification, error) {
-	if c, ok := m.Classifications[codeNum]; ok {
+func (m *MockClassificationRepository) FindByCode(code domain.ClassCode) (*domain.Classification, error) {
+	if c, ok := m.Classifications[code]; ok {
 		return c, nil
 	}
 	return nil, nil
 }
 
+func (m *MockClassificationRepository) Delete(id domain.ClassificationID) error {
+	for code, c := range m.Classifications {
+		if c.ID == id {
+			delete(m.Classifications, code)
+		}
+	}
+	return nil
+}
+
 func TestAddBibliography(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			56: {CodeNum: 56, Name: "Technology"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case
 	title := "Domain Driven Design"
 	author := "Eric Evans"
-	isbn := "978-0321125217"
-	desc := "Tackling Complexity"
+	publisher := "Addison-Wesley"
+	isbn := "0-321-12521-5"
 	typeStr := "Book"
-	classCode := 56
+	classCode := "56"
 	pubDate := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	bib, err := svc.AddBibliography(title, author, isbn, desc, typeStr, classCode, pubDate, "", "", "")
+	bib, err := svc.AddBibliography(title, author, nil, publisher, isbn, typeStr, classCode, pubDate, "", "", "")
 	// Assertions
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -114,22 +173,146 @@ func TestAddBibliography(t *testing.T) {
 		t.Errorf("Expected BibIndex %s, got %s", expectedBibIndex, bib.BibIndex)
 	}
 
+	// ISBN-10 input is stored as ISBN-13
+	if bib.ISBN.Compact() != "9780321125217" {
+		t.Errorf("Expected ISBN 9780321125217, got %s", bib.ISBN.Compact())
+	}
+
 	if bibRepo.SavedBibliography != bib {
 		t.Error("Expected bibliography to be saved to repository")
 	}
 }
 
+func TestAddBibliography_Contributors(t *testing.T) {
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(&MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}, classRepo, &MockReviewRepository{})
+	year := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Only the lead author counts for the BibIndex, using the romanized name if given
+	contributors := []domain.Contributor{
+		{Name: "稲岡大志", Role: domain.RoleTranslator},
+		{Name: "マシュー スチュワート", Role: domain.RoleAuthor, NameEn: "Matthew Stewart"},
+	}
+	bib, err := svc.AddBibliography("The Management Myth", "", contributors, "", "", "Book", "56", year, "", "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bib.BibIndex != "B56MS24TMM" {
+		t.Errorf("Expected BibIndex B56MS24TMM, got %s", bib.BibIndex)
+	}
+	if bib.Author != "稲岡大志 (translator); マシュー スチュワート" {
+		t.Errorf("Expected the author line to be formatted from the contributors, got %q", bib.Author)
+	}
+
+	// Contributors are read from the author line if not given
+	bib, err = svc.AddBibliography("Design Patterns", "Gamma, Erich and Helm, Richard (ed.)", nil, "", "", "Book", "56", year, "", "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []domain.Contributor{{Name: "Gamma, Erich", Role: domain.RoleAuthor}, {Name: "Helm, Richard", Role: domain.RoleEditor}}
+	if !reflect.DeepEqual(bib.Contributors, want) {
+		t.Errorf("Contributors = %+v, want %+v", bib.Contributors, want)
+	}
+	if bib.BibIndex != "B56GE24DP" {
+		t.Errorf("Expected BibIndex B56GE24DP, got %s", bib.BibIndex)
+	}
+
+	if _, err := svc.AddBibliography("Title", "", []domain.Contributor{{Name: "X", Role: "narrator"}}, "", "", "Book", "56", year, "", "", ""); err == nil {
+		t.Error("Expected error for an unknown role, got nil")
+	}
+	if _, err := svc.AddBibliography("Title", "", []domain.Contributor{{Role: domain.RoleAuthor}}, "", "", "Book", "56", year, "", "", ""); err == nil {
+		t.Error("Expected error for a contributor without a name, got nil")
+	}
+}
+
+func TestParseCreditLine(t *testing.T) {
+	author := func(name string) domain.Contributor { return domain.Contributor{Name: name, Role: domain.RoleAuthor} }
+	tests := []struct {
+		line string
+		want []domain.Contributor
+	}{
+		{"Eric Evans", []domain.Contributor{author("Eric Evans")}},
+		{"Evans, Eric", []domain.Contributor{author("Evans, Eric")}},
+		{"マシュー スチュワート(稲岡大志訳)", []domain.Contributor{author("マシュー スチュワート"), {Name: "稲岡大志", Role: domain.RoleTranslator}}},
+		{"エリック・エヴァンス 著、今関剛 監訳", []domain.Contributor{author("エリック・エヴァンス"), {Name: "今関剛", Role: domain.RoleTranslator}}},
+		{"Knuth & Lamport; Helm (Editor)", []domain.Contributor{author("Knuth"), author("Lamport"), {Name: "Helm", Role: domain.RoleEditor}}},
+		{"山田千絵", []domain.Contributor{author("山田千絵")}},
+		{"宮沢賢治 (作); 田中 絵", []domain.Contributor{author("宮沢賢治"), {Name: "田中", Role: domain.RoleIllustrator}}},
+		{"Robert C. Martin (Uncle Bob)", []domain.Contributor{author("Robert C. Martin")}},
+		{"Sandra Anderson and Ben Andrews", []domain.Contributor{author("Sandra Anderson"), author("Ben Andrews")}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := domain.ParseCreditLine(tt.line)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCreditLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+		if len(got) > 0 && !reflect.DeepEqual(domain.ParseCreditLine(domain.CreditLine(got)), got) {
+			t.Errorf("CreditLine(%+v) = %q does not parse back", got, domain.CreditLine(got))
+		}
+	}
+}
+
+func TestParseContributor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    domain.Contributor
+		wantErr bool
+	}{
+		{"Eric Evans", domain.Contributor{Name: "Eric Evans", Role: domain.RoleAuthor}, false},
+		{"translator: 稲岡大志 = Taishi Inaoka", domain.Contributor{Name: "稲岡大志", Role: domain.RoleTranslator, NameEn: "Taishi Inaoka"}, false},
+		{"Host:Jane Doe", domain.Contributor{Name: "Jane Doe", Role: domain.RoleHost}, false},
+		{"訳:稲岡大志", domain.Contributor{Name: "稲岡大志", Role: domain.RoleTranslator}, false},
+		{"narrator:Jane Doe", domain.Contributor{}, true},
+		{"editor:", domain.Contributor{}, true},
+	}
+	for _, tt := range tests {
+		got, err := domain.ParseContributor(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseContributor(%q) = %+v, %v; want %+v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+		if !tt.wantErr {
+			if again, _ := domain.ParseContributor(got.String()); again != got {
+				t.Errorf("String() = %q does not parse back", got.String())
+			}
+		}
+	}
+}
+
+func TestAddBibliography_InvalidISBN(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	_, err := svc.AddBibliography("Domain Driven Design", "Eric Evans", nil, "", "978-0-321-12521-0", "Book", "56",
+		time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "")
+	if err == nil {
+		t.Fatal("Expected error for wrong check digit, got nil")
+	}
+	if bibRepo.SavedBibliography != nil {
+		t.Error("Expected nothing to be saved")
+	}
+}
+
 func TestAddClassification(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case
-	codeNum := 99
+	code := domain.ClassCode("547.48")
 	name := "Test Class"
 
-	class, err := svc.AddClassification(codeNum, name)
+	class, err := svc.AddClassification(" 547.48 ", name)
 	// Assertions
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -139,8 +322,8 @@ func TestAddClassification(t *testing.T) {
 		t.Fatal("Expected classification to be returned")
 	}
 
-	if class.CodeNum != codeNum {
-		t.Errorf("Expected CodeNum %d, got %d", codeNum, class.CodeNum)
+	if class.Code != code {
+		t.Errorf("Expected Code %s, got %s", code, class.Code)
 	}
 
 	if class.Name != name {
@@ -148,7 +331,7 @@ func TestAddClassification(t *testing.T) {
 	}
 
 	// Verify it was saved
-	saved, _ := classRepo.FindByCodeNum(codeNum)
+	saved, _ := classRepo.FindByCode(code)
 	if saved == nil {
 		t.Error("Expected classification to be saved to repository")
 	}
@@ -158,17 +341,17 @@ func TestAddClassification_Duplicate(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			99: {CodeNum: 99, Name: "Existing Class"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"99": {Code: "99", Name: "Existing Class"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case
-	codeNum := 99
+	code := "99"
 	name := "New Class"
 
-	_, err := svc.AddClassification(codeNum, name)
+	_, err := svc.AddClassification(code, name)
 
 	// Assertions
 	if err == nil {
@@ -180,14 +363,14 @@ func TestAddBibliography_EmptyTitle(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			56: {CodeNum: 56, Name: "Technology"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with empty title
-	_, err := svc.AddBibliography("", "Author", "ISBN", "Desc", "Book", 56, time.Now(), "", "", "")
+	_, err := svc.AddBibliography("", "Author", nil, "ISBN", "Desc", "Book", "56", time.Now(), "", "", "")
 
 	// Assertions
 	if err == nil {
@@ -202,14 +385,14 @@ func TestAddBibliography_EmptyAuthor(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			56: {CodeNum: 56, Name: "Technology"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with empty author
-	_, err := svc.AddBibliography("Title", "", "ISBN", "Desc", "Book", 56, time.Now(), "", "", "")
+	_, err := svc.AddBibliography("Title", "", nil, "ISBN", "Desc", "Book", "56", time.Now(), "", "", "")
 
 	// Assertions
 	if err == nil {
@@ -224,14 +407,14 @@ func TestAddBibliography_EmptyType(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			56: {CodeNum: 56, Name: "Technology"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with empty type
-	_, err := svc.AddBibliography("Title", "Author", "ISBN", "Desc", "", 56, time.Now(), "", "", "")
+	_, err := svc.AddBibliography("Title", "Author", nil, "ISBN", "Desc", "", "56", time.Now(), "", "", "")
 
 	// Assertions
 	if err == nil {
@@ -246,10 +429,10 @@ func TestAddClassification_EmptyName(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with empty name
-	_, err := svc.AddClassification(99, "")
+	_, err := svc.AddClassification("99", "")
 
 	// Assertions
 	if err == nil {
@@ -264,10 +447,10 @@ func TestAddClassification_WhitespaceName(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with whitespace-only name
-	_, err := svc.AddClassification(99, "   ")
+	_, err := svc.AddClassification("99", "   ")
 
 	// Assertions
 	if err == nil {
@@ -308,20 +491,21 @@ func TestAddBibliography_JapaneseWithEnglish(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			16: {CodeNum: 16, Name: "Philosophy"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"16": {Code: "16", Name: "Philosophy"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with Japanese title and author, with English translations
 	bib, err := svc.AddBibliography(
 		"マネジメント神話",
 		"マシュー・スチュワート",
+		nil,
 		"978-4750356884",
 		"",
 		"Book",
-		16,
+		"16",
 		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		"The Management Myth",
 		"Matthew Stewart",
@@ -358,20 +542,21 @@ func TestAddBibliography_JapaneseWithoutEnglish_TitleError(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			16: {CodeNum: 16, Name: "Philosophy"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"16": {Code: "16", Name: "Philosophy"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
-	// Test Case with Japanese title but no English translation
+	// Test Case with a Japanese title whose kanji have no known reading and no English translation
 	_, err := svc.AddBibliography(
-		"マネジメント神話",
+		"鬱の本",
 		"Matthew Stewart",
+		nil,
 		"",
 		"",
 		"Book",
-		16,
+		"16",
 		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		"", // No English title
 		"",
@@ -382,7 +567,7 @@ func TestAddBibliography_JapaneseWithoutEnglish_TitleError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error for Japanese title without English translation, got nil")
 	}
-	if err.Error() != "title contains Japanese characters; please provide English translation via -title-en flag" {
+	if err.Error() != "title contains Japanese characters that could not be romanized (no reading for 鬱); please provide English translation via -title-en flag" {
 		t.Errorf("Expected specific error message, got: %v", err)
 	}
 }
@@ -391,20 +576,21 @@ func TestAddBibliography_JapaneseWithoutEnglish_AuthorError(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			16: {CodeNum: 16, Name: "Philosophy"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"16": {Code: "16", Name: "Philosophy"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
-	// Test Case with Japanese author but no English translation
+	// Test Case with a Japanese author whose kanji have no known reading and no English translation
 	_, err := svc.AddBibliography(
 		"The Management Myth",
-		"マシュー・スチュワート",
+		"薔薇園子",
+		nil,
 		"",
 		"",
 		"Book",
-		16,
+		"16",
 		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		"",
 		"", // No English author
@@ -415,30 +601,76 @@ func TestAddBibliography_JapaneseWithoutEnglish_AuthorError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error for Japanese author without English translation, got nil")
 	}
-	if err.Error() != "author contains Japanese characters; please provide English translation via -author-en flag" {
+	if err.Error() != "author contains Japanese characters that could not be romanized (no reading for 薔薇園子); please provide English translation via -author-en flag" {
 		t.Errorf("Expected specific error message, got: %v", err)
 	}
 }
 
+func TestAddBibliography_JapaneseRomanized(t *testing.T) {
+	// Setup
+	bibRepo := &MockBibliographyRepository{}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"16": {Code: "16", Name: "Philosophy"},
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	tests := []struct {
+		name     string
+		title    string
+		author   string
+		class    string
+		expected string
+	}{
+		// Author: Sugimoto Kei, Title: Dētamoderingude Domeino Kudōsuru
+		{"kanji author", "データモデリングでドメインを駆動する", "杉本啓", "56", "B56SK24DDK"},
+		// Translator note in parentheses is ignored; Title: Manejimento Shinwa Gendai ...
+		{"katakana author with note", "マネジメント神話　現代ビジネス哲学の真実に迫る", "マシュー スチュワート(稲岡大志訳)", "16", "B16MS24MSG"},
+		// Diacritics and macrons are folded: Émile Zola, Title: Ōsaka
+		{"latin author with diacritics", "おおさか", "Émile Zola", "16", "B16EZ24O"},
+		{"latin single-word author", "こころ", "Gödel", "16", "B16GO24K"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bib, err := svc.AddBibliography(tt.title, tt.author, nil, "", "", "Book", tt.class,
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if bib.BibIndex != tt.expected {
+				t.Errorf("Expected BibIndex %s, got %s", tt.expected, bib.BibIndex)
+			}
+			// Original Japanese is stored
+			if bib.Title != tt.title || bib.Author != tt.author {
+				t.Errorf("Expected original title/author to be stored, got %s / %s", bib.Title, bib.Author)
+			}
+		})
+	}
+}
+
 func TestAddBibliography_ManualBibIndex(t *testing.T) {
 	// Setup
 	bibRepo := &MockBibliographyRepository{}
 	classRepo := &MockClassificationRepository{
-		Classifications: map[int]*domain.Classification{
-			56: {CodeNum: 56, Name: "Technology"},
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
 		},
 	}
-	svc := NewBibliographyService(bibRepo, classRepo)
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
 
 	// Test Case with manual BibIndex
 	manualIndex := "CUSTOM123"
 	bib, err := svc.AddBibliography(
 		"Domain Driven Design",
 		"Eric Evans",
+		nil,
+		"Addison-Wesley",
 		"978-0321125217",
-		"Desc",
 		"Book",
-		56,
+		"56",
 		time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
 		"", "", manualIndex,
 	)
@@ -450,3 +682,396 @@ func TestAddBibliography_ManualBibIndex(t *testing.T) {
 		t.Errorf("Expected BibIndex %s, got %s", manualIndex, bib.BibIndex)
 	}
 }
+
+func TestFindClassification(t *testing.T) {
+	// Setup
+	bibRepo := &MockBibliographyRepository{}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	// Test Case
+	class, err := svc.FindClassification(&domain.Bibliography{Code: "B56"})
+	// Assertions
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if class == nil || class.Name != "Technology" {
+		t.Errorf("Expected Technology classification, got %v", class)
+	}
+
+	// Invalid code
+	if _, err := svc.FindClassification(&domain.Bibliography{Code: "B"}); err == nil {
+		t.Error("Expected error for code without number, got nil")
+	}
+}
+
+func TestUpdateBibliography_PartialUpdateKeepsBibIndex(t *testing.T) {
+	existing := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56EE03DDD",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Domain Driven Design",
+		Author:        "Eric Evans",
+		PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{existing.ID: existing},
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	title := "  Domain-Driven Design  "
+	updated, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{Title: &title})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated.Title != "Domain-Driven Design" {
+		t.Errorf("Expected trimmed title, got %q", updated.Title)
+	}
+	if updated.BibIndex != "B56EE03DDD" {
+		t.Errorf("Expected BibIndex to be unchanged without regeneration, got %s", updated.BibIndex)
+	}
+	if updated.Author != "Eric Evans" {
+		t.Errorf("Expected author to be unchanged, got %s", updated.Author)
+	}
+	if bibRepo.SavedBibliography != updated {
+		t.Error("Expected bibliography to be saved to repository")
+	}
+}
+
+func TestUpdateBibliography_Contributors(t *testing.T) {
+	existing := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56EE03DDD",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Domain Driven Design",
+		Author:        "Eric Evans",
+		PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{existing.ID: existing},
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	// Adding a translator keeps the lead author, so the BibIndex stays
+	contributors := []domain.Contributor{
+		{Name: "Eric Evans", Role: domain.RoleAuthor},
+		{Name: "今関剛", Role: domain.RoleTranslator, NameEn: "Tsuyoshi Imazeki"},
+	}
+	updated, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{Contributors: contributors, RegenerateBibIndex: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Author != "Eric Evans; 今関剛 (translator)" || updated.BibIndex != "B56EE03DDD" {
+		t.Errorf("Unexpected update %q, %s", updated.Author, updated.BibIndex)
+	}
+
+	// A new author line replaces the contributors and changes the lead author
+	author := "Vaughn Vernon"
+	updated, err = svc.UpdateBibliography(existing.ID, BibliographyUpdate{Author: &author, RegenerateBibIndex: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(updated.Contributors) != 1 || updated.Contributors[0].Name != "Vaughn Vernon" || updated.BibIndex != "B56VV03DDD" {
+		t.Errorf("Unexpected update %+v, %s", updated.Contributors, updated.BibIndex)
+	}
+}
+
+func TestUpdateBibliography_RegenerateBibIndex(t *testing.T) {
+	existing := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56EE03DDD",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Domain Driven Design",
+		Author:        "Eric Evans",
+		PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{existing.ID: existing},
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+			"16": {Code: "16", Name: "Philosophy"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	classCode := "16"
+	year := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{
+		ClassCode:          &classCode,
+		PublishedDate:      &year,
+		RegenerateBibIndex: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated.Code != "B16" {
+		t.Errorf("Expected Code B16, got %s", updated.Code)
+	}
+	if updated.BibIndex != "B16EE24DDD" {
+		t.Errorf("Expected BibIndex B16EE24DDD, got %s", updated.BibIndex)
+	}
+}
+
+func TestUpdateBibliography_Validation(t *testing.T) {
+	existing := &domain.Bibliography{
+		ID:            domain.NewBibliographyID(),
+		BibIndex:      "B56EE03DDD",
+		Code:          "B56",
+		Type:          "Book",
+		Title:         "Domain Driven Design",
+		Author:        "Eric Evans",
+		PublishedDate: time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{existing.ID: existing},
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	empty := "   "
+	if _, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{Author: &empty}); err == nil {
+		t.Error("Expected error for empty author, got nil")
+	}
+
+	missingClass, invalidClass := "99", "5.6"
+	if _, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{ClassCode: &missingClass}); err == nil {
+		t.Error("Expected error for unknown classification, got nil")
+	}
+	if _, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{ClassCode: &invalidClass}); err == nil {
+		t.Error("Expected error for invalid classification code, got nil")
+	}
+
+	japanese := "鬱の本"
+	_, err := svc.UpdateBibliography(existing.ID, BibliographyUpdate{Title: &japanese, RegenerateBibIndex: true})
+	if err == nil || err.Error() != "title contains Japanese characters that could not be romanized (no reading for 鬱); please provide English translation via -title-en flag" {
+		t.Errorf("Expected translation error, got %v", err)
+	}
+
+	if _, err := svc.UpdateBibliography(domain.NewBibliographyID(), BibliographyUpdate{Title: &japanese}); err == nil {
+		t.Error("Expected error for unknown bibliography, got nil")
+	}
+}
+
+func TestDeleteBibliography(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       ReviewDeletePolicy
+		enforcing    bool // the repository enforces references, like SQLite
+		deleteFails  bool // deleting a review fails
+		wantErr      bool
+		wantBibKept  bool
+		wantReviews  int // left in the repository
+		wantReturned int // returned as deleted or orphaned
+	}{
+		{name: "refuse", policy: RefuseIfReviewed, wantErr: true, wantBibKept: true, wantReviews: 1},
+		{name: "cascade", policy: CascadeReviews, wantReviews: 0, wantReturned: 1},
+		{name: "keep orphans", policy: KeepOrphanReviews, wantReviews: 1, wantReturned: 1},
+		{name: "keep orphans with enforced references", policy: KeepOrphanReviews, enforcing: true, wantErr: true, wantBibKept: true, wantReviews: 1},
+		{name: "cascade with enforced references", policy: CascadeReviews, enforcing: true, wantReviews: 0, wantReturned: 1},
+		{name: "cascade keeps the bibliography if a review is kept", policy: CascadeReviews, deleteFails: true, wantErr: true, wantBibKept: true, wantReviews: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookID := domain.NewBibliographyID()
+			bibRepo := &MockBibliographyRepository{
+				Bibliographies: map[domain.BibliographyID]*domain.Bibliography{
+					bookID: {ID: bookID, BibIndex: "B56TEST"},
+				},
+			}
+			reviewID := domain.NewReviewID()
+			reviewRepo := &MockReviewRepository{
+				Reviews: map[domain.ReviewID]*domain.Review{
+					reviewID: {ID: reviewID, BookID: bookID, Goals: "Goals"},
+				},
+			}
+			if tt.deleteFails {
+				reviewRepo.DeleteErr = errors.New("disk full")
+			}
+			var repo domain.BibliographyRepository = bibRepo
+			if tt.enforcing {
+				repo = enforcingBibliographyRepository{bibRepo}
+			}
+			svc := NewBibliographyService(repo, &MockClassificationRepository{}, reviewRepo)
+
+			deleted, reviews, err := svc.DeleteBibliography(bookID, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteBibliography() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (deleted.ID != bookID || len(reviews) != tt.wantReturned) {
+				t.Errorf("Expected the bibliography and %d review(s), got %v / %d", tt.wantReturned, deleted, len(reviews))
+			}
+			if _, ok := bibRepo.Bibliographies[bookID]; ok != tt.wantBibKept {
+				t.Errorf("Expected bibliography kept = %v", tt.wantBibKept)
+			}
+			if len(reviewRepo.Reviews) != tt.wantReviews {
+				t.Errorf("Expected %d review(s) left, got %d", tt.wantReviews, len(reviewRepo.Reviews))
+			}
+		})
+	}
+}
+
+// enforcingBibliographyRepository is a MockBibliographyRepository behaving like storage
+// that enforces references to bibliographies.
+type enforcingBibliographyRepository struct {
+	*MockBibliographyRepository
+}
+
+func (enforcingBibliographyRepository) EnforcesReferences() bool { return true }
+
+func TestAddBibliography_GeneratedBibIndexCollision(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}
+	for _, index := range []string{"B56EE03DDD", "B56EE03DDDa"} {
+		id := domain.NewBibliographyID()
+		bibRepo.Bibliographies[id] = &domain.Bibliography{ID: id, BibIndex: index, Code: "B56", Type: "Book", Title: "Existing"}
+	}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	bib, err := svc.AddBibliography("Domain Driven Design", "Eric Evans", nil, "", "", "Book", "56",
+		time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bib.BibIndex != "B56EE03DDDb" {
+		t.Errorf("Expected BibIndex B56EE03DDDb, got %s", bib.BibIndex)
+	}
+}
+
+func TestAddBibliography_ManualBibIndexCollision(t *testing.T) {
+	id := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{
+		id: {ID: id, BibIndex: "CUSTOM123", Code: "B56", Type: "Book", Title: "Existing"},
+	}}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(bibRepo, classRepo, &MockReviewRepository{})
+
+	_, err := svc.AddBibliography("Domain Driven Design", "Eric Evans", nil, "", "", "Book", "56",
+		time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "CUSTOM123")
+	if err == nil {
+		t.Fatal("Expected error for duplicate manual BibIndex, got nil")
+	}
+	if len(bibRepo.Bibliographies) != 1 {
+		t.Errorf("Expected nothing to be saved, got %d bibliographies", len(bibRepo.Bibliographies))
+	}
+}
+
+func TestUpdateBibliography_ManualBibIndexCollision(t *testing.T) {
+	other := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "TAKEN", Code: "B56", Type: "Book", Title: "Existing"}
+	own := &domain.Bibliography{ID: domain.NewBibliographyID(), BibIndex: "MINE", Code: "B56", Type: "Book", Title: "Mine"}
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{other.ID: other, own.ID: own}}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, &MockReviewRepository{})
+
+	taken := "TAKEN"
+	if _, err := svc.UpdateBibliography(own.ID, BibliographyUpdate{BibIndex: &taken}); err == nil {
+		t.Error("Expected error for BibIndex used by another bibliography, got nil")
+	}
+
+	// Keeping its own BibIndex is not a collision
+	mine := "MINE"
+	if _, err := svc.UpdateBibliography(own.ID, BibliographyUpdate{BibIndex: &mine}); err != nil {
+		t.Errorf("Expected no error keeping own BibIndex, got %v", err)
+	}
+}
+
+func TestFindDuplicateBibIndexes(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}
+	for _, index := range []string{"B56B", "B56A", "B56B", "B56C", "B56A", "B56B"} {
+		id := domain.NewBibliographyID()
+		bibRepo.Bibliographies[id] = &domain.Bibliography{ID: id, BibIndex: index, Code: "B56", Type: "Book", Title: "Existing"}
+	}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, &MockReviewRepository{})
+
+	duplicates, err := svc.FindDuplicateBibIndexes()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Fatalf("Expected 2 duplicate groups, got %d", len(duplicates))
+	}
+	if duplicates[0].BibIndex != "B56A" || len(duplicates[0].Bibliographies) != 2 {
+		t.Errorf("Expected B56A x2, got %s x%d", duplicates[0].BibIndex, len(duplicates[0].Bibliographies))
+	}
+	if duplicates[1].BibIndex != "B56B" || len(duplicates[1].Bibliographies) != 3 {
+		t.Errorf("Expected B56B x3, got %s x%d", duplicates[1].BibIndex, len(duplicates[1].Bibliographies))
+	}
+}
+
+func TestBibliographyErrors_Kinds(t *testing.T) {
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	svc := NewBibliographyService(&MockBibliographyRepository{}, classRepo, &MockReviewRepository{})
+	pubDate := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.AddBibliography("DDD", "Eric Evans", nil, "", "", "Book", "56", pubDate, "", "", "B56EE03DDD"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	invalid := map[string]func() error{
+		"missing title": func() error {
+			_, err := svc.AddBibliography(" ", "Eric Evans", nil, "", "", "Book", "56", pubDate, "", "", "")
+			return err
+		},
+		"unknown class": func() error {
+			_, err := svc.AddBibliography("DDD", "Eric Evans", nil, "", "", "Book", "99", pubDate, "", "", "")
+			return err
+		},
+		"used BibIndex": func() error {
+			_, err := svc.AddBibliography("DDD", "Eric Evans", nil, "", "", "Book", "56", pubDate, "", "", "B56EE03DDD")
+			return err
+		},
+		"existing class": func() error {
+			_, err := svc.AddClassification("56", "Again")
+			return err
+		},
+	}
+	for name, fn := range invalid {
+		if err := fn(); !errors.Is(err, domain.ErrInvalid) || errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("%s: expected ErrInvalid, got %v", name, err)
+		}
+	}
+
+	title := "New"
+	_, err := svc.UpdateBibliography(domain.NewBibliographyID(), BibliographyUpdate{Title: &title})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing bibliography, got %v", err)
+	}
+	if _, err := svc.FindClassificationByCode("99"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing classification, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListClassifications returns all classifications in code order, so that every class comes
+// right before its subclasses.
+func (s *BibliographyService) ListClassifications() ([]*domain.Classification, error) {
+	classes, err := s.classRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classifications: %w", err)
+	}
+	sort.SliceStable(classes, func(i, j int) bool { return classes[i].Code.Less(classes[j].Code) })
+	return classes, nil
+}
+
+// ClassificationTree returns the classifications arranged by code (see domain.BuildClassTree),
+// with the number of bibliographies filed under each class and each subtree.
+func (s *BibliographyService) ClassificationTree() ([]*domain.ClassNode, error) {
+	classes, err := s.classRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classifications: %w", err)
+	}
+	bibs, err := s.bibRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+	counts := make(map[domain.ClassCode]int)
+	for _, bib := range bibs {
+		if code, err := bib.ClassCode(); err == nil {
+			counts[code]++
+		}
+	}
+	return domain.BuildClassTree(classes, counts), nil
+}
+
+// ClassificationImport is a classification read from a bundled or external scheme such as the NDC.
+type ClassificationImport struct {
+	Code string
+	Name string
+}
+
+// ClassificationImportResult reports what happened, or would happen in a dry run, to one
+// imported classification.
+type ClassificationImportResult struct {
+	Item           ClassificationImport
+	Classification *domain.Classification // created (or to be created); nil if skipped
+	SkipReason     string
+}
+
+// Skipped reports whether the item was not imported.
+func (r ClassificationImportResult) Skipped() bool {
+	return r.Classification == nil
+}
+
+// ImportClassifications adds items with the same validation as AddClassification. Codes that
+// are already recorded keep their existing name, so seeding a scheme into a collection that
+// has its own classes never renames them.
+// With dryRun nothing is saved. An error is returned only if storage fails; results up to
+// that point are returned with it.
+func (s *BibliographyService) ImportClassifications(items []ClassificationImport, dryRun bool) ([]ClassificationImportResult, error) {
+	existing, err := s.classRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classifications: %w", err)
+	}
+	byCode := make(map[domain.ClassCode]*domain.Classification)
+	for _, class := range existing {
+		byCode[class.Code] = class
+	}
+
+	results := make([]ClassificationImportResult, 0, len(items))
+	for _, item := range items {
+		result := ClassificationImportResult{Item: item}
+		code, err := domain.ParseClassCode(item.Code)
+		switch {
+		case err != nil:
+			result.SkipReason = err.Error()
+		case strings.TrimSpace(item.Name) == "":
+			result.SkipReason = "classification name must not be empty"
+		case byCode[code] != nil:
+			result.SkipReason = fmt.Sprintf("already recorded as %q", byCode[code].Name)
+		default:
+			class := &domain.Classification{
+				ID:   domain.NewClassificationID(),
+				Code: code,
+				Name: strings.TrimSpace(item.Name),
+			}
+			if !dryRun {
+				if err := s.classRepo.Save(class); err != nil {
+					return results, fmt.Errorf("failed to save classification %s: %w", code, err)
+				}
+			}
+			result.Classification = class
+			byCode[code] = class
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// FindClassificationByCode parses code and returns its classification, which must exist.
+func (s *BibliographyService) FindClassificationByCode(codeStr string) (*domain.Classification, error) {
+	code, err := domain.ParseClassCode(codeStr)
+	if err != nil {
+		return nil, domain.Invalid(err)
+	}
+	class, err := s.classRepo.FindByCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find classification: %w", err)
+	}
+	if class == nil {
+		return nil, fmt.Errorf("classification with code %s %w", code, domain.ErrNotFound)
+	}
+	return class, nil
+}
+
+// RenameClassification changes the name of the classification with the given code.
+func (s *BibliographyService) RenameClassification(codeStr, name string) (*domain.Classification, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("classification name must not be empty")
+	}
+	class, err := s.FindClassificationByCode(codeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := *class
+	renamed.Name = name
+	if err := s.classRepo.Save(&renamed); err != nil {
+		return nil, fmt.Errorf("failed to save classification: %w", err)
+	}
+	return &renamed, nil
+}
+
+// Reclassification is how renumbering or deleting a classification changes one bibliography.
+type Reclassification struct {
+	Bibliography *domain.Bibliography // as stored before the change
+	Code         string
+	BibIndex     string // the stored BibIndex unless its prefix is rewritten
+}
+
+// BibIndexChanged reports whether the BibIndex is rewritten.
+func (r Reclassification) BibIndexChanged() bool {
+	return r.BibIndex != r.Bibliography.BibIndex
+}
+
+// RenumberClassification changes the code of a classification and the Code of every
+// bibliography filed under it. With rewriteBibIndex, BibIndexes that start with the old
+// Code get the new one instead (B56EE03DDD becomes B547EE03DDD); manual BibIndexes that
+// do not are kept. Subclasses keep their codes.
+// Nothing is saved if a rewritten BibIndex is already used, and with dryRun nothing is
+// saved at all. It returns the renumbered classification and the changed bibliographies.
+func (s *BibliographyService) RenumberClassification(fromStr, toStr string, rewriteBibIndex, dryRun bool) (*domain.Classification, []Reclassification, error) {
+	class, err := s.FindClassificationByCode(fromStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := domain.ParseClassCode(toStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing, err := s.classRepo.FindByCode(to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check for existing classification: %w", err)
+	}
+	if existing != nil {
+		return nil, nil, fmt.Errorf("classification with code %s already exists", to)
+	}
+
+	moves, err := s.planReclassification(class.Code, to, rewriteBibIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	renumbered := *class
+	renumbered.Code = to
+	if dryRun {
+		return &renumbered, moves, nil
+	}
+
+	// Bibliographies are moved first, so that running the command again after a failure
+	// finishes the job
+	if err := s.applyReclassification(moves); err != nil {
+		return nil, nil, err
+	}
+	if err := s.classRepo.Save(&renumbered); err != nil {
+		return nil, nil, fmt.Errorf("failed to save classification: %w", err)
+	}
+	return &renumbered, moves, nil
+}
+
+// DeleteClassification deletes a classification. A classification that bibliographies
+// are filed under is only deleted with reassignTo, the code of an existing classification
+// they are moved to as by RenumberClassification. With dryRun nothing is saved.
+// It returns the deleted classification and the moved bibliographies.
+func (s *BibliographyService) DeleteClassification(codeStr, reassignTo string, rewriteBibIndex, dryRun bool) (*domain.Classification, []Reclassification, error) {
+	class, err := s.FindClassificationByCode(codeStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var moves []Reclassification
+	if reassignTo == "" {
+		bibs, err := s.bibRepo.Find(domain.BibliographyQuery{ClassCode: &class.Code})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find bibliographies: %w", err)
+		}
+		if len(bibs) > 0 {
+			return nil, nil, fmt.Errorf("classification %s is used by %d bibliographies; reassign them to another classification", class.Code, len(bibs))
+		}
+	} else {
+		target, err := s.FindClassificationByCode(reassignTo)
+		if err != nil {
+			return nil, nil, err
+		}
+		if target.ID == class.ID {
+			return nil, nil, fmt.Errorf("cannot reassign bibliographies to the classification being deleted")
+		}
+		moves, err = s.planReclassification(class.Code, target.Code, rewriteBibIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if dryRun {
+		return class, moves, nil
+	}
+
+	if err := s.applyReclassification(moves); err != nil {
+		return nil, nil, err
+	}
+	if err := s.classRepo.Delete(class.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete classification: %w", err)
+	}
+	return class, moves, nil
+}
+
+// planReclassification computes how the bibliographies filed directly under from change
+// when moved to to, checking that rewritten BibIndexes are free.
+func (s *BibliographyService) planReclassification(from, to domain.ClassCode, rewriteBibIndex bool) ([]Reclassification, error) {
+	bibs, err := s.bibRepo.Find(domain.BibliographyQuery{ClassCode: &from})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bibliographies: %w", err)
+	}
+
+	reserved := make(map[string]bool)
+	moves := make([]Reclassification, 0, len(bibs))
+	for _, bib := range bibs {
+		move := Reclassification{Bibliography: bib, Code: generateCode(bib.Type, to), BibIndex: bib.BibIndex}
+		if rest, ok := strings.CutPrefix(bib.BibIndex, bib.Code); ok && rewriteBibIndex {
+			move.BibIndex = move.Code + rest
+			if err := s.ensureBibIndexAvailable(move.BibIndex, bib.ID, reserved); err != nil {
+				return nil, err
+			}
+			reserved[move.BibIndex] = true
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// applyReclassification saves all planned changes at once.
+func (s *BibliographyService) applyReclassification(moves []Reclassification) error {
+	if len(moves) == 0 {
+		return nil
+	}
+	updated := make([]*domain.Bibliography, 0, len(moves))
+	for _, move := range moves {
+		bib := *move.Bibliography
+		bib.Code, bib.BibIndex = move.Code, move.BibIndex
+		updated = append(updated, &bib)
+	}
+	if err := s.bibRepo.SaveAll(updated); err != nil {
+		return fmt.Errorf("failed to update bibliographies: %w", err)
+	}
+	for _, bib := range updated {
+		updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+			return index.IndexBibliography(bib)
+		})
+	}
+	return nil
+}
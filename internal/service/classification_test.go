@@ -0,0 +1,341 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newClassTreeTestService returns a service with NDC-style classes, a decimal class and a
+// legacy class "16", and bibliographies filed under 547 (two), 547.48, 5 and 16.
+func newClassTreeTestService(t *testing.T) *BibliographyService {
+	t.Helper()
+	classRepo := &MockClassificationRepository{}
+	svc := NewBibliographyService(&MockBibliographyRepository{}, classRepo, &MockReviewRepository{})
+	for _, c := range []struct{ code, name string }{
+		{"547", "通信工学"}, {"5", "技術"}, {"16", "Philosophy"}, {"54", "電気工学"}, {"547.48", "情報通信"}, {"50", "技術. 工学"},
+	} {
+		if _, err := svc.AddClassification(c.code, c.name); err != nil {
+			t.Fatalf("AddClassification(%s) error = %v", c.code, err)
+		}
+	}
+	for i, b := range []struct{ title, class string }{
+		{"Network Basics", "547"}, {"TCP IP", "547"}, {"Data Communication", "547.48"}, {"Engineering", "5"}, {"Ethics", "16"},
+	} {
+		if _, err := svc.AddBibliography(b.title, "Jane Doe", nil, "", "", "Book", b.class,
+			time.Date(2000+i, 1, 1, 0, 0, 0, 0, time.UTC), "", "", ""); err != nil {
+			t.Fatalf("AddBibliography(%s) error = %v", b.title, err)
+		}
+	}
+	return svc
+}
+
+func TestClassificationTree(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	roots, err := svc.ClassificationTree()
+	if err != nil {
+		t.Fatalf("ClassificationTree() error = %v", err)
+	}
+
+	type row struct {
+		code         domain.ClassCode
+		depth        int
+		count, total int
+	}
+	var got []row
+	domain.Walk(roots, func(n *domain.ClassNode) {
+		got = append(got, row{n.Class.Code, n.Depth(), n.Count, n.Total})
+	})
+	want := []row{
+		{"16", 0, 1, 1},
+		{"5", 0, 1, 4},
+		{"50", 1, 0, 0},
+		{"54", 1, 0, 3},
+		{"547", 2, 2, 3},
+		{"547.48", 3, 1, 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Walk() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("node %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(roots) != 2 || roots[1].Children[1].Parent != roots[1] {
+		t.Error("Expected children to point at their parent")
+	}
+}
+
+func TestClassificationTree_MissingIntermediateLevel(t *testing.T) {
+	classes := []*domain.Classification{{Code: "547"}, {Code: "5"}, {Code: "55"}}
+	roots := domain.BuildClassTree(classes, map[domain.ClassCode]int{"547": 2, "99": 5})
+	if len(roots) != 1 || len(roots[0].Children) != 2 {
+		t.Fatalf("Expected 547 and 55 directly under 5, got %+v", roots)
+	}
+	if roots[0].Children[0].Class.Code != "547" || roots[0].Total != 2 {
+		t.Errorf("Unexpected tree: first child %s, total %d", roots[0].Children[0].Class.Code, roots[0].Total)
+	}
+}
+
+func TestParseClassCode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    domain.ClassCode
+		wantErr bool
+	}{
+		{"5", "5", false},
+		{" 56 ", "56", false},
+		{"007", "007", false},
+		{"547.48", "547.48", false},
+		{"", "", true},
+		{"5.6", "", true},
+		{"547.", "", true},
+		{"54a", "", true},
+		{"-5", "", true},
+		{"1234567890123", "", true},
+	}
+	for _, tt := range tests {
+		got, err := domain.ParseClassCode(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseClassCode(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if !domain.ClassCode("547.48").Within("54") || !domain.ClassCode("547").Within("547") {
+		t.Error("Expected 547.48 within 54 and 547 within itself")
+	}
+	if domain.ClassCode("7").Within("007") || domain.ClassCode("5").Within("54") || domain.ClassCode("56").Within("547") {
+		t.Error("Expected 7, 5 and 56 outside 007, 54 and 547")
+	}
+}
+
+func TestFindBibliographies_ClassRecursive(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	class := domain.ClassCode("54")
+
+	direct, err := svc.FindBibliographies(domain.BibliographyQuery{ClassCode: &class, Sort: domain.SortByTitle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(direct) != 0 {
+		t.Errorf("Expected nothing filed directly under 54, got %d", len(direct))
+	}
+
+	all, err := svc.FindBibliographies(domain.BibliographyQuery{ClassCode: &class, ClassRecursive: true, Sort: domain.SortByTitle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, bib := range all {
+		titles = append(titles, bib.Title)
+	}
+	if len(titles) != 3 || titles[0] != "Data Communication" || titles[1] != "Network Basics" || titles[2] != "TCP IP" {
+		t.Errorf("Expected the three bibliographies under 547, got %v", titles)
+	}
+}
+
+func TestAddBibliography_DecimalClassCode(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	bib, err := svc.AddBibliography("Data Networks", "Dimitri Bertsekas", nil, "", "", "Book", "547.48",
+		time.Date(1992, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bib.Code != "B547.48" || bib.BibIndex != "B547.48DB92DN" {
+		t.Errorf("Unexpected Code %s, BibIndex %s", bib.Code, bib.BibIndex)
+	}
+	class, err := svc.FindClassification(bib)
+	if err != nil || class == nil || class.Name != "情報通信" {
+		t.Errorf("FindClassification() = %v, %v", class, err)
+	}
+}
+
+func TestImportClassifications(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	items := []ClassificationImport{
+		{Code: "5", Name: "技術. 工学"}, // already recorded
+		{Code: "4", Name: "自然科学"},
+		{Code: "41", Name: "数学"},
+		{Code: "4x", Name: "Invalid"},
+		{Code: "42", Name: " "},
+		{Code: "41", Name: "Duplicate in batch"},
+	}
+
+	dryRun, err := svc.ImportClassifications(items, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	classes, _ := svc.ListClassifications()
+	if len(classes) != 6 {
+		t.Errorf("Expected a dry run to save nothing, got %d classifications", len(classes))
+	}
+
+	results, err := svc.ImportClassifications(items, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, wantImported := range []bool{false, true, true, false, false, false} {
+		if results[i].Skipped() == wantImported || dryRun[i].Skipped() == wantImported {
+			t.Errorf("item %d: skipped = %v (%s), want imported %v", i, results[i].Skipped(), results[i].SkipReason, wantImported)
+		}
+	}
+	if results[0].SkipReason != `already recorded as "技術"` {
+		t.Errorf("Unexpected skip reason %q", results[0].SkipReason)
+	}
+
+	classes, _ = svc.ListClassifications()
+	var codes []domain.ClassCode
+	for _, c := range classes {
+		codes = append(codes, c.Code)
+	}
+	want := []domain.ClassCode{"16", "4", "41", "5", "50", "54", "547", "547.48"}
+	if len(codes) != len(want) {
+		t.Fatalf("ListClassifications() = %v, want %v", codes, want)
+	}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("ListClassifications() = %v, want %v", codes, want)
+			break
+		}
+	}
+}
+
+func TestRenameClassification(t *testing.T) {
+	svc := newClassTreeTestService(t)
+
+	class, err := svc.RenameClassification("547", " Telecommunications ")
+	if err != nil {
+		t.Fatalf("RenameClassification() error = %v", err)
+	}
+	if class.Code != "547" || class.Name != "Telecommunications" {
+		t.Errorf("Unexpected classification %+v", class)
+	}
+	if stored, _ := svc.FindClassificationByCode("547"); stored == nil || stored.Name != "Telecommunications" || stored.ID != class.ID {
+		t.Errorf("Expected the rename to be saved, got %+v", stored)
+	}
+
+	if _, err := svc.RenameClassification("547", " "); err == nil {
+		t.Error("Expected error for an empty name")
+	}
+	if _, err := svc.RenameClassification("548", "Missing"); err == nil {
+		t.Error("Expected error for a missing classification")
+	}
+}
+
+func TestRenumberClassification(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	before, _ := svc.FindBibliographies(domain.BibliographyQuery{Sort: domain.SortByTitle})
+
+	_, preview, err := svc.RenumberClassification("547", "548", true, true)
+	if err != nil {
+		t.Fatalf("RenumberClassification() dry run error = %v", err)
+	}
+	if len(preview) != 2 || preview[0].Code != "B548" || !preview[0].BibIndexChanged() {
+		t.Fatalf("Unexpected preview %+v", preview)
+	}
+	if class, _ := svc.classRepo.FindByCode("547"); class == nil {
+		t.Error("Expected a dry run to keep the classification")
+	}
+
+	class, moves, err := svc.RenumberClassification("547", "548", true, false)
+	if err != nil {
+		t.Fatalf("RenumberClassification() error = %v", err)
+	}
+	if class.Code != "548" || class.Name != "通信工学" {
+		t.Errorf("Unexpected classification %+v", class)
+	}
+	for _, move := range moves {
+		bib, _ := svc.FindByID(move.Bibliography.ID)
+		if bib.Code != "B548" || bib.BibIndex != "B548"+strings.TrimPrefix(move.Bibliography.BibIndex, "B547") {
+			t.Errorf("Unexpected Code %s, BibIndex %s (was %s)", bib.Code, bib.BibIndex, move.Bibliography.BibIndex)
+		}
+	}
+	if c, _ := svc.classRepo.FindByCode("547"); c != nil {
+		t.Error("Expected the old code to be gone")
+	}
+	if c, _ := svc.classRepo.FindByCode("547.48"); c == nil {
+		t.Error("Expected the subclass to keep its code")
+	}
+
+	after, _ := svc.FindBibliographies(domain.BibliographyQuery{Sort: domain.SortByTitle})
+	if len(after) != len(before) {
+		t.Errorf("Expected %d bibliographies, got %d", len(before), len(after))
+	}
+}
+
+func TestRenumberClassification_KeepsBibIndex(t *testing.T) {
+	svc := newClassTreeTestService(t)
+	_, moves, err := svc.RenumberClassification("547", "548", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, move := range moves {
+		bib, _ := svc.FindByID(move.Bibliography.ID)
+		if bib.Code != "B548" || bib.BibIndex != move.Bibliography.BibIndex {
+			t.Errorf("Expected only Code to change, got %s, %s", bib.Code, bib.BibIndex)
+		}
+	}
+}
+
+func TestRenumberClassification_Conflicts(t *testing.T) {
+	svc := newClassTreeTestService(t)
+
+	if _, _, err := svc.RenumberClassification("547", "54", false, false); err == nil {
+		t.Error("Expected error when the new code is taken")
+	}
+
+	// A bibliography already holds the BibIndex one of the renumbered ones would get
+	_, preview, err := svc.RenumberClassification("547", "548", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddBibliography("Taken", "Jane Doe", nil, "", "", "Book", "16",
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "", "", preview[1].BibIndex); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.RenumberClassification("547", "548", true, false); err == nil {
+		t.Fatal("Expected error for a BibIndex that is already used")
+	}
+	for _, move := range preview {
+		if bib, _ := svc.FindByID(move.Bibliography.ID); bib.Code != "B547" {
+			t.Errorf("Expected nothing to be saved, got Code %s", bib.Code)
+		}
+	}
+}
+
+func TestDeleteClassification(t *testing.T) {
+	svc := newClassTreeTestService(t)
+
+	if _, _, err := svc.DeleteClassification("547", "", false, false); err == nil {
+		t.Error("Expected error for a classification that is still used")
+	}
+	if _, _, err := svc.DeleteClassification("547", "547", false, false); err == nil {
+		t.Error("Expected error for reassigning to the deleted classification")
+	}
+	if _, _, err := svc.DeleteClassification("547", "548", false, false); err == nil {
+		t.Error("Expected error for reassigning to a missing classification")
+	}
+
+	if _, _, err := svc.DeleteClassification("50", "", false, false); err != nil {
+		t.Errorf("Expected unused classification to be deleted, got %v", err)
+	}
+
+	class, moves, err := svc.DeleteClassification("547", "54", false, false)
+	if err != nil {
+		t.Fatalf("DeleteClassification() error = %v", err)
+	}
+	if class.Code != "547" || len(moves) != 2 {
+		t.Fatalf("Unexpected result %+v, %d moves", class, len(moves))
+	}
+	classes, _ := svc.ListClassifications()
+	if len(classes) != 4 {
+		t.Errorf("Expected 4 classifications left, got %d", len(classes))
+	}
+	code := domain.ClassCode("54")
+	bibs, _ := svc.FindBibliographies(domain.BibliographyQuery{ClassCode: &code})
+	if len(bibs) != 2 {
+		t.Errorf("Expected 2 bibliographies moved to 54, got %d", len(bibs))
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HighlightImport is a highlight read from an external source such as a Kindle clippings file.
+type HighlightImport struct {
+	Text      string
+	Location  string
+	Comment   string
+	Tags      []string
+	CreatedAt time.Time // zero if unknown
+}
+
+// HighlightImportResult reports what happened, or would happen in a dry run, to one imported highlight.
+type HighlightImportResult struct {
+	Item       HighlightImport
+	Highlight  *domain.Highlight // created (or to be created); nil if skipped
+	SkipReason string
+}
+
+// Skipped reports whether the item was not imported.
+func (r HighlightImportResult) Skipped() bool {
+	return r.Highlight == nil
+}
+
+// ImportHighlights adds items to a bibliography with the same validation as AddHighlight.
+// Items are skipped if the bibliography already has a highlight with the same text at the
+// same location (including earlier in the same batch), so importing the same file again
+// adds only new highlights.
+// With dryRun nothing is saved. An error is returned only if the bibliography does not
+// exist or storage fails; results up to that point are returned with it.
+func (s *HighlightService) ImportHighlights(bookID domain.BibliographyID, items []HighlightImport, dryRun bool) ([]HighlightImportResult, error) {
+	bib, err := s.bibRepo.FindByID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify book existence: %w", err)
+	}
+	if bib == nil {
+		return nil, fmt.Errorf("bibliography with ID %s not found", bookID)
+	}
+
+	existing, err := s.highlightRepo.FindByBookID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find highlights: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, h := range existing {
+		seen[highlightKey(h.Location, h.Text)] = true
+	}
+
+	results := make([]HighlightImportResult, 0, len(items))
+	for _, item := range items {
+		result := HighlightImportResult{Item: item}
+		key := highlightKey(item.Location, item.Text)
+		tags, err := domain.NormalizeTags(item.Tags)
+		switch {
+		case strings.TrimSpace(item.Text) == "":
+			result.SkipReason = "text is empty"
+		case err != nil:
+			result.SkipReason = err.Error()
+		case seen[key]:
+			result.SkipReason = "already imported"
+		default:
+			createdAt := item.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			highlight := &domain.Highlight{
+				ID:        domain.NewHighlightID(),
+				BookID:    bookID,
+				Text:      item.Text,
+				Location:  strings.TrimSpace(item.Location),
+				Comment:   item.Comment,
+				Tags:      tags,
+				CreatedAt: createdAt,
+			}
+			if !dryRun {
+				if err := s.highlightRepo.Save(highlight); err != nil {
+					return results, fmt.Errorf("failed to save highlight: %w", err)
+				}
+			}
+			result.Highlight = highlight
+			seen[key] = true
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// highlightKey identifies a highlight by its location and text, ignoring surrounding whitespace.
+func highlightKey(location, text string) string {
+	return strings.TrimSpace(location) + "\x00" + strings.TrimSpace(text)
+}
+
+// MatchBibliographies returns the bibliographies whose title matches title once both are
+// normalized: case, full-width letters, punctuation, spacing, subtitles after a colon and
+// parenthesized notes are ignored. If author is given and some of them also share a name
+// with it, in any order ("Evans, Eric" matches "Eric Evans"), only those are returned.
+func (s *BibliographyService) MatchBibliographies(title, author string) ([]*domain.Bibliography, error) {
+	key := titleKey(title)
+	if key == "" {
+		return nil, nil
+	}
+	all, err := s.bibRepo.FindAll(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+
+	var matches, byAuthor []*domain.Bibliography
+	for _, bib := range all {
+		if titleKey(bib.Title) != key {
+			continue
+		}
+		matches = append(matches, bib)
+		if authorsOverlap(bib.Author, author) {
+			byAuthor = append(byAuthor, bib)
+		}
+	}
+	if len(byAuthor) > 0 {
+		return byAuthor, nil
+	}
+	return matches, nil
+}
+
+// titleKey normalizes a title for matching: the main title without parenthesized notes,
+// folded to lower-case ASCII where possible and reduced to letters and digits.
+func titleKey(title string) string {
+	title = stripParenthesized(title)
+	if i := strings.IndexAny(title, ":："); i > 0 {
+		title = title[:i]
+	}
+	return matchKey(title)
+}
+
+// authorsOverlap reports whether two author fields share a name. Names are compared as
+// whole fields and word by word, so that "Evans, Eric" matches "Eric Evans" and
+// "夏目漱石" matches "夏目 漱石".
+func authorsOverlap(a, b string) bool {
+	if matchKey(a) == "" || matchKey(b) == "" {
+		return false
+	}
+	if matchKey(a) == matchKey(b) {
+		return true
+	}
+	names := make(map[string]bool)
+	for _, name := range authorNames(a) {
+		names[name] = true
+	}
+	for _, name := range authorNames(b) {
+		if names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// authorNames splits an author field into lower-cased name parts, dropping initials.
+func authorNames(author string) []string {
+	var names []string
+	for _, part := range strings.FieldsFunc(stripParenthesized(author), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if name := matchKey(part); len([]rune(name)) > 1 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// matchKey folds full-width ASCII variants and case and keeps only letters and digits.
+func matchKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r = unicode.ToLower(r - 0xFEE0)
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestHighlightService_ImportHighlights(t *testing.T) {
+	svc, highlightRepo, bookID := newHighlightTestService(t)
+	highlightRepo.Highlights = []*domain.Highlight{
+		{ID: domain.NewHighlightID(), BookID: bookID, Text: "Already here", Location: "loc. 10-12"},
+	}
+
+	added := time.Date(2019, 2, 11, 14, 44, 9, 0, time.UTC)
+	items := []HighlightImport{
+		{Text: "Already here\n", Location: "loc. 10-12"},
+		{Text: "New one", Location: "p. 3, loc. 40", Comment: "note", CreatedAt: added},
+		{Text: "New one", Location: "p. 3, loc. 40"},
+		{Text: "Same text elsewhere", Location: "loc. 10-12", Tags: []string{"note"}},
+		{Text: " ", Location: "loc. 99"},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		results, err := svc.ImportHighlights(bookID, items, dryRun)
+		if err != nil {
+			t.Fatalf("dryRun=%v: ImportHighlights() error = %v", dryRun, err)
+		}
+		var skipped []int
+		for i, r := range results {
+			if r.Skipped() {
+				skipped = append(skipped, i)
+			}
+		}
+		if len(skipped) != 3 || skipped[0] != 0 || skipped[1] != 2 || skipped[2] != 4 {
+			t.Errorf("dryRun=%v: expected items 0, 2 and 4 to be skipped, got %v", dryRun, skipped)
+		}
+		if h := results[1].Highlight; h == nil || !h.CreatedAt.Equal(added) || h.Comment != "note" {
+			t.Errorf("dryRun=%v: unexpected highlight %+v", dryRun, h)
+		}
+		if h := results[3].Highlight; h == nil || !h.HasTag("note") {
+			t.Errorf("dryRun=%v: expected a tagged highlight, got %+v", dryRun, h)
+		}
+		if want := map[bool]int{true: 1, false: 3}[dryRun]; len(highlightRepo.Highlights) != want {
+			t.Errorf("dryRun=%v: expected %d stored highlights, got %d", dryRun, want, len(highlightRepo.Highlights))
+		}
+	}
+
+	// Importing again adds nothing
+	results, err := svc.ImportHighlights(bookID, items, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if !r.Skipped() {
+			t.Errorf("Expected item %d to be skipped on re-import", i)
+		}
+	}
+
+	if _, err := svc.ImportHighlights(domain.NewBibliographyID(), items, false); err == nil {
+		t.Error("Expected error for unknown bibliography, got nil")
+	}
+}
+
+func TestBibliographyService_MatchBibliographies(t *testing.T) {
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, &MockReviewRepository{})
+	add := func(index, title, author string) {
+		id := domain.NewBibliographyID()
+		bibRepo.Bibliographies[id] = &domain.Bibliography{ID: id, BibIndex: index, Title: title, Author: author}
+	}
+	add("ddd", "Domain-Driven Design", "Eric Evans")
+	add("ddd-ja", "エリック・エヴァンスのドメイン駆動設計", "エリック・エヴァンス")
+	add("neko", "吾輩は猫である", "夏目 漱石")
+	add("neko-other", "吾輩は猫である", "Someone Else")
+	add("ref", "Refactoring", "Martin Fowler")
+	add("ref2", "Refactoring (2nd Edition)", "Martin Fowler")
+
+	tests := []struct {
+		title, author string
+		want          []string
+	}{
+		{"Domain-Driven Design: Tackling Complexity in the Heart of Software", "Evans, Eric", []string{"ddd"}},
+		{"ＤＯＭＡＩＮ ＤＲＩＶＥＮ ＤＥＳＩＧＮ", "", []string{"ddd"}},
+		{"Domain-Driven Design", "Someone Unknown", []string{"ddd"}},
+		{"エリック・エヴァンスのドメイン駆動設計 (IT Architects' Archive)", "エリック・エヴァンス", []string{"ddd-ja"}},
+		{"吾輩は猫である", "夏目漱石", []string{"neko"}},
+		{"吾輩は猫である", "", []string{"neko", "neko-other"}},
+		{"Refactoring", "Fowler, Martin", []string{"ref", "ref2"}},
+		{"Unknown Book", "Eric Evans", nil},
+		{"(   )", "", nil},
+	}
+	for _, tt := range tests {
+		bibs, err := svc.MatchBibliographies(tt.title, tt.author)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, bib := range bibs {
+			got = append(got, bib.BibIndex)
+		}
+		sort.Strings(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("MatchBibliographies(%q, %q) = %v, want %v", tt.title, tt.author, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HighlightService records quotes and excerpts taken from bibliographies.
+type HighlightService struct {
+	highlightRepo domain.HighlightRepository
+	bibRepo       domain.BibliographyRepository
+}
+
+func NewHighlightService(highlightRepo domain.HighlightRepository, bibRepo domain.BibliographyRepository) *HighlightService {
+	return &HighlightService{
+		highlightRepo: highlightRepo,
+		bibRepo:       bibRepo,
+	}
+}
+
+// AddHighlight attaches a quote to a bibliography. As with review goals and summaries,
+// text and comment are stored as given: TrimSpace is only used to check that the text
+// is not empty. The location is a short label and is trimmed. If createdAt is zero the
+// highlight is stamped with the current time.
+func (s *HighlightService) AddHighlight(bookID domain.BibliographyID, text, location, comment string, tags []string, createdAt time.Time) (*domain.Highlight, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("text is required and cannot be empty")
+	}
+	tags, err := domain.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	bib, err := s.bibRepo.FindByID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify book existence: %w", err)
+	}
+	if bib == nil {
+		return nil, fmt.Errorf("bibliography with ID %s not found", bookID)
+	}
+
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	highlight := &domain.Highlight{
+		ID:        domain.NewHighlightID(),
+		BookID:    bookID,
+		Text:      text,
+		Location:  strings.TrimSpace(location),
+		Comment:   comment,
+		Tags:      tags,
+		CreatedAt: createdAt,
+	}
+	if err := s.highlightRepo.Save(highlight); err != nil {
+		return nil, fmt.Errorf("failed to save highlight: %w", err)
+	}
+	return highlight, nil
+}
+
+// ListHighlightsByBookID returns every highlight of the given bibliography.
+func (s *HighlightService) ListHighlightsByBookID(bookID domain.BibliographyID) ([]*domain.Highlight, error) {
+	return s.highlightRepo.FindByBookID(bookID)
+}
+
+// ListHighlights returns highlights in the order they were added, restricted to one
+// bibliography if bookID is non-nil and to one tag if tag is non-empty. limit and
+// offset apply to the matching highlights (limit <= 0 means no limit).
+func (s *HighlightService) ListHighlights(bookID *domain.BibliographyID, tag string, limit, offset int) ([]*domain.Highlight, error) {
+	if bookID == nil && tag == "" {
+		return s.highlightRepo.FindAll(limit, offset)
+	}
+
+	var highlights []*domain.Highlight
+	var err error
+	if bookID != nil {
+		highlights, err = s.highlightRepo.FindByBookID(*bookID)
+	} else {
+		highlights, err = s.highlightRepo.FindAll(0, 0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find highlights: %w", err)
+	}
+
+	var matches []*domain.Highlight
+	for _, h := range highlights {
+		if tag == "" || h.HasTag(tag) {
+			matches = append(matches, h)
+		}
+	}
+	if offset > 0 {
+		matches = matches[min(offset, len(matches)):]
+	}
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
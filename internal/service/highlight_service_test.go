@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// MockHighlightRepository is a mock implementation of domain.HighlightRepository
+type MockHighlightRepository struct {
+	Highlights []*domain.Highlight
+}
+
+func (m *MockHighlightRepository) Save(highlight *domain.Highlight) error {
+	for i, existing := range m.Highlights {
+		if existing.ID == highlight.ID {
+			m.Highlights[i] = highlight
+			return nil
+		}
+	}
+	m.Highlights = append(m.Highlights, highlight)
+	return nil
+}
+
+func (m *MockHighlightRepository) FindAll(limit, offset int) ([]*domain.Highlight, error) {
+	highlights := m.Highlights
+	if offset > 0 {
+		highlights = highlights[min(offset, len(highlights)):]
+	}
+	if limit > 0 && limit < len(highlights) {
+		highlights = highlights[:limit]
+	}
+	return highlights, nil
+}
+
+func (m *MockHighlightRepository) FindByID(id domain.HighlightID) (*domain.Highlight, error) {
+	for _, highlight := range m.Highlights {
+		if highlight.ID == id {
+			return highlight, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockHighlightRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.Highlight, error) {
+	var highlights []*domain.Highlight
+	for _, highlight := range m.Highlights {
+		if highlight.BookID == bookID {
+			highlights = append(highlights, highlight)
+		}
+	}
+	return highlights, nil
+}
+
+func (m *MockHighlightRepository) Delete(id domain.HighlightID) error {
+	kept := m.Highlights[:0]
+	for _, highlight := range m.Highlights {
+		if highlight.ID != id {
+			kept = append(kept, highlight)
+		}
+	}
+	m.Highlights = kept
+	return nil
+}
+
+func newHighlightTestService(t *testing.T) (*HighlightService, *MockHighlightRepository, domain.BibliographyID) {
+	t.Helper()
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{
+		bookID: {ID: bookID, BibIndex: "B56EE03DDD", Title: "Domain Driven Design"},
+	}}
+	highlightRepo := &MockHighlightRepository{}
+	return NewHighlightService(highlightRepo, bibRepo), highlightRepo, bookID
+}
+
+func TestHighlightService_AddHighlight(t *testing.T) {
+	svc, highlightRepo, bookID := newHighlightTestService(t)
+
+	text := "  Knowledge crunching\n\n  is collaborative.\n"
+	comment := "\tSee chapter 1\n"
+	highlight, err := svc.AddHighlight(bookID, text, "  p. 13 ", comment, []string{"#ddd", " modeling", "DDD", ""}, time.Time{})
+	if err != nil {
+		t.Fatalf("AddHighlight() error = %v", err)
+	}
+	// Text and comment are kept exactly; the location is trimmed
+	if highlight.Text != text || highlight.Comment != comment || highlight.Location != "p. 13" {
+		t.Errorf("Unexpected highlight %+v", highlight)
+	}
+	if want := []string{"ddd", "modeling"}; !reflect.DeepEqual(highlight.Tags, want) {
+		t.Errorf("Tags = %v, want %v", highlight.Tags, want)
+	}
+	if highlight.CreatedAt.IsZero() || len(highlightRepo.Highlights) != 1 {
+		t.Errorf("Expected one saved highlight with a creation time, got %d", len(highlightRepo.Highlights))
+	}
+
+	tests := []struct {
+		name   string
+		bookID domain.BibliographyID
+		text   string
+		tags   []string
+	}{
+		{"empty text", bookID, " \n\t", nil},
+		{"tag with space", bookID, "text", []string{"two words"}},
+		{"unknown bibliography", domain.NewBibliographyID(), "text", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.AddHighlight(tt.bookID, tt.text, "", "", tt.tags, time.Time{}); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+	if len(highlightRepo.Highlights) != 1 {
+		t.Errorf("Expected nothing more saved, got %d highlights", len(highlightRepo.Highlights))
+	}
+}
+
+func TestHighlightService_ListHighlights(t *testing.T) {
+	svc, highlightRepo, bookID := newHighlightTestService(t)
+	other := domain.NewBibliographyID()
+	highlightRepo.Highlights = []*domain.Highlight{
+		{ID: domain.NewHighlightID(), BookID: bookID, Text: "a", Tags: []string{"ddd"}},
+		{ID: domain.NewHighlightID(), BookID: other, Text: "b", Tags: []string{"DDD", "essay"}},
+		{ID: domain.NewHighlightID(), BookID: bookID, Text: "c"},
+		{ID: domain.NewHighlightID(), BookID: bookID, Text: "d", Tags: []string{"ddd"}},
+	}
+
+	texts := func(highlights []*domain.Highlight) []string {
+		var s []string
+		for _, h := range highlights {
+			s = append(s, h.Text)
+		}
+		return s
+	}
+	tests := []struct {
+		name          string
+		bookID        *domain.BibliographyID
+		tag           string
+		limit, offset int
+		want          []string
+	}{
+		{"all", nil, "", 0, 0, []string{"a", "b", "c", "d"}},
+		{"paged", nil, "", 2, 1, []string{"b", "c"}},
+		{"by book", &bookID, "", 0, 0, []string{"a", "c", "d"}},
+		{"by tag ignoring case", nil, "#ddd", 0, 0, []string{"a", "b", "d"}},
+		{"by book and tag", &bookID, "ddd", 0, 1, []string{"d"}},
+		{"offset past the end", &bookID, "ddd", 0, 5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ListHighlights(tt.bookID, tt.tag, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(texts(got), tt.want) {
+				t.Errorf("ListHighlights() = %v, want %v", texts(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteBibliography_RemovesHighlights(t *testing.T) {
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bookID: {ID: bookID, BibIndex: "B56TEST"}},
+	}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, &MockReviewRepository{})
+	other := domain.NewBibliographyID()
+	highlightRepo := &MockHighlightRepository{Highlights: []*domain.Highlight{
+		{ID: domain.NewHighlightID(), BookID: bookID, Text: "a"},
+		{ID: domain.NewHighlightID(), BookID: other, Text: "b"},
+	}}
+	svc.SetHighlightRepository(highlightRepo)
+
+	if _, _, err := svc.DeleteBibliography(bookID, CascadeReviews); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(highlightRepo.Highlights) != 1 || highlightRepo.Highlights[0].BookID != other {
+		t.Errorf("Expected only the other bibliography's highlight to remain, got %d highlights", len(highlightRepo.Highlights))
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"fmt"
+)
+
+// MetadataService looks up bibliographic data by ISBN in external catalogues.
+type MetadataService struct {
+	providers []domain.MetadataProvider
+}
+
+// NewMetadataService creates a MetadataService that asks providers in the given order.
+func NewMetadataService(providers ...domain.MetadataProvider) *MetadataService {
+	return &MetadataService{providers: providers}
+}
+
+// LookupISBN asks each provider in turn and returns the first answer, or nil if no
+// provider knows the ISBN. A provider that fails is skipped; its error is returned
+// only if no other provider answered.
+func (s *MetadataService) LookupISBN(isbnStr string) (*domain.BookMetadata, error) {
+	isbn, err := domain.ParseISBN(isbnStr)
+	if err != nil {
+		return nil, err
+	}
+	if isbn.IsZero() {
+		return nil, fmt.Errorf("an ISBN is required for lookup")
+	}
+	if len(s.providers) == 0 {
+		return nil, fmt.Errorf("no metadata providers are configured")
+	}
+
+	var errs []error
+	for _, p := range s.providers {
+		meta, err := p.LookupISBN(isbn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if meta != nil {
+			return meta, nil
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to look up ISBN %s: %w", isbn, errors.Join(errs...))
+	}
+	return nil, nil
+}
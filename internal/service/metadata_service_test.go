@@ -0,0 +1,65 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"testing"
+)
+
+// MockMetadataProvider answers lookups from a fixed map, or fails with Err.
+type MockMetadataProvider struct {
+	ProviderName string
+	Books        map[string]*domain.BookMetadata // keyed by compact ISBN
+	Err          error
+	Calls        int
+}
+
+func (m *MockMetadataProvider) Name() string {
+	return m.ProviderName
+}
+
+func (m *MockMetadataProvider) LookupISBN(isbn domain.ISBN) (*domain.BookMetadata, error) {
+	m.Calls++
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Books[isbn.Compact()], nil
+}
+
+func TestLookupISBN(t *testing.T) {
+	ddd := &domain.BookMetadata{Title: "Domain Driven Design", Author: "Eric Evans", Source: "second"}
+	failing := &MockMetadataProvider{ProviderName: "first", Err: errors.New("connection refused")}
+	empty := &MockMetadataProvider{ProviderName: "empty"}
+	second := &MockMetadataProvider{ProviderName: "second", Books: map[string]*domain.BookMetadata{"9780321125217": ddd}}
+	last := &MockMetadataProvider{ProviderName: "last"}
+
+	service := NewMetadataService(failing, empty, second, last)
+	got, err := service.LookupISBN("0-321-12521-5")
+	if err != nil {
+		t.Fatalf("LookupISBN() error = %v", err)
+	}
+	if got != ddd {
+		t.Errorf("LookupISBN() = %+v, want %+v", got, ddd)
+	}
+	if last.Calls != 0 {
+		t.Errorf("Expected providers after the first answer not to be asked, got %d calls", last.Calls)
+	}
+
+	// Unknown everywhere: nil, with the failure reported since nobody answered
+	if _, err := service.LookupISBN("978-4-7503-5688-4"); err == nil {
+		t.Error("Expected the provider error when no provider answered, got nil")
+	}
+	got, err = NewMetadataService(empty, last).LookupISBN("978-4-7503-5688-4")
+	if err != nil || got != nil {
+		t.Errorf("LookupISBN() = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestLookupISBN_InvalidISBN(t *testing.T) {
+	service := NewMetadataService(&MockMetadataProvider{ProviderName: "any"})
+	for _, isbn := range []string{"", "978-4-7503-5688-0"} {
+		if _, err := service.LookupISBN(isbn); err == nil {
+			t.Errorf("LookupISBN(%q): expected error, got nil", isbn)
+		}
+	}
+}
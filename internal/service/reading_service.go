@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"fmt"
+	"time"
+)
+
+// ReadingService tracks where the reader is with each bibliography. Every status
+// change is validated against the lifecycle in domain.ReadingStatus and recorded with
+// its time, so the history shows when a book was queued, started and finished.
+// Reading sessions log the individual sittings and give the progress through a book.
+type ReadingService struct {
+	statusRepo  domain.ReadingStatusRepository
+	sessionRepo domain.ReadingSessionRepository
+	bibRepo     domain.BibliographyRepository
+}
+
+func NewReadingService(statusRepo domain.ReadingStatusRepository, sessionRepo domain.ReadingSessionRepository, bibRepo domain.BibliographyRepository) *ReadingService {
+	return &ReadingService{
+		statusRepo:  statusRepo,
+		sessionRepo: sessionRepo,
+		bibRepo:     bibRepo,
+	}
+}
+
+// Queue puts a bibliography on the to-read list, or on the wishlist if wishlist is set.
+func (s *ReadingService) Queue(bookID domain.BibliographyID, wishlist bool, at time.Time) (*domain.ReadingStatusChange, error) {
+	if wishlist {
+		return s.ChangeStatus(bookID, domain.ReadingStatusWishlist, at)
+	}
+	return s.ChangeStatus(bookID, domain.ReadingStatusToRead, at)
+}
+
+// Start marks a bibliography as being read.
+func (s *ReadingService) Start(bookID domain.BibliographyID, at time.Time) (*domain.ReadingStatusChange, error) {
+	return s.ChangeStatus(bookID, domain.ReadingStatusReading, at)
+}
+
+// Finish marks a bibliography that is being read as finished.
+func (s *ReadingService) Finish(bookID domain.BibliographyID, at time.Time) (*domain.ReadingStatusChange, error) {
+	return s.ChangeStatus(bookID, domain.ReadingStatusFinished, at)
+}
+
+// Abandon marks a bibliography as abandoned.
+func (s *ReadingService) Abandon(bookID domain.BibliographyID, at time.Time) (*domain.ReadingStatusChange, error) {
+	return s.ChangeStatus(bookID, domain.ReadingStatusAbandoned, at)
+}
+
+// ChangeStatus moves a bibliography to status to at the given time (now if zero).
+// It fails with domain.ErrInvalidTransition if the lifecycle does not allow the move.
+func (s *ReadingService) ChangeStatus(bookID domain.BibliographyID, to domain.ReadingStatus, at time.Time) (*domain.ReadingStatusChange, error) {
+	bib, err := s.findBibliography(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.statusRepo.FindByBookID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reading status: %w", err)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	change, err := history.Transition(bookID, to, at.UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", bib.BibIndex, err)
+	}
+	if err := s.statusRepo.Save(change); err != nil {
+		return nil, fmt.Errorf("failed to save reading status: %w", err)
+	}
+	return change, nil
+}
+
+// History returns the status history of a bibliography, oldest change first.
+func (s *ReadingService) History(bookID domain.BibliographyID) (domain.ReadingHistory, error) {
+	history, err := s.statusRepo.FindByBookID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reading status: %w", err)
+	}
+	return history, nil
+}
+
+const (
+	// ContinueFromLast is the session start that continues where the previous session
+	// of the same bibliography ended.
+	ContinueFromLast = -1
+	// TimeOnly is the session end of a session that records only the time spent.
+	TimeOnly = -1
+)
+
+// LogSession records a reading session for a bibliography. A start of ContinueFromLast
+// picks up at the end of the latest session in the same unit, or at 0 if there is none;
+// an end of TimeOnly ends the session where it started.
+// A totalPages of 0 keeps the page count given by an earlier session, if any.
+// The date is truncated to the day (today if zero). Logging a session does not change
+// the reading status.
+func (s *ReadingService) LogSession(bookID domain.BibliographyID, date time.Time, duration time.Duration, unit domain.ProgressUnit, start, end, totalPages int, note string) (*domain.ReadingSession, error) {
+	bib, err := s.findBibliography(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.Sessions(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if start == ContinueFromLast {
+		start = 0
+		for i := len(sessions) - 1; i >= 0; i-- {
+			if sessions[i].Unit == unit {
+				start = sessions[i].End
+				break
+			}
+		}
+	}
+	if end == TimeOnly {
+		end = start
+	}
+	if totalPages == 0 {
+		for _, previous := range sessions {
+			if previous.TotalPages > 0 {
+				totalPages = previous.TotalPages
+			}
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if date.IsZero() {
+		date = now
+	}
+	session := &domain.ReadingSession{
+		ID:         domain.NewReadingSessionID(),
+		BookID:     bookID,
+		Date:       time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Duration:   duration.Truncate(time.Second),
+		Unit:       unit,
+		Start:      start,
+		End:        end,
+		TotalPages: totalPages,
+		Note:       note,
+		CreatedAt:  now,
+	}
+	if err := session.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", bib.BibIndex, err)
+	}
+	if err := s.sessionRepo.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to save reading session: %w", err)
+	}
+	return session, nil
+}
+
+// Sessions returns the reading sessions of a bibliography in the order they were logged.
+func (s *ReadingService) Sessions(bookID domain.BibliographyID) ([]*domain.ReadingSession, error) {
+	sessions, err := s.sessionRepo.FindByBookID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reading sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Progress summarizes the reading sessions of a bibliography.
+func (s *ReadingService) Progress(bookID domain.BibliographyID) (domain.ReadingProgress, error) {
+	sessions, err := s.Sessions(bookID)
+	if err != nil {
+		return domain.ReadingProgress{}, err
+	}
+	return domain.ComputeProgress(sessions), nil
+}
+
+func (s *ReadingService) findBibliography(bookID domain.BibliographyID) (*domain.Bibliography, error) {
+	bib, err := s.bibRepo.FindByID(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bibliography: %w", err)
+	}
+	if bib == nil {
+		return nil, fmt.Errorf("bibliography with ID %s not found", bookID)
+	}
+	return bib, nil
+}
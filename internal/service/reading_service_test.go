@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+// MockReadingStatusRepository is a mock implementation of domain.ReadingStatusRepository
+type MockReadingStatusRepository struct {
+	Changes []*domain.ReadingStatusChange
+}
+
+func (m *MockReadingStatusRepository) Save(change *domain.ReadingStatusChange) error {
+	m.Changes = append(m.Changes, change)
+	return nil
+}
+
+func (m *MockReadingStatusRepository) FindByBookID(bookID domain.BibliographyID) (domain.ReadingHistory, error) {
+	var history domain.ReadingHistory
+	for _, c := range m.Changes {
+		if c.BookID == bookID {
+			history = append(history, c)
+		}
+	}
+	return history, nil
+}
+
+func (m *MockReadingStatusRepository) FindCurrent() (map[domain.BibliographyID]domain.ReadingStatus, error) {
+	current := make(map[domain.BibliographyID]domain.ReadingStatus)
+	for _, c := range m.Changes {
+		current[c.BookID] = c.To
+	}
+	return current, nil
+}
+
+func (m *MockReadingStatusRepository) DeleteByBookID(bookID domain.BibliographyID) error {
+	kept := m.Changes[:0]
+	for _, c := range m.Changes {
+		if c.BookID != bookID {
+			kept = append(kept, c)
+		}
+	}
+	m.Changes = kept
+	return nil
+}
+
+// MockReadingSessionRepository is a mock implementation of domain.ReadingSessionRepository
+type MockReadingSessionRepository struct {
+	Sessions []*domain.ReadingSession
+}
+
+func (m *MockReadingSessionRepository) Save(session *domain.ReadingSession) error {
+	for i, existing := range m.Sessions {
+		if existing.ID == session.ID {
+			m.Sessions[i] = session
+			return nil
+		}
+	}
+	m.Sessions = append(m.Sessions, session)
+	return nil
+}
+
+func (m *MockReadingSessionRepository) FindAll(limit, offset int) ([]*domain.ReadingSession, error) {
+	return m.Sessions, nil
+}
+
+func (m *MockReadingSessionRepository) FindByID(id domain.ReadingSessionID) (*domain.ReadingSession, error) {
+	for _, session := range m.Sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockReadingSessionRepository) FindByBookID(bookID domain.BibliographyID) ([]*domain.ReadingSession, error) {
+	var sessions []*domain.ReadingSession
+	for _, session := range m.Sessions {
+		if session.BookID == bookID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *MockReadingSessionRepository) Delete(id domain.ReadingSessionID) error {
+	kept := m.Sessions[:0]
+	for _, session := range m.Sessions {
+		if session.ID != id {
+			kept = append(kept, session)
+		}
+	}
+	m.Sessions = kept
+	return nil
+}
+
+func newReadingTestService(t *testing.T) (*ReadingService, *MockReadingStatusRepository, domain.BibliographyID) {
+	t.Helper()
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{
+		bookID: {ID: bookID, BibIndex: "B56EE03DDD", Title: "Domain Driven Design"},
+	}}
+	statusRepo := &MockReadingStatusRepository{}
+	return NewReadingService(statusRepo, &MockReadingSessionRepository{}, bibRepo), statusRepo, bookID
+}
+
+func TestReadingService_Lifecycle(t *testing.T) {
+	svc, statusRepo, bookID := newReadingTestService(t)
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 9, 0, 0, 0, time.UTC) }
+
+	if _, err := svc.Queue(bookID, false, day(1)); err != nil {
+		t.Fatalf("Queue() error = %v", err)
+	}
+	if _, err := svc.Finish(bookID, day(2)); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("Finish() before Start: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := svc.Start(bookID, day(3)); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	change, err := svc.Finish(bookID, day(20))
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if change.From != domain.ReadingStatusReading || change.To != domain.ReadingStatusFinished || !change.ChangedAt.Equal(day(20)) {
+		t.Errorf("Unexpected change %+v", change)
+	}
+
+	history, err := svc.History(bookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history.Current() != domain.ReadingStatusFinished {
+		t.Fatalf("Expected 3 changes ending in finished, got %d ending in %s", len(history), history.Current())
+	}
+	if started, ok := history.LastChangedTo(domain.ReadingStatusReading); !ok || !started.Equal(day(3)) {
+		t.Errorf("LastChangedTo(reading) = %v, %v; want %v", started, ok, day(3))
+	}
+
+	// Changes cannot be backdated before the latest one
+	if _, err := svc.Start(bookID, day(10)); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition for a backdated change, got %v", err)
+	}
+	// Finished books can be read again
+	if _, err := svc.Start(bookID, time.Time{}); err != nil {
+		t.Errorf("Start() after Finish error = %v", err)
+	}
+	if len(statusRepo.Changes) != 4 {
+		t.Errorf("Expected 4 saved changes, got %d", len(statusRepo.Changes))
+	}
+}
+
+func TestReadingService_UnknownBibliography(t *testing.T) {
+	svc, statusRepo, _ := newReadingTestService(t)
+	if _, err := svc.Start(domain.NewBibliographyID(), time.Time{}); err == nil {
+		t.Error("Expected error for unknown bibliography, got nil")
+	}
+	if len(statusRepo.Changes) != 0 {
+		t.Errorf("Expected nothing saved, got %d changes", len(statusRepo.Changes))
+	}
+}
+
+func TestReadingStatus_Transitions(t *testing.T) {
+	tests := []struct {
+		from, to domain.ReadingStatus
+		want     bool
+	}{
+		{domain.ReadingStatusNone, domain.ReadingStatusReading, true},
+		{domain.ReadingStatusNone, domain.ReadingStatusFinished, false},
+		{domain.ReadingStatusWishlist, domain.ReadingStatusToRead, true},
+		{domain.ReadingStatusToRead, domain.ReadingStatusFinished, false},
+		{domain.ReadingStatusReading, domain.ReadingStatusAbandoned, true},
+		{domain.ReadingStatusReading, domain.ReadingStatusReading, false},
+		{domain.ReadingStatusFinished, domain.ReadingStatusAbandoned, false},
+		{domain.ReadingStatusAbandoned, domain.ReadingStatusReading, true},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s allowed = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteBibliography_RemovesReadingStatus(t *testing.T) {
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bookID: {ID: bookID, BibIndex: "B56TEST"}},
+	}
+	reviewID := domain.NewReviewID()
+	reviewRepo := &MockReviewRepository{
+		Reviews: map[domain.ReviewID]*domain.Review{reviewID: {ID: reviewID, BookID: bookID, Goals: "Goals"}},
+	}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, reviewRepo)
+	statusRepo := &MockReadingStatusRepository{Changes: []*domain.ReadingStatusChange{
+		{BookID: bookID, To: domain.ReadingStatusReading, ChangedAt: time.Now()},
+	}}
+	svc.SetReadingStatusRepository(statusRepo)
+
+	if _, _, err := svc.DeleteBibliography(bookID, CascadeReviews); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statusRepo.Changes) != 0 || len(reviewRepo.Reviews) != 0 {
+		t.Errorf("Expected status history and reviews to be deleted, got %d changes and %d reviews", len(statusRepo.Changes), len(reviewRepo.Reviews))
+	}
+}
+
+func TestReadingService_LogSession(t *testing.T) {
+	svc, statusRepo, bookID := newReadingTestService(t)
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 21, 30, 0, 0, time.UTC) }
+
+	first, err := svc.LogSession(bookID, day(1), 40*time.Minute, domain.ProgressPages, ContinueFromLast, 30, 300, "")
+	if err != nil {
+		t.Fatalf("LogSession() error = %v", err)
+	}
+	if first.Start != 0 || !first.Date.Equal(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first session to start at 0 on 2025-11-01, got %d on %v", first.Start, first.Date)
+	}
+	second, err := svc.LogSession(bookID, day(4), time.Hour, domain.ProgressPages, ContinueFromLast, 90, 0, "Chapter 3")
+	if err != nil {
+		t.Fatalf("LogSession() error = %v", err)
+	}
+	if second.Start != 30 {
+		t.Errorf("Expected second session to continue at 30, got %d", second.Start)
+	}
+
+	progress, err := svc.Progress(bookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 90 pages over 4 days is 22.5 pages a day; 210 pages remain, so 10 more days
+	if progress.Sessions != 2 || progress.TotalTime != 100*time.Minute || progress.Position != 90 || progress.Remaining() != 210 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	if want := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC); !progress.EstimatedFinish.Equal(want) {
+		t.Errorf("EstimatedFinish = %v, want %v", progress.EstimatedFinish, want)
+	}
+
+	if _, err := svc.LogSession(bookID, day(5), 0, domain.ProgressPages, 90, 320, 0, ""); !errors.Is(err, domain.ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for a page beyond the end of the book, got %v", err)
+	}
+	if _, err := svc.LogSession(bookID, day(5), 0, domain.ProgressPages, 90, 80, 0, ""); !errors.Is(err, domain.ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for an end before the start, got %v", err)
+	}
+	// A time-only session ends where it starts
+	if session, err := svc.LogSession(bookID, day(5), 20*time.Minute, domain.ProgressPages, ContinueFromLast, TimeOnly, 0, ""); err != nil || session.Start != 90 || session.End != 90 {
+		t.Errorf("Expected a time-only session at page 90, got %+v, %v", session, err)
+	}
+	if _, err := svc.LogSession(domain.NewBibliographyID(), day(5), time.Hour, domain.ProgressPages, 0, 10, 0, ""); err == nil {
+		t.Error("Expected error for unknown bibliography, got nil")
+	}
+	if len(statusRepo.Changes) != 0 {
+		t.Errorf("Expected sessions not to change the reading status, got %d changes", len(statusRepo.Changes))
+	}
+}
+
+func TestComputeProgress(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.UTC) }
+	bookID := domain.NewBibliographyID()
+	session := func(d int, unit domain.ProgressUnit, start, end, total int) *domain.ReadingSession {
+		return &domain.ReadingSession{BookID: bookID, Date: day(d), Duration: 30 * time.Minute, Unit: unit, Start: start, End: end, TotalPages: total}
+	}
+
+	tests := []struct {
+		name          string
+		sessions      []*domain.ReadingSession
+		wantUnit      domain.ProgressUnit
+		wantPosition  int
+		wantRemaining int
+		wantFinish    time.Time
+	}{
+		{"no sessions", nil, "", 0, -1, time.Time{}},
+		{"unknown page count", []*domain.ReadingSession{session(1, domain.ProgressPages, 0, 50, 0)}, domain.ProgressPages, 50, -1, time.Time{}},
+		{"percent", []*domain.ReadingSession{session(1, domain.ProgressPercent, 0, 10, 0), session(2, domain.ProgressPercent, 10, 20, 0)}, domain.ProgressPercent, 20, 80, day(10)},
+		{"finished", []*domain.ReadingSession{session(1, domain.ProgressPages, 0, 100, 100)}, domain.ProgressPages, 100, 0, time.Time{}},
+		{"logged out of order", []*domain.ReadingSession{session(3, domain.ProgressPages, 50, 100, 200), session(2, domain.ProgressPages, 0, 50, 0)}, domain.ProgressPages, 100, 100, day(5)},
+		{"converted from percent", []*domain.ReadingSession{session(1, domain.ProgressPercent, 0, 25, 0), session(2, domain.ProgressPages, 50, 100, 200)}, domain.ProgressPages, 100, 100, day(4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := domain.ComputeProgress(tt.sessions)
+			if p.Unit != tt.wantUnit || p.Position != tt.wantPosition || p.Remaining() != tt.wantRemaining || !p.EstimatedFinish.Equal(tt.wantFinish) {
+				t.Errorf("ComputeProgress() = %+v (remaining %d), want unit %q position %d remaining %d finish %v",
+					p, p.Remaining(), tt.wantUnit, tt.wantPosition, tt.wantRemaining, tt.wantFinish)
+			}
+		})
+	}
+}
+
+func TestDeleteBibliography_RemovesReadingSessions(t *testing.T) {
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bookID: {ID: bookID, BibIndex: "B56TEST"}},
+	}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, &MockReviewRepository{})
+	other := domain.NewBibliographyID()
+	sessionRepo := &MockReadingSessionRepository{Sessions: []*domain.ReadingSession{
+		{ID: domain.NewReadingSessionID(), BookID: bookID},
+		{ID: domain.NewReadingSessionID(), BookID: other},
+	}}
+	svc.SetReadingSessionRepository(sessionRepo)
+
+	if _, _, err := svc.DeleteBibliography(bookID, CascadeReviews); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessionRepo.Sessions) != 1 || sessionRepo.Sessions[0].BookID != other {
+		t.Errorf("Expected only the other bibliography's session to remain, got %d sessions", len(sessionRepo.Sessions))
+	}
+}
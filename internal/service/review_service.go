@@ -2,7 +2,9 @@ package service
 
 import (
 	"bibliography_log/internal/domain"
+	"bibliography_log/internal/textdiff"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 )
@@ -10,6 +12,12 @@ import (
 type ReviewService struct {
 	reviewRepo domain.ReviewRepository
 	bibRepo    domain.BibliographyRepository
+	// searchIndex is kept in sync with saved reviews if set.
+	searchIndex domain.SearchIndex
+	// revisionRepo records every version of a review if set; changedBy names whoever
+	// makes the changes.
+	revisionRepo domain.ReviewRevisionRepository
+	changedBy    string
 }
 
 func NewReviewService(reviewRepo domain.ReviewRepository, bibRepo domain.BibliographyRepository) *ReviewService {
@@ -19,6 +27,18 @@ func NewReviewService(reviewRepo domain.ReviewRepository, bibRepo domain.Bibliog
 	}
 }
 
+// SetSearchIndex makes the service update index whenever reviews are saved.
+func (s *ReviewService) SetSearchIndex(index domain.SearchIndex) {
+	s.searchIndex = index
+}
+
+// SetRevisionRepository makes the service record a revision each time a review is added,
+// updated or reverted. changedBy is stored as who made the change.
+func (s *ReviewService) SetRevisionRepository(repo domain.ReviewRevisionRepository, changedBy string) {
+	s.revisionRepo = repo
+	s.changedBy = changedBy
+}
+
 func (s *ReviewService) AddReview(bookID domain.BibliographyID, goals string, summary string) (*domain.Review, error) {
 	// Validate inputs
 	// Note: 'goals' and 'summary' are text fields that may contain meaningful whitespace
@@ -27,7 +47,7 @@ func (s *ReviewService) AddReview(bookID domain.BibliographyID, goals string, su
 	// We only use TrimSpace() for validation to check if the content is non-empty.
 	// Note: 'summary' is optional and does not require validation. If this changes, add validation here.
 	if strings.TrimSpace(goals) == "" {
-		return nil, fmt.Errorf("goals are required and cannot be empty")
+		return nil, domain.Invalid(fmt.Errorf("goals are required and cannot be empty"))
 	}
 
 	// Verify book exists
@@ -37,7 +57,7 @@ func (s *ReviewService) AddReview(bookID domain.BibliographyID, goals string, su
 		return nil, fmt.Errorf("failed to verify book existence: %w", err)
 	}
 	if bib == nil {
-		return nil, fmt.Errorf("bibliography with ID %s not found", bookID)
+		return nil, fmt.Errorf("bibliography with ID %s %w", bookID, domain.ErrNotFound)
 	}
 
 	review := &domain.Review{
@@ -52,6 +72,10 @@ func (s *ReviewService) AddReview(bookID domain.BibliographyID, goals string, su
 	if err := s.reviewRepo.Save(review); err != nil {
 		return nil, fmt.Errorf("failed to save review: %w", err)
 	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.IndexReview(review)
+	})
+	s.recordRevision(nil, review, domain.RevisionCreated, 0)
 
 	return review, nil
 }
@@ -64,7 +88,11 @@ func (s *ReviewService) AddReview(bookID domain.BibliographyID, goals string, su
 func (s *ReviewService) UpdateReview(id domain.ReviewID, goals *string, summary *string) (*domain.Review, error) {
 	// Validate that at least one field is being updated
 	if goals == nil && summary == nil {
-		return nil, fmt.Errorf("at least one field (goals or summary) must be provided for update")
+		return nil, domain.Invalid(fmt.Errorf("at least one field (goals or summary) must be provided for update"))
+	}
+	// Validate goals if being updated (same validation as AddReview)
+	if goals != nil && strings.TrimSpace(*goals) == "" {
+		return nil, domain.Invalid(fmt.Errorf("goals cannot be empty or whitespace-only"))
 	}
 
 	// Retrieve existing review
@@ -73,15 +101,16 @@ func (s *ReviewService) UpdateReview(id domain.ReviewID, goals *string, summary
 		return nil, fmt.Errorf("failed to find review: %w", err)
 	}
 	if review == nil {
-		return nil, fmt.Errorf("review with ID %s not found", id)
+		return nil, fmt.Errorf("review with ID %s %w", id, domain.ErrNotFound)
+	}
+	history, err := s.keptHistory(review)
+	if err != nil {
+		return nil, err
 	}
+	previous := *review
 
 	// Update fields if provided
 	if goals != nil {
-		// Validate goals if being updated (same validation as AddReview)
-		if strings.TrimSpace(*goals) == "" {
-			return nil, fmt.Errorf("goals cannot be empty or whitespace-only")
-		}
 		review.Goals = *goals
 	}
 	if summary != nil {
@@ -96,6 +125,156 @@ func (s *ReviewService) UpdateReview(id domain.ReviewID, goals *string, summary
 	if err := s.reviewRepo.Save(review); err != nil {
 		return nil, fmt.Errorf("failed to update review: %w", err)
 	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.IndexReview(review)
+	})
+	if review.Goals != previous.Goals || review.Summary != previous.Summary {
+		s.recordRevision(history, review, domain.RevisionUpdated, 0)
+	}
 
 	return review, nil
 }
+
+// ListReviewsByBookID returns every review attached to the given bibliography.
+// FindByID returns the review with the given ID, or nil if there is none.
+func (s *ReviewService) FindByID(id domain.ReviewID) (*domain.Review, error) {
+	return s.reviewRepo.FindByID(id)
+}
+
+// ListReviews returns up to limit reviews (0 for all) after skipping offset.
+func (s *ReviewService) ListReviews(limit, offset int) ([]*domain.Review, error) {
+	return s.reviewRepo.FindAll(limit, offset)
+}
+
+func (s *ReviewService) ListReviewsByBookID(bookID domain.BibliographyID) ([]*domain.Review, error) {
+	return s.reviewRepo.FindByBookID(bookID)
+}
+
+// keptHistory returns the recorded revisions of review. A review written before revisions
+// were kept gets its current state recorded first, so that updating it loses nothing.
+// It returns nil if revisions are not kept.
+func (s *ReviewService) keptHistory(review *domain.Review) (domain.ReviewHistory, error) {
+	if s.revisionRepo == nil {
+		return nil, nil
+	}
+	history, err := s.revisionRepo.FindByReviewID(review.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review revisions: %w", err)
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+	recorded := history.Next(review, domain.RevisionRecorded, "")
+	if err := s.revisionRepo.Append(recorded); err != nil {
+		return nil, fmt.Errorf("failed to record review revision: %w", err)
+	}
+	return domain.ReviewHistory{recorded}, nil
+}
+
+// recordRevision appends the review as saved to history, if revisions are kept, and returns
+// the new revision. The review itself is already saved by then, so a failure is logged
+// rather than failing the change, and nil is returned.
+func (s *ReviewService) recordRevision(history domain.ReviewHistory, review *domain.Review, action domain.RevisionAction, revertedTo int) *domain.ReviewRevision {
+	if s.revisionRepo == nil {
+		return nil
+	}
+	revision := history.Next(review, action, s.changedBy)
+	revision.RevertedTo = revertedTo
+	if err := s.revisionRepo.Append(revision); err != nil {
+		slog.Error("Failed to record review revision; the review was saved without it", "review", review.ID, "err", err)
+		return nil
+	}
+	return revision
+}
+
+// ReviewHistory returns the revisions of a review, oldest first. A review written before
+// revisions were kept and not updated since has a single "recorded" revision holding its
+// current state.
+func (s *ReviewService) ReviewHistory(id domain.ReviewID) (*domain.Review, domain.ReviewHistory, error) {
+	if s.revisionRepo == nil {
+		return nil, nil, fmt.Errorf("review revisions are not kept")
+	}
+	review, err := s.reviewRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find review: %w", err)
+	}
+	if review == nil {
+		return nil, nil, fmt.Errorf("review with ID %s %w", id, domain.ErrNotFound)
+	}
+	history, err := s.revisionRepo.FindByReviewID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find review revisions: %w", err)
+	}
+	if len(history) == 0 {
+		history = domain.ReviewHistory{history.Next(review, domain.RevisionRecorded, "")}
+	}
+	return review, history, nil
+}
+
+// ReviewDiff is the line-level difference between two revisions of a review.
+type ReviewDiff struct {
+	From    *domain.ReviewRevision // nil to compare To with an empty review
+	To      *domain.ReviewRevision
+	Goals   []textdiff.Line
+	Summary []textdiff.Line
+}
+
+// DiffReview compares two revisions of a review. to defaults (0) to the latest revision,
+// and from to the one before to; the first revision is compared with an empty review.
+func (s *ReviewService) DiffReview(id domain.ReviewID, from, to int) (*ReviewDiff, error) {
+	_, history, err := s.ReviewHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &ReviewDiff{To: history.Latest()}
+	if to != 0 {
+		if d.To, err = history.Revision(to); err != nil {
+			return nil, err
+		}
+	}
+	if from == 0 {
+		d.From = history.Previous(d.To)
+	} else if d.From, err = history.Revision(from); err != nil {
+		return nil, err
+	}
+
+	var old domain.ReviewRevision
+	if d.From != nil {
+		old = *d.From
+	}
+	d.Goals = textdiff.Lines(old.Goals, d.To.Goals)
+	d.Summary = textdiff.Lines(old.Summary, d.To.Summary)
+	return d, nil
+}
+
+// RevertReview restores the Goals and Summary of an older revision. The revert is itself
+// recorded as a new revision, so it can be undone the same way; the returned revision is
+// nil if it could not be recorded.
+func (s *ReviewService) RevertReview(id domain.ReviewID, number int) (*domain.Review, *domain.ReviewRevision, error) {
+	review, history, err := s.ReviewHistory(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := history.Revision(number)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.Goals == review.Goals && target.Summary == review.Summary {
+		return nil, nil, domain.Invalid(fmt.Errorf("review already matches revision %d", number))
+	}
+	// A legacy review's recorded state only exists in memory until now
+	if history, err = s.keptHistory(review); err != nil {
+		return nil, nil, err
+	}
+
+	review.Goals, review.Summary = target.Goals, target.Summary
+	review.UpdatedAt = time.Now()
+	if err := s.reviewRepo.Save(review); err != nil {
+		return nil, nil, fmt.Errorf("failed to update review: %w", err)
+	}
+	updateSearchIndex(s.searchIndex, func(index domain.SearchIndex) error {
+		return index.IndexReview(review)
+	})
+	return review, s.recordRevision(history, review, domain.RevisionReverted, number), nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bibliography_log/internal/domain"
+	"bibliography_log/internal/textdiff"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -9,7 +11,8 @@ import (
 
 // MockReviewRepository for testing
 type MockReviewRepository struct {
-	Reviews map[domain.ReviewID]*domain.Review
+	Reviews   map[domain.ReviewID]*domain.Review
+	DeleteErr error
 }
 
 func (m *MockReviewRepository) Save(review *domain.Review) error {
@@ -56,6 +59,14 @@ func (m *MockReviewRepository) FindByBookID(bookID domain.BibliographyID) ([]*do
 	return reviews, nil
 }
 
+func (m *MockReviewRepository) Delete(id domain.ReviewID) error {
+	if m.DeleteErr != nil {
+		return m.DeleteErr
+	}
+	delete(m.Reviews, id)
+	return nil
+}
+
 func TestAddReview_Success(t *testing.T) {
 	// Setup
 	reviewRepo := &MockReviewRepository{}
@@ -334,3 +345,248 @@ func TestUpdateReview_EmptyGoals(t *testing.T) {
 		t.Errorf("Expected specific error message, got '%s'", err.Error())
 	}
 }
+
+// MockReviewRevisionRepository for testing
+type MockReviewRevisionRepository struct {
+	Revisions map[domain.ReviewID]domain.ReviewHistory
+	AppendErr error // returned by Append without recording anything
+}
+
+func (m *MockReviewRevisionRepository) Append(rev *domain.ReviewRevision) error {
+	if m.AppendErr != nil {
+		return m.AppendErr
+	}
+	if m.Revisions == nil {
+		m.Revisions = make(map[domain.ReviewID]domain.ReviewHistory)
+	}
+	history := m.Revisions[rev.ReviewID]
+	if want := len(history) + 1; rev.Number != want {
+		return fmt.Errorf("expected revision %d, got %d", want, rev.Number)
+	}
+	saved := *rev
+	m.Revisions[rev.ReviewID] = append(history, &saved)
+	return nil
+}
+
+func (m *MockReviewRevisionRepository) FindByReviewID(id domain.ReviewID) (domain.ReviewHistory, error) {
+	return m.Revisions[id], nil
+}
+
+func (m *MockReviewRevisionRepository) DeleteByReviewID(id domain.ReviewID) error {
+	delete(m.Revisions, id)
+	return nil
+}
+
+// newRevisedReview adds a review with goals "Goals" and summary "First" and updates the
+// summary to "First\nSecond".
+func newRevisedReview(t *testing.T) (*ReviewService, *MockReviewRevisionRepository, domain.ReviewID) {
+	t.Helper()
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bookID: {ID: bookID, Title: "Test Book"}},
+	}
+	revisionRepo := &MockReviewRevisionRepository{}
+	svc := NewReviewService(&MockReviewRepository{}, bibRepo)
+	svc.SetRevisionRepository(revisionRepo, "alice")
+
+	review, err := svc.AddReview(bookID, "Goals", "First")
+	if err != nil {
+		t.Fatalf("AddReview failed: %v", err)
+	}
+	summary := "First\nSecond"
+	if _, err := svc.UpdateReview(review.ID, nil, &summary); err != nil {
+		t.Fatalf("UpdateReview failed: %v", err)
+	}
+	return svc, revisionRepo, review.ID
+}
+
+func TestReviewHistory(t *testing.T) {
+	svc, _, id := newRevisedReview(t)
+
+	// Saving unchanged text adds no revision
+	goals := "Goals"
+	if _, err := svc.UpdateReview(id, &goals, nil); err != nil {
+		t.Fatalf("UpdateReview failed: %v", err)
+	}
+
+	_, history, err := svc.ReviewHistory(id)
+	if err != nil {
+		t.Fatalf("ReviewHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d", len(history))
+	}
+	if history[0].Action != domain.RevisionCreated || history[0].Summary != "First" || history[0].ChangedBy != "alice" {
+		t.Errorf("Unexpected first revision: %+v", history[0])
+	}
+	if history[1].Action != domain.RevisionUpdated || history[1].Number != 2 || history[1].Summary != "First\nSecond" {
+		t.Errorf("Unexpected second revision: %+v", history[1])
+	}
+	if fields := history[1].ChangedFields(history[0]); len(fields) != 1 || fields[0] != "summary" {
+		t.Errorf("Expected only the summary to change, got %v", fields)
+	}
+
+	if _, _, err := svc.ReviewHistory(domain.NewReviewID()); err == nil {
+		t.Error("Expected error for a missing review")
+	}
+}
+
+func TestReviewHistory_LegacyReview(t *testing.T) {
+	reviewRepo := &MockReviewRepository{}
+	review := &domain.Review{ID: domain.NewReviewID(), Goals: "Old goals", Summary: "Old summary", UpdatedAt: time.Now().Add(-time.Hour)}
+	reviewRepo.Save(review)
+	revisionRepo := &MockReviewRevisionRepository{}
+	svc := NewReviewService(reviewRepo, &MockBibliographyRepository{})
+	svc.SetRevisionRepository(revisionRepo, "alice")
+
+	// A review without revisions shows its current state, without recording it yet
+	_, history, err := svc.ReviewHistory(review.ID)
+	if err != nil {
+		t.Fatalf("ReviewHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Action != domain.RevisionRecorded || history[0].Summary != "Old summary" {
+		t.Fatalf("Unexpected history: %+v", history)
+	}
+	if len(revisionRepo.Revisions[review.ID]) != 0 {
+		t.Error("Expected nothing to be recorded by ReviewHistory")
+	}
+
+	// Updating it records the old text first
+	summary := "New summary"
+	if _, err := svc.UpdateReview(review.ID, nil, &summary); err != nil {
+		t.Fatalf("UpdateReview failed: %v", err)
+	}
+	history = revisionRepo.Revisions[review.ID]
+	if len(history) != 2 || history[0].Summary != "Old summary" || history[0].ChangedBy != "" || history[1].Summary != "New summary" {
+		t.Errorf("Unexpected history after update: %+v", history)
+	}
+}
+
+func TestUpdateReview_LegacyReviewInvalidGoals(t *testing.T) {
+	reviewRepo := &MockReviewRepository{}
+	review := &domain.Review{ID: domain.NewReviewID(), Goals: "Old goals", Summary: "Old summary"}
+	reviewRepo.Save(review)
+	revisionRepo := &MockReviewRevisionRepository{}
+	svc := NewReviewService(reviewRepo, &MockBibliographyRepository{})
+	svc.SetRevisionRepository(revisionRepo, "alice")
+
+	// A rejected update records nothing, not even the legacy review's current state
+	emptyGoals := " "
+	if _, err := svc.UpdateReview(review.ID, &emptyGoals, nil); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if len(revisionRepo.Revisions[review.ID]) != 0 {
+		t.Errorf("Expected no revisions, got %+v", revisionRepo.Revisions[review.ID])
+	}
+}
+
+func TestReviewService_RevisionFailureKeepsChange(t *testing.T) {
+	svc, revisionRepo, id := newRevisedReview(t)
+	revisionRepo.AppendErr = errors.New("disk full")
+
+	// The review is saved even though its revision cannot be recorded
+	summary := "Third"
+	review, err := svc.UpdateReview(id, nil, &summary)
+	if err != nil || review.Summary != "Third" {
+		t.Fatalf("UpdateReview() = %+v, %v; want the updated review", review, err)
+	}
+	review, revision, err := svc.RevertReview(id, 1)
+	if err != nil || review.Summary != "First" || revision != nil {
+		t.Errorf("RevertReview() = %+v, %+v, %v; want the reverted review without a revision", review, revision, err)
+	}
+	if saved, _ := svc.FindByID(id); saved.Summary != "First" {
+		t.Errorf("Expected the revert to be saved, got %q", saved.Summary)
+	}
+	if len(revisionRepo.Revisions[id]) != 2 {
+		t.Errorf("Expected the 2 earlier revisions, got %d", len(revisionRepo.Revisions[id]))
+	}
+
+	bookID := domain.NewBibliographyID()
+	svc.bibRepo.Save(&domain.Bibliography{ID: bookID, Title: "Another Book"})
+	if review, err := svc.AddReview(bookID, "Goals", ""); err != nil || review == nil {
+		t.Errorf("AddReview() = %+v, %v; want the new review", review, err)
+	}
+}
+
+func TestDiffReview(t *testing.T) {
+	svc, _, id := newRevisedReview(t)
+
+	// Defaults compare the latest revision with the one before it
+	diff, err := svc.DiffReview(id, 0, 0)
+	if err != nil {
+		t.Fatalf("DiffReview failed: %v", err)
+	}
+	if diff.From.Number != 1 || diff.To.Number != 2 {
+		t.Errorf("Expected revisions 1 to 2, got %d to %d", diff.From.Number, diff.To.Number)
+	}
+	if textdiff.Changed(diff.Goals) {
+		t.Errorf("Expected goals unchanged, got %+v", diff.Goals)
+	}
+	if len(diff.Summary) != 2 || diff.Summary[0].Op != textdiff.Equal || diff.Summary[1].Op != textdiff.Insert || diff.Summary[1].Text != "Second" {
+		t.Errorf("Unexpected summary diff: %+v", diff.Summary)
+	}
+
+	// The first revision is compared with an empty review
+	diff, err = svc.DiffReview(id, 0, 1)
+	if err != nil {
+		t.Fatalf("DiffReview failed: %v", err)
+	}
+	if diff.From != nil || len(diff.Goals) != 1 || diff.Goals[0].Op != textdiff.Insert {
+		t.Errorf("Unexpected diff of the first revision: %+v", diff)
+	}
+
+	if _, err := svc.DiffReview(id, 3, 0); err == nil {
+		t.Error("Expected error for a missing revision")
+	}
+}
+
+func TestRevertReview(t *testing.T) {
+	svc, revisionRepo, id := newRevisedReview(t)
+
+	review, revision, err := svc.RevertReview(id, 1)
+	if err != nil {
+		t.Fatalf("RevertReview failed: %v", err)
+	}
+	if review.Summary != "First" {
+		t.Errorf("Expected summary 'First', got %q", review.Summary)
+	}
+	if revision.Number != 3 || revision.Action != domain.RevisionReverted || revision.RevertedTo != 1 {
+		t.Errorf("Unexpected revert revision: %+v", revision)
+	}
+	if len(revisionRepo.Revisions[id]) != 3 {
+		t.Errorf("Expected 3 revisions, got %d", len(revisionRepo.Revisions[id]))
+	}
+
+	// Reverting to the current text is refused
+	if _, _, err := svc.RevertReview(id, 1); err == nil {
+		t.Error("Expected error when the review already matches the revision")
+	}
+	// The revert itself can be undone
+	if review, _, err = svc.RevertReview(id, 2); err != nil || review.Summary != "First\nSecond" {
+		t.Errorf("RevertReview(2) = %+v, %v", review, err)
+	}
+}
+
+func TestDeleteBibliography_RemovesReviewRevisions(t *testing.T) {
+	bookID := domain.NewBibliographyID()
+	bibRepo := &MockBibliographyRepository{
+		Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bookID: {ID: bookID, BibIndex: "B56TEST"}},
+	}
+	reviewID := domain.NewReviewID()
+	reviewRepo := &MockReviewRepository{
+		Reviews: map[domain.ReviewID]*domain.Review{reviewID: {ID: reviewID, BookID: bookID, Goals: "Goals"}},
+	}
+	svc := NewBibliographyService(bibRepo, &MockClassificationRepository{}, reviewRepo)
+	revisionRepo := &MockReviewRevisionRepository{}
+	for _, review := range reviewRepo.Reviews {
+		revisionRepo.Append(domain.ReviewHistory{}.Next(review, domain.RevisionCreated, ""))
+	}
+	svc.SetReviewRevisionRepository(revisionRepo)
+
+	if _, _, err := svc.DeleteBibliography(bookID, CascadeReviews); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(revisionRepo.Revisions) != 0 {
+		t.Errorf("Expected the revisions of deleted reviews to be removed, got %d", len(revisionRepo.Revisions))
+	}
+}
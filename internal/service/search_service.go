@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// SearchResult is a bibliography matched by a full-text search.
+type SearchResult struct {
+	Bibliography *domain.Bibliography
+	Score        float64
+	Fields       []string         // matched fields
+	Reviews      []*domain.Review // reviews that matched
+}
+
+// SearchService answers full-text queries over bibliographies and reviews.
+type SearchService struct {
+	index      domain.SearchIndex
+	bibRepo    domain.BibliographyRepository
+	reviewRepo domain.ReviewRepository
+}
+
+func NewSearchService(index domain.SearchIndex, bibRepo domain.BibliographyRepository, reviewRepo domain.ReviewRepository) *SearchService {
+	return &SearchService{
+		index:      index,
+		bibRepo:    bibRepo,
+		reviewRepo: reviewRepo,
+	}
+}
+
+// Search returns up to limit results (all if limit <= 0), most relevant first.
+// The index is built from the repositories on first use.
+func (s *SearchService) Search(query string, limit int) ([]SearchResult, error) {
+	hits, err := s.index.Search(query)
+	if errors.Is(err, domain.ErrSearchIndexNotBuilt) {
+		if _, _, err := s.RebuildIndex(); err != nil {
+			return nil, err
+		}
+		hits, err = s.index.Search(query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	var results []SearchResult
+	for _, hit := range hits {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		bib, err := s.bibRepo.FindByID(hit.BookID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bibliography: %w", err)
+		}
+		if bib == nil {
+			// Only orphaned reviews matched
+			continue
+		}
+		result := SearchResult{Bibliography: bib, Score: hit.Score, Fields: hit.Fields}
+		for _, id := range hit.ReviewIDs {
+			review, err := s.reviewRepo.FindByID(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find review: %w", err)
+			}
+			if review != nil {
+				result.Reviews = append(result.Reviews, review)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// RebuildIndex indexes every bibliography and review from scratch, e.g. after the
+// data files were edited by hand. It returns the number of entities indexed.
+func (s *SearchService) RebuildIndex() (int, int, error) {
+	bibs, err := s.bibRepo.FindAll(0, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list bibliographies: %w", err)
+	}
+	reviews, err := s.reviewRepo.FindAll(0, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	if err := s.index.Rebuild(bibs, reviews); err != nil {
+		return 0, 0, fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+	return len(bibs), len(reviews), nil
+}
+
+// updateSearchIndex applies fn to index if one is configured. Failures are logged rather
+// than returned because the entity has already been saved; 'biblog reindex' repairs the index.
+func updateSearchIndex(index domain.SearchIndex, fn func(index domain.SearchIndex) error) {
+	if index == nil {
+		return
+	}
+	if err := fn(index); err != nil {
+		slog.Error("Failed to update search index; run 'biblog reindex' to rebuild it", "err", err)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bibliography_log/internal/domain"
+	"testing"
+	"time"
+)
+
+// MockSearchIndex records the entities indexed through it.
+type MockSearchIndex struct {
+	Built          bool
+	Bibliographies map[domain.BibliographyID]*domain.Bibliography
+	Reviews        map[domain.ReviewID]*domain.Review
+	Hits           []domain.SearchHit
+}
+
+func (m *MockSearchIndex) IndexBibliography(b *domain.Bibliography) error {
+	if m.Bibliographies == nil {
+		m.Bibliographies = make(map[domain.BibliographyID]*domain.Bibliography)
+	}
+	m.Bibliographies[b.ID] = b
+	return nil
+}
+
+func (m *MockSearchIndex) IndexReview(r *domain.Review) error {
+	if m.Reviews == nil {
+		m.Reviews = make(map[domain.ReviewID]*domain.Review)
+	}
+	m.Reviews[r.ID] = r
+	return nil
+}
+
+func (m *MockSearchIndex) RemoveBibliography(id domain.BibliographyID) error {
+	delete(m.Bibliographies, id)
+	return nil
+}
+
+func (m *MockSearchIndex) RemoveReview(id domain.ReviewID) error {
+	delete(m.Reviews, id)
+	return nil
+}
+
+func (m *MockSearchIndex) Rebuild(bibs []*domain.Bibliography, reviews []*domain.Review) error {
+	m.Built = true
+	m.Bibliographies, m.Reviews = nil, nil
+	for _, b := range bibs {
+		_ = m.IndexBibliography(b)
+	}
+	for _, r := range reviews {
+		_ = m.IndexReview(r)
+	}
+	return nil
+}
+
+func (m *MockSearchIndex) Search(_ string) ([]domain.SearchHit, error) {
+	if !m.Built {
+		return nil, domain.ErrSearchIndexNotBuilt
+	}
+	return m.Hits, nil
+}
+
+func TestSearchIndexMaintainedByServices(t *testing.T) {
+	index := &MockSearchIndex{}
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{}}
+	classRepo := &MockClassificationRepository{
+		Classifications: map[domain.ClassCode]*domain.Classification{
+			"56": {Code: "56", Name: "Technology"},
+		},
+	}
+	reviewRepo := &MockReviewRepository{}
+	bibSvc := NewBibliographyService(bibRepo, classRepo, reviewRepo)
+	bibSvc.SetSearchIndex(index)
+	reviewSvc := NewReviewService(reviewRepo, bibRepo)
+	reviewSvc.SetSearchIndex(index)
+
+	bib, err := bibSvc.AddBibliography("Domain Driven Design", "Eric Evans", nil, "", "", "Book", "56",
+		time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC), "", "", "")
+	if err != nil {
+		t.Fatalf("Failed to add bibliography: %v", err)
+	}
+	title := "Domain-Driven Design"
+	if _, err := bibSvc.UpdateBibliography(bib.ID, BibliographyUpdate{Title: &title}); err != nil {
+		t.Fatalf("Failed to update bibliography: %v", err)
+	}
+	if got := index.Bibliographies[bib.ID]; got == nil || got.Title != title {
+		t.Errorf("Expected updated bibliography to be indexed, got %+v", got)
+	}
+
+	review, err := reviewSvc.AddReview(bib.ID, "Learn modeling", "")
+	if err != nil {
+		t.Fatalf("Failed to add review: %v", err)
+	}
+	summary := "Bounded contexts"
+	if _, err := reviewSvc.UpdateReview(review.ID, nil, &summary); err != nil {
+		t.Fatalf("Failed to update review: %v", err)
+	}
+	if got := index.Reviews[review.ID]; got == nil || got.Summary != summary {
+		t.Errorf("Expected updated review to be indexed, got %+v", got)
+	}
+
+	if _, _, err := bibSvc.DeleteBibliography(bib.ID, CascadeReviews); err != nil {
+		t.Fatalf("Failed to delete bibliography: %v", err)
+	}
+	if len(index.Bibliographies) != 0 || len(index.Reviews) != 0 {
+		t.Errorf("Expected deleted entities to be removed from the index, got %d/%d", len(index.Bibliographies), len(index.Reviews))
+	}
+}
+
+func TestSearch_BuildsIndexOnFirstUse(t *testing.T) {
+	bib := &domain.Bibliography{ID: domain.NewBibliographyID(), Title: "Found"}
+	review := &domain.Review{ID: domain.NewReviewID(), BookID: bib.ID, Goals: "Goals"}
+	bibRepo := &MockBibliographyRepository{Bibliographies: map[domain.BibliographyID]*domain.Bibliography{bib.ID: bib}}
+	reviewRepo := &MockReviewRepository{Reviews: map[domain.ReviewID]*domain.Review{review.ID: review}}
+	index := &MockSearchIndex{Hits: []domain.SearchHit{
+		{BookID: domain.NewBibliographyID(), Score: 2}, // orphaned reviews only
+		{BookID: bib.ID, Score: 1, Fields: []string{domain.SearchFieldGoals}, ReviewIDs: []domain.ReviewID{review.ID}},
+	}}
+
+	results, err := NewSearchService(index, bibRepo, reviewRepo).Search("goals", 0)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if !index.Built || index.Bibliographies[bib.ID] == nil || index.Reviews[review.ID] == nil {
+		t.Error("Expected index to be built from the repositories")
+	}
+	if len(results) != 1 || results[0].Bibliography != bib {
+		t.Fatalf("Expected only the existing bibliography, got %+v", results)
+	}
+	if len(results[0].Reviews) != 1 || results[0].Reviews[0] != review {
+		t.Errorf("Expected the matching review, got %+v", results[0].Reviews)
+	}
+}
@@ -0,0 +1,216 @@
+// Package textdiff compares two texts line by line. Replaced lines also get their
+// changed characters marked: Japanese has no spaces between words, so a word-level
+// diff would mark whole sentences, and runes are compared instead.
+package textdiff
+
+import "strings"
+
+// Op says what happened to a line or character.
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+func (op Op) String() string {
+	switch op {
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	default:
+		return "equal"
+	}
+}
+
+// Span is a run of characters of a replaced line.
+type Span struct {
+	Text    string
+	Changed bool
+}
+
+// Line is one line of a diff: a line of the old text that was kept or deleted, or a line
+// of the new text that was inserted.
+type Line struct {
+	Op   Op
+	Text string
+	// Spans splits a replaced line (a deleted line paired with the inserted line that
+	// took its place) into unchanged and changed runs. It is nil for other lines and for
+	// replacements that have too little in common to be worth marking.
+	Spans []Span
+}
+
+// maxInlineCells bounds the table of the character comparison of one pair of lines, so
+// that a huge single-line text costs memory proportional to its length only.
+const maxInlineCells = 4_000_000
+
+// Lines compares a and b line by line. "\r\n" counts as "\n", and a final line break
+// does not start another line. Deleted lines come before the lines inserted in their place.
+func Lines(a, b string) []Line {
+	oldLines, newLines := splitLines(a), splitLines(b)
+	var lines []Line
+	i, j := 0, 0
+	for _, op := range diff(oldLines, newLines) {
+		switch op {
+		case Equal:
+			lines = append(lines, Line{Op: Equal, Text: oldLines[i]})
+			i++
+			j++
+		case Delete:
+			lines = append(lines, Line{Op: Delete, Text: oldLines[i]})
+			i++
+		case Insert:
+			lines = append(lines, Line{Op: Insert, Text: newLines[j]})
+			j++
+		}
+	}
+	markReplacements(lines)
+	return lines
+}
+
+// Changed reports whether any line was deleted or inserted.
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// markReplacements pairs each run of deleted lines with the run of inserted lines that
+// follows it, first with first, and marks the characters that changed.
+func markReplacements(lines []Line) {
+	for start := 0; start < len(lines); {
+		if lines[start].Op != Delete {
+			start++
+			continue
+		}
+		deletes := start
+		for deletes < len(lines) && lines[deletes].Op == Delete {
+			deletes++
+		}
+		inserts := deletes
+		for inserts < len(lines) && lines[inserts].Op == Insert {
+			inserts++
+		}
+		for k := 0; start+k < deletes && deletes+k < inserts; k++ {
+			markPair(&lines[start+k], &lines[deletes+k])
+		}
+		start = inserts
+	}
+}
+
+func markPair(deleted, inserted *Line) {
+	a, b := []rune(deleted.Text), []rune(inserted.Text)
+	if len(a)*len(b) > maxInlineCells {
+		return
+	}
+	ops := diff(a, b)
+	common := 0
+	for _, op := range ops {
+		if op == Equal {
+			common++
+		}
+	}
+	// Lines with little in common read better as a plain replacement
+	if common*2 < max(len(a), len(b)) {
+		return
+	}
+
+	var oldSpans, newSpans spanBuilder
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case Equal:
+			oldSpans.add(a[i], false)
+			newSpans.add(b[j], false)
+			i++
+			j++
+		case Delete:
+			oldSpans.add(a[i], true)
+			i++
+		case Insert:
+			newSpans.add(b[j], true)
+			j++
+		}
+	}
+	deleted.Spans, inserted.Spans = oldSpans.spans, newSpans.spans
+}
+
+type spanBuilder struct {
+	spans []Span
+}
+
+func (b *spanBuilder) add(r rune, changed bool) {
+	if n := len(b.spans); n > 0 && b.spans[n-1].Changed == changed {
+		b.spans[n-1].Text += string(r)
+		return
+	}
+	b.spans = append(b.spans, Span{Text: string(r), Changed: changed})
+}
+
+// diff returns the shortest edit turning a into b, from the longest common subsequence.
+// Equal consumes an element of both, Delete one of a and Insert one of b.
+func diff[T comparable](a, b []T) []Op {
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+	n, m := len(midA), len(midB)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	for k := 0; k < prefix; k++ {
+		ops = append(ops, Equal)
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && midA[i] == midB[j]:
+			ops = append(ops, Equal)
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, Delete)
+			i++
+		default:
+			ops = append(ops, Insert)
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, Equal)
+	}
+	return ops
+}
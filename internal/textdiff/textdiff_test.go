@@ -0,0 +1,127 @@
+package textdiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// format renders lines like a unified diff, with changed characters of replaced lines in
+// [-...-] and {+...+}.
+func format(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(map[Op]string{Equal: " ", Delete: "-", Insert: "+"}[line.Op])
+		if line.Spans == nil {
+			b.WriteString(line.Text)
+		}
+		for _, span := range line.Spans {
+			switch {
+			case !span.Changed:
+				b.WriteString(span.Text)
+			case line.Op == Delete:
+				b.WriteString("[-" + span.Text + "-]")
+			default:
+				b.WriteString("{+" + span.Text + "+}")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    "一行目\n二行目\n",
+			b:    "一行目\n二行目",
+			want: " 一行目\n 二行目\n",
+		},
+		{
+			name: "inserted and deleted lines",
+			a:    "a\nb\nc",
+			b:    "a\nc\nd",
+			want: " a\n-b\n c\n+d\n",
+		},
+		{
+			name: "Japanese line marked by character",
+			a:    "データモデルは事実を記録する。\n段落2",
+			b:    "データモデルは、業務の事実を記録する。\n段落2",
+			want: "-データモデルは事実を記録する。\n+データモデルは{+、業務の+}事実を記録する。\n 段落2\n",
+		},
+		{
+			name: "replaced character",
+			a:    "今日は晴れです",
+			b:    "今日は雨です",
+			want: "-今日は[-晴れ-]です\n+今日は{+雨+}です\n",
+		},
+		{
+			name: "unrelated lines are not marked",
+			a:    "まったく別の文章",
+			b:    "Something else",
+			want: "-まったく別の文章\n+Something else\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "新しい\r\nサマリー\r\n",
+			want: "+新しい\n+サマリー\n",
+		},
+		{
+			name: "blank lines are kept",
+			a:    "段落1\n\n段落2",
+			b:    "段落1\n\n\n段落2",
+			want: " 段落1\n \n+\n 段落2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := format(Lines(tt.a, tt.b)); got != tt.want {
+				t.Errorf("Lines() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	if Changed(Lines("a\nb", "a\nb\n")) {
+		t.Error("Expected no change for a trailing line break")
+	}
+	if !Changed(Lines("a", "")) {
+		t.Error("Expected a deleted line to be a change")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := diff([]rune("abcabba"), []rune("cbabac"))
+	// Applying the ops must turn a into b with the fewest edits (LCS length 4)
+	var out []rune
+	a, b := []rune("abcabba"), []rune("cbabac")
+	i, j, edits := 0, 0, 0
+	for _, op := range got {
+		switch op {
+		case Equal:
+			if a[i] != b[j] {
+				t.Fatalf("Equal op at %d, %d compares %c and %c", i, j, a[i], b[j])
+			}
+			out = append(out, a[i])
+			i++
+			j++
+		case Delete:
+			i++
+			edits++
+		case Insert:
+			out = append(out, b[j])
+			j++
+			edits++
+		}
+	}
+	if !reflect.DeepEqual(out, b) || edits != 5 {
+		t.Errorf("diff() produced %q with %d edits, want %q with 5", string(out), edits, string(b))
+	}
+}